@@ -2,15 +2,22 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/client"
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/nfe"
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/pix"
 	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/repo/mem"
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/sefaz"
 	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/usecase"
 	httpTransport "github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/transport/http"
 )
@@ -23,13 +30,62 @@ func main() {
 	log.Printf("   - Porta: %s", port)
 	log.Printf("   - Stock Service URL: %s", stockServiceURL)
 
+	taxConfig := loadTaxConfig()
+	nfeConfig := nfe.Config{
+		Environment: getEnvInt("NFE_ENVIRONMENT", nfe.EnvironmentHomologation),
+		AppVersion:  getEnv("NFE_APP_VERSION", ""),
+
+		CSCID:             getEnv("NFCE_CSC_ID", ""),
+		CSC:               os.Getenv("NFCE_CSC"),
+		ConsumerQRCodeURL: getEnv("NFCE_QRCODE_URL", ""),
+		ConsumerQueryURL:  getEnv("NFCE_QUERY_URL", ""),
+	}
+	log.Printf("   - Ambiente NF-e (tpAmb): %d", nfeConfig.Environment)
+	if path := getEnv("NFE_CERT_FILE", ""); path != "" {
+		nfeConfig.Signer = loadCertificate(path, os.Getenv("NFE_CERT_PASSWORD"))
+	}
+
 	// Inicialização das camadas (Dependency Injection)
 	// Client -> Repository -> UseCase -> Handler -> Router
 	stockClient := client.NewStockHTTPClient(stockServiceURL)
 	invoiceRepo := mem.NewInvoiceMemRepository()
-	invoiceService := usecase.NewInvoiceService(invoiceRepo, stockClient)
-	handler := httpTransport.NewHandler(invoiceService)
-	router := httpTransport.NewRouter(handler)
+	customerRepo := mem.NewCustomerMemRepository()
+	issuerRepo := mem.NewIssuerMemRepository()
+	serviceRepo := mem.NewServiceMemRepository()
+	carrierRepo := mem.NewCarrierMemRepository()
+	correctionRepo := mem.NewCorrectionMemRepository()
+	contingencyRepo := mem.NewContingencyMemRepository()
+	transmissionQueue := mem.NewTransmissionQueueMemRepository()
+	salesOrderRepo := mem.NewSalesOrderMemRepository()
+	backorderRepo := mem.NewBackorderMemRepository()
+	authority := loadAuthority(nfeConfig)
+	creditPolicy := domain.CreditPolicy{Approvers: getEnvList("CREDIT_APPROVERS")}
+	invoiceService := usecase.NewInvoiceService(invoiceRepo, customerRepo, issuerRepo, serviceRepo, carrierRepo, stockClient, taxConfig, nfeConfig, authority, contingencyRepo, transmissionQueue, creditPolicy, loadApprovalPolicy(), salesOrderRepo, backorderRepo)
+	customerService := usecase.NewCustomerService(customerRepo)
+	issuerService := usecase.NewIssuerService(issuerRepo)
+	correctionService := usecase.NewCorrectionService(correctionRepo, invoiceRepo, nfeConfig)
+	serviceCatalog := usecase.NewServiceCatalogService(serviceRepo)
+	carrierService := usecase.NewCarrierService(carrierRepo)
+	pixConfig := pix.Config{LocationURL: getEnv("PIX_LOCATION_URL", "")}
+	receivableService := usecase.NewReceivableService(invoiceRepo, issuerRepo, loadLateFeeConfig(), pixConfig)
+	salesOrderService := usecase.NewSalesOrderService(salesOrderRepo, customerRepo, issuerRepo, stockClient, invoiceService)
+	backorderService := usecase.NewBackorderService(backorderRepo, stockClient, invoiceService)
+	handler := httpTransport.NewHandler(invoiceService, customerService, issuerService, correctionService, serviceCatalog, carrierService, receivableService, salesOrderService, backorderService)
+
+	// Emitentes pré-configurados (um por estabelecimento)
+	if path := getEnv("ISSUERS_FILE", ""); path != "" {
+		loadIssuers(path, issuerService)
+	}
+	router := httpTransport.NewRouter(handler, loadUserIdentity())
+
+	// Transmissão em segundo plano das notas emitidas em contingência
+	workerCtx, stopWorker := context.WithCancel(context.Background())
+	defer stopWorker()
+	if authority != nil {
+		interval := time.Duration(getEnvInt("SEFAZ_QUEUE_INTERVAL_SECONDS", 30)) * time.Second
+		go usecase.NewTransmissionWorker(invoiceService, interval).Run(workerCtx)
+		log.Printf("   - Fila de contingência: transmissão a cada %s", interval)
+	}
 
 	// Servidor HTTP
 	srv := &http.Server{
@@ -54,6 +110,7 @@ func main() {
 	<-quit
 
 	log.Println("Desligando Billing Service...")
+	stopWorker()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
@@ -70,4 +127,157 @@ func getEnv(key, defaultValue string) string {
 		return value
 	}
 	return defaultValue
-}
\ No newline at end of file
+}
+
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+			return parsed
+		}
+		log.Printf("Valor inválido para %s: %q, usando padrão %v", key, value, defaultValue)
+	}
+	return defaultValue
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil {
+			return parsed
+		}
+		log.Printf("Valor inválido para %s: %q, usando padrão %v", key, value, defaultValue)
+	}
+	return defaultValue
+}
+
+// getEnvList lê uma lista separada por vírgulas, ignorando itens vazios
+func getEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// loadTaxConfig monta a configuração tributária a partir das variáveis de ambiente.
+// As alíquotas de IBS/CBS informadas valem a partir de TAX_REFORM_YEAR
+func loadTaxConfig() domain.TaxConfig {
+	config := domain.DefaultTaxConfig()
+
+	config.Legacy.ICMSRate = getEnvFloat("ICMS_RATE", config.Legacy.ICMSRate)
+	config.Legacy.PISRate = getEnvFloat("PIS_RATE", config.Legacy.PISRate)
+	config.Legacy.COFINSRate = getEnvFloat("COFINS_RATE", config.Legacy.COFINSRate)
+
+	config.Reform.CST = getEnv("IBSCBS_CST", config.Reform.CST)
+	config.Reform.ClassTrib = getEnv("IBSCBS_CLASS_TRIB", config.Reform.ClassTrib)
+
+	year := getEnvInt("TAX_REFORM_YEAR", 2026)
+	rates, _ := config.Reform.RatesFor(year)
+	config.Reform.Rates[year] = domain.TaxReformRates{
+		IBSUFRate:  getEnvFloat("IBS_UF_RATE", rates.IBSUFRate),
+		IBSMunRate: getEnvFloat("IBS_MUN_RATE", rates.IBSMunRate),
+		CBSRate:    getEnvFloat("CBS_RATE", rates.CBSRate),
+	}
+
+	log.Printf("   - IBS/CBS (%d): IBS UF %.2f%%, IBS Mun %.2f%%, CBS %.2f%%",
+		year, config.Reform.Rates[year].IBSUFRate, config.Reform.Rates[year].IBSMunRate, config.Reform.Rates[year].CBSRate)
+
+	return config
+}
+
+// loadLateFeeConfig monta os encargos de atraso dos recebimentos a partir das
+// variáveis de ambiente
+func loadLateFeeConfig() domain.LateFeeConfig {
+	config := domain.DefaultLateFeeConfig()
+	config.PenaltyRate = getEnvFloat("LATE_PENALTY_RATE", config.PenaltyRate)
+	config.MonthlyInterestRate = getEnvFloat("LATE_INTEREST_RATE", config.MonthlyInterestRate)
+	log.Printf("   - Encargos de atraso: multa %.2f%%, juros %.2f%% a.m.", config.PenaltyRate, config.MonthlyInterestRate)
+	return config
+}
+
+// loadApprovalPolicy monta as regras que retêm notas para aprovação. Sem
+// variáveis configuradas nenhuma nota é retida
+func loadApprovalPolicy() domain.ApprovalPolicy {
+	policy := domain.ApprovalPolicy{
+		TotalThreshold:     domain.NewMoneyFromFloat(getEnvFloat("APPROVAL_TOTAL_THRESHOLD", 0)),
+		MaxDiscountPercent: getEnvFloat("APPROVAL_MAX_DISCOUNT_PERCENT", 0),
+		UnverifiedItems:    getEnv("APPROVAL_UNVERIFIED_ITEMS", "false") == "true",
+		Approvers:          getEnvList("APPROVAL_APPROVERS"),
+	}
+	log.Printf("   - Aprovação: total acima de %s, desconto acima de %.2f%%, itens sem NCM: %t",
+		policy.TotalThreshold, policy.MaxDiscountPercent, policy.UnverifiedItems)
+	if len(policy.Approvers) == 0 {
+		log.Printf("   - Aprovação: APPROVAL_APPROVERS vazio; notas retidas não podem ser decididas")
+	}
+	return policy
+}
+
+// loadUserIdentity configura a conferência do usuário informado pelo gateway
+// em X-User. Sem USER_SIGNATURE_SECRET o cabeçalho é ignorado, e liberação de
+// crédito e aprovação de notas ficam indisponíveis
+func loadUserIdentity() *httpTransport.UserIdentity {
+	identity := httpTransport.NewUserIdentity(getEnv("USER_SIGNATURE_SECRET", ""))
+	if identity.Verified() {
+		log.Printf("   - Usuário (X-User): conferido pela assinatura do gateway")
+	} else {
+		log.Printf("   - Usuário (X-User): ignorado sem USER_SIGNATURE_SECRET; liberação de crédito e aprovação recusadas")
+	}
+	return identity
+}
+
+// loadCertificate carrega o certificado A1 usado para assinar as NF-e
+func loadCertificate(path, password string) *nfe.Signer {
+	signer, err := nfe.LoadCertificate(path, password)
+	if err != nil {
+		log.Fatalf("Erro ao carregar certificado digital: %v", err)
+	}
+	certificate := signer.Certificate()
+	log.Printf("   - Certificado: %s (válido até %s)", certificate.Subject.CommonName, certificate.NotAfter.Format("02/01/2006"))
+	return signer
+}
+
+// loadAuthority configura o cliente dos web services de autorização da SEFAZ.
+// Sem SEFAZ_URL as notas são fechadas sem pedido de autorização
+func loadAuthority(nfeConfig nfe.Config) domain.AuthorityClient {
+	url := getEnv("SEFAZ_URL", "")
+	if url == "" {
+		log.Printf("   - SEFAZ: não configurada (notas não serão autorizadas)")
+		return nil
+	}
+
+	config := sefaz.Config{
+		BaseURL:      url,
+		Environment:  nfeConfig.Environment,
+		Timeout:      time.Duration(getEnvInt("SEFAZ_TIMEOUT_SECONDS", 30)) * time.Second,
+		PollInterval: time.Duration(getEnvInt("SEFAZ_POLL_INTERVAL_MS", 1000)) * time.Millisecond,
+		MaxPolls:     getEnvInt("SEFAZ_MAX_POLLS", 5),
+	}
+	if nfeConfig.Signer != nil {
+		certificate := nfeConfig.Signer.TLSCertificate()
+		config.Certificate = &certificate
+	}
+	log.Printf("   - SEFAZ: %s", url)
+	return sefaz.NewClient(config)
+}
+
+// loadIssuers cadastra os emitentes descritos em um arquivo JSON (lista de emitentes)
+func loadIssuers(path string, issuerService *usecase.IssuerService) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Fatalf("Erro ao ler arquivo de emitentes: %v", err)
+	}
+
+	var issuers []domain.Issuer
+	if err := json.Unmarshal(data, &issuers); err != nil {
+		log.Fatalf("Erro ao decodificar arquivo de emitentes: %v", err)
+	}
+
+	for _, issuer := range issuers {
+		created, err := issuerService.CreateIssuer(issuer)
+		if err != nil {
+			log.Fatalf("Emitente %q inválido: %v", issuer.Name, err)
+		}
+		log.Printf("   - Emitente: %s (CNPJ %s, série %d, id %s)", created.Name, created.CNPJ, created.Series, created.ID)
+	}
+}
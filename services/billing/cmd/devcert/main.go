@@ -0,0 +1,70 @@
+// Comando devcert gera um certificado A1 autoassinado (PKCS#12) para assinar
+// NF-e em desenvolvimento e homologação local. O certificado não tem valor
+// fiscal e não é aceito pela SEFAZ.
+//
+// Uso: go run ./cmd/devcert -cnpj 11222333000181 -out dev.pfx -password 1234
+package main
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"flag"
+	"log"
+	"math/big"
+	"os"
+	"time"
+
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+func main() {
+	name := flag.String("name", "EMPRESA DE DESENVOLVIMENTO", "razão social do titular")
+	cnpj := flag.String("cnpj", "11222333000181", "CNPJ do titular")
+	out := flag.String("out", "dev.pfx", "arquivo de saída")
+	password := flag.String("password", "1234", "senha do arquivo PKCS#12")
+	days := flag.Int("days", 365, "dias de validade")
+	flag.Parse()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		log.Fatalf("Erro ao gerar chave: %v", err)
+	}
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	if err != nil {
+		log.Fatalf("Erro ao gerar número de série: %v", err)
+	}
+
+	// Certificados ICP-Brasil de pessoa jurídica trazem "RAZÃO SOCIAL:CNPJ" no CN
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			CommonName:   *name + ":" + *cnpj,
+			Organization: []string{*name},
+			Country:      []string{"BR"},
+		},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().AddDate(0, 0, *days),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageContentCommitment | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageEmailProtection},
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		log.Fatalf("Erro ao criar certificado: %v", err)
+	}
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		log.Fatalf("Erro ao ler certificado: %v", err)
+	}
+
+	pfx, err := pkcs12.Modern.Encode(key, certificate, nil, *password)
+	if err != nil {
+		log.Fatalf("Erro ao gerar PKCS#12: %v", err)
+	}
+	if err := os.WriteFile(*out, pfx, 0o600); err != nil {
+		log.Fatalf("Erro ao gravar %s: %v", *out, err)
+	}
+	log.Printf("Certificado gerado em %s (CN=%s, válido até %s)", *out, certificate.Subject.CommonName, certificate.NotAfter.Format("02/01/2006"))
+}
@@ -0,0 +1,48 @@
+// Comando sefazmock sobe um autorizador de NF-e simulado, com o mesmo
+// contrato SOAP dos web services NFeAutorizacao4 e NFeRetAutorizacao4.
+// O roteiro de respostas pode ser alterado em execução por PUT /mock/script.
+//
+// Uso: go run ./cmd/sefazmock -addr :8090 -script authorized,rejected,timeout
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/sefaz"
+)
+
+func main() {
+	addr := flag.String("addr", ":8090", "endereço de escuta")
+	steps := flag.String("script", "", "resultados dos próximos lotes, separados por vírgula (authorized, rejected, denied, timeout)")
+	fallback := flag.String("default", string(sefaz.OutcomeAuthorized), "resultado após o fim do roteiro")
+	polls := flag.Int("processing-polls", 1, "consultas respondidas como lote em processamento")
+	hang := flag.Int("hang", 60, "segundos sem resposta no resultado timeout")
+	flag.Parse()
+
+	script := sefaz.DefaultScript()
+	script.Default = sefaz.Step{Outcome: sefaz.Outcome(*fallback)}
+	script.ProcessingPolls = *polls
+	script.HangSeconds = *hang
+	for _, outcome := range strings.Split(*steps, ",") {
+		if outcome = strings.TrimSpace(outcome); outcome != "" {
+			script.Steps = append(script.Steps, sefaz.Step{Outcome: sefaz.Outcome(outcome)})
+		}
+	}
+	if err := script.Validate(); err != nil {
+		log.Fatalf("Roteiro inválido: %v", err)
+	}
+
+	srv := &http.Server{
+		Addr:        *addr,
+		Handler:     sefaz.NewMockServer(script),
+		ReadTimeout: 15 * time.Second,
+	}
+	log.Printf("SEFAZ simulada em %s (roteiro: %v, padrão: %s)", *addr, script.Steps, script.Default.Outcome)
+	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		log.Fatalf("Erro ao iniciar servidor: %v", err)
+	}
+}
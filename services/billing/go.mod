@@ -3,7 +3,17 @@ module github.com/VitorMozer9/korp-teste-VitorMozer/services/billing
 go 1.25.1
 
 require (
+	github.com/beevik/etree v1.1.0
+	github.com/boombuler/barcode v1.0.1
 	github.com/go-chi/chi/v5 v5.0.11
 	github.com/go-chi/cors v1.2.1
 	github.com/google/uuid v1.5.0
+	github.com/jung-kurt/gofpdf v1.16.2
+	github.com/russellhaering/goxmldsig v1.4.0
+	software.sslmate.com/src/go-pkcs12 v0.5.0
+)
+
+require (
+	github.com/jonboulle/clockwork v0.2.2 // indirect
+	golang.org/x/crypto v0.11.0 // indirect
 )
@@ -0,0 +1,201 @@
+// Package boleto gera o código de barras e a linha digitável dos boletos de
+// cobrança das parcelas, conforme as especificações da Febraban e os leiautes
+// de campo livre de cada banco, e a ficha de compensação em PDF.
+package boleto
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+)
+
+// Valores fixos do código de barras
+const (
+	currencyReal = "9"        // Código da moeda: real
+	maxAmount    = 9999999999 // Valor máximo, em centavos, nas 10 posições do código
+	factorMin    = 1000       // Fator de vencimento mínimo, usado após o reinício do ciclo
+	factorMax    = 9999       // Fator de vencimento máximo de cada ciclo
+	factorCycle  = factorMax - factorMin + 1
+)
+
+// factorBase é a data base do fator de vencimento (fator 0). O fator 9999
+// corresponde a 21/02/2025; a partir de 22/02/2025 a contagem recomeça em 1000
+var factorBase = time.Date(1997, 10, 7, 0, 0, 0, 0, time.UTC)
+
+// ourNumberSizes é o tamanho do nosso número sequencial no campo livre de cada banco
+var ourNumberSizes = map[string]int{
+	domain.BankBrasil:   10,
+	domain.BankBradesco: 11,
+	domain.BankItau:     8,
+}
+
+// Generate monta o boleto de uma parcela. O nosso número é derivado do número
+// da nota e da parcela, o que o mantém único por conta de cobrança enquanto a
+// numeração da nota couber no campo do banco
+func Generate(account domain.BankSlipAccount, invoice *domain.Invoice, installment domain.Installment) (*domain.BankSlip, error) {
+	size, ok := ourNumberSizes[account.Bank]
+	if !ok {
+		return nil, domain.ErrUnsupportedBank
+	}
+	amount := installment.Outstanding()
+	if amount > maxAmount {
+		return nil, domain.ErrBankSlipAmount
+	}
+
+	sequence, err := strconv.Atoi(installment.Number)
+	if err != nil {
+		return nil, domain.ErrInstallmentNotFound
+	}
+	ourNumber := fmt.Sprintf("%0*d", size, (int64(invoice.Number)*1000+int64(sequence))%pow10(size))
+
+	free, printed := freeField(account, ourNumber)
+	barcode := Barcode(account.Bank, DueFactor(installment.DueDate), amount, free)
+	line := DigitableLine(barcode)
+
+	return &domain.BankSlip{
+		Bank:                   account.Bank,
+		Installment:            installment.Number,
+		DocumentNumber:         fmt.Sprintf("%d/%s", invoice.Number, installment.Number),
+		DueDate:                installment.DueDate,
+		Amount:                 amount,
+		OurNumber:              printed,
+		Barcode:                barcode,
+		DigitableLine:          line,
+		DigitableLineFormatted: FormatDigitableLine(line),
+	}, nil
+}
+
+// freeField monta o campo livre (25 posições) do banco e o nosso número como
+// impresso no boleto
+func freeField(account domain.BankSlipAccount, ourNumber string) (free, printed string) {
+	switch account.Bank {
+	case domain.BankBrasil:
+		// 000000 + convênio (7) + nosso número (10) + carteira (2)
+		free = "000000" + account.Agreement + ourNumber + account.Wallet
+		printed = account.Agreement + ourNumber
+	case domain.BankBradesco:
+		// agência (4) + carteira (2) + nosso número (11) + conta (7) + zero
+		free = account.Agency + account.Wallet + ourNumber + account.Account + "0"
+		printed = account.Wallet + "/" + ourNumber + "-" + bradescoDigit(account.Wallet+ourNumber)
+	case domain.BankItau:
+		// carteira (3) + nosso número (8) + DAC + agência (4) + conta (5) + DAC + 000
+		ourNumberDigit := strconv.Itoa(Mod10(account.Agency + account.Account + account.Wallet + ourNumber))
+		accountDigit := strconv.Itoa(Mod10(account.Agency + account.Account))
+		free = account.Wallet + ourNumber + ourNumberDigit + account.Agency + account.Account + accountDigit + "000"
+		printed = account.Wallet + "/" + ourNumber + "-" + ourNumberDigit
+	}
+	return free, printed
+}
+
+// Barcode monta o código de barras de 44 posições: banco, moeda, dígito
+// verificador geral, fator de vencimento, valor e campo livre
+func Barcode(bank string, factor int, amount domain.Money, free string) string {
+	body := fmt.Sprintf("%s%s%04d%010d%s", bank, currencyReal, factor, int64(amount), free)
+	return body[:4] + strconv.Itoa(barcodeDigit(body)) + body[4:]
+}
+
+// DigitableLine converte o código de barras na linha digitável de 47 dígitos:
+// três campos com o campo livre, cada um com seu dígito módulo 10, o dígito
+// geral e o fator de vencimento com o valor
+func DigitableLine(barcode string) string {
+	field1 := barcode[0:4] + barcode[19:24]
+	field2 := barcode[24:34]
+	field3 := barcode[34:44]
+	return field1 + strconv.Itoa(Mod10(field1)) +
+		field2 + strconv.Itoa(Mod10(field2)) +
+		field3 + strconv.Itoa(Mod10(field3)) +
+		barcode[4:5] + barcode[5:19]
+}
+
+// FormatDigitableLine formata a linha digitável com os separadores impressos
+// no boleto (AAAAA.AAAAA BBBBB.BBBBBB CCCCC.CCCCCC D EEEEEEEEEEEEEE)
+func FormatDigitableLine(line string) string {
+	if len(line) != 47 {
+		return line
+	}
+	return strings.Join([]string{
+		line[0:5] + "." + line[5:10],
+		line[10:15] + "." + line[15:21],
+		line[21:26] + "." + line[26:32],
+		line[32:33],
+		line[33:47],
+	}, " ")
+}
+
+// DueFactor calcula o fator de vencimento: dias desde a data base, reiniciando
+// em 1000 após atingir 9999. Datas anteriores à base resultam em zero (sem
+// vencimento)
+func DueFactor(dueDate time.Time) int {
+	days := domain.DaysBetween(factorBase, dueDate)
+	if days <= 0 {
+		return 0
+	}
+	if days > factorMax {
+		days = (days-factorMin)%factorCycle + factorMin
+	}
+	return days
+}
+
+// Mod10 calcula o dígito verificador módulo 10 dos campos da linha digitável:
+// pesos 2 e 1 alternados da direita para a esquerda, somando os algarismos
+// dos produtos
+func Mod10(digits string) int {
+	sum := 0
+	weight := 2
+	for i := len(digits) - 1; i >= 0; i-- {
+		product := int(digits[i]-'0') * weight
+		sum += product/10 + product%10
+		weight = 3 - weight
+	}
+	return (10 - sum%10) % 10
+}
+
+// mod11 soma os algarismos com pesos de 2 até maxWeight, da direita para a
+// esquerda, e retorna o resto da divisão por 11
+func mod11(digits string, maxWeight int) int {
+	sum := 0
+	weight := 2
+	for i := len(digits) - 1; i >= 0; i-- {
+		sum += int(digits[i]-'0') * weight
+		weight++
+		if weight > maxWeight {
+			weight = 2
+		}
+	}
+	return sum % 11
+}
+
+// barcodeDigit calcula o dígito verificador geral do código de barras
+// (módulo 11, pesos 2 a 9); os resultados 0, 10 e 11 viram 1
+func barcodeDigit(body string) int {
+	digit := 11 - mod11(body, 9)
+	if digit == 0 || digit >= 10 {
+		return 1
+	}
+	return digit
+}
+
+// bradescoDigit calcula o dígito do nosso número do Bradesco (módulo 11,
+// pesos 2 a 7, sobre carteira e nosso número); resto 1 resulta em "P"
+func bradescoDigit(digits string) string {
+	switch rest := mod11(digits, 7); rest {
+	case 0:
+		return "0"
+	case 1:
+		return "P"
+	default:
+		return strconv.Itoa(11 - rest)
+	}
+}
+
+// pow10 retorna 10 elevado ao expoente informado
+func pow10(exponent int) int64 {
+	result := int64(1)
+	for i := 0; i < exponent; i++ {
+		result *= 10
+	}
+	return result
+}
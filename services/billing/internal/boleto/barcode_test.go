@@ -0,0 +1,116 @@
+package boleto
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDueFactor(t *testing.T) {
+	// Datas de referência da Febraban: fator 1000 em 03/07/2000, 9999 em
+	// 21/02/2025 e reinício em 1000 no dia seguinte; o segundo ciclo termina em
+	// 13/10/2049
+	tests := []struct {
+		date     string
+		expected int
+	}{
+		{date: "1997-10-07", expected: 0},
+		{date: "1997-10-08", expected: 1},
+		{date: "2000-07-03", expected: 1000},
+		{date: "2007-12-31", expected: 3737},
+		{date: "2025-02-21", expected: 9999},
+		{date: "2025-02-22", expected: 1000},
+		{date: "2025-02-23", expected: 1001},
+		{date: "2049-10-13", expected: 9999},
+		{date: "2049-10-14", expected: 1000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.date, func(t *testing.T) {
+			date, err := time.Parse(time.DateOnly, tt.date)
+			if err != nil {
+				t.Fatalf("data inválida: %v", err)
+			}
+			if got := DueFactor(date); got != tt.expected {
+				t.Errorf("DueFactor(%s) = %d, esperado %d", tt.date, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMod10(t *testing.T) {
+	tests := []struct {
+		digits   string
+		expected int
+	}{
+		// Campos da linha digitável do exemplo do Banco do Brasil
+		{digits: "001905009", expected: 5},
+		{digits: "4014481606", expected: 9},
+		{digits: "0680935031", expected: 4},
+		{digits: "0000000000", expected: 0},
+		// Produto 9 x 2 = 18 soma os algarismos (1 + 8)
+		{digits: "9", expected: 1},
+	}
+
+	for _, tt := range tests {
+		if got := Mod10(tt.digits); got != tt.expected {
+			t.Errorf("Mod10(%q) = %d, esperado %d", tt.digits, got, tt.expected)
+		}
+	}
+}
+
+func TestBarcodeDigit(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string // Código de barras sem o dígito geral (43 posições)
+		expected int
+	}{
+		{name: "exemplo do Banco do Brasil", body: "0019373700000001000500940144816060680935031", expected: 3},
+		{name: "resto zero vira 1", body: strings.Repeat("0", 43), expected: 1},
+		{name: "resto um vira 1", body: strings.Repeat("0", 42) + "6", expected: 1},
+		{name: "resto dez", body: strings.Repeat("0", 42) + "5", expected: 1},
+		{name: "peso 2 no último dígito", body: strings.Repeat("0", 42) + "1", expected: 9},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := barcodeDigit(tt.body); got != tt.expected {
+				t.Errorf("barcodeDigit(%q) = %d, esperado %d", tt.body, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDigitableLine(t *testing.T) {
+	// Exemplo do Banco do Brasil: vencimento em 31/12/2007 (fator 3737),
+	// R$ 1,00 e campo livre do convênio de 7 posições
+	barcode := Barcode("001", 3737, 100, "0500940144816060680935031")
+	if barcode != "00193373700000001000500940144816060680935031" {
+		t.Fatalf("Barcode() = %s", barcode)
+	}
+	line := DigitableLine(barcode)
+	if line != "00190500954014481606906809350314337370000000100" {
+		t.Errorf("DigitableLine() = %s", line)
+	}
+	if formatted := FormatDigitableLine(line); formatted != "00190.50095 40144.816069 06809.350314 3 37370000000100" {
+		t.Errorf("FormatDigitableLine() = %s", formatted)
+	}
+}
+
+func TestBradescoDigit(t *testing.T) {
+	tests := []struct {
+		digits   string // Carteira e nosso número
+		expected string
+	}{
+		// Exemplo do manual do Bradesco: carteira 19, nosso número 00000000002
+		{digits: "1900000000002", expected: "8"},
+		{digits: "0000000000000", expected: "0"},
+		{digits: "0000000000006", expected: "P"},
+	}
+
+	for _, tt := range tests {
+		if got := bradescoDigit(tt.digits); got != tt.expected {
+			t.Errorf("bradescoDigit(%q) = %q, esperado %q", tt.digits, got, tt.expected)
+		}
+	}
+}
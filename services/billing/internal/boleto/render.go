@@ -0,0 +1,280 @@
+package boleto
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/boombuler/barcode/twooffive"
+	"github.com/jung-kurt/gofpdf"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+)
+
+// Dimensões da página A4 retrato e da ficha de compensação, em milímetros
+const (
+	pageWidth     = 210.0
+	margin        = 10.0
+	contentWidth  = pageWidth - 2*margin
+	sideWidth     = 45.0 // Coluna de vencimento, valores e nosso número
+	fieldHeight   = 8.0
+	barcodeWidth  = 103.0 // Largura do código de barras prevista pela Febraban
+	barcodeHeight = 13.0
+)
+
+// bankNames identifica o banco com o código de compensação e seu dígito
+var bankNames = map[string]struct{ code, name string }{
+	domain.BankBrasil:   {"001-9", "Banco do Brasil"},
+	domain.BankBradesco: {"237-2", "Bradesco"},
+	domain.BankItau:     {"341-7", "Itaú"},
+}
+
+// Document reúne os dados impressos no boleto além do código de barras
+type Document struct {
+	Slip         *domain.BankSlip
+	Account      domain.BankSlipAccount
+	Beneficiary  *domain.Issuer
+	Payer        *domain.Customer
+	IssuedAt     time.Time
+	Instructions []string // Instruções ao caixa (multa, juros)
+}
+
+// renderer mantém o estado da geração do PDF
+type renderer struct {
+	pdf *gofpdf.Fpdf
+	tr  func(string) string
+	doc Document
+}
+
+// Render gera o boleto em PDF: recibo do pagador e ficha de compensação com o
+// código de barras intercalado 2 de 5
+func Render(doc Document) ([]byte, error) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(margin, margin, margin)
+	pdf.SetAutoPageBreak(false, margin)
+	pdf.SetTitle("Boleto "+doc.Slip.DigitableLine, true)
+	pdf.SetCreator("korp-billing", true)
+	pdf.AddPage()
+
+	r := &renderer{
+		pdf: pdf,
+		tr:  pdf.UnicodeTranslatorFromDescriptor(""),
+		doc: doc,
+	}
+	y := r.receipt(margin)
+	r.cutLine(y + 4)
+	r.compensation(y + 10)
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("erro ao gerar boleto: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// receipt desenha o recibo do pagador e retorna a posição abaixo dele
+func (r *renderer) receipt(y float64) float64 {
+	slip := r.doc.Slip
+	y = r.header(y, "Recibo do Pagador")
+
+	half := contentWidth / 2
+	r.field(margin, y, half, "Beneficiário", r.beneficiary(), "L")
+	r.field(margin+half, y, half/2, "Agência / Código do beneficiário", r.accountCode(), "L")
+	r.field(margin+half*1.5, y, half/2, "Vencimento", formatDate(slip.DueDate), "R")
+	y += fieldHeight
+	r.field(margin, y, half, "Pagador", r.payer(), "L")
+	r.field(margin+half, y, half/2, "Nosso número", slip.OurNumber, "L")
+	r.field(margin+half*1.5, y, half/2, "Valor do documento", formatMoney(slip.Amount), "R")
+	y += fieldHeight
+	r.field(margin, y, half, "Número do documento", slip.DocumentNumber, "L")
+	r.field(margin+half, y, half, "Autenticação mecânica", "", "L")
+	return y + fieldHeight
+}
+
+// compensation desenha a ficha de compensação, lida pelo banco no pagamento
+func (r *renderer) compensation(y float64) {
+	slip := r.doc.Slip
+	y = r.header(y, slip.DigitableLineFormatted)
+
+	main := contentWidth - sideWidth
+	side := margin + main
+	r.field(margin, y, main, "Local de pagamento", "Pagável em qualquer banco até o vencimento", "L")
+	r.field(side, y, sideWidth, "Vencimento", formatDate(slip.DueDate), "R")
+	y += fieldHeight
+	r.field(margin, y, main, "Beneficiário", r.beneficiary(), "L")
+	r.field(side, y, sideWidth, "Agência / Código do beneficiário", r.accountCode(), "R")
+	y += fieldHeight
+
+	columns := []struct {
+		label, value string
+		width        float64
+	}{
+		{"Data do documento", formatDate(r.doc.IssuedAt), 0.22},
+		{"Número do documento", slip.DocumentNumber, 0.24},
+		{"Espécie doc.", "DM", 0.14},
+		{"Aceite", "N", 0.10},
+		{"Data do processamento", formatDate(r.doc.IssuedAt), 0.30},
+	}
+	x := margin
+	for _, column := range columns {
+		r.field(x, y, main*column.width, column.label, column.value, "L")
+		x += main * column.width
+	}
+	r.field(side, y, sideWidth, "Nosso número", slip.OurNumber, "R")
+	y += fieldHeight
+
+	columns = []struct {
+		label, value string
+		width        float64
+	}{
+		{"Uso do banco", "", 0.22},
+		{"Carteira", r.doc.Account.Wallet, 0.24},
+		{"Espécie", "R$", 0.14},
+		{"Quantidade", "", 0.20},
+		{"Valor", "", 0.20},
+	}
+	x = margin
+	for _, column := range columns {
+		r.field(x, y, main*column.width, column.label, column.value, "L")
+		x += main * column.width
+	}
+	r.field(side, y, sideWidth, "(=) Valor do documento", formatMoney(slip.Amount), "R")
+	y += fieldHeight
+
+	instructionsHeight := 4 * fieldHeight
+	r.pdf.Rect(margin, y, main, instructionsHeight, "D")
+	r.label(margin, y, "Instruções (texto de responsabilidade do beneficiário)")
+	r.pdf.SetFont("Helvetica", "", 8)
+	r.pdf.SetXY(margin+1, y+4)
+	r.pdf.MultiCell(main-2, 4, r.tr(strings.Join(r.doc.Instructions, "\n")), "", "L", false)
+	for i, label := range []string{"(-) Desconto / Abatimento", "(+) Mora / Multa", "(+) Outros acréscimos", "(=) Valor cobrado"} {
+		r.field(side, y+float64(i)*fieldHeight, sideWidth, label, "", "R")
+	}
+	y += instructionsHeight
+
+	r.pdf.Rect(margin, y, contentWidth, 2*fieldHeight, "D")
+	r.label(margin, y, "Pagador")
+	r.pdf.SetFont("Helvetica", "", 8)
+	r.pdf.SetXY(margin+1, y+3.5)
+	r.pdf.MultiCell(contentWidth-2, 4, r.tr(r.payer()+"\n"+r.payerAddress()), "", "L", false)
+	y += 2 * fieldHeight
+
+	r.label(margin+contentWidth-60, y, "Autenticação mecânica - Ficha de Compensação")
+	r.barcode(margin, y+2, slip.Barcode)
+}
+
+// header desenha o logotipo textual do banco, o código com dígito e o texto
+// à direita (título do recibo ou linha digitável)
+func (r *renderer) header(y float64, text string) float64 {
+	bank := bankNames[r.doc.Slip.Bank]
+	r.pdf.SetFont("Helvetica", "B", 11)
+	r.pdf.SetXY(margin, y)
+	r.pdf.CellFormat(45, 8, r.tr(bank.name), "B", 0, "L", false, 0, "")
+	r.pdf.SetFont("Helvetica", "B", 13)
+	r.pdf.CellFormat(20, 8, bank.code, "LRB", 0, "C", false, 0, "")
+	r.pdf.SetFont("Helvetica", "B", 10)
+	r.pdf.CellFormat(contentWidth-65, 8, r.tr(text), "B", 0, "R", false, 0, "")
+	return y + 8
+}
+
+// cutLine desenha a linha tracejada de corte entre o recibo e a ficha
+func (r *renderer) cutLine(y float64) {
+	r.pdf.SetDashPattern([]float64{1, 1}, 0)
+	r.pdf.Line(margin, y, margin+contentWidth, y)
+	r.pdf.SetDashPattern([]float64{}, 0)
+}
+
+// barcode desenha o código de barras intercalado 2 de 5 com a largura e a
+// altura previstas pela Febraban
+func (r *renderer) barcode(x, y float64, content string) {
+	code, err := twooffive.Encode(content, true)
+	if err != nil {
+		r.pdf.SetError(fmt.Errorf("erro ao gerar código de barras: %w", err))
+		return
+	}
+	modules := code.Bounds().Dx()
+	module := barcodeWidth / float64(modules)
+	r.pdf.SetFillColor(0, 0, 0)
+	for i := 0; i < modules; i++ {
+		if c, _, _, _ := code.At(i, 0).RGBA(); c == 0 {
+			r.pdf.Rect(x+float64(i)*module, y, module, barcodeHeight, "F")
+		}
+	}
+}
+
+// field desenha um campo com moldura, rótulo e valor
+func (r *renderer) field(x, y, w float64, label, value, align string) {
+	r.pdf.Rect(x, y, w, fieldHeight, "D")
+	r.label(x, y, label)
+	r.pdf.SetFont("Helvetica", "B", 8.5)
+	r.pdf.SetXY(x+0.5, y+3.6)
+	r.pdf.CellFormat(w-1, 4, r.fit(value, w-1), "", 0, align, false, 0, "")
+}
+
+// label desenha o rótulo de um campo
+func (r *renderer) label(x, y float64, text string) {
+	r.pdf.SetFont("Helvetica", "", 5.5)
+	r.pdf.SetXY(x+0.5, y+0.4)
+	r.pdf.CellFormat(0, 2.5, r.tr(text), "", 0, "L", false, 0, "")
+}
+
+// fit converte o texto para a codificação da fonte e o trunca na largura informada
+func (r *renderer) fit(text string, width float64) string {
+	text = r.tr(text)
+	for len(text) > 0 && r.pdf.GetStringWidth(text) > width-0.5 {
+		text = text[:len(text)-1]
+	}
+	return text
+}
+
+func (r *renderer) beneficiary() string {
+	issuer := r.doc.Beneficiary
+	return issuer.Name + " - CNPJ " + issuer.CNPJ
+}
+
+func (r *renderer) accountCode() string {
+	account := r.doc.Account
+	if account.Bank == domain.BankBrasil {
+		return account.Agency + " / " + account.Agreement
+	}
+	return account.Agency + " / " + account.Account
+}
+
+func (r *renderer) payer() string {
+	customer := r.doc.Payer
+	if customer == nil {
+		return ""
+	}
+	return customer.Name + " - " + string(customer.DocumentType) + " " + customer.Document
+}
+
+func (r *renderer) payerAddress() string {
+	if r.doc.Payer == nil {
+		return ""
+	}
+	address := r.doc.Payer.Address
+	return fmt.Sprintf("%s, %s - %s - %s/%s - CEP %s", address.Street, address.Number, address.District, address.City, address.UF, address.CEP)
+}
+
+// formatDate formata datas no padrão brasileiro
+func formatDate(date time.Time) string {
+	if date.IsZero() {
+		return ""
+	}
+	return date.Format("02/01/2006")
+}
+
+// formatMoney formata valores no padrão brasileiro ("1.234,50")
+func formatMoney(value domain.Money) string {
+	text := value.String()
+	integer, decimals := text[:len(text)-3], text[len(text)-2:]
+	var b strings.Builder
+	for i, digit := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(digit)
+	}
+	return b.String() + "," + decimals
+}
@@ -38,8 +38,45 @@ type ReservationResponse struct {
 	ErrorMessage string `json:"error_message,omitempty"`
 }
 
-// ReserveProducts reserva múltiplos produtos no Stock Service
+// ReserveProducts reserva múltiplos produtos no Stock Service. A recusa de um
+// produto pelo Stock Service é falta de saldo, não falha de comunicação
 func (c *StockHTTPClient) ReserveProducts(items []domain.InvoiceItem) error {
+	responses, err := c.postItems("reserve", items)
+	if err != nil {
+		return err
+	}
+	if failed := firstFailure(responses); failed != nil {
+		return fmt.Errorf("%w: falha ao reservar produto %s: %s",
+			domain.ErrInsufficientStock, failed.ProductID, failed.ErrorMessage)
+	}
+	return nil
+}
+
+// RestockProducts devolve ao Stock Service as quantidades de uma nota de devolução
+func (c *StockHTTPClient) RestockProducts(items []domain.InvoiceItem) error {
+	responses, err := c.postItems("restock", items)
+	if err != nil {
+		return err
+	}
+	if failed := firstFailure(responses); failed != nil {
+		return fmt.Errorf("falha ao devolver produto %s: %s", failed.ProductID, failed.ErrorMessage)
+	}
+	return nil
+}
+
+// firstFailure retorna o primeiro produto recusado pelo Stock Service
+func firstFailure(responses []ReservationResponse) *ReservationResponse {
+	for i := range responses {
+		if !responses[i].Success {
+			return &responses[i]
+		}
+	}
+	return nil
+}
+
+// postItems envia as quantidades dos itens ao endpoint de estoque informado
+// e retorna o resultado de cada produto
+func (c *StockHTTPClient) postItems(endpoint string, items []domain.InvoiceItem) ([]ReservationResponse, error) {
 	// Converte InvoiceItems para ReservationRequests
 	requests := make([]ReservationRequest, len(items))
 	for i, item := range items {
@@ -52,40 +89,33 @@ func (c *StockHTTPClient) ReserveProducts(items []domain.InvoiceItem) error {
 	// Serializa o payload
 	payload, err := json.Marshal(requests)
 	if err != nil {
-		return fmt.Errorf("erro ao serializar requisição: %w", err)
+		return nil, fmt.Errorf("erro ao serializar requisição: %w", err)
 	}
 
 	// Faz a requisição HTTP
-	url := fmt.Sprintf("%s/api/products/reserve", c.baseURL)
+	url := fmt.Sprintf("%s/api/products/%s", c.baseURL, endpoint)
 	req, err := http.NewRequest("POST", url, bytes.NewBuffer(payload))
 	if err != nil {
-		return fmt.Errorf("erro ao criar requisição: %w", err)
+		return nil, fmt.Errorf("erro ao criar requisição: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("erro ao comunicar com Stock Service: %w", err)
+		return nil, fmt.Errorf("erro ao comunicar com Stock Service: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("Stock Service retornou erro: status %d", resp.StatusCode)
+		return nil, fmt.Errorf("Stock Service retornou erro: status %d", resp.StatusCode)
 	}
 
 	var responses []ReservationResponse
 	if err := json.NewDecoder(resp.Body).Decode(&responses); err != nil {
-		return fmt.Errorf("erro ao decodificar resposta: %w", err)
-	}
-
-	// Verifica se todas as reservas foram bem-sucedidas
-	for _, res := range responses {
-		if !res.Success {
-			return fmt.Errorf("falha ao reservar produto %s: %s", res.ProductID, res.ErrorMessage)
-		}
+		return nil, fmt.Errorf("erro ao decodificar resposta: %w", err)
 	}
 
-	return nil
+	return responses, nil
 }
 
 //verifica disponibilidade de um produto
@@ -0,0 +1,684 @@
+// Package danfe gera o Documento Auxiliar da NF-e (DANFE) em PDF, no leiaute
+// retrato do Manual de Orientação do Contribuinte, a partir do documento NF-e
+// montado pelo pacote nfe.
+package danfe
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/boombuler/barcode/code128"
+	"github.com/jung-kurt/gofpdf"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/nfe"
+)
+
+// Dimensões da página A4 retrato, em milímetros
+const (
+	pageWidth    = 210.0
+	pageHeight   = 297.0
+	margin       = 6.0
+	contentWidth = pageWidth - 2*margin
+	bottomLimit  = pageHeight - margin
+
+	fieldHeight      = 7.0  // Altura dos campos com rótulo e valor
+	titleHeight      = 4.0  // Altura do título de cada bloco
+	stubHeight       = 17.0 // Canhoto de recebimento
+	headerHeight     = 32.0 // Emitente, identificação do DANFE e código de barras
+	additionalHeight = 30.0 // Dados adicionais (somente na primeira folha)
+	itemLineHeight   = 2.8
+)
+
+// column descreve uma coluna da tabela de produtos
+type column struct {
+	title string
+	width float64
+	align string
+}
+
+var itemColumns = []column{
+	{"CÓDIGO", 16, "L"},
+	{"DESCRIÇÃO DO PRODUTO / SERVIÇO", 50, "L"},
+	{"NCM/SH", 13, "C"},
+	{"CST", 9, "C"},
+	{"CFOP", 9, "C"},
+	{"UN", 8, "C"},
+	{"QUANT.", 14, "R"},
+	{"V. UNIT.", 15, "R"},
+	{"V. TOTAL", 15, "R"},
+	{"BC ICMS", 14, "R"},
+	{"V. ICMS", 12, "R"},
+	{"V. IPI", 9, "R"},
+	{"% ICMS", 8, "R"},
+	{"% IPI", 6, "R"},
+}
+
+// renderer mantém o estado da geração do PDF
+type renderer struct {
+	pdf  *gofpdf.Fpdf
+	tr   func(string) string
+	doc  *nfe.NFe
+	auth *domain.Authorization
+}
+
+// Render gera o DANFE da nota. Sem protocolo de autorização, ou em
+// homologação, o documento é marcado como sem valor fiscal
+func Render(doc *nfe.NFe, auth *domain.Authorization) ([]byte, error) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(margin, margin, margin)
+	pdf.SetAutoPageBreak(false, margin)
+	pdf.AliasNbPages("{nb}")
+	pdf.SetTitle("DANFE "+doc.AccessKey(), true)
+	pdf.SetCreator("korp-billing", true)
+
+	r := &renderer{
+		pdf:  pdf,
+		tr:   pdf.UnicodeTranslatorFromDescriptor(""),
+		doc:  doc,
+		auth: auth,
+	}
+	r.render()
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("erro ao gerar DANFE: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (r *renderer) render() {
+	items := r.doc.InfNFe.Det
+	first := true
+	for first || len(items) > 0 {
+		r.pdf.AddPage()
+		r.watermark()
+
+		y := margin
+		if first {
+			y = r.stub(y)
+		}
+		y = r.header(y)
+
+		limit := bottomLimit
+		if first {
+			y = r.recipient(y)
+			y = r.billing(y)
+			y = r.taxes(y)
+			y = r.transport(y)
+			limit -= additionalHeight
+			r.additional(limit)
+		}
+		items = r.itemTable(y, limit, items)
+		first = false
+	}
+}
+
+// stub desenha o canhoto de recebimento
+func (r *renderer) stub(y float64) float64 {
+	ide := r.doc.InfNFe.Ide
+	text := fmt.Sprintf("RECEBEMOS DE %s OS PRODUTOS/SERVIÇOS CONSTANTES DA NOTA FISCAL INDICADA AO LADO. EMISSÃO: %s  DESTINATÁRIO: %s  VALOR TOTAL: R$ %s",
+		r.doc.InfNFe.Emit.XNome, formatDate(ide.DhEmi), recipientName(r.doc), formatDecimal(r.doc.InfNFe.Total.ICMSTot.VNF))
+
+	stubWidth := contentWidth - 40
+	r.pdf.Rect(margin, y, stubWidth, stubHeight-3, "D")
+	r.pdf.SetFont("Helvetica", "", 6)
+	r.pdf.SetXY(margin+1, y+1)
+	r.pdf.MultiCell(stubWidth-2, 2.6, r.tr(text), "", "L", false)
+	r.field(margin, y+7, 40, "DATA DE RECEBIMENTO", "", "L")
+	r.field(margin+40, y+7, stubWidth-40, "IDENTIFICAÇÃO E ASSINATURA DO RECEBEDOR", "", "L")
+
+	r.pdf.Rect(margin+stubWidth, y, 40, stubHeight-3, "D")
+	r.pdf.SetFont("Helvetica", "B", 11)
+	r.pdf.SetXY(margin+stubWidth, y+1.5)
+	r.pdf.CellFormat(40, 5, "NF-e", "", 2, "C", false, 0, "")
+	r.pdf.SetFont("Helvetica", "B", 8)
+	r.pdf.CellFormat(40, 4, r.tr("Nº "+formatNumber(ide.NNF)), "", 2, "C", false, 0, "")
+	r.pdf.CellFormat(40, 4, r.tr("SÉRIE "+ide.Serie), "", 2, "C", false, 0, "")
+
+	r.pdf.SetDashPattern([]float64{1, 1}, 0)
+	r.pdf.Line(margin, y+stubHeight-1.5, margin+contentWidth, y+stubHeight-1.5)
+	r.pdf.SetDashPattern([]float64{}, 0)
+	return y + stubHeight
+}
+
+// header desenha emitente, identificação do DANFE, código de barras da chave,
+// natureza da operação, protocolo e inscrições do emitente
+func (r *renderer) header(y float64) float64 {
+	inf := r.doc.InfNFe
+	emit := inf.Emit
+	address := emit.EnderEmit
+
+	// Identificação do emitente
+	issuerWidth, danfeWidth := 80.0, 34.0
+	keyWidth := contentWidth - issuerWidth - danfeWidth
+	r.pdf.Rect(margin, y, issuerWidth, headerHeight, "D")
+	r.pdf.SetXY(margin+1, y+3)
+	r.pdf.SetFont("Helvetica", "B", 10)
+	r.pdf.MultiCell(issuerWidth-2, 4.5, r.tr(emit.XNome), "", "C", false)
+	r.pdf.SetFont("Helvetica", "", 7)
+	lines := []string{
+		strings.TrimSpace(fmt.Sprintf("%s, %s %s", address.XLgr, address.Nro, address.XCpl)),
+		fmt.Sprintf("%s - CEP %s", address.XBairro, formatCEP(address.CEP)),
+		fmt.Sprintf("%s - %s", address.XMun, address.UF),
+	}
+	if address.Fone != "" {
+		lines = append(lines, "Fone: "+address.Fone)
+	}
+	for _, line := range lines {
+		r.pdf.SetX(margin + 1)
+		r.pdf.CellFormat(issuerWidth-2, 3.4, r.tr(line), "", 2, "C", false, 0, "")
+	}
+
+	// Identificação do DANFE
+	x := margin + issuerWidth
+	r.pdf.Rect(x, y, danfeWidth, headerHeight, "D")
+	r.pdf.SetXY(x, y+1.5)
+	r.pdf.SetFont("Helvetica", "B", 12)
+	r.pdf.CellFormat(danfeWidth, 5, "DANFE", "", 2, "C", false, 0, "")
+	r.pdf.SetFont("Helvetica", "", 6)
+	r.pdf.MultiCell(danfeWidth, 2.6, r.tr("Documento Auxiliar da\nNota Fiscal Eletrônica"), "", "C", false)
+	r.pdf.SetXY(x+3, y+13)
+	r.pdf.CellFormat(18, 3, "0 - ENTRADA", "", 2, "L", false, 0, "")
+	r.pdf.CellFormat(18, 3, r.tr("1 - SAÍDA"), "", 0, "L", false, 0, "")
+	r.pdf.Rect(x+23, y+12.5, 6, 6, "D")
+	r.pdf.SetFont("Helvetica", "B", 10)
+	r.pdf.SetXY(x+23, y+12.5)
+	r.pdf.CellFormat(6, 6, inf.Ide.TpNF, "", 0, "C", false, 0, "")
+	r.pdf.SetFont("Helvetica", "B", 8)
+	r.pdf.SetXY(x, y+20)
+	r.pdf.CellFormat(danfeWidth, 3.6, r.tr("Nº "+formatNumber(inf.Ide.NNF)), "", 2, "C", false, 0, "")
+	r.pdf.CellFormat(danfeWidth, 3.6, r.tr("SÉRIE "+inf.Ide.Serie), "", 2, "C", false, 0, "")
+	r.pdf.CellFormat(danfeWidth, 3.6, fmt.Sprintf("FOLHA %d/{nb}", r.pdf.PageNo()), "", 2, "C", false, 0, "")
+
+	// Código de barras e chave de acesso
+	x += danfeWidth
+	r.pdf.Rect(x, y, keyWidth, headerHeight, "D")
+	r.barcode(x+3, y+2, keyWidth-6, 11, r.doc.AccessKey())
+	r.field(x, y+14, keyWidth, "CHAVE DE ACESSO", formatAccessKey(r.doc.AccessKey()), "C")
+	r.pdf.SetFont("Helvetica", "", 7)
+	r.pdf.SetXY(x+1, y+22.5)
+	r.pdf.MultiCell(keyWidth-2, 3, r.tr("Consulta de autenticidade no portal nacional da NF-e www.nfe.fazenda.gov.br/portal ou no site da Sefaz Autorizadora"), "", "C", false)
+	y += headerHeight
+
+	// Natureza da operação e protocolo
+	protocolWidth := keyWidth
+	r.field(margin, y, contentWidth-protocolWidth, "NATUREZA DA OPERAÇÃO", inf.Ide.NatOp, "L")
+	r.field(margin+contentWidth-protocolWidth, y, protocolWidth, "PROTOCOLO DE AUTORIZAÇÃO DE USO", r.protocol(), "C")
+	y += fieldHeight
+
+	third := contentWidth / 3
+	document := emit.CNPJ
+	if document == "" {
+		document = emit.CPF
+	}
+	r.field(margin, y, third, "INSCRIÇÃO ESTADUAL", emit.IE, "L")
+	r.field(margin+third, y, third, "INSC. ESTADUAL DO SUBST. TRIB.", "", "L")
+	r.field(margin+2*third, y, third, "CNPJ / CPF", formatDocument(document), "L")
+	return y + fieldHeight
+}
+
+// recipient desenha o bloco do destinatário
+func (r *renderer) recipient(y float64) float64 {
+	y = r.title(y, "DESTINATÁRIO / REMETENTE")
+	inf := r.doc.InfNFe
+	dest := inf.Dest
+	if dest == nil {
+		dest = &nfe.Dest{}
+	}
+	address := dest.EnderDest
+	if address == nil {
+		address = &nfe.Endereco{}
+	}
+	document := dest.CNPJ
+	if document == "" {
+		document = dest.CPF
+	}
+
+	r.field(margin, y, 118, "NOME / RAZÃO SOCIAL", dest.XNome, "L")
+	r.field(margin+118, y, 50, "CNPJ / CPF", formatDocument(document), "C")
+	r.field(margin+168, y, 30, "DATA DA EMISSÃO", formatDate(inf.Ide.DhEmi), "C")
+	y += fieldHeight
+
+	r.field(margin, y, 98, "ENDEREÇO", strings.TrimSpace(fmt.Sprintf("%s, %s %s", address.XLgr, address.Nro, address.XCpl)), "L")
+	r.field(margin+98, y, 50, "BAIRRO / DISTRITO", address.XBairro, "L")
+	r.field(margin+148, y, 20, "CEP", formatCEP(address.CEP), "C")
+	r.field(margin+168, y, 30, "DATA DA SAÍDA/ENTRADA", formatDate(inf.Ide.DhSaiEnt), "C")
+	y += fieldHeight
+
+	r.field(margin, y, 78, "MUNICÍPIO", address.XMun, "L")
+	r.field(margin+78, y, 40, "FONE / FAX", address.Fone, "L")
+	r.field(margin+118, y, 10, "UF", address.UF, "C")
+	r.field(margin+128, y, 40, "INSCRIÇÃO ESTADUAL", dest.IE, "L")
+	r.field(margin+168, y, 30, "HORA DA SAÍDA/ENTRADA", formatTime(inf.Ide.DhSaiEnt), "C")
+	return y + fieldHeight
+}
+
+// Duplicatas exibidas no bloco de fatura; as excedentes são resumidas no último campo
+const (
+	billingColumns = 6
+	billingMaxRows = 2
+)
+
+// billing desenha o bloco de fatura/duplicatas das vendas a prazo. Sem grupo
+// de cobrança o bloco é omitido
+func (r *renderer) billing(y float64) float64 {
+	cobr := r.doc.InfNFe.Cobr
+	if cobr == nil || len(cobr.Dup) == 0 {
+		return y
+	}
+	y = r.title(y, "FATURA / DUPLICATAS")
+	w := contentWidth / billingColumns
+
+	dups := cobr.Dup
+	limit := billingColumns * billingMaxRows
+	var remaining []nfe.Dup
+	if len(dups) > limit {
+		dups, remaining = dups[:limit-1], dups[limit-1:]
+	}
+	for idx, dup := range dups {
+		row, col := idx/billingColumns, idx%billingColumns
+		value := fmt.Sprintf("%s  R$ %s", formatDay(dup.DVenc), formatDecimal(dup.VDup))
+		r.field(margin+float64(col)*w, y+float64(row)*fieldHeight, w, "Nº "+dup.NDup, value, "L")
+	}
+	cells := len(dups)
+	if len(remaining) > 0 {
+		var total domain.Money
+		for _, dup := range remaining {
+			total += parseCents(dup.VDup)
+		}
+		row, col := cells/billingColumns, cells%billingColumns
+		label := fmt.Sprintf("DEMAIS %d PARCELAS", len(remaining))
+		r.field(margin+float64(col)*w, y+float64(row)*fieldHeight, w, label, "R$ "+formatDecimal(total.String()), "L")
+		cells++
+	}
+	rows := (cells + billingColumns - 1) / billingColumns
+	return y + float64(rows)*fieldHeight
+}
+
+// taxes desenha o bloco de cálculo do imposto
+func (r *renderer) taxes(y float64) float64 {
+	y = r.title(y, "CÁLCULO DO IMPOSTO")
+	tot := r.doc.InfNFe.Total.ICMSTot
+	w := contentWidth / 6
+
+	first := []struct{ label, value string }{
+		{"BASE DE CÁLC. DO ICMS", tot.VBC},
+		{"VALOR DO ICMS", tot.VICMS},
+		{"BASE DE CÁLC. ICMS S.T.", tot.VBCST},
+		{"VALOR DO ICMS SUBST.", tot.VST},
+		{"VALOR DO PIS", tot.VPIS},
+		{"VALOR TOTAL DOS PRODUTOS", tot.VProd},
+	}
+	second := []struct{ label, value string }{
+		{"VALOR DO FRETE", tot.VFrete},
+		{"VALOR DO SEGURO", tot.VSeg},
+		{"DESCONTO", tot.VDesc},
+		{"OUTRAS DESPESAS", tot.VOutro},
+		{"VALOR DA COFINS", tot.VCOFINS},
+		{"VALOR TOTAL DA NOTA", tot.VNF},
+	}
+	for i, f := range first {
+		r.field(margin+float64(i)*w, y, w, f.label, formatDecimal(f.value), "R")
+	}
+	y += fieldHeight
+	for i, f := range second {
+		r.field(margin+float64(i)*w, y, w, f.label, formatDecimal(f.value), "R")
+	}
+	return y + fieldHeight
+}
+
+// transport desenha o bloco de transportador e volumes
+func (r *renderer) transport(y float64) float64 {
+	transp := r.doc.InfNFe.Transp
+	var carrier nfe.Transporta
+	if transp.Transporta != nil {
+		carrier = *transp.Transporta
+	}
+	var vehicle nfe.VeicTransp
+	if transp.VeicTransp != nil {
+		vehicle = *transp.VeicTransp
+	}
+	var vol nfe.Vol
+	if len(transp.Vol) > 0 {
+		vol = transp.Vol[0]
+	}
+
+	y = r.title(y, "TRANSPORTADOR / VOLUMES TRANSPORTADOS")
+	r.field(margin, y, 70, "RAZÃO SOCIAL", carrier.XNome, "L")
+	r.field(margin+70, y, 38, "FRETE POR CONTA", freightModes[transp.ModFrete], "L")
+	r.field(margin+108, y, 20, "CÓDIGO ANTT", vehicle.RNTC, "L")
+	r.field(margin+128, y, 25, "PLACA DO VEÍCULO", vehicle.Placa, "L")
+	r.field(margin+153, y, 10, "UF", vehicle.UF, "C")
+	r.field(margin+163, y, 35, "CNPJ / CPF", formatDocument(carrier.CNPJ+carrier.CPF), "L")
+	y += fieldHeight
+
+	r.field(margin, y, 93, "ENDEREÇO", carrier.XEnder, "L")
+	r.field(margin+93, y, 60, "MUNICÍPIO", carrier.XMun, "L")
+	r.field(margin+153, y, 10, "UF", carrier.UF, "C")
+	r.field(margin+163, y, 35, "INSCRIÇÃO ESTADUAL", carrier.IE, "L")
+	y += fieldHeight
+
+	r.field(margin, y, 25, "QUANTIDADE", vol.QVol, "R")
+	r.field(margin+25, y, 40, "ESPÉCIE", vol.Esp, "L")
+	r.field(margin+65, y, 33, "MARCA", "", "L")
+	r.field(margin+98, y, 30, "NUMERAÇÃO", "", "L")
+	r.field(margin+128, y, 35, "PESO BRUTO", formatDecimal(vol.PesoB), "R")
+	r.field(margin+163, y, 35, "PESO LÍQUIDO", formatDecimal(vol.PesoL), "R")
+	return y + fieldHeight
+}
+
+// freightModes descreve a modalidade do frete (modFrete)
+var freightModes = map[string]string{
+	"0": "0 - Emitente",
+	"1": "1 - Destinatário",
+	"2": "2 - Terceiros",
+	"3": "3 - Próprio Remetente",
+	"4": "4 - Próprio Destinatário",
+	"9": "9 - Sem Frete",
+}
+
+// itemTable desenha os itens que couberem até o limite e retorna os restantes
+func (r *renderer) itemTable(y, limit float64, items []nfe.Det) []nfe.Det {
+	y = r.title(y, "DADOS DOS PRODUTOS / SERVIÇOS")
+
+	r.pdf.SetFont("Helvetica", "B", 5.5)
+	x := margin
+	for _, col := range itemColumns {
+		r.pdf.SetXY(x, y)
+		r.pdf.CellFormat(col.width, 5, r.tr(col.title), "1", 0, "C", false, 0, "")
+		x += col.width
+	}
+	y += 5
+	top := y
+
+	r.pdf.SetFont("Helvetica", "", 6)
+	for len(items) > 0 {
+		values := itemValues(items[0])
+		description := r.pdf.SplitLines([]byte(r.tr(values[1])), itemColumns[1].width-1)
+		height := float64(len(description))*itemLineHeight + 1
+		if y+height > limit {
+			break
+		}
+
+		x = margin
+		for i, col := range itemColumns {
+			r.pdf.SetXY(x, y+0.5)
+			if i == 1 {
+				for _, line := range description {
+					r.pdf.SetX(x)
+					r.pdf.CellFormat(col.width, itemLineHeight, string(line), "", 2, col.align, false, 0, "")
+				}
+			} else {
+				r.pdf.CellFormat(col.width, itemLineHeight, r.fit(values[i], col.width), "", 0, col.align, false, 0, "")
+			}
+			x += col.width
+		}
+		y += height
+		r.pdf.SetDrawColor(200, 200, 200)
+		r.pdf.Line(margin, y, margin+contentWidth, y)
+		r.pdf.SetDrawColor(0, 0, 0)
+		items = items[1:]
+	}
+
+	// Moldura e divisórias das colunas até o fim da área de itens
+	x = margin
+	for _, col := range itemColumns {
+		r.pdf.Rect(x, top, col.width, limit-top, "D")
+		x += col.width
+	}
+	return items
+}
+
+// itemValues retorna o conteúdo das colunas da tabela para um item
+func itemValues(det nfe.Det) []string {
+	prod := det.Prod
+	cst, base, value, rate := "", "0.00", "0.00", "0.00"
+	switch icms := det.Imposto.ICMS; {
+	case icms.ICMS00 != nil:
+		cst = icms.ICMS00.Orig + icms.ICMS00.CST
+		base, value, rate = icms.ICMS00.VBC, icms.ICMS00.VICMS, icms.ICMS00.PICMS
+	case icms.ICMSSN102 != nil:
+		cst = icms.ICMSSN102.Orig + icms.ICMSSN102.CSOSN
+	}
+	return []string{
+		prod.CProd,
+		prod.XProd,
+		prod.NCM,
+		cst,
+		prod.CFOP,
+		prod.UCom,
+		formatDecimal(prod.QCom),
+		formatDecimal(prod.VUnCom),
+		formatDecimal(prod.VProd),
+		formatDecimal(base),
+		formatDecimal(value),
+		"0,00",
+		formatRate(rate),
+		"0,00",
+	}
+}
+
+// additional desenha os dados adicionais no rodapé da primeira folha
+func (r *renderer) additional(y float64) {
+	y = r.title(y, "DADOS ADICIONAIS")
+	height := additionalHeight - titleHeight
+	infoWidth := contentWidth * 0.65
+
+	r.field(margin, y, infoWidth, "INFORMAÇÕES COMPLEMENTARES", "", "L")
+	r.pdf.Rect(margin, y, infoWidth, height, "D")
+	r.pdf.SetFont("Helvetica", "", 6.5)
+	r.pdf.SetXY(margin+1, y+3)
+	r.pdf.MultiCell(infoWidth-2, 2.8, r.tr(r.complementaryInfo()), "", "L", false)
+
+	r.pdf.Rect(margin+infoWidth, y, contentWidth-infoWidth, height, "D")
+	r.field(margin+infoWidth, y, contentWidth-infoWidth, "RESERVADO AO FISCO", "", "L")
+}
+
+// complementaryInfo reúne as informações complementares exibidas no DANFE
+func (r *renderer) complementaryInfo() string {
+	var lines []string
+	inf := r.doc.InfNFe
+	if inf.Ide.TpAmb == fmt.Sprint(nfe.EnvironmentHomologation) {
+		lines = append(lines, "NF-e emitida em ambiente de homologação - sem valor fiscal.")
+	}
+	if tot := inf.Total.IBSCBSTot; tot != nil {
+		lines = append(lines, fmt.Sprintf("IBS: R$ %s (UF R$ %s, Mun. R$ %s); CBS: R$ %s; base R$ %s.",
+			formatDecimal(tot.GIBS.VIBS), formatDecimal(tot.GIBS.GIBSUF.VIBSUF), formatDecimal(tot.GIBS.GIBSMun.VIBSMun),
+			formatDecimal(tot.GCBS.VCBS), formatDecimal(tot.VBCIBSCBS)))
+	}
+	if inf.Emit.CRT == "1" || inf.Emit.CRT == "4" {
+		lines = append(lines, "Documento emitido por ME ou EPP optante pelo Simples Nacional. Não gera direito a crédito fiscal de IPI.")
+	}
+	if r.contingency() {
+		lines = append(lines, fmt.Sprintf("DANFE em contingência - impresso em decorrência de problemas técnicos. Entrada em contingência: %s %s. Justificativa: %s",
+			formatDate(inf.Ide.DhCont), formatTime(inf.Ide.DhCont), inf.Ide.XJust))
+	}
+	if inf.InfAdic != nil && inf.InfAdic.InfCpl != "" {
+		lines = append(lines, inf.InfAdic.InfCpl)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// watermark marca o documento sem valor fiscal
+func (r *renderer) watermark() {
+	text := ""
+	switch {
+	case (r.auth == nil || r.auth.Status != domain.AuthorizationAuthorized) && !r.contingency():
+		text = "SEM VALOR FISCAL - NF-e NÃO AUTORIZADA"
+	case r.doc.InfNFe.Ide.TpAmb == fmt.Sprint(nfe.EnvironmentHomologation):
+		text = "SEM VALOR FISCAL - HOMOLOGAÇÃO"
+	default:
+		return
+	}
+	r.pdf.SetFont("Helvetica", "B", 30)
+	r.pdf.SetTextColor(215, 215, 215)
+	r.pdf.TransformBegin()
+	r.pdf.TransformRotate(45, pageWidth/2, pageHeight/2)
+	width := r.pdf.GetStringWidth(r.tr(text))
+	r.pdf.Text(pageWidth/2-width/2, pageHeight/2, r.tr(text))
+	r.pdf.TransformEnd()
+	r.pdf.SetTextColor(0, 0, 0)
+}
+
+// contingency indica se a nota foi emitida em contingência (tpEmis diferente de 1)
+func (r *renderer) contingency() bool {
+	return r.doc.InfNFe.Ide.TpEmis != strconv.Itoa(int(domain.EmissionNormal))
+}
+
+// protocol formata o protocolo de autorização com a data de recebimento. Em
+// contingência, antes da autorização, o DANFE identifica a forma de emissão
+func (r *renderer) protocol() string {
+	if r.auth == nil || r.auth.Protocol == "" || r.auth.Status != domain.AuthorizationAuthorized {
+		if r.contingency() {
+			return "DANFE EM CONTINGÊNCIA"
+		}
+		return ""
+	}
+	if r.auth.ReceivedAt == nil {
+		return r.auth.Protocol
+	}
+	return r.auth.Protocol + " - " + r.auth.ReceivedAt.Local().Format("02/01/2006 15:04:05")
+}
+
+// barcode desenha a chave de acesso em Code-128 (conjunto C para chaves
+// numéricas; chaves com CNPJ alfanumérico alternam para o conjunto B)
+func (r *renderer) barcode(x, y, w, h float64, content string) {
+	code, err := code128.Encode(content)
+	if err != nil {
+		r.pdf.SetError(fmt.Errorf("erro ao gerar código de barras: %w", err))
+		return
+	}
+	modules := code.Bounds().Dx()
+	module := w / float64(modules)
+	r.pdf.SetFillColor(0, 0, 0)
+	for i := 0; i < modules; i++ {
+		if c, _, _, _ := code.At(i, 0).RGBA(); c == 0 {
+			r.pdf.Rect(x+float64(i)*module, y, module, h, "F")
+		}
+	}
+}
+
+// title desenha o título de um bloco e retorna a posição abaixo dele
+func (r *renderer) title(y float64, text string) float64 {
+	r.pdf.SetFont("Helvetica", "B", 6.5)
+	r.pdf.SetXY(margin, y+0.5)
+	r.pdf.CellFormat(contentWidth, titleHeight-0.5, r.tr(text), "", 0, "L", false, 0, "")
+	return y + titleHeight
+}
+
+// field desenha um campo com moldura, rótulo e valor
+func (r *renderer) field(x, y, w float64, label, value, align string) {
+	r.pdf.Rect(x, y, w, fieldHeight, "D")
+	r.pdf.SetFont("Helvetica", "", 5.5)
+	r.pdf.SetXY(x+0.5, y+0.3)
+	r.pdf.CellFormat(w-1, 2.5, r.tr(label), "", 0, "L", false, 0, "")
+	r.pdf.SetFont("Helvetica", "B", 8)
+	r.pdf.SetXY(x+0.5, y+3)
+	r.pdf.CellFormat(w-1, 3.6, r.fit(value, w-1), "", 0, align, false, 0, "")
+}
+
+// fit converte o texto para a codificação da fonte e o trunca na largura informada
+func (r *renderer) fit(text string, width float64) string {
+	text = r.tr(text)
+	for len(text) > 0 && r.pdf.GetStringWidth(text) > width-0.5 {
+		text = text[:len(text)-1]
+	}
+	return text
+}
+
+func recipientName(doc *nfe.NFe) string {
+	if doc.InfNFe.Dest == nil {
+		return ""
+	}
+	return doc.InfNFe.Dest.XNome
+}
+
+// formatDecimal converte valores do XML ("1234.50") para o formato brasileiro ("1.234,50")
+func formatDecimal(value string) string {
+	if value == "" {
+		return ""
+	}
+	integer, fraction, hasFraction := strings.Cut(value, ".")
+	var b strings.Builder
+	for i, digit := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(digit)
+	}
+	if hasFraction {
+		b.WriteByte(',')
+		b.WriteString(fraction)
+	}
+	return b.String()
+}
+
+// formatRate exibe alíquotas com duas casas decimais
+func formatRate(value string) string {
+	integer, fraction, _ := strings.Cut(value, ".")
+	fraction = (fraction + "00")[:2]
+	return integer + "," + fraction
+}
+
+// formatNumber exibe o número da nota com nove dígitos agrupados (000.000.001)
+func formatNumber(number string) string {
+	padded := number
+	if len(padded) < 9 {
+		padded = strings.Repeat("0", 9-len(padded)) + padded
+	}
+	return padded[0:3] + "." + padded[3:6] + "." + padded[6:9]
+}
+
+// formatAccessKey agrupa a chave de acesso de quatro em quatro posições
+func formatAccessKey(key string) string {
+	var groups []string
+	for len(key) > 4 {
+		groups = append(groups, key[:4])
+		key = key[4:]
+	}
+	return strings.Join(append(groups, key), " ")
+}
+
+// formatDocument pontua CNPJ e CPF
+func formatDocument(document string) string {
+	switch len(document) {
+	case 14:
+		return document[0:2] + "." + document[2:5] + "." + document[5:8] + "/" + document[8:12] + "-" + document[12:]
+	case 11:
+		return document[0:3] + "." + document[3:6] + "." + document[6:9] + "-" + document[9:]
+	}
+	return document
+}
+
+func formatCEP(cep string) string {
+	if len(cep) != 8 {
+		return cep
+	}
+	return cep[:5] + "-" + cep[5:]
+}
+
+func formatDate(value string) string {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.Format("02/01/2006")
+	}
+	return ""
+}
+
+// formatDay formata uma data AAAA-MM-DD no padrão brasileiro
+func formatDay(value string) string {
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t.Format("02/01/2006")
+	}
+	return value
+}
+
+func formatTime(value string) string {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.Format("15:04:05")
+	}
+	return ""
+}
@@ -0,0 +1,361 @@
+package danfe
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/nfe"
+)
+
+// testDocument monta o documento NF-e de uma nota fechada com a quantidade de
+// itens informada. change, quando informado, ajusta a nota antes do fechamento
+func testDocument(t *testing.T, items int, config nfe.Config, change func(*domain.Invoice, *domain.Issuer)) *nfe.NFe {
+	t.Helper()
+	address := domain.Address{
+		Street:   "Avenida Paulista",
+		Number:   "1000",
+		District: "Bela Vista",
+		CityCode: "3550308",
+		City:     "São Paulo",
+		UF:       "SP",
+		CEP:      "01310100",
+	}
+	issuer := &domain.Issuer{
+		ID:                "issuer-1",
+		Name:              "EMPRESA TESTE LTDA",
+		CNPJ:              "11222333000181",
+		StateRegistration: "110042490114",
+		CRT:               domain.TaxRegimeNormal,
+		Series:            1,
+		Address:           address,
+	}
+	invoice := &domain.Invoice{
+		ID:         "invoice-1",
+		Number:     1,
+		Model:      domain.ModelNFe,
+		Series:     1,
+		Status:     domain.StatusOpen,
+		IssuerID:   issuer.ID,
+		CustomerID: "customer-1",
+		Customer: &domain.Customer{
+			ID:           "customer-1",
+			Name:         "CLIENTE TESTE SA",
+			Document:     "11444777000161",
+			DocumentType: domain.DocumentCNPJ,
+			TaxRegime:    domain.TaxRegimeNormal,
+			Address:      address,
+		},
+		CreatedAt: time.Now(),
+	}
+	for idx := range items {
+		invoice.Items = append(invoice.Items, domain.InvoiceItem{
+			Type:        domain.ItemGoods,
+			ProductID:   fmt.Sprintf("product-%d", idx+1),
+			ProductCode: fmt.Sprintf("P%03d", idx+1),
+			Description: "Parafuso sextavado",
+			Quantity:    10,
+			NCM:         "73181500",
+			Unit:        "UN",
+			UnitPrice:   1250,
+		})
+	}
+	if change != nil {
+		change(invoice, issuer)
+	}
+	invoice.ApplyTaxes(domain.DefaultTaxConfig(), invoice.CreatedAt, issuer.CRT)
+	if err := invoice.Close(issuer); err != nil {
+		t.Fatalf("erro ao fechar nota: %v", err)
+	}
+	doc, err := nfe.Build(invoice, config)
+	if err != nil {
+		t.Fatalf("Build() erro inesperado: %v", err)
+	}
+	return doc
+}
+
+// newTestRenderer prepara o renderizador do DANFE sem gerar o documento
+func newTestRenderer(doc *nfe.NFe, auth *domain.Authorization) *renderer {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	return &renderer{pdf: pdf, tr: pdf.UnicodeTranslatorFromDescriptor(""), doc: doc, auth: auth}
+}
+
+var pageCount = regexp.MustCompile(`/Type /Pages\s*/Kids \[[^\]]*\]\s*/Count (\d+)`)
+
+// pages retorna a quantidade de folhas do PDF gerado
+func pages(t *testing.T, pdf []byte) int {
+	t.Helper()
+	match := pageCount.FindSubmatch(pdf)
+	if match == nil {
+		t.Fatalf("PDF sem árvore de páginas")
+	}
+	count, _ := strconv.Atoi(string(match[1]))
+	return count
+}
+
+func TestRender(t *testing.T) {
+	production := nfe.Config{Environment: nfe.EnvironmentProduction}
+
+	tests := []struct {
+		name  string
+		items int
+		pages int // Folhas esperadas; zero para mais de uma
+	}{
+		{name: "um item", items: 1, pages: 1},
+		{name: "itens na primeira folha", items: 20, pages: 1},
+		{name: "itens em folhas adicionais", items: 150},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pdf, err := Render(testDocument(t, tt.items, production, nil), nil)
+			if err != nil {
+				t.Fatalf("Render() erro inesperado: %v", err)
+			}
+			if !bytes.HasPrefix(pdf, []byte("%PDF-")) {
+				t.Fatalf("Render() não gerou um PDF")
+			}
+			switch count := pages(t, pdf); {
+			case tt.pages > 0 && count != tt.pages:
+				t.Errorf("Render() gerou %d folhas, esperado %d", count, tt.pages)
+			case tt.pages == 0 && count < 2:
+				t.Errorf("Render() gerou %d folha, esperado mais de uma", count)
+			}
+		})
+	}
+}
+
+func TestItemTablePagination(t *testing.T) {
+	doc := testDocument(t, 5, nfe.Config{}, func(invoice *domain.Invoice, _ *domain.Issuer) {
+		invoice.Items[1].Description = strings.Repeat("Descrição longa do produto que ocupa mais de uma linha ", 3)
+	})
+	r := newTestRenderer(doc, nil)
+	r.pdf.AddPage()
+
+	// Título (4 mm) e cabeçalho (5 mm), seguidos de itens de uma linha (3,8 mm)
+	// e do segundo item com descrição em várias linhas, medida na fonte da tabela
+	r.pdf.SetFont("Helvetica", "", 6)
+	lines := len(r.pdf.SplitLines([]byte(r.tr(doc.InfNFe.Det[1].Prod.XProd)), itemColumns[1].width-1))
+	if lines < 2 {
+		t.Fatalf("descrição do segundo item ocupa %d linha", lines)
+	}
+	second := float64(lines)*itemLineHeight + 1
+
+	tests := []struct {
+		name      string
+		limit     float64
+		remaining int
+	}{
+		{name: "sem espaço para itens", limit: 9 + 3.7, remaining: 5},
+		{name: "somente o primeiro item", limit: 9 + 3.8 + second - 0.1, remaining: 4},
+		{name: "item em várias linhas", limit: 9 + 3.8 + second, remaining: 3},
+		{name: "todos os itens", limit: 9 + 3.8*4 + second + 0.01, remaining: 0},
+	}
+	for _, tt := range tests {
+		remaining := r.itemTable(0, tt.limit, doc.InfNFe.Det)
+		if len(remaining) != tt.remaining {
+			t.Errorf("%s: itemTable() deixou %d itens, esperado %d", tt.name, len(remaining), tt.remaining)
+		}
+		if tt.remaining > 0 && remaining[0].NItem != doc.InfNFe.Det[5-tt.remaining].NItem {
+			t.Errorf("%s: itemTable() continua no item %d", tt.name, remaining[0].NItem)
+		}
+	}
+}
+
+func TestComplementaryInfo(t *testing.T) {
+	started := time.Date(2025, 3, 10, 9, 30, 0, 0, time.FixedZone("BRT", -3*60*60))
+	contingency := func(invoice *domain.Invoice, _ *domain.Issuer) {
+		invoice.Contingency = &domain.Contingency{
+			EmissionType:  domain.EmissionFSDA,
+			Justification: "Falha de comunicação com a SEFAZ autorizadora",
+			StartedAt:     started,
+		}
+	}
+
+	tests := []struct {
+		name     string
+		config   nfe.Config
+		change   func(*domain.Invoice, *domain.Issuer)
+		expected []string // Trechos esperados
+		absent   []string // Trechos que não devem constar
+	}{
+		{
+			// Base do IBS/CBS: R$ 125,00 menos ICMS (R$ 22,50), PIS (R$ 2,06) e COFINS (R$ 9,50)
+			name:     "produção",
+			config:   nfe.Config{Environment: nfe.EnvironmentProduction},
+			expected: []string{"IBS: R$ 0,09 (UF R$ 0,09, Mun. R$ 0,00); CBS: R$ 0,82; base R$ 90,94."},
+			absent:   []string{"homologação", "Simples Nacional", "contingência"},
+		},
+		{
+			name:     "homologação",
+			expected: []string{"NF-e emitida em ambiente de homologação - sem valor fiscal."},
+		},
+		{
+			name:   "Simples Nacional",
+			config: nfe.Config{Environment: nfe.EnvironmentProduction},
+			change: func(_ *domain.Invoice, issuer *domain.Issuer) { issuer.CRT = domain.TaxRegimeSimples },
+			expected: []string{
+				"Documento emitido por ME ou EPP optante pelo Simples Nacional. Não gera direito a crédito fiscal de IPI.",
+			},
+		},
+		{
+			name:   "contingência",
+			config: nfe.Config{Environment: nfe.EnvironmentProduction},
+			change: contingency,
+			expected: []string{
+				"DANFE em contingência - impresso em decorrência de problemas técnicos. Entrada em contingência: 10/03/2025 09:30:00. " +
+					"Justificativa: Falha de comunicação com a SEFAZ autorizadora",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := newTestRenderer(testDocument(t, 1, tt.config, tt.change), nil).complementaryInfo()
+			for _, text := range tt.expected {
+				if !strings.Contains(info, text) {
+					t.Errorf("complementaryInfo() = %q, esperado conter %q", info, text)
+				}
+			}
+			for _, text := range tt.absent {
+				if strings.Contains(info, text) {
+					t.Errorf("complementaryInfo() = %q, não deveria conter %q", info, text)
+				}
+			}
+		})
+	}
+}
+
+func TestProtocol(t *testing.T) {
+	received := time.Date(2025, 3, 10, 10, 0, 5, 0, time.Local)
+	normal := testDocument(t, 1, nfe.Config{}, nil)
+	contingency := testDocument(t, 1, nfe.Config{}, func(invoice *domain.Invoice, _ *domain.Issuer) {
+		invoice.Contingency = &domain.Contingency{EmissionType: domain.EmissionFSDA, Justification: "Falha de comunicação com a SEFAZ", StartedAt: time.Now()}
+	})
+
+	tests := []struct {
+		name     string
+		doc      *nfe.NFe
+		auth     *domain.Authorization
+		expected string
+	}{
+		{name: "sem autorização", doc: normal},
+		{name: "rejeitada", doc: normal, auth: &domain.Authorization{Status: domain.AuthorizationRejected, Protocol: "135250000000001"}},
+		{name: "autorizada sem data", doc: normal, auth: &domain.Authorization{Status: domain.AuthorizationAuthorized, Protocol: "135250000000001"}, expected: "135250000000001"},
+		{
+			name:     "autorizada",
+			doc:      normal,
+			auth:     &domain.Authorization{Status: domain.AuthorizationAuthorized, Protocol: "135250000000001", ReceivedAt: &received},
+			expected: "135250000000001 - 10/03/2025 10:00:05",
+		},
+		{name: "contingência pendente", doc: contingency, expected: "DANFE EM CONTINGÊNCIA"},
+		{
+			name:     "contingência autorizada",
+			doc:      contingency,
+			auth:     &domain.Authorization{Status: domain.AuthorizationAuthorized, Protocol: "135250000000002"},
+			expected: "135250000000002",
+		},
+	}
+	for _, tt := range tests {
+		if got := newTestRenderer(tt.doc, tt.auth).protocol(); got != tt.expected {
+			t.Errorf("%s: protocol() = %q, esperado %q", tt.name, got, tt.expected)
+		}
+	}
+}
+
+func TestRenderConsumer(t *testing.T) {
+	doc := testDocument(t, 1, nfe.Config{}, nil)
+	if _, err := RenderConsumer(doc, nil); err == nil {
+		t.Fatalf("RenderConsumer() sem erro em documento sem QR Code")
+	}
+
+	// A altura da bobina acompanha a quantidade de itens
+	// height retorna a altura da bobina, em milímetros
+	height := func(items int) float64 {
+		doc := testDocument(t, items, nfe.Config{}, nil)
+		doc.InfNFeSupl = &nfe.InfNFeSupl{
+			QRCode:   "https://www.homologacao.nfce.fazenda.sp.gov.br/qrcode?p=" + doc.AccessKey() + "|2|2|1|0123456789ABCDEF0123456789ABCDEF01234567",
+			URLChave: "https://www.homologacao.nfce.fazenda.sp.gov.br/consulta",
+		}
+		pdf, err := RenderConsumer(doc, nil)
+		if err != nil {
+			t.Fatalf("RenderConsumer() erro inesperado: %v", err)
+		}
+		if pages(t, pdf) != 1 {
+			t.Fatalf("RenderConsumer() gerou %d folhas, esperado 1", pages(t, pdf))
+		}
+		match := regexp.MustCompile(`/MediaBox \[0 0 ([\d.]+) ([\d.]+)\]`).FindSubmatch(pdf)
+		if match == nil {
+			t.Fatalf("PDF sem dimensões da página")
+		}
+		width, _ := strconv.ParseFloat(string(match[1]), 64)
+		if mm := width * 25.4 / 72; mm < receiptWidth-0.1 || mm > receiptWidth+0.1 {
+			t.Errorf("largura da bobina = %.1f mm, esperado %.0f mm", mm, receiptWidth)
+		}
+		value, _ := strconv.ParseFloat(string(match[2]), 64)
+		return value * 25.4 / 72
+	}
+	one, three := height(1), height(3)
+	if three <= one {
+		t.Errorf("altura com 3 itens = %.2f, com 1 item = %.2f; esperado maior", three, one)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   func(string) string
+		value    string
+		expected string
+	}{
+		{name: "decimal", format: formatDecimal, value: "1234567.50", expected: "1.234.567,50"},
+		{name: "decimal sem milhar", format: formatDecimal, value: "123.45", expected: "123,45"},
+		{name: "decimal inteiro", format: formatDecimal, value: "1000", expected: "1.000"},
+		{name: "decimal vazio", format: formatDecimal, value: "", expected: ""},
+		{name: "alíquota", format: formatRate, value: "18.0000", expected: "18,00"},
+		{name: "alíquota fracionária", format: formatRate, value: "1.65", expected: "1,65"},
+		{name: "alíquota inteira", format: formatRate, value: "7", expected: "7,00"},
+		{name: "número", format: formatNumber, value: "1", expected: "000.000.001"},
+		{name: "número completo", format: formatNumber, value: "123456789", expected: "123.456.789"},
+		{name: "chave", format: formatAccessKey, value: "35250311222333000181550010000000011123456780", expected: "3525 0311 2223 3300 0181 5500 1000 0000 0111 2345 6780"},
+		{name: "CNPJ", format: formatDocument, value: "11222333000181", expected: "11.222.333/0001-81"},
+		{name: "CPF", format: formatDocument, value: "52998224725", expected: "529.982.247-25"},
+		{name: "documento desconhecido", format: formatDocument, value: "123", expected: "123"},
+		{name: "CEP", format: formatCEP, value: "01310100", expected: "01310-100"},
+		{name: "data", format: formatDate, value: "2025-03-10T09:30:00-03:00", expected: "10/03/2025"},
+		{name: "data inválida", format: formatDate, value: "10/03/2025", expected: ""},
+		{name: "hora", format: formatTime, value: "2025-03-10T09:30:00-03:00", expected: "09:30:00"},
+		{name: "dia", format: formatDay, value: "2025-04-09", expected: "09/04/2025"},
+		{name: "quantidade inteira", format: formatQuantity, value: "2.0000", expected: "2"},
+		{name: "quantidade fracionária", format: formatQuantity, value: "1.5000", expected: "1,5"},
+	}
+	for _, tt := range tests {
+		if got := tt.format(tt.value); got != tt.expected {
+			t.Errorf("%s: format(%q) = %q, esperado %q", tt.name, tt.value, got, tt.expected)
+		}
+	}
+}
+
+func TestParseCents(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected domain.Money
+	}{
+		{value: "1234.50", expected: 123450},
+		{value: "0.05", expected: 5},
+		{value: "10.5", expected: 1050},
+		{value: "7", expected: 700},
+		{value: "", expected: 0},
+		{value: "abc", expected: 0},
+	}
+	for _, tt := range tests {
+		if got := parseCents(tt.value); got != tt.expected {
+			t.Errorf("parseCents(%q) = %d, esperado %d", tt.value, got, tt.expected)
+		}
+	}
+}
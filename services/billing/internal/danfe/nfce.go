@@ -0,0 +1,304 @@
+package danfe
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/boombuler/barcode/qr"
+	"github.com/jung-kurt/gofpdf"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/nfe"
+)
+
+// Dimensões da bobina de 80 mm usada no DANFE NFC-e, em milímetros. A altura
+// da página acompanha o conteúdo
+const (
+	receiptWidth      = 80.0
+	receiptMargin     = 4.0
+	receiptContent    = receiptWidth - 2*receiptMargin
+	receiptLineHeight = 3.2
+	receiptQRCodeSize = 32.0 // O manual exige ao menos 25 mm
+	receiptMaxHeight  = 3000.0
+)
+
+// paymentNames descreve os meios de pagamento (tPag) exibidos no DANFE NFC-e
+var paymentNames = map[string]string{
+	"01": "Dinheiro",
+	"02": "Cheque",
+	"03": "Cartão de Crédito",
+	"04": "Cartão de Débito",
+	"05": "Crédito Loja",
+	"15": "Boleto Bancário",
+	"16": "Depósito Bancário",
+	"17": "PIX",
+	"18": "Transferência Bancária",
+	"90": "Sem pagamento",
+	"99": "Outros",
+}
+
+// consumerRenderer mantém o estado da geração do DANFE NFC-e
+type consumerRenderer struct {
+	pdf  *gofpdf.Fpdf
+	tr   func(string) string
+	doc  *nfe.NFe
+	auth *domain.Authorization
+}
+
+// RenderConsumer gera o DANFE NFC-e (modelo 65) em bobina de 80 mm, com as
+// divisões do Manual de Especificações Técnicas do DANFE NFC-e e o QR Code.
+// O documento é desenhado uma vez para medir a altura e outra na página final
+func RenderConsumer(doc *nfe.NFe, auth *domain.Authorization) ([]byte, error) {
+	if doc.InfNFeSupl == nil {
+		return nil, fmt.Errorf("erro ao gerar DANFE NFC-e: documento sem QR Code")
+	}
+
+	measure := newConsumerRenderer(doc, auth, receiptMaxHeight)
+	height := measure.render() + receiptMargin
+
+	r := newConsumerRenderer(doc, auth, height)
+	r.render()
+
+	var buf bytes.Buffer
+	if err := r.pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("erro ao gerar DANFE NFC-e: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func newConsumerRenderer(doc *nfe.NFe, auth *domain.Authorization, height float64) *consumerRenderer {
+	pdf := gofpdf.NewCustom(&gofpdf.InitType{
+		OrientationStr: "P",
+		UnitStr:        "mm",
+		Size:           gofpdf.SizeType{Wd: receiptWidth, Ht: height},
+	})
+	pdf.SetMargins(receiptMargin, receiptMargin, receiptMargin)
+	pdf.SetAutoPageBreak(false, 0)
+	pdf.SetTitle("DANFE NFC-e "+doc.AccessKey(), true)
+	pdf.SetCreator("korp-billing", true)
+	pdf.AddPage()
+
+	return &consumerRenderer{
+		pdf:  pdf,
+		tr:   pdf.UnicodeTranslatorFromDescriptor(""),
+		doc:  doc,
+		auth: auth,
+	}
+}
+
+// render desenha as divisões do DANFE NFC-e e retorna a altura ocupada
+func (r *consumerRenderer) render() float64 {
+	y := r.issuer(receiptMargin)
+	y = r.items(y)
+	y = r.totals(y)
+	y = r.query(y)
+	y = r.consumer(y)
+	y = r.identification(y)
+	y = r.qrCode(y)
+	return r.taxInfo(y)
+}
+
+// issuer desenha a divisão I: emitente e título do documento
+func (r *consumerRenderer) issuer(y float64) float64 {
+	emit := r.doc.InfNFe.Emit
+	address := emit.EnderEmit
+	document := emit.CNPJ
+	if document == "" {
+		document = emit.CPF
+	}
+
+	y = r.text(y, emit.XNome, "B", 8, "C")
+	y = r.text(y, fmt.Sprintf("CNPJ: %s  IE: %s", formatDocument(document), emit.IE), "", 6.5, "C")
+	y = r.text(y, strings.TrimSpace(fmt.Sprintf("%s, %s %s, %s, %s - %s", address.XLgr, address.Nro, address.XCpl, address.XBairro, address.XMun, address.UF)), "", 6.5, "C")
+	y = r.separator(y)
+	y = r.text(y, "DANFE NFC-e - Documento Auxiliar da Nota Fiscal de Consumidor Eletrônica", "B", 7, "C")
+	if r.doc.InfNFe.Ide.TpAmb == fmt.Sprint(nfe.EnvironmentHomologation) {
+		y = r.text(y, "EMITIDA EM AMBIENTE DE HOMOLOGAÇÃO - SEM VALOR FISCAL", "B", 7, "C")
+	}
+	return r.separator(y)
+}
+
+// items desenha a divisão II: detalhe dos produtos
+func (r *consumerRenderer) items(y float64) float64 {
+	r.pdf.SetFont("Helvetica", "B", 6)
+	r.row(y, "CÓDIGO  DESCRIÇÃO", "QTDE UN x VL UNIT", "VL TOTAL")
+	y += receiptLineHeight
+
+	for _, det := range r.doc.InfNFe.Det {
+		prod := det.Prod
+		y = r.text(y, prod.CProd+"  "+prod.XProd, "", 6, "L")
+		r.pdf.SetFont("Helvetica", "", 6)
+		r.row(y, "", fmt.Sprintf("%s %s x %s", formatQuantity(prod.QCom), prod.UCom, formatDecimal(prod.VUnCom)), formatDecimal(prod.VProd))
+		y += receiptLineHeight
+	}
+	return r.separator(y)
+}
+
+// totals desenha a divisão III: totais e formas de pagamento
+func (r *consumerRenderer) totals(y float64) float64 {
+	inf := r.doc.InfNFe
+	tot := inf.Total.ICMSTot
+
+	r.pdf.SetFont("Helvetica", "", 7)
+	r.row(y, "Qtde. total de itens", "", strconv.Itoa(len(inf.Det)))
+	y += receiptLineHeight
+	r.row(y, "Valor total R$", "", formatDecimal(tot.VProd))
+	y += receiptLineHeight
+	if parseCents(tot.VDesc) > 0 {
+		r.row(y, "Descontos R$", "", formatDecimal(tot.VDesc))
+		y += receiptLineHeight
+	}
+	// Frete, seguro e outras despesas aparecem somados como acréscimos
+	if extras := parseCents(tot.VFrete) + parseCents(tot.VSeg) + parseCents(tot.VOutro); extras > 0 {
+		r.row(y, "Acréscimos R$", "", formatDecimal(extras.String()))
+		y += receiptLineHeight
+	}
+	r.pdf.SetFont("Helvetica", "B", 7)
+	r.row(y, "Valor a pagar R$", "", formatDecimal(tot.VNF))
+	y += receiptLineHeight
+
+	r.pdf.SetFont("Helvetica", "", 7)
+	r.row(y, "FORMA PAGAMENTO", "", "VALOR PAGO R$")
+	y += receiptLineHeight
+	for _, payment := range inf.Pag.DetPag {
+		name, ok := paymentNames[payment.TPag]
+		if payment.XPag != "" {
+			name = payment.XPag
+		} else if !ok {
+			name = payment.TPag
+		}
+		r.row(y, name, "", formatDecimal(payment.VPag))
+		y += receiptLineHeight
+	}
+	return r.separator(y)
+}
+
+// query desenha a divisão IV: consulta pela chave de acesso
+func (r *consumerRenderer) query(y float64) float64 {
+	y = r.text(y, "Consulte pela Chave de Acesso em", "B", 6.5, "C")
+	y = r.text(y, r.doc.InfNFeSupl.URLChave, "", 6.5, "C")
+	y = r.text(y, formatAccessKey(r.doc.AccessKey()), "", 6.5, "C")
+	return r.separator(y)
+}
+
+// consumer desenha a divisão V: identificação do consumidor
+func (r *consumerRenderer) consumer(y float64) float64 {
+	dest := r.doc.InfNFe.Dest
+	if dest == nil {
+		y = r.text(y, "CONSUMIDOR NÃO IDENTIFICADO", "B", 6.5, "C")
+		return r.separator(y)
+	}
+
+	document := dest.CNPJ
+	label := "CNPJ"
+	if document == "" {
+		document, label = dest.CPF, "CPF"
+	}
+	y = r.text(y, fmt.Sprintf("CONSUMIDOR - %s %s", label, formatDocument(document)), "B", 6.5, "C")
+	if dest.XNome != "" {
+		y = r.text(y, dest.XNome, "", 6.5, "C")
+	}
+	return r.separator(y)
+}
+
+// identification desenha a divisão VI: número, série, emissão e protocolo
+func (r *consumerRenderer) identification(y float64) float64 {
+	ide := r.doc.InfNFe.Ide
+	y = r.text(y, fmt.Sprintf("NFC-e nº %s  Série %s  %s %s", formatNumber(ide.NNF), ide.Serie, formatDate(ide.DhEmi), formatTime(ide.DhEmi)), "B", 6.5, "C")
+
+	if r.auth != nil && r.auth.Status == domain.AuthorizationAuthorized && r.auth.Protocol != "" {
+		y = r.text(y, "Protocolo de autorização: "+r.auth.Protocol, "", 6.5, "C")
+		if r.auth.ReceivedAt != nil {
+			y = r.text(y, "Data de autorização: "+r.auth.ReceivedAt.Local().Format("02/01/2006 15:04:05"), "", 6.5, "C")
+		}
+	} else {
+		y = r.text(y, "SEM VALOR FISCAL - NFC-e NÃO AUTORIZADA", "B", 6.5, "C")
+	}
+	return y + 1
+}
+
+// qrCode desenha a divisão VII: QR Code de consulta
+func (r *consumerRenderer) qrCode(y float64) float64 {
+	code, err := qr.Encode(r.doc.InfNFeSupl.QRCode, qr.M, qr.Auto)
+	if err != nil {
+		r.pdf.SetError(fmt.Errorf("erro ao gerar QR Code: %w", err))
+		return y
+	}
+	modules := code.Bounds().Dx()
+	module := receiptQRCodeSize / float64(modules)
+	x := (receiptWidth - receiptQRCodeSize) / 2
+	r.pdf.SetFillColor(0, 0, 0)
+	for row := 0; row < modules; row++ {
+		for col := 0; col < modules; col++ {
+			if c, _, _, _ := code.At(col, row).RGBA(); c == 0 {
+				r.pdf.Rect(x+float64(col)*module, y+float64(row)*module, module, module, "F")
+			}
+		}
+	}
+	return r.separator(y + receiptQRCodeSize + 1)
+}
+
+// taxInfo desenha a divisão VIII: tributos incidentes (Lei 12.741/2012)
+func (r *consumerRenderer) taxInfo(y float64) float64 {
+	tot := r.doc.InfNFe.Total
+	taxes := parseCents(tot.ICMSTot.VICMS) + parseCents(tot.ICMSTot.VPIS) + parseCents(tot.ICMSTot.VCOFINS)
+	if ibscbs := tot.IBSCBSTot; ibscbs != nil {
+		taxes += parseCents(ibscbs.GIBS.VIBS) + parseCents(ibscbs.GCBS.VCBS)
+	}
+	return r.text(y, "Tributos totais incidentes (Lei Federal 12.741/2012): R$ "+formatDecimal(taxes.String()), "", 6, "C")
+}
+
+// text escreve um parágrafo com quebra de linha na largura da bobina e
+// retorna a posição abaixo dele
+func (r *consumerRenderer) text(y float64, text, style string, size float64, align string) float64 {
+	r.pdf.SetFont("Helvetica", style, size)
+	lines := r.pdf.SplitLines([]byte(r.tr(text)), receiptContent)
+	for _, line := range lines {
+		r.pdf.SetXY(receiptMargin, y)
+		r.pdf.CellFormat(receiptContent, receiptLineHeight, string(line), "", 0, align, false, 0, "")
+		y += receiptLineHeight
+	}
+	return y
+}
+
+// row escreve uma linha com texto à esquerda, ao centro e à direita
+func (r *consumerRenderer) row(y float64, left, center, right string) {
+	r.pdf.SetXY(receiptMargin, y)
+	r.pdf.CellFormat(receiptContent, receiptLineHeight, r.tr(left), "", 0, "L", false, 0, "")
+	r.pdf.SetXY(receiptMargin, y)
+	r.pdf.CellFormat(receiptContent, receiptLineHeight, r.tr(center), "", 0, "C", false, 0, "")
+	r.pdf.SetXY(receiptMargin, y)
+	r.pdf.CellFormat(receiptContent, receiptLineHeight, r.tr(right), "", 0, "R", false, 0, "")
+}
+
+// separator traça uma linha tracejada entre as divisões
+func (r *consumerRenderer) separator(y float64) float64 {
+	y += 1
+	r.pdf.SetDashPattern([]float64{0.8, 0.8}, 0)
+	r.pdf.Line(receiptMargin, y, receiptWidth-receiptMargin, y)
+	r.pdf.SetDashPattern([]float64{}, 0)
+	return y + 1.5
+}
+
+// formatQuantity remove as casas decimais zeradas da quantidade ("2.0000" -> "2")
+func formatQuantity(value string) string {
+	integer, fraction, _ := strings.Cut(value, ".")
+	fraction = strings.TrimRight(fraction, "0")
+	if fraction == "" {
+		return integer
+	}
+	return integer + "," + fraction
+}
+
+// parseCents converte valores do XML ("1234.50") em centavos
+func parseCents(value string) domain.Money {
+	integer, fraction, _ := strings.Cut(value, ".")
+	fraction = (fraction + "00")[:2]
+	cents, err := strconv.ParseInt(integer+fraction, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return domain.Money(cents)
+}
@@ -0,0 +1,194 @@
+package domain
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AccessKeyLength é o tamanho da chave de acesso da NF-e
+const AccessKeyLength = 44
+
+// Modelos de documento fiscal eletrônico
+const (
+	ModelNFe  = "55"
+	ModelNFCe = "65"
+)
+
+// EmissionType representa a forma de emissão da nota (tpEmis)
+type EmissionType int
+
+const (
+	EmissionNormal EmissionType = 1 // Emissão normal
+	EmissionFSIA   EmissionType = 2 // Contingência FS-IA (formulário de segurança, impressor autônomo)
+	EmissionFSDA   EmissionType = 5 // Contingência FS-DA (formulário de segurança para DANFE)
+)
+
+// IsValid verifica se a forma de emissão existe no leiaute (1 a 7 e 9)
+func (t EmissionType) IsValid() bool {
+	return (t >= 1 && t <= 7) || t == 9
+}
+
+// Erros da chave de acesso
+var (
+	ErrInvalidAccessKey     = errors.New("chave de acesso inválida")
+	ErrAccessKeyCheckDigit  = errors.New("dígito verificador da chave de acesso não confere")
+	ErrAccessKeyNotAssigned = errors.New("nota fiscal sem chave de acesso")
+)
+
+// AccessKey descreve os campos que compõem a chave de acesso:
+// cUF (2) + AAMM (4) + CNPJ (14) + modelo (2) + série (3) + número (9) +
+// tpEmis (1) + cNF (8) + cDV (1)
+type AccessKey struct {
+	UFCode       string       `json:"uf_code"`
+	YearMonth    string       `json:"year_month"` // AAMM da emissão
+	IssuerCNPJ   string       `json:"issuer_cnpj"`
+	Model        string       `json:"model"`
+	Series       int          `json:"series"`
+	Number       int          `json:"number"`
+	EmissionType EmissionType `json:"emission_type"`
+	NumericCode  string       `json:"numeric_code"` // cNF
+	CheckDigit   int          `json:"check_digit"`
+}
+
+// NewAccessKey monta a chave de uma nota emitida na data informada,
+// calculando o dígito verificador
+func NewAccessKey(uf string, issuedAt time.Time, issuerCNPJ, model string, series, number int, emissionType EmissionType, numericCode string) (AccessKey, error) {
+	ufCode, ok := UFCode(uf)
+	if !ok {
+		return AccessKey{}, ErrInvalidUF
+	}
+	key := AccessKey{
+		UFCode:       ufCode,
+		YearMonth:    issuedAt.Format("0601"),
+		IssuerCNPJ:   issuerCNPJ,
+		Model:        model,
+		Series:       series,
+		Number:       number,
+		EmissionType: emissionType,
+		NumericCode:  numericCode,
+	}
+	if len(key.IssuerCNPJ) != 14 || !isDigits(key.NumericCode, 8) || series < 0 || series > 999 || number < 1 || number > 999999999 {
+		return AccessKey{}, ErrInvalidAccessKey
+	}
+	key.CheckDigit = AccessKeyCheckDigit(key.base())
+	return key, nil
+}
+
+// base retorna as 43 posições que antecedem o dígito verificador
+func (k AccessKey) base() string {
+	return fmt.Sprintf("%s%s%s%s%03d%09d%d%s", k.UFCode, k.YearMonth, k.IssuerCNPJ, k.Model, k.Series, k.Number, k.EmissionType, k.NumericCode)
+}
+
+// String retorna a chave com 44 posições
+func (k AccessKey) String() string {
+	return k.base() + strconv.Itoa(k.CheckDigit)
+}
+
+// AccessKeyCheckDigit calcula o dígito verificador (módulo 11, pesos de 2 a 9
+// da direita para a esquerda). Letras do CNPJ alfanumérico valem o código
+// ASCII menos 48, como no cálculo dos dígitos do próprio CNPJ
+func AccessKeyCheckDigit(base string) int {
+	sum, weight := 0, 2
+	for i := len(base) - 1; i >= 0; i-- {
+		sum += int(base[i]-'0') * weight
+		weight++
+		if weight > 9 {
+			weight = 2
+		}
+	}
+	digit := 11 - sum%11
+	if digit >= 10 {
+		return 0
+	}
+	return digit
+}
+
+// NormalizeAccessKey remove espaços, pontuação e o prefixo "NFe" usado no
+// atributo Id do XML
+func NormalizeAccessKey(key string) string {
+	key = strings.TrimSpace(key)
+	if len(key) > 3 && strings.EqualFold(key[:3], "NFe") {
+		key = key[3:]
+	}
+	return NormalizeDocument(key)
+}
+
+// ParseAccessKey valida uma chave recebida externamente e retorna seus campos
+func ParseAccessKey(key string) (AccessKey, error) {
+	key = NormalizeAccessKey(key)
+	if len(key) != AccessKeyLength || !isDigits(key[:6], 6) || !isDigits(key[20:], 24) {
+		return AccessKey{}, fmt.Errorf("%w: deve ter 44 posições numéricas (CNPJ pode ser alfanumérico)", ErrInvalidAccessKey)
+	}
+
+	parsed := AccessKey{
+		UFCode:      key[0:2],
+		YearMonth:   key[2:6],
+		IssuerCNPJ:  key[6:20],
+		Model:       key[20:22],
+		NumericCode: key[35:43],
+	}
+	parsed.Series, _ = strconv.Atoi(key[22:25])
+	parsed.Number, _ = strconv.Atoi(key[25:34])
+	emissionType, _ := strconv.Atoi(key[34:35])
+	parsed.EmissionType = EmissionType(emissionType)
+	parsed.CheckDigit, _ = strconv.Atoi(key[43:])
+
+	if !isKnownUFCode(parsed.UFCode) {
+		return AccessKey{}, fmt.Errorf("%w: código de UF %s desconhecido", ErrInvalidAccessKey, parsed.UFCode)
+	}
+	if month, _ := strconv.Atoi(parsed.YearMonth[2:]); month < 1 || month > 12 {
+		return AccessKey{}, fmt.Errorf("%w: mês de emissão %s inválido", ErrInvalidAccessKey, parsed.YearMonth[2:])
+	}
+	// Emitentes pessoa física informam o CPF precedido de três zeros
+	if ValidateCNPJ(parsed.IssuerCNPJ) != nil &&
+		(!strings.HasPrefix(parsed.IssuerCNPJ, "000") || ValidateCPF(parsed.IssuerCNPJ[3:]) != nil) {
+		return AccessKey{}, fmt.Errorf("%w: CNPJ/CPF do emitente inválido", ErrInvalidAccessKey)
+	}
+	if parsed.Model != ModelNFe && parsed.Model != ModelNFCe {
+		return AccessKey{}, fmt.Errorf("%w: modelo %s não é NF-e nem NFC-e", ErrInvalidAccessKey, parsed.Model)
+	}
+	if parsed.Number == 0 {
+		return AccessKey{}, fmt.Errorf("%w: número da nota zerado", ErrInvalidAccessKey)
+	}
+	if !parsed.EmissionType.IsValid() {
+		return AccessKey{}, fmt.Errorf("%w: forma de emissão %d inválida", ErrInvalidAccessKey, parsed.EmissionType)
+	}
+	if AccessKeyCheckDigit(key[:43]) != parsed.CheckDigit {
+		return AccessKey{}, ErrAccessKeyCheckDigit
+	}
+	return parsed, nil
+}
+
+// ValidateAccessKey verifica formato, campos e dígito verificador da chave
+func ValidateAccessKey(key string) error {
+	_, err := ParseAccessKey(key)
+	return err
+}
+
+// NewNumericCode sorteia o código numérico (cNF) de 8 dígitos, que não pode
+// ser igual ao número da nota
+func NewNumericCode(number int) (string, error) {
+	for {
+		n, err := rand.Int(rand.Reader, big.NewInt(100000000))
+		if err != nil {
+			return "", fmt.Errorf("erro ao gerar código numérico: %w", err)
+		}
+		if int(n.Int64()) != number {
+			return fmt.Sprintf("%08d", n.Int64()), nil
+		}
+	}
+}
+
+func isKnownUFCode(code string) bool {
+	for _, known := range ufCodes {
+		if known == code {
+			return true
+		}
+	}
+	return false
+}
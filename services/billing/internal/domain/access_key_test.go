@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAccessKeyCheckDigit(t *testing.T) {
+	tests := []struct {
+		name     string
+		base     string
+		expected int
+	}{
+		// Exemplo do Manual de Orientação do Contribuinte: soma ponderada 644,
+		// resto 6 e dígito 11 - 6 = 5
+		{name: "exemplo do MOC", base: "5206043300991100250655012000000780026730161", expected: 5},
+		{name: "resto zero", base: strings.Repeat("0", 43), expected: 0},
+		{name: "resto um", base: strings.Repeat("0", 42) + "6", expected: 0},
+		{name: "peso 2 no último dígito", base: strings.Repeat("0", 42) + "1", expected: 9},
+		{name: "pesos reiniciam após 9", base: strings.Repeat("0", 34) + "1" + strings.Repeat("0", 8), expected: 9},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AccessKeyCheckDigit(tt.base); got != tt.expected {
+				t.Errorf("AccessKeyCheckDigit(%q) = %d, esperado %d", tt.base, got, tt.expected)
+			}
+		})
+	}
+}
+
+// withCheckDigit completa as 43 posições com o dígito verificador correto,
+// para que a chave falhe apenas no campo alterado
+func withCheckDigit(base string) string {
+	return base + strconv.Itoa(AccessKeyCheckDigit(base))
+}
+
+func TestParseAccessKey(t *testing.T) {
+	issuedAt := time.Date(2025, 3, 10, 10, 0, 0, 0, time.UTC)
+	key, err := NewAccessKey("SP", issuedAt, "11222333000181", ModelNFe, 1, 1, EmissionNormal, "12345678")
+	if err != nil {
+		t.Fatalf("NewAccessKey() erro inesperado: %v", err)
+	}
+	valid := key.String()
+	if valid != "35250311222333000181550010000000011123456787" {
+		t.Fatalf("NewAccessKey() = %s", valid)
+	}
+	base := valid[:43]
+
+	tests := []struct {
+		name   string
+		key    string
+		number int
+		err    error
+	}{
+		{name: "chave gerada", key: valid, number: 1},
+		{name: "atributo Id do XML", key: "NFe" + valid, number: 1},
+		{name: "chave formatada em blocos", key: "3525 0311 2223 3300 0181 5500 1000 0000 0111 2345 6787", number: 1},
+		{name: "exemplo do MOC com forma de emissão normal", key: withCheckDigit("5206043300991100250655012000000780126730161"), number: 780},
+		{name: "emitente pessoa física", key: withCheckDigit("3525030005299822472555001000000001112345678"), number: 1},
+		{name: "emitente com CNPJ alfanumérico", key: withCheckDigit("35250312ABC34501DE3555001000000001112345678"), number: 1},
+		{name: "dígito verificador alterado", key: base + "3", err: ErrAccessKeyCheckDigit},
+		{name: "número alterado", key: strings.Replace(valid, "000000001", "000000002", 1), err: ErrAccessKeyCheckDigit},
+		{name: "tamanho inválido", key: valid[:43], err: ErrInvalidAccessKey},
+		{name: "UF desconhecida", key: withCheckDigit("99" + base[2:]), err: ErrInvalidAccessKey},
+		{name: "mês inválido", key: withCheckDigit(base[:4] + "13" + base[6:]), err: ErrInvalidAccessKey},
+		{name: "CNPJ do emitente inválido", key: withCheckDigit(base[:6] + "11222333000180" + base[20:]), err: ErrInvalidAccessKey},
+		{name: "modelo de CT-e", key: withCheckDigit(base[:20] + "57" + base[22:]), err: ErrInvalidAccessKey},
+		{name: "número zerado", key: withCheckDigit(base[:25] + "000000000" + base[34:]), err: ErrInvalidAccessKey},
+		{name: "forma de emissão inexistente", key: withCheckDigit(base[:34] + "8" + base[35:]), err: ErrInvalidAccessKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := ParseAccessKey(tt.key)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("ParseAccessKey(%q) erro = %v, esperado %v", tt.key, err, tt.err)
+			}
+			if tt.err == nil && (parsed.Number != tt.number || parsed.String() != NormalizeAccessKey(tt.key)) {
+				t.Errorf("ParseAccessKey(%q) = %+v, esperado número %d", tt.key, parsed, tt.number)
+			}
+		})
+	}
+}
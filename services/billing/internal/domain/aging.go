@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// AgingBuckets distribui o saldo em aberto das parcelas pelas faixas de
+// atraso em relação à data de referência
+type AgingBuckets struct {
+	Current       Money `json:"current"`         // A vencer
+	Overdue1To30  Money `json:"overdue_1_30"`    // Vencidas há 1 a 30 dias
+	Overdue31To60 Money `json:"overdue_31_60"`   // Vencidas há 31 a 60 dias
+	Overdue61To90 Money `json:"overdue_61_90"`   // Vencidas há 61 a 90 dias
+	OverdueOver90 Money `json:"overdue_over_90"` // Vencidas há mais de 90 dias
+	Total         Money `json:"total"`
+}
+
+// CustomerAging é a distribuição do saldo em aberto de um cliente
+type CustomerAging struct {
+	CustomerID   string `json:"customer_id"`
+	CustomerName string `json:"customer_name,omitempty"`
+	AgingBuckets
+}
+
+// AgingReport é o relatório de vencimentos (aging) dos recebíveis em aberto
+type AgingReport struct {
+	Date      time.Time       `json:"date"`
+	Totals    AgingBuckets    `json:"totals"`
+	Customers []CustomerAging `json:"customers"`
+}
+
+// add soma o saldo na faixa correspondente aos dias de atraso
+func (b *AgingBuckets) add(outstanding Money, daysLate int) {
+	switch {
+	case daysLate <= 0:
+		b.Current += outstanding
+	case daysLate <= 30:
+		b.Overdue1To30 += outstanding
+	case daysLate <= 60:
+		b.Overdue31To60 += outstanding
+	case daysLate <= 90:
+		b.Overdue61To90 += outstanding
+	default:
+		b.OverdueOver90 += outstanding
+	}
+	b.Total += outstanding
+}
+
+// NewAgingReport monta o aging dos recebíveis na data de referência,
+// considerando apenas o saldo em aberto (sem encargos) de cada parcela.
+// Os clientes são ordenados pelo maior saldo
+func NewAgingReport(receivables []Receivable, date time.Time) *AgingReport {
+	report := &AgingReport{Date: date, Customers: []CustomerAging{}}
+	customers := make(map[string]*CustomerAging)
+	var order []string
+
+	for _, receivable := range receivables {
+		outstanding := receivable.Installment.Outstanding()
+		if outstanding == 0 {
+			continue
+		}
+		daysLate := DaysBetween(receivable.DueDate, date)
+		report.Totals.add(outstanding, daysLate)
+
+		customer, ok := customers[receivable.CustomerID]
+		if !ok {
+			customer = &CustomerAging{CustomerID: receivable.CustomerID, CustomerName: receivable.CustomerName}
+			customers[receivable.CustomerID] = customer
+			order = append(order, receivable.CustomerID)
+		}
+		customer.add(outstanding, daysLate)
+	}
+
+	for _, id := range order {
+		report.Customers = append(report.Customers, *customers[id])
+	}
+	sort.SliceStable(report.Customers, func(a, b int) bool {
+		return report.Customers[a].Total > report.Customers[b].Total
+	})
+	return report
+}
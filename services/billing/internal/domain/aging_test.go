@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAgingBuckets(t *testing.T) {
+	date := time.Date(2025, 3, 31, 15, 0, 0, 0, time.UTC)
+
+	// Cada parcela vence daysLate dias antes da data de referência; as
+	// fronteiras das faixas são 0, 30, 60 e 90 dias
+	tests := []struct {
+		name     string
+		daysLate int
+		expected AgingBuckets
+	}{
+		{name: "a vencer", daysLate: -5, expected: AgingBuckets{Current: 1000, Total: 1000}},
+		{name: "vence na data", daysLate: 0, expected: AgingBuckets{Current: 1000, Total: 1000}},
+		{name: "1 dia", daysLate: 1, expected: AgingBuckets{Overdue1To30: 1000, Total: 1000}},
+		{name: "30 dias", daysLate: 30, expected: AgingBuckets{Overdue1To30: 1000, Total: 1000}},
+		{name: "31 dias", daysLate: 31, expected: AgingBuckets{Overdue31To60: 1000, Total: 1000}},
+		{name: "60 dias", daysLate: 60, expected: AgingBuckets{Overdue31To60: 1000, Total: 1000}},
+		{name: "61 dias", daysLate: 61, expected: AgingBuckets{Overdue61To90: 1000, Total: 1000}},
+		{name: "90 dias", daysLate: 90, expected: AgingBuckets{Overdue61To90: 1000, Total: 1000}},
+		{name: "91 dias", daysLate: 91, expected: AgingBuckets{OverdueOver90: 1000, Total: 1000}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Vencimento no fim do dia: o atraso conta dias de calendário
+			due := time.Date(2025, 3, 31, 23, 59, 0, 0, time.UTC).AddDate(0, 0, -tt.daysLate)
+			report := NewAgingReport([]Receivable{{
+				CustomerID:  "customer-1",
+				Installment: Installment{DueDate: due, Amount: 1500, Paid: 500},
+			}}, date)
+			if report.Totals != tt.expected {
+				t.Errorf("faixas = %+v, esperado %+v", report.Totals, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAgingReportCustomers(t *testing.T) {
+	date := time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)
+	receivable := func(customerID string, dueDays int, amount, paid Money) Receivable {
+		return Receivable{
+			CustomerID:  customerID,
+			Installment: Installment{DueDate: date.AddDate(0, 0, dueDays), Amount: amount, Paid: paid},
+		}
+	}
+
+	report := NewAgingReport([]Receivable{
+		receivable("customer-1", 10, 1000, 0),
+		receivable("customer-1", -45, 2000, 2000), // Quitada: fora do relatório
+		receivable("customer-2", -45, 3000, 1000),
+		receivable("customer-2", -100, 500, 0),
+		receivable("customer-1", -1, 700, 0),
+	}, date)
+
+	expected := AgingBuckets{Current: 1000, Overdue1To30: 700, Overdue31To60: 2000, OverdueOver90: 500, Total: 4200}
+	if report.Totals != expected {
+		t.Errorf("totais = %+v, esperado %+v", report.Totals, expected)
+	}
+	// Clientes pelo maior saldo em aberto
+	if len(report.Customers) != 2 || report.Customers[0].CustomerID != "customer-2" || report.Customers[1].CustomerID != "customer-1" {
+		t.Fatalf("clientes = %+v, esperado customer-2 e customer-1", report.Customers)
+	}
+	if report.Customers[0].Total != 2500 || report.Customers[1].Total != 1700 {
+		t.Errorf("saldos = %d e %d, esperados 2500 e 1700", report.Customers[0].Total, report.Customers[1].Total)
+	}
+}
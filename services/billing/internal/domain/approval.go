@@ -0,0 +1,166 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ApprovalPolicy reúne as regras que retêm uma nota para aprovação antes da
+// impressão. Regras com valor zero ficam desligadas
+type ApprovalPolicy struct {
+	TotalThreshold     Money    // Total da nota acima do qual a aprovação é exigida
+	MaxDiscountPercent float64  // Desconto máximo de um item, em % do valor da linha
+	UnverifiedItems    bool     // Retém notas com mercadorias sem NCM válido
+	Approvers          []string // Usuários que podem decidir; vazio, nenhum usuário decide
+}
+
+// ApprovalRule identifica a regra que reteve a nota
+type ApprovalRule string
+
+const (
+	RuleTotalThreshold  ApprovalRule = "VALOR_TOTAL"
+	RuleDiscount        ApprovalRule = "DESCONTO"
+	RuleUnverifiedItems ApprovalRule = "ITEM_NAO_VERIFICADO"
+)
+
+// ApprovalReason descreve por que uma regra reteve a nota
+type ApprovalReason struct {
+	Rule    ApprovalRule `json:"rule"`
+	Item    int          `json:"item,omitempty"` // Número do item (nItem), nas regras por item
+	Message string       `json:"message"`
+}
+
+// ApprovalDecision é o resultado da aprovação
+type ApprovalDecision string
+
+const (
+	DecisionApproved    ApprovalDecision = "APROVADA"
+	DecisionDisapproved ApprovalDecision = "REPROVADA"
+)
+
+// InvoiceApproval registra as regras que retiveram a nota e a decisão
+type InvoiceApproval struct {
+	Reasons     []ApprovalReason `json:"reasons"`
+	RequestedAt time.Time        `json:"requested_at"`
+	Decision    ApprovalDecision `json:"decision,omitempty"`
+	DecidedBy   string           `json:"decided_by,omitempty"`
+	Comment     string           `json:"comment,omitempty"`
+	DecidedAt   *time.Time       `json:"decided_at,omitempty"`
+}
+
+// Erros de domínio da aprovação
+var (
+	ErrInvoiceAwaitingApproval = errors.New("nota fiscal aguarda aprovação e não pode ser impressa")
+	ErrInvoiceDisapproved      = errors.New("nota fiscal reprovada não pode ser impressa")
+	ErrInvoiceNotAwaiting      = errors.New("nota fiscal não está aguardando aprovação")
+	ErrApprovalUserRequired    = errors.New("nota sujeita a aprovação exige usuário identificado")
+	ErrApprovalComment         = errors.New("decisão exige comentário de 10 a 500 caracteres")
+	ErrSelfApproval            = errors.New("quem criou a nota não pode decidir sobre a própria aprovação")
+	ErrApprovalUnauthorized    = errors.New("usuário não autorizado a aprovar notas")
+)
+
+// Evaluate aplica as regras à nota e retorna os motivos para retê-la. Sem
+// motivos, a nota segue aberta para impressão
+func (p ApprovalPolicy) Evaluate(invoice *Invoice) []ApprovalReason {
+	var reasons []ApprovalReason
+	if p.TotalThreshold > 0 && invoice.Totals.Total > p.TotalThreshold {
+		reasons = append(reasons, ApprovalReason{
+			Rule:    RuleTotalThreshold,
+			Message: fmt.Sprintf("total %s acima do limite de %s", invoice.Totals.Total, p.TotalThreshold),
+		})
+	}
+	for idx, item := range invoice.Items {
+		if percent := item.DiscountPercent(); p.MaxDiscountPercent > 0 && percent > p.MaxDiscountPercent {
+			reasons = append(reasons, ApprovalReason{
+				Rule:    RuleDiscount,
+				Item:    idx + 1,
+				Message: fmt.Sprintf("desconto de %.2f%% acima do máximo de %.2f%%", percent, p.MaxDiscountPercent),
+			})
+		}
+		if p.UnverifiedItems && !item.IsService() && !isDigits(item.NCM, 8) {
+			reasons = append(reasons, ApprovalReason{
+				Rule:    RuleUnverifiedItems,
+				Item:    idx + 1,
+				Message: fmt.Sprintf("produto %s sem NCM válido", item.ProductCode),
+			})
+		}
+	}
+	return reasons
+}
+
+// EvaluateBatch aplica as regras a cada nota de um grupo. O limite de valor
+// também é verificado contra o total do grupo, para que a divisão da nota não
+// escape da aprovação
+func (p ApprovalPolicy) EvaluateBatch(invoices []*Invoice) [][]ApprovalReason {
+	reasons := make([][]ApprovalReason, len(invoices))
+	total := BatchTotal(invoices)
+	for idx, invoice := range invoices {
+		reasons[idx] = p.Evaluate(invoice)
+		if len(invoices) < 2 || p.TotalThreshold <= 0 || total <= p.TotalThreshold || invoice.Totals.Total > p.TotalThreshold {
+			continue
+		}
+		reasons[idx] = append([]ApprovalReason{{
+			Rule:    RuleTotalThreshold,
+			Message: fmt.Sprintf("total do grupo %s acima do limite de %s", total, p.TotalThreshold),
+		}}, reasons[idx]...)
+	}
+	return reasons
+}
+
+// CanApprove indica se o usuário pode decidir sobre notas retidas. Sem
+// aprovadores configurados ninguém decide, e as notas retidas aguardam a
+// configuração de APPROVAL_APPROVERS
+func (p ApprovalPolicy) CanApprove(user string) bool {
+	if user == "" {
+		return false
+	}
+	for _, approver := range p.Approvers {
+		if strings.EqualFold(approver, user) {
+			return true
+		}
+	}
+	return false
+}
+
+// RequestApproval retém a nota aberta até a decisão de um aprovador
+func (i *Invoice) RequestApproval(reasons []ApprovalReason, now time.Time) {
+	i.Status = StatusAwaitingApproval
+	i.Approval = &InvoiceApproval{Reasons: reasons, RequestedAt: now}
+	i.UpdatedAt = now
+}
+
+// Decide registra a decisão sobre a nota retida. O aprovador deve estar
+// autorizado, comentar a decisão e ser outra pessoa que não o criador da nota
+// (quatro olhos). Aprovada, a nota volta a ficar aberta para impressão
+func (i *Invoice) Decide(decision ApprovalDecision, user, comment string, policy ApprovalPolicy, now time.Time) error {
+	if !i.IsAwaitingApproval() || i.Approval == nil {
+		return ErrInvoiceNotAwaiting
+	}
+	comment = strings.TrimSpace(comment)
+	if user == "" {
+		return ErrApprovalUserRequired
+	}
+	if utf8.RuneCountInString(comment) < 10 || utf8.RuneCountInString(comment) > 500 {
+		return ErrApprovalComment
+	}
+	if strings.EqualFold(i.CreatedBy, user) {
+		return ErrSelfApproval
+	}
+	if !policy.CanApprove(user) {
+		return ErrApprovalUnauthorized
+	}
+
+	i.Approval.Decision = decision
+	i.Approval.DecidedBy = user
+	i.Approval.Comment = comment
+	i.Approval.DecidedAt = &now
+	i.Status = StatusOpen
+	if decision == DecisionDisapproved {
+		i.Status = StatusDisapproved
+	}
+	i.UpdatedAt = now
+	return nil
+}
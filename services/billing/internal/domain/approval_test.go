@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestInvoiceDecide(t *testing.T) {
+	approvers := ApprovalPolicy{Approvers: []string{"Gerente"}}
+	comment := "Pedido conferido com o cliente"
+
+	tests := []struct {
+		name    string
+		user    string
+		comment string
+		policy  ApprovalPolicy
+		err     error
+	}{
+		{name: "aprovador configurado", user: "gerente", comment: comment, policy: approvers},
+		{name: "sem aprovadores configurados", user: "gerente", comment: comment, policy: ApprovalPolicy{}, err: ErrApprovalUnauthorized},
+		{name: "usuário fora da lista", user: "bruno", comment: comment, policy: approvers, err: ErrApprovalUnauthorized},
+		{name: "sem usuário identificado", comment: comment, policy: approvers, err: ErrApprovalUserRequired},
+		{name: "criador da nota", user: "ana", comment: comment, policy: ApprovalPolicy{Approvers: []string{"ana"}}, err: ErrSelfApproval},
+		{name: "comentário curto", user: "gerente", comment: "  ok  ", policy: approvers, err: ErrApprovalComment},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			invoice := &Invoice{CreatedBy: "ana"}
+			invoice.RequestApproval([]ApprovalReason{{Rule: RuleTotalThreshold}}, time.Now())
+
+			err := invoice.Decide(DecisionApproved, tt.user, tt.comment, tt.policy, time.Now())
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Decide() erro = %v, esperado %v", err, tt.err)
+			}
+			expected := StatusOpen
+			if tt.err != nil {
+				expected = StatusAwaitingApproval
+			}
+			if invoice.Status != expected {
+				t.Errorf("status = %s, esperado %s", invoice.Status, expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// AuthorizationStatus representa a situação da nota perante a SEFAZ
+type AuthorizationStatus string
+
+const (
+	AuthorizationPending    AuthorizationStatus = "PENDENTE"   // Não enviada ou lote ainda em processamento
+	AuthorizationAuthorized AuthorizationStatus = "AUTORIZADA" // Autorizado o uso (cStat 100)
+	AuthorizationRejected   AuthorizationStatus = "REJEITADA"  // Rejeitada; pode ser reenviada após correção
+	AuthorizationDenied     AuthorizationStatus = "DENEGADA"   // Uso denegado; a numeração não pode ser reaproveitada
+)
+
+// Códigos de situação (cStat) relevantes da SEFAZ
+const (
+	SEFAZBatchReceived   = 103 // Lote recebido com sucesso
+	SEFAZBatchProcessed  = 104 // Lote processado
+	SEFAZBatchProcessing = 105 // Lote em processamento
+	SEFAZAuthorized      = 100 // Autorizado o uso da NF-e
+)
+
+// Erros de autorização
+var (
+	ErrInvoiceAlreadyAuthorized = errors.New("nota fiscal já autorizada pela SEFAZ")
+	ErrInvoiceDenied            = errors.New("uso da nota fiscal denegado pela SEFAZ")
+	ErrSEFAZNotConfigured       = errors.New("autorização na SEFAZ não configurada")
+	ErrSEFAZUnavailable         = errors.New("SEFAZ indisponível")
+)
+
+// Authorization registra o resultado do pedido de autorização na SEFAZ
+type Authorization struct {
+	Status     AuthorizationStatus `json:"status"`
+	Receipt    string              `json:"receipt,omitempty"`     // Número do recibo do lote (nRec)
+	Protocol   string              `json:"protocol,omitempty"`    // Número do protocolo (nProt)
+	StatusCode int                 `json:"status_code,omitempty"` // cStat
+	Reason     string              `json:"reason,omitempty"`      // xMotivo
+	ReceivedAt *time.Time          `json:"received_at,omitempty"` // dhRecbto
+	Attempts   int                 `json:"attempts"`
+	UpdatedAt  time.Time           `json:"updated_at"`
+}
+
+// AuthorizationStatusFor classifica o cStat devolvido no protocolo da nota.
+// 110, 301, 302 e 303 indicam uso denegado; os demais códigos, rejeição
+func AuthorizationStatusFor(code int) AuthorizationStatus {
+	switch code {
+	case SEFAZAuthorized:
+		return AuthorizationAuthorized
+	case SEFAZBatchReceived, SEFAZBatchProcessing:
+		return AuthorizationPending
+	case 110, 301, 302, 303:
+		return AuthorizationDenied
+	default:
+		return AuthorizationRejected
+	}
+}
+
+// IsAuthorized indica se a SEFAZ autorizou o uso da nota
+func (i *Invoice) IsAuthorized() bool {
+	return i.Authorization != nil && i.Authorization.Status == AuthorizationAuthorized
+}
+
+// CanRequestAuthorization verifica se a nota fechada pode ser (re)enviada à
+// SEFAZ. Notas de serviço (NFS-e) não são autorizadas pela SEFAZ
+func (i *Invoice) CanRequestAuthorization() error {
+	if !i.IsClosed() {
+		return ErrInvoiceNotClosed
+	}
+	if i.IsService() {
+		return ErrServiceInvoice
+	}
+	if i.Authorization == nil {
+		return nil
+	}
+	switch i.Authorization.Status {
+	case AuthorizationAuthorized:
+		return ErrInvoiceAlreadyAuthorized
+	case AuthorizationDenied:
+		return ErrInvoiceDenied
+	}
+	return nil
+}
+
+// RecordAuthorization registra o retorno da SEFAZ, contabilizando a tentativa
+func (i *Invoice) RecordAuthorization(result Authorization) {
+	attempts := 1
+	if i.Authorization != nil {
+		attempts = i.Authorization.Attempts + 1
+	}
+	now := time.Now()
+	result.Attempts = attempts
+	result.UpdatedAt = now
+	i.Authorization = &result
+	i.UpdatedAt = now
+}
+
+// AuthorityClient define o contrato para autorização de NF-e na SEFAZ
+type AuthorityClient interface {
+	// Authorize envia o XML assinado em um lote e consulta o recibo até o
+	// processamento. Lotes ainda em processamento retornam status PENDENTE
+	// com o número do recibo preenchido
+	Authorize(document []byte) (*Authorization, error)
+	// QueryReceipt consulta o resultado de um lote enviado anteriormente
+	QueryReceipt(receipt string) (*Authorization, error)
+}
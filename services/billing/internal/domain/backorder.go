@@ -0,0 +1,145 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// BackorderStatus representa a situação de uma pendência de entrega
+type BackorderStatus string
+
+const (
+	BackorderPending   BackorderStatus = "PENDENTE"   // Aguardando entrada no estoque
+	BackorderAvailable BackorderStatus = "DISPONIVEL" // Estoque reservado, pronta para faturar
+	BackorderInvoiced  BackorderStatus = "FATURADA"   // Nota gerada; a reserva segue até a impressão
+	BackorderCancelled BackorderStatus = "CANCELADA"
+)
+
+// Backorder registra a quantidade de um item que não pôde ser faturada por
+// falta de estoque. As pendências são reavaliadas, por ordem de criação, a
+// cada entrada do produto no Stock Service
+type Backorder struct {
+	ID          string          `json:"id"`
+	Status      BackorderStatus `json:"status"`
+	InvoiceID   string          `json:"invoice_id"` // Nota que faturou a quantidade disponível
+	Model       string          `json:"model"`
+	IssuerID    string          `json:"issuer_id"`
+	CustomerID  string          `json:"customer_id,omitempty"`
+	ProductID   string          `json:"product_id"`
+	ProductCode string          `json:"product_code"`
+	Description string          `json:"description"`
+	Quantity    int             `json:"quantity"` // Quantidade pendente de entrega
+	UnitPrice   Money           `json:"unit_price"`
+	Discount    Money           `json:"discount,omitempty"`     // Parcela do desconto da linha original
+	Payment     *PaymentTerms   `json:"payment,omitempty"`      // Condição de pagamento da nota original
+	FulfilledBy string          `json:"fulfilled_by,omitempty"` // Nota gerada para a pendência
+	CreatedBy   string          `json:"created_by,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+	AvailableAt *time.Time      `json:"available_at,omitempty"` // Reserva do estoque que chegou
+	DeliveredAt *time.Time      `json:"delivered_at,omitempty"` // Impressão da nota gerada, que baixou o estoque
+}
+
+// Erros de domínio das pendências de entrega
+var (
+	ErrBackorderNotFound     = errors.New("pendência de entrega não encontrada")
+	ErrBackorderNotAvailable = errors.New("somente pendências com estoque disponível podem ser faturadas")
+	ErrBackorderClosed       = errors.New("pendência faturada ou cancelada não pode ser alterada")
+	ErrNothingAvailable      = errors.New("nenhum item da nota tem estoque disponível")
+)
+
+// IsOpen indica se a pendência ainda aguarda faturamento
+func (b *Backorder) IsOpen() bool {
+	return b.Status == BackorderPending || b.Status == BackorderAvailable
+}
+
+// MarkAvailable reserva para a pendência o estoque que entrou
+func (b *Backorder) MarkAvailable(now time.Time) {
+	b.Status = BackorderAvailable
+	b.AvailableAt = &now
+	b.UpdatedAt = now
+}
+
+// Fulfil registra a nota gerada para a pendência. A reserva continua até a
+// impressão da nota, que baixa o estoque
+func (b *Backorder) Fulfil(invoiceID string, now time.Time) error {
+	if b.Status != BackorderAvailable {
+		return ErrBackorderNotAvailable
+	}
+	b.Status = BackorderInvoiced
+	b.FulfilledBy = invoiceID
+	b.UpdatedAt = now
+	return nil
+}
+
+// Deliver registra a impressão da nota gerada para a pendência, liberando a
+// reserva
+func (b *Backorder) Deliver(now time.Time) {
+	b.DeliveredAt = &now
+	b.UpdatedAt = now
+}
+
+// IsHolding indica se a pendência reserva estoque: disponível, ou faturada com
+// a nota ainda não impressa
+func (b *Backorder) IsHolding() bool {
+	return b.Status == BackorderAvailable || (b.Status == BackorderInvoiced && b.DeliveredAt == nil)
+}
+
+// Reopen devolve a pendência à fila quando a nota gerada é reprovada
+func (b *Backorder) Reopen(now time.Time) {
+	b.Status = BackorderPending
+	b.FulfilledBy = ""
+	b.AvailableAt = nil
+	b.UpdatedAt = now
+}
+
+// Cancel cancela a pendência, liberando a reserva se houver
+func (b *Backorder) Cancel(now time.Time) error {
+	if !b.IsOpen() {
+		return ErrBackorderClosed
+	}
+	b.Status = BackorderCancelled
+	b.UpdatedAt = now
+	return nil
+}
+
+// HeldBackorders soma, por produto, o estoque reservado pelas pendências
+// disponíveis e pelas faturadas com a nota ainda não impressa, ignorando a
+// pendência informada (que consome a própria reserva)
+func HeldBackorders(backorders []*Backorder, exceptID string) map[string]int {
+	held := make(map[string]int)
+	for _, backorder := range backorders {
+		if backorder.ID != exceptID && backorder.IsHolding() {
+			held[backorder.ProductID] += backorder.Quantity
+		}
+	}
+	return held
+}
+
+// AllocateBackorders reserva o saldo disponível às pendências do produto por
+// ordem de criação. A alocação para na primeira pendência que o saldo não
+// comporta, para que pendências mais novas não passem à frente das antigas
+func AllocateBackorders(pending []*Backorder, available int, now time.Time) []*Backorder {
+	var allocated []*Backorder
+	for _, backorder := range pending {
+		if backorder.Status != BackorderPending {
+			continue
+		}
+		if backorder.Quantity > available {
+			break
+		}
+		available -= backorder.Quantity
+		backorder.MarkAvailable(now)
+		allocated = append(allocated, backorder)
+	}
+	return allocated
+}
+
+// BackorderRepository define o contrato para persistência das pendências
+type BackorderRepository interface {
+	Create(backorder *Backorder) error
+	FindByID(id string) (*Backorder, error)
+	FindAll() ([]*Backorder, error)                       // Ordenadas pela criação
+	FindByProduct(productID string) ([]*Backorder, error) // Ordenadas pela criação
+	Update(backorder *Backorder) error
+}
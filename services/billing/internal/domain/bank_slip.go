@@ -0,0 +1,132 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// Bancos com leiaute de campo livre do boleto suportado
+const (
+	BankBrasil   = "001" // Banco do Brasil, convênio de 7 dígitos (carteiras 17 e 18)
+	BankBradesco = "237"
+	BankItau     = "341"
+)
+
+// BankSlipAccount reúne os dados da conta de cobrança do emitente no banco.
+// Agência, conta, carteira e convênio são completados com zeros à esquerda
+type BankSlipAccount struct {
+	Bank      string `json:"bank"`                // Código de compensação do banco (3 dígitos)
+	Agency    string `json:"agency"`              // Agência, sem dígito
+	Account   string `json:"account"`             // Conta corrente, sem dígito
+	Wallet    string `json:"wallet"`              // Carteira de cobrança
+	Agreement string `json:"agreement,omitempty"` // Convênio (Banco do Brasil)
+}
+
+// BankSlip é o boleto de cobrança de uma parcela, com o código de barras de 44
+// posições e a linha digitável de 47 dígitos no padrão Febraban
+type BankSlip struct {
+	Bank                   string    `json:"bank"`
+	Installment            string    `json:"installment"`
+	DocumentNumber         string    `json:"document_number"` // Número da nota e da parcela
+	DueDate                time.Time `json:"due_date"`
+	Amount                 Money     `json:"amount"`
+	OurNumber              string    `json:"our_number"` // Nosso número, como impresso no boleto
+	Barcode                string    `json:"barcode"`
+	DigitableLine          string    `json:"digitable_line"`
+	DigitableLineFormatted string    `json:"digitable_line_formatted"`
+}
+
+// Erros de domínio do boleto
+var (
+	ErrUnsupportedBank            = errors.New("banco de cobrança deve ser 001, 237 ou 341")
+	ErrInvalidBankSlipAccount     = errors.New("agência, conta, carteira e convênio devem ser numéricos e caber no leiaute do banco")
+	ErrBankSlipNotConfigured      = errors.New("emitente não possui conta de cobrança para boletos")
+	ErrBankSlipAmount             = errors.New("valor da parcela excede o limite do boleto")
+	ErrBankSlipInstallmentMissing = errors.New("boleto exige a parcela")
+)
+
+// bankSlipLayout define o tamanho dos campos da conta de cobrança usados no
+// campo livre de cada banco; zero indica campo não usado
+type bankSlipLayout struct {
+	agency    int
+	account   int
+	wallet    int
+	agreement int
+}
+
+var bankSlipLayouts = map[string]bankSlipLayout{
+	BankBrasil:   {agency: 4, account: 8, wallet: 2, agreement: 7},
+	BankBradesco: {agency: 4, account: 7, wallet: 2},
+	BankItau:     {agency: 4, account: 5, wallet: 3},
+}
+
+// Normalize remove a pontuação e completa os campos com zeros à esquerda
+// conforme o leiaute do banco
+func (a *BankSlipAccount) Normalize() {
+	a.Bank = onlyDigits(a.Bank)
+	a.Agency = onlyDigits(a.Agency)
+	a.Account = onlyDigits(a.Account)
+	a.Wallet = onlyDigits(a.Wallet)
+	a.Agreement = onlyDigits(a.Agreement)
+
+	layout, ok := bankSlipLayouts[a.Bank]
+	if !ok {
+		return
+	}
+	a.Agency = padDigits(a.Agency, layout.agency)
+	a.Account = padDigits(a.Account, layout.account)
+	a.Wallet = padDigits(a.Wallet, layout.wallet)
+	if layout.agreement > 0 {
+		a.Agreement = padDigits(a.Agreement, layout.agreement)
+	} else {
+		a.Agreement = ""
+	}
+}
+
+// Validate valida a conta de cobrança conforme o leiaute do banco
+func (a *BankSlipAccount) Validate() error {
+	layout, ok := bankSlipLayouts[a.Bank]
+	if !ok {
+		return ErrUnsupportedBank
+	}
+	fields := []struct {
+		value string
+		size  int
+	}{
+		{a.Agency, layout.agency},
+		{a.Account, layout.account},
+		{a.Wallet, layout.wallet},
+		{a.Agreement, layout.agreement},
+	}
+	for _, field := range fields {
+		if len(field.value) != field.size || (field.size > 0 && strings.Trim(field.value, "0") == "") {
+			return ErrInvalidBankSlipAccount
+		}
+	}
+	return nil
+}
+
+// OpenInstallment retorna a parcela informada de uma nota que admite
+// recebimentos, desde que ainda tenha saldo em aberto
+func (i *Invoice) OpenInstallment(number string) (*Installment, error) {
+	if err := i.CanReceivePayment(); err != nil {
+		return nil, err
+	}
+	if number == "" {
+		return nil, ErrBankSlipInstallmentMissing
+	}
+	targets, err := i.paymentTargets(number)
+	if err != nil {
+		return nil, err
+	}
+	return &i.Installments[targets[0]], nil
+}
+
+// padDigits completa o valor com zeros à esquerda até o tamanho informado
+func padDigits(value string, size int) string {
+	if value == "" || len(value) >= size {
+		return value
+	}
+	return strings.Repeat("0", size-len(value)) + value
+}
@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// Carrier representa uma transportadora, informada no grupo de transporte das
+// notas de mercadorias
+type Carrier struct {
+	ID                string       `json:"id"`
+	Name              string       `json:"name"`                         // Nome ou razão social
+	Document          string       `json:"document"`                     // CPF ou CNPJ (sem pontuação)
+	DocumentType      DocumentType `json:"document_type"`                // CPF ou CNPJ
+	StateRegistration string       `json:"state_registration,omitempty"` // Inscrição estadual ou ISENTO
+	RNTC              string       `json:"rntc,omitempty"`               // Registro Nacional de Transportador de Carga (ANTT)
+	Address           Address      `json:"address"`
+	CreatedAt         time.Time    `json:"created_at"`
+	UpdatedAt         time.Time    `json:"updated_at"`
+}
+
+// Erros de domínio da transportadora
+var (
+	ErrCarrierNotFound          = errors.New("transportadora não encontrada")
+	ErrInvalidCarrier           = errors.New("transportadora inválida: nome é obrigatório")
+	ErrDuplicateCarrierDocument = errors.New("já existe transportadora com este documento")
+	ErrInvalidRNTC              = errors.New("RNTC deve ter até 20 caracteres")
+)
+
+// Normalize padroniza documento, inscrição estadual, RNTC e endereço
+func (c *Carrier) Normalize() {
+	c.Name = strings.TrimSpace(c.Name)
+	c.Document = NormalizeDocument(c.Document)
+	c.StateRegistration = normalizeStateRegistration(c.StateRegistration)
+	c.RNTC = strings.ToUpper(strings.TrimSpace(c.RNTC))
+	c.Address.Normalize()
+}
+
+// Validate valida os dados da transportadora, incluindo os dígitos do CPF/CNPJ
+func (c *Carrier) Validate() error {
+	if c.Name == "" {
+		return ErrInvalidCarrier
+	}
+	_, documentType, err := ParseDocument(c.Document)
+	if err != nil {
+		return err
+	}
+	c.DocumentType = documentType
+
+	if err := validateStateRegistration(c.StateRegistration); err != nil {
+		return err
+	}
+	if len(c.RNTC) > 20 {
+		return ErrInvalidRNTC
+	}
+	return c.Address.Validate()
+}
+
+// CarrierRepository define o contrato para persistência de transportadoras
+type CarrierRepository interface {
+	Create(carrier *Carrier) error
+	FindByID(id string) (*Carrier, error)
+	FindAll() ([]*Carrier, error)
+	Update(carrier *Carrier) error
+	Delete(id string) error
+}
@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCarrierValidate(t *testing.T) {
+	address := Address{
+		Street:   "Rodovia Anhanguera",
+		Number:   "km 20",
+		District: "Jaraguá",
+		CityCode: "3550308",
+		City:     "São Paulo",
+		UF:       "SP",
+		CEP:      "05275000",
+	}
+
+	tests := []struct {
+		name         string
+		carrier      Carrier
+		err          error
+		documentType DocumentType // Tipo de documento identificado na validação
+	}{
+		{
+			name:         "CNPJ pontuado",
+			carrier:      Carrier{Name: " TRANSPORTES TESTE LTDA ", Document: "11.222.333/0001-81", StateRegistration: "110.042.490.114", RNTC: " abc123 ", Address: address},
+			documentType: DocumentCNPJ,
+		},
+		{
+			name:         "CPF e IE isenta",
+			carrier:      Carrier{Name: "JOÃO TRANSPORTADOR", Document: "529.982.247-25", StateRegistration: "isento", Address: address},
+			documentType: DocumentCPF,
+		},
+		{name: "sem nome", carrier: Carrier{Document: "11222333000181", Address: address}, err: ErrInvalidCarrier},
+		{name: "CNPJ com dígito inválido", carrier: Carrier{Name: "TRANSPORTES", Document: "11222333000180", Address: address}, err: ErrInvalidCNPJ},
+		{name: "CPF com dígito inválido", carrier: Carrier{Name: "TRANSPORTES", Document: "52998224724", Address: address}, err: ErrInvalidCPF},
+		{name: "documento incompleto", carrier: Carrier{Name: "TRANSPORTES", Document: "1122233300018", Address: address}, err: ErrInvalidDocument},
+		{name: "IE com um dígito", carrier: Carrier{Name: "TRANSPORTES", Document: "11222333000181", StateRegistration: "1", Address: address}, err: ErrInvalidStateRegistration},
+		{name: "RNTC longo", carrier: Carrier{Name: "TRANSPORTES", Document: "11222333000181", RNTC: strings.Repeat("1", 21), Address: address}, err: ErrInvalidRNTC},
+		{name: "sem endereço", carrier: Carrier{Name: "TRANSPORTES", Document: "11222333000181"}, err: ErrInvalidAddress},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			carrier := tt.carrier
+			carrier.Normalize()
+			if err := carrier.Validate(); !errors.Is(err, tt.err) {
+				t.Fatalf("Validate() erro = %v, esperado %v", err, tt.err)
+			}
+			if tt.err == nil && carrier.DocumentType != tt.documentType {
+				t.Errorf("Validate() tipo de documento = %s, esperado %s", carrier.DocumentType, tt.documentType)
+			}
+		})
+	}
+
+	carrier := tests[0].carrier
+	carrier.Normalize()
+	if carrier.Name != "TRANSPORTES TESTE LTDA" || carrier.Document != "11222333000181" || carrier.StateRegistration != "110042490114" || carrier.RNTC != "ABC123" {
+		t.Errorf("Normalize() = %+v", carrier)
+	}
+}
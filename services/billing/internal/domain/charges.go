@@ -0,0 +1,89 @@
+package domain
+
+import "errors"
+
+// Charges reúne as despesas acessórias da nota (frete, seguro e outras
+// despesas). No cabeçalho são informadas pelo usuário; nos itens, são a parte
+// rateada para cada linha
+type Charges struct {
+	Freight   Money `json:"freight"`
+	Insurance Money `json:"insurance"`
+	Other     Money `json:"other"`
+}
+
+// Erros de domínio das despesas acessórias
+var (
+	ErrInvalidCharges = errors.New("frete, seguro e outras despesas não podem ser negativos")
+	ErrServiceCharges = errors.New("NFS-e não admite frete, seguro ou outras despesas")
+)
+
+// Total retorna a soma das despesas acessórias
+func (c Charges) Total() Money {
+	return c.Freight + c.Insurance + c.Other
+}
+
+// IsZero indica se não há despesas acessórias
+func (c Charges) IsZero() bool {
+	return c == Charges{}
+}
+
+// Validate verifica se as despesas não são negativas
+func (c Charges) Validate() error {
+	if c.Freight < 0 || c.Insurance < 0 || c.Other < 0 {
+		return ErrInvalidCharges
+	}
+	return nil
+}
+
+// Apportion divide um valor entre as linhas proporcionalmente aos pesos
+// informados (o valor de cada linha), truncando cada parcela no centavo. O
+// resíduo do arredondamento vai para a linha de maior peso (a primeira, em
+// caso de empate); sem peso algum, o valor inteiro fica na primeira linha
+func Apportion(value Money, weights []Money) []Money {
+	shares := make([]Money, len(weights))
+	if len(weights) == 0 || value == 0 {
+		return shares
+	}
+
+	var total Money
+	largest := 0
+	for idx, weight := range weights {
+		total += weight
+		if weight > weights[largest] {
+			largest = idx
+		}
+	}
+	if total <= 0 {
+		shares[0] = value
+		return shares
+	}
+
+	var allocated Money
+	for idx, weight := range weights {
+		shares[idx] = value * weight / total
+		allocated += shares[idx]
+	}
+	shares[largest] += value - allocated
+	return shares
+}
+
+// ApportionCharges rateia as despesas do cabeçalho entre os itens,
+// proporcionalmente ao valor de cada linha. Cada despesa é rateada
+// separadamente, de modo que a soma dos itens coincide com o cabeçalho
+func (i *Invoice) ApportionCharges() {
+	weights := make([]Money, len(i.Items))
+	for idx, item := range i.Items {
+		weights[idx] = item.Total
+	}
+
+	freight := Apportion(i.Charges.Freight, weights)
+	insurance := Apportion(i.Charges.Insurance, weights)
+	other := Apportion(i.Charges.Other, weights)
+	for idx := range i.Items {
+		i.Items[idx].Charges = Charges{
+			Freight:   freight[idx],
+			Insurance: insurance[idx],
+			Other:     other[idx],
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestApportion(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    Money
+		weights  []Money
+		expected []Money
+	}{
+		{name: "proporção exata", value: 1000, weights: []Money{2500, 7500}, expected: []Money{250, 750}},
+		{name: "resíduo na linha de maior peso", value: 100, weights: []Money{1, 2, 3}, expected: []Money{16, 33, 51}},
+		{name: "empate no maior peso fica com a primeira", value: 10000, weights: []Money{1000, 1000, 1000}, expected: []Money{3334, 3333, 3333}},
+		{name: "linha sem valor não recebe parcela", value: 99, weights: []Money{0, 1000}, expected: []Money{0, 99}},
+		{name: "sem peso algum fica na primeira linha", value: 500, weights: []Money{0, 0}, expected: []Money{500, 0}},
+		{name: "valor zero", value: 0, weights: []Money{1000, 2000}, expected: []Money{0, 0}},
+		{name: "sem linhas", value: 500, weights: []Money{}, expected: []Money{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shares := Apportion(tt.value, tt.weights)
+			if !slices.Equal(shares, tt.expected) {
+				t.Fatalf("Apportion(%d, %v) = %v, esperado %v", tt.value, tt.weights, shares, tt.expected)
+			}
+			var sum Money
+			for _, share := range shares {
+				sum += share
+			}
+			if len(shares) > 0 && sum != tt.value {
+				t.Errorf("soma das parcelas = %d, esperado %d", sum, tt.value)
+			}
+		})
+	}
+}
+
+func TestApportionCharges(t *testing.T) {
+	// R$ 10,00 de frete, R$ 1,00 de seguro e R$ 0,01 de outras despesas sobre
+	// linhas de R$ 30,00 e R$ 70,00
+	invoice := &Invoice{
+		Charges: Charges{Freight: 1000, Insurance: 100, Other: 1},
+		Items:   []InvoiceItem{{Total: 3000}, {Total: 7000}},
+	}
+	invoice.ApportionCharges()
+
+	expected := []Charges{
+		{Freight: 300, Insurance: 30, Other: 0},
+		{Freight: 700, Insurance: 70, Other: 1},
+	}
+	var total Charges
+	for idx, item := range invoice.Items {
+		if item.Charges != expected[idx] {
+			t.Errorf("item %d despesas = %+v, esperado %+v", idx+1, item.Charges, expected[idx])
+		}
+		total.Freight += item.Charges.Freight
+		total.Insurance += item.Charges.Insurance
+		total.Other += item.Charges.Other
+	}
+	if total != invoice.Charges {
+		t.Errorf("soma dos itens = %+v, esperado %+v", total, invoice.Charges)
+	}
+}
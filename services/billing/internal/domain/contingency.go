@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Limites da justificativa de entrada em contingência (xJust)
+const (
+	ContingencyJustificationMin = 15
+	ContingencyJustificationMax = 256
+)
+
+// Erros de contingência
+var (
+	ErrContingencyJustification = errors.New("justificativa da contingência deve ter entre 15 e 256 caracteres")
+	ErrContingencyEmissionType  = errors.New("forma de emissão em contingência inválida (use 2 - FS-IA ou 5 - FS-DA)")
+	ErrContingencyInactive      = errors.New("emissão em contingência não está ativa")
+	ErrTransmissionNotQueued    = errors.New("nota fiscal não está na fila de transmissão")
+)
+
+// Contingency descreve a emissão em contingência off-line: as notas são
+// emitidas com a forma de emissão (tpEmis) informada, sem consulta prévia à
+// SEFAZ, e transmitidas quando a comunicação é restabelecida
+type Contingency struct {
+	EmissionType  EmissionType `json:"emission_type"`
+	Justification string       `json:"justification"` // xJust
+	StartedAt     time.Time    `json:"started_at"`    // dhCont
+}
+
+// NewContingency valida a forma de emissão e a justificativa da contingência
+func NewContingency(emissionType EmissionType, justification string, startedAt time.Time) (*Contingency, error) {
+	if emissionType != EmissionFSIA && emissionType != EmissionFSDA {
+		return nil, ErrContingencyEmissionType
+	}
+	justification = strings.Join(strings.Fields(justification), " ")
+	length := utf8.RuneCountInString(justification)
+	if length < ContingencyJustificationMin || length > ContingencyJustificationMax {
+		return nil, ErrContingencyJustification
+	}
+	return &Contingency{
+		EmissionType:  emissionType,
+		Justification: justification,
+		StartedAt:     startedAt,
+	}, nil
+}
+
+// EmissionType retorna a forma de emissão da nota (normal ou em contingência)
+func (i *Invoice) EmissionType() EmissionType {
+	if i.Contingency != nil {
+		return i.Contingency.EmissionType
+	}
+	return EmissionNormal
+}
+
+// AwaitTransmission marca a nota emitida em contingência como pendente de
+// transmissão, sem contabilizar tentativa de autorização
+func (i *Invoice) AwaitTransmission() {
+	now := time.Now()
+	i.Authorization = &Authorization{
+		Status:    AuthorizationPending,
+		Reason:    "emitida em contingência; aguardando transmissão à SEFAZ",
+		UpdatedAt: now,
+	}
+	i.UpdatedAt = now
+}
+
+// QueuedTransmission é uma nota emitida em contingência aguardando transmissão
+type QueuedTransmission struct {
+	InvoiceID     string     `json:"invoice_id"`
+	Number        int        `json:"number"`
+	EnqueuedAt    time.Time  `json:"enqueued_at"`
+	Attempts      int        `json:"attempts"`
+	LastAttemptAt *time.Time `json:"last_attempt_at,omitempty"`
+	LastError     string     `json:"last_error,omitempty"`
+}
+
+// ContingencyRepository guarda a contingência vigente (nil quando em emissão normal)
+type ContingencyRepository interface {
+	Get() (*Contingency, error)
+	Save(contingency *Contingency) error
+	Clear() error
+}
+
+// TransmissionQueue define o contrato da fila de notas a transmitir
+type TransmissionQueue interface {
+	Enqueue(entry *QueuedTransmission) error
+	Pending() ([]*QueuedTransmission, error) // Em ordem de entrada na fila
+	Update(entry *QueuedTransmission) error
+	Remove(invoiceID string) error
+}
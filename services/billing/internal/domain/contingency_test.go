@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewContingency(t *testing.T) {
+	startedAt := time.Date(2025, 3, 10, 9, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		emissionType  EmissionType
+		justification string
+		expected      string // Justificativa registrada
+		err           error
+	}{
+		{name: "FS-DA", emissionType: EmissionFSDA, justification: "Falha de comunicação com a SEFAZ", expected: "Falha de comunicação com a SEFAZ"},
+		{name: "FS-IA", emissionType: EmissionFSIA, justification: "Falha de comunicação com a SEFAZ", expected: "Falha de comunicação com a SEFAZ"},
+		{name: "emissão normal", emissionType: EmissionNormal, justification: "Falha de comunicação com a SEFAZ", err: ErrContingencyEmissionType},
+		{name: "forma desconhecida", emissionType: 9, justification: "Falha de comunicação com a SEFAZ", err: ErrContingencyEmissionType},
+		{name: "espaços removidos", emissionType: EmissionFSDA, justification: "  SEFAZ\n  fora do ar  ", expected: "SEFAZ fora do ar"},
+		{name: "14 caracteres", emissionType: EmissionFSDA, justification: strings.Repeat("a", 14), err: ErrContingencyJustification},
+		// Espaços repetidos não contam para o mínimo
+		{name: "14 caracteres normalizados", emissionType: EmissionFSDA, justification: "SEFAZ    indispo", err: ErrContingencyJustification},
+		{name: "15 caracteres acentuados", emissionType: EmissionFSDA, justification: strings.Repeat("ç", 15), expected: strings.Repeat("ç", 15)},
+		{name: "256 caracteres", emissionType: EmissionFSDA, justification: strings.Repeat("a", 256), expected: strings.Repeat("a", 256)},
+		{name: "257 caracteres", emissionType: EmissionFSDA, justification: strings.Repeat("a", 257), err: ErrContingencyJustification},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contingency, err := NewContingency(tt.emissionType, tt.justification, startedAt)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("NewContingency() erro = %v, esperado %v", err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+			expected := Contingency{EmissionType: tt.emissionType, Justification: tt.expected, StartedAt: startedAt}
+			if *contingency != expected {
+				t.Errorf("NewContingency() = %+v, esperado %+v", *contingency, expected)
+			}
+		})
+	}
+}
+
+func TestInvoiceContingency(t *testing.T) {
+	invoice := &Invoice{}
+	if invoice.EmissionType() != EmissionNormal {
+		t.Errorf("EmissionType() = %d, esperado %d", invoice.EmissionType(), EmissionNormal)
+	}
+
+	invoice.Contingency = &Contingency{EmissionType: EmissionFSIA}
+	if invoice.EmissionType() != EmissionFSIA {
+		t.Errorf("EmissionType() = %d, esperado %d", invoice.EmissionType(), EmissionFSIA)
+	}
+
+	// A nota aguarda a transmissão sem contabilizar tentativa de autorização
+	invoice.AwaitTransmission()
+	if auth := invoice.Authorization; auth == nil || auth.Status != AuthorizationPending || auth.Attempts != 0 {
+		t.Fatalf("AwaitTransmission() autorização = %+v", auth)
+	}
+	if err := invoice.CanRequestAuthorization(); !errors.Is(err, ErrInvoiceNotClosed) {
+		t.Errorf("CanRequestAuthorization() erro = %v, esperado %v", err, ErrInvoiceNotClosed)
+	}
+}
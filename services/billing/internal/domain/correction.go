@@ -0,0 +1,115 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Limites da carta de correção eletrônica (CC-e)
+const (
+	EventCorrectionLetter = "110110" // tpEvento da CC-e
+	CorrectionMinLength   = 15       // Tamanho mínimo de xCorrecao
+	CorrectionMaxLength   = 1000     // Tamanho máximo de xCorrecao
+	MaxCorrectionsPerNote = 20       // nSeqEvento vai de 1 a 20
+)
+
+// Erros de carta de correção
+var (
+	ErrCorrectionRequiresAuthorization = errors.New("carta de correção exige nota autorizada pela SEFAZ")
+	ErrCorrectionTextLength            = fmt.Errorf("texto da correção deve ter entre %d e %d caracteres", CorrectionMinLength, CorrectionMaxLength)
+	ErrCorrectionForbiddenField        = errors.New("texto da correção altera informação que não pode ser corrigida por CC-e")
+	ErrCorrectionLimitReached          = fmt.Errorf("limite de %d cartas de correção por nota atingido", MaxCorrectionsPerNote)
+	ErrCorrectionNotFound              = errors.New("carta de correção não encontrada")
+)
+
+// CorrectionLetter representa uma carta de correção (evento 110110) vinculada
+// a uma nota autorizada. Cada nova carta substitui as anteriores, portanto o
+// texto deve reunir todas as correções vigentes
+type CorrectionLetter struct {
+	ID        string    `json:"id"`
+	InvoiceID string    `json:"invoice_id"`
+	AccessKey string    `json:"access_key"` // chNFe da nota corrigida
+	Sequence  int       `json:"sequence"`   // nSeqEvento, sequencial por nota
+	Text      string    `json:"text"`       // xCorrecao
+	EventID   string    `json:"event_id"`   // Id do infEvento
+	Document  []byte    `json:"-"`          // XML do evento assinado
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// forbiddenCorrectionTerms relaciona as informações que o § 1º-A do art. 7º do
+// Convênio S/N de 1970 proíbe corrigir por carta de correção: variáveis que
+// determinam o imposto, dados cadastrais que mudam remetente ou destinatário e
+// as datas de emissão ou saída. Os termos são comparados sem acentos
+var forbiddenCorrectionTerms = []string{
+	"base de calculo",
+	"aliquota",
+	"diferenca de preco",
+	"preco unitario",
+	"quantidade",
+	"valor da operacao",
+	"valor da prestacao",
+	"valor do imposto",
+	"valor total",
+	"cnpj",
+	"cpf",
+	"razao social",
+	"data de emissao",
+	"data da emissao",
+	"data de saida",
+	"data da saida",
+}
+
+// accentFolder remove a acentuação usada em português
+var accentFolder = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a",
+	"é", "e", "ê", "e", "è", "e", "ë", "e",
+	"í", "i", "î", "i", "ì", "i", "ï", "i",
+	"ó", "o", "ô", "o", "õ", "o", "ò", "o", "ö", "o",
+	"ú", "u", "û", "u", "ù", "u", "ü", "u",
+	"ç", "c",
+)
+
+// NormalizeCorrectionText remove espaços nas extremidades e quebras de linha,
+// que o leiaute do evento não aceita
+func NormalizeCorrectionText(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// ValidateCorrectionText valida o tamanho do texto já normalizado e recusa
+// correções de informações vedadas
+func ValidateCorrectionText(text string) error {
+	length := utf8.RuneCountInString(text)
+	if length < CorrectionMinLength || length > CorrectionMaxLength {
+		return ErrCorrectionTextLength
+	}
+
+	folded := accentFolder.Replace(strings.ToLower(text))
+	for _, term := range forbiddenCorrectionTerms {
+		if strings.Contains(folded, term) {
+			return fmt.Errorf("%w (%s)", ErrCorrectionForbiddenField, term)
+		}
+	}
+	return nil
+}
+
+// CanReceiveCorrection verifica se a nota aceita carta de correção
+func (i *Invoice) CanReceiveCorrection() error {
+	if !i.IsClosed() {
+		return ErrInvoiceNotClosed
+	}
+	if !i.IsAuthorized() {
+		return ErrCorrectionRequiresAuthorization
+	}
+	return nil
+}
+
+// CorrectionRepository define o contrato para persistência das cartas de correção
+type CorrectionRepository interface {
+	Create(letter *CorrectionLetter) error
+	FindByInvoice(invoiceID string) ([]*CorrectionLetter, error) // Ordenadas pela sequência
+	FindBySequence(invoiceID string, sequence int) (*CorrectionLetter, error)
+	GetNextSequence(invoiceID string) (int, error) // Retorna o próximo nSeqEvento da nota
+}
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeCorrectionText(t *testing.T) {
+	got := NormalizeCorrectionText("  Endereço de entrega:\r\n\tRua   das Flores, 100 \n")
+	if expected := "Endereço de entrega: Rua das Flores, 100"; got != expected {
+		t.Errorf("NormalizeCorrectionText() = %q, esperado %q", got, expected)
+	}
+}
+
+func TestValidateCorrectionText(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		err  error
+	}{
+		{name: "válido", text: "Endereço de entrega: Rua das Flores, 100"},
+		{name: "14 caracteres", text: strings.Repeat("a", 14), err: ErrCorrectionTextLength},
+		{name: "15 caracteres", text: strings.Repeat("a", 15)},
+		// O tamanho é contado em caracteres, não em bytes
+		{name: "15 caracteres acentuados", text: strings.Repeat("ç", 15)},
+		{name: "14 caracteres acentuados", text: strings.Repeat("ç", 14), err: ErrCorrectionTextLength},
+		{name: "1000 caracteres", text: strings.Repeat("ã", 1000)},
+		{name: "1001 caracteres", text: strings.Repeat("a", 1001), err: ErrCorrectionTextLength},
+		{name: "vazio", text: "", err: ErrCorrectionTextLength},
+		{name: "alíquota", text: "Corrigir a Alíquota do item 1", err: ErrCorrectionForbiddenField},
+		{name: "base de cálculo", text: "BASE DE CÁLCULO do ICMS incorreta", err: ErrCorrectionForbiddenField},
+		{name: "quantidade", text: "Quantidade do item 2 passa a ser 5", err: ErrCorrectionForbiddenField},
+		{name: "CNPJ", text: "Corrigir o CNPJ do destinatário", err: ErrCorrectionForbiddenField},
+		{name: "razão social", text: "Razão social do destinatário incorreta", err: ErrCorrectionForbiddenField},
+		{name: "data de saída", text: "Data de saída correta: 10/03/2025", err: ErrCorrectionForbiddenField},
+		{name: "valor total", text: "Valor total da nota incorreto", err: ErrCorrectionForbiddenField},
+		{name: "dados de transporte", text: "Placa do veículo transportador: ABC1D23"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateCorrectionText(tt.text); !errors.Is(err, tt.err) {
+				t.Errorf("ValidateCorrectionText() erro = %v, esperado %v", err, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// CreditPolicy define os usuários autorizados a liberar notas acima do limite
+// de crédito do cliente
+type CreditPolicy struct {
+	Approvers []string
+}
+
+// CreditCheck compara o limite de crédito do cliente com os recebíveis em
+// aberto somados ao total da nota
+type CreditCheck struct {
+	CustomerID   string `json:"customer_id"`
+	Limit        Money  `json:"limit"`
+	Outstanding  Money  `json:"outstanding"`   // Saldo em aberto das parcelas do cliente
+	InvoiceTotal Money  `json:"invoice_total"` // Total da nota verificada
+	Available    Money  `json:"available"`     // Limite menos o saldo em aberto
+	Excess       Money  `json:"excess"`        // Quanto a nota ultrapassa o disponível
+}
+
+// CreditOverride registra a liberação de uma nota acima do limite de crédito,
+// com quem aprovou e os valores no momento da aprovação
+type CreditOverride struct {
+	ApprovedBy   string    `json:"approved_by"`
+	Reason       string    `json:"reason"`
+	Limit        Money     `json:"limit"`
+	Outstanding  Money     `json:"outstanding"`
+	InvoiceTotal Money     `json:"invoice_total"`
+	ApprovedAt   time.Time `json:"approved_at"`
+}
+
+// Erros de domínio do limite de crédito
+var (
+	ErrCreditLimitExceeded        = errors.New("limite de crédito do cliente excedido")
+	ErrInvalidCreditLimit         = errors.New("limite de crédito não pode ser negativo")
+	ErrCreditOverrideUser         = errors.New("liberação de crédito exige usuário identificado")
+	ErrCreditOverrideReason       = errors.New("liberação de crédito exige justificativa de 10 a 500 caracteres")
+	ErrCreditOverrideUnauthorized = errors.New("usuário não autorizado a liberar crédito")
+)
+
+// CreditLimitError é a recusa de uma nota por limite de crédito, com os
+// valores que a motivaram
+type CreditLimitError struct {
+	Check CreditCheck
+}
+
+func (e *CreditLimitError) Error() string {
+	return fmt.Sprintf("%s: limite %s, em aberto %s, nota %s, excedente %s",
+		ErrCreditLimitExceeded, e.Check.Limit, e.Check.Outstanding, e.Check.InvoiceTotal, e.Check.Excess)
+}
+
+// Unwrap permite identificar a recusa com errors.Is(err, ErrCreditLimitExceeded)
+func (e *CreditLimitError) Unwrap() error {
+	return ErrCreditLimitExceeded
+}
+
+// NewCreditCheck monta a verificação do limite do cliente para uma nota
+func NewCreditCheck(customerID string, limit, outstanding, total Money) CreditCheck {
+	check := CreditCheck{
+		CustomerID:   customerID,
+		Limit:        limit,
+		Outstanding:  outstanding,
+		InvoiceTotal: total,
+		Available:    limit - outstanding,
+	}
+	if excess := outstanding + total - limit; excess > 0 {
+		check.Excess = excess
+	}
+	return check
+}
+
+// Exceeded indica se a nota ultrapassa o limite de crédito
+func (c CreditCheck) Exceeded() bool {
+	return c.Excess > 0
+}
+
+// CustomerOutstanding soma o saldo em aberto, sem encargos, das parcelas das
+// notas emitidas para o cliente
+func CustomerOutstanding(invoices []*Invoice, customerID string) Money {
+	var total Money
+	for _, invoice := range invoices {
+		if invoice.CustomerID != customerID {
+			continue
+		}
+		for _, receivable := range invoice.Receivables() {
+			total += receivable.Outstanding
+		}
+	}
+	return total
+}
+
+// CanOverride indica se o usuário pode liberar notas acima do limite
+func (p CreditPolicy) CanOverride(user string) bool {
+	for _, approver := range p.Approvers {
+		if strings.EqualFold(approver, user) {
+			return true
+		}
+	}
+	return false
+}
+
+// Approve registra a liberação da nota pelo usuário, que deve estar entre os
+// aprovadores e justificar a liberação
+func (p CreditPolicy) Approve(user, reason string, check CreditCheck, now time.Time) (*CreditOverride, error) {
+	reason = strings.TrimSpace(reason)
+	if user == "" {
+		return nil, ErrCreditOverrideUser
+	}
+	if utf8.RuneCountInString(reason) < 10 || utf8.RuneCountInString(reason) > 500 {
+		return nil, ErrCreditOverrideReason
+	}
+	if !p.CanOverride(user) {
+		return nil, ErrCreditOverrideUnauthorized
+	}
+	return &CreditOverride{
+		ApprovedBy:   user,
+		Reason:       reason,
+		Limit:        check.Limit,
+		Outstanding:  check.Outstanding,
+		InvoiceTotal: check.InvoiceTotal,
+		ApprovedAt:   now,
+	}, nil
+}
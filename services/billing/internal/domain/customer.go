@@ -0,0 +1,216 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// TaxRegime representa o regime tributário (código CRT da NF-e)
+type TaxRegime int
+
+const (
+	TaxRegimeNotInformed   TaxRegime = 0 // Não informado (ex: pessoa física)
+	TaxRegimeSimples       TaxRegime = 1 // Simples Nacional
+	TaxRegimeSimplesExcess TaxRegime = 2 // Simples Nacional - excesso de sublimite de receita bruta
+	TaxRegimeNormal        TaxRegime = 3 // Regime Normal
+	TaxRegimeMEI           TaxRegime = 4 // Simples Nacional - Microempreendedor Individual
+)
+
+// IsValid verifica se o regime tributário é conhecido
+func (t TaxRegime) IsValid() bool {
+	return t >= TaxRegimeNotInformed && t <= TaxRegimeMEI
+}
+
+// IsSimples indica se o regime usa CSOSN em vez de CST para o ICMS
+// (Simples Nacional e MEI; o excesso de sublimite segue o regime normal)
+func (t TaxRegime) IsSimples() bool {
+	return t == TaxRegimeSimples || t == TaxRegimeMEI
+}
+
+// StateRegistrationExempt é o valor usado para contribuintes isentos de inscrição estadual
+const StateRegistrationExempt = "ISENTO"
+
+// IEIndicator indica a situação do destinatário perante o ICMS (indIEDest da NF-e)
+type IEIndicator int
+
+const (
+	IEContributor    IEIndicator = 1 // Contribuinte ICMS
+	IEExempt         IEIndicator = 2 // Contribuinte isento de inscrição
+	IENonContributor IEIndicator = 9 // Não contribuinte
+)
+
+// ufCodes relaciona as unidades federativas aceitas aos seus códigos IBGE
+var ufCodes = map[string]string{
+	"RO": "11", "AC": "12", "AM": "13", "RR": "14", "PA": "15", "AP": "16", "TO": "17",
+	"MA": "21", "PI": "22", "CE": "23", "RN": "24", "PB": "25", "PE": "26", "AL": "27",
+	"SE": "28", "BA": "29", "MG": "31", "ES": "32", "RJ": "33", "SP": "35", "PR": "41",
+	"SC": "42", "RS": "43", "MS": "50", "MT": "51", "GO": "52", "DF": "53",
+}
+
+// IsValidUF verifica se a sigla é de uma unidade federativa brasileira
+func IsValidUF(uf string) bool {
+	_, ok := ufCodes[uf]
+	return ok
+}
+
+// UFCode retorna o código IBGE de duas posições da unidade federativa
+func UFCode(uf string) (string, bool) {
+	code, ok := ufCodes[uf]
+	return code, ok
+}
+
+// Address representa um endereço fiscal
+type Address struct {
+	Street     string `json:"street"`               // Logradouro
+	Number     string `json:"number"`               // Número (ou "SN")
+	Complement string `json:"complement,omitempty"` // Complemento
+	District   string `json:"district"`             // Bairro
+	CityCode   string `json:"city_code"`            // Código IBGE do município (7 dígitos)
+	City       string `json:"city"`                 // Nome do município
+	UF         string `json:"uf"`                   // Sigla da unidade federativa
+	CEP        string `json:"cep"`                  // CEP (8 dígitos)
+	Phone      string `json:"phone,omitempty"`
+}
+
+// Normalize padroniza CEP, UF e código do município
+func (a *Address) Normalize() {
+	a.CEP = onlyDigits(a.CEP)
+	a.CityCode = onlyDigits(a.CityCode)
+	a.UF = strings.ToUpper(strings.TrimSpace(a.UF))
+	a.Phone = onlyDigits(a.Phone)
+}
+
+// Validate valida os campos obrigatórios do endereço
+func (a *Address) Validate() error {
+	if strings.TrimSpace(a.Street) == "" || strings.TrimSpace(a.Number) == "" ||
+		strings.TrimSpace(a.District) == "" || strings.TrimSpace(a.City) == "" {
+		return ErrInvalidAddress
+	}
+	if !isDigits(a.CityCode, 7) {
+		return ErrInvalidCityCode
+	}
+	if !IsValidUF(a.UF) {
+		return ErrInvalidUF
+	}
+	if !isDigits(a.CEP, 8) {
+		return ErrInvalidCEP
+	}
+	return nil
+}
+
+// Customer representa o destinatário das notas fiscais
+type Customer struct {
+	ID                string       `json:"id"`
+	Name              string       `json:"name"`                         // Nome ou razão social
+	Document          string       `json:"document"`                     // CPF ou CNPJ (sem pontuação)
+	DocumentType      DocumentType `json:"document_type"`                // CPF ou CNPJ
+	StateRegistration string       `json:"state_registration,omitempty"` // Inscrição estadual ou ISENTO
+	TaxRegime         TaxRegime    `json:"tax_regime"`
+	Email             string       `json:"email,omitempty"`
+	Address           Address      `json:"address"`
+	CreditLimit       *Money       `json:"credit_limit,omitempty"` // Limite para recebíveis em aberto; sem limite se ausente
+	CreatedAt         time.Time    `json:"created_at"`
+	UpdatedAt         time.Time    `json:"updated_at"`
+}
+
+// Erros de domínio do cliente
+var (
+	ErrCustomerNotFound         = errors.New("cliente não encontrado")
+	ErrInvalidCustomer          = errors.New("cliente inválido")
+	ErrDuplicateDocument        = errors.New("já existe cliente com este documento")
+	ErrInvalidStateRegistration = errors.New("inscrição estadual inválida")
+	ErrInvalidTaxRegime         = errors.New("regime tributário inválido")
+	ErrInvalidAddress           = errors.New("endereço incompleto")
+	ErrInvalidCityCode          = errors.New("código do município deve conter 7 dígitos")
+	ErrInvalidUF                = errors.New("UF inválida")
+	ErrInvalidCEP               = errors.New("CEP deve conter 8 dígitos")
+	ErrCustomerRequired         = errors.New("cliente (destinatário) é obrigatório")
+)
+
+// Normalize padroniza documento, inscrição estadual e endereço
+func (c *Customer) Normalize() {
+	c.Name = strings.TrimSpace(c.Name)
+	c.Document = NormalizeDocument(c.Document)
+	c.StateRegistration = normalizeStateRegistration(c.StateRegistration)
+	c.Address.Normalize()
+}
+
+// Validate valida os dados do cliente, incluindo os dígitos do CPF/CNPJ
+func (c *Customer) Validate() error {
+	if c.Name == "" {
+		return ErrInvalidCustomer
+	}
+	_, documentType, err := ParseDocument(c.Document)
+	if err != nil {
+		return err
+	}
+	c.DocumentType = documentType
+
+	if err := validateStateRegistration(c.StateRegistration); err != nil {
+		return err
+	}
+	// Pessoa física não possui inscrição estadual como contribuinte
+	if documentType == DocumentCPF && c.StateRegistration == StateRegistrationExempt {
+		return ErrInvalidStateRegistration
+	}
+	if !c.TaxRegime.IsValid() {
+		return ErrInvalidTaxRegime
+	}
+	if c.CreditLimit != nil && *c.CreditLimit < 0 {
+		return ErrInvalidCreditLimit
+	}
+	return c.Address.Validate()
+}
+
+// IEIndicator deriva o indicador de IE do destinatário a partir da inscrição estadual
+func (c *Customer) IEIndicator() IEIndicator {
+	switch c.StateRegistration {
+	case "":
+		return IENonContributor
+	case StateRegistrationExempt:
+		return IEExempt
+	default:
+		return IEContributor
+	}
+}
+
+// CustomerRepository define o contrato para persistência de clientes
+type CustomerRepository interface {
+	Create(customer *Customer) error
+	FindByID(id string) (*Customer, error)
+	FindByDocument(document string) (*Customer, error)
+	FindAll() ([]*Customer, error)
+	Update(customer *Customer) error
+	Delete(id string) error
+}
+
+// normalizeStateRegistration remove pontuação da IE, preservando o valor ISENTO
+func normalizeStateRegistration(ie string) string {
+	ie = strings.ToUpper(strings.TrimSpace(ie))
+	if ie == StateRegistrationExempt {
+		return ie
+	}
+	return onlyDigits(ie)
+}
+
+// validateStateRegistration valida o formato da IE (2 a 14 dígitos, ou ISENTO)
+func validateStateRegistration(ie string) error {
+	if ie == "" || ie == StateRegistrationExempt {
+		return nil
+	}
+	if len(ie) < 2 || len(ie) > 14 || onlyDigits(ie) != ie {
+		return ErrInvalidStateRegistration
+	}
+	return nil
+}
+
+func onlyDigits(value string) string {
+	var b strings.Builder
+	for _, r := range value {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
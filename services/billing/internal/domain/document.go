@@ -0,0 +1,135 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+// DocumentType identifica o tipo de documento de uma pessoa (CPF ou CNPJ)
+type DocumentType string
+
+const (
+	DocumentCPF  DocumentType = "CPF"
+	DocumentCNPJ DocumentType = "CNPJ"
+)
+
+// Erros de documentos
+var (
+	ErrInvalidCPF      = errors.New("CPF inválido")
+	ErrInvalidCNPJ     = errors.New("CNPJ inválido")
+	ErrInvalidDocument = errors.New("documento deve ser um CPF ou CNPJ válido")
+)
+
+// NormalizeDocument remove pontuação do documento e converte letras para
+// maiúsculas (o CNPJ alfanumérico aceita letras nas 12 primeiras posições)
+func NormalizeDocument(document string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(document) {
+		if unicode.IsDigit(r) || (r >= 'A' && r <= 'Z') {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// ParseDocument normaliza e valida o documento, identificando se é CPF ou CNPJ
+func ParseDocument(document string) (string, DocumentType, error) {
+	normalized := NormalizeDocument(document)
+	switch len(normalized) {
+	case 11:
+		if err := ValidateCPF(normalized); err != nil {
+			return "", "", err
+		}
+		return normalized, DocumentCPF, nil
+	case 14:
+		if err := ValidateCNPJ(normalized); err != nil {
+			return "", "", err
+		}
+		return normalized, DocumentCNPJ, nil
+	default:
+		return "", "", ErrInvalidDocument
+	}
+}
+
+// ValidateCPF valida os dígitos verificadores de um CPF (somente números)
+func ValidateCPF(cpf string) error {
+	cpf = NormalizeDocument(cpf)
+	if !isDigits(cpf, 11) || allSameChar(cpf) {
+		return ErrInvalidCPF
+	}
+
+	for _, length := range []int{9, 10} {
+		sum := 0
+		for i := 0; i < length; i++ {
+			sum += int(cpf[i]-'0') * (length + 1 - i)
+		}
+		digit := (sum * 10) % 11
+		if digit == 10 {
+			digit = 0
+		}
+		if digit != int(cpf[length]-'0') {
+			return ErrInvalidCPF
+		}
+	}
+	return nil
+}
+
+// ValidateCNPJ valida os dígitos verificadores de um CNPJ, numérico ou
+// alfanumérico (IN RFB 2.229/2024). Cada caractere vale seu código ASCII
+// menos 48, o que mantém o cálculo idêntico para CNPJs numéricos
+func ValidateCNPJ(cnpj string) error {
+	cnpj = NormalizeDocument(cnpj)
+	if len(cnpj) != 14 || allSameChar(cnpj) {
+		return ErrInvalidCNPJ
+	}
+	for i, r := range cnpj {
+		isAlnum := unicode.IsDigit(r) || (r >= 'A' && r <= 'Z')
+		if !isAlnum || (i >= 12 && !unicode.IsDigit(r)) {
+			return ErrInvalidCNPJ
+		}
+	}
+
+	if CNPJCheckDigits(cnpj[:12]) != cnpj[12:] {
+		return ErrInvalidCNPJ
+	}
+	return nil
+}
+
+// CNPJCheckDigits calcula os dois dígitos verificadores da base (12 caracteres) do CNPJ
+func CNPJCheckDigits(base string) string {
+	digits := base
+	for len(digits) < 14 {
+		sum := 0
+		weight := 2
+		for i := len(digits) - 1; i >= 0; i-- {
+			sum += int(digits[i]-'0') * weight
+			weight++
+			if weight > 9 {
+				weight = 2
+			}
+		}
+		digit := 0
+		if remainder := sum % 11; remainder >= 2 {
+			digit = 11 - remainder
+		}
+		digits += string(rune('0' + digit))
+	}
+	return digits[12:]
+}
+
+func isDigits(value string, length int) bool {
+	if len(value) != length {
+		return false
+	}
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func allSameChar(value string) bool {
+	return strings.Count(value, value[:1]) == len(value)
+}
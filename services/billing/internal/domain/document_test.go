@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+// Documentos com dígitos verificadores publicados: os exemplos de CPF e CNPJ
+// usados pela Receita Federal e o exemplo de CNPJ alfanumérico da
+// IN RFB 2.229/2024 (12.ABC.345/01DE-35); 00.000.000/0001-91 é o CNPJ do
+// Banco do Brasil
+func TestParseDocument(t *testing.T) {
+	tests := []struct {
+		name         string
+		document     string
+		normalized   string
+		documentType DocumentType
+		err          error
+	}{
+		{name: "CPF formatado", document: "529.982.247-25", normalized: "52998224725", documentType: DocumentCPF},
+		{name: "CPF sem pontuação", document: "11144477735", normalized: "11144477735", documentType: DocumentCPF},
+		{name: "CPF com primeiro dígito zero", document: "123.456.789-09", normalized: "12345678909", documentType: DocumentCPF},
+		{name: "CPF com dígito errado", document: "529.982.247-24", err: ErrInvalidCPF},
+		{name: "CPF com dígitos repetidos", document: "111.111.111-11", err: ErrInvalidCPF},
+		{name: "CNPJ formatado", document: "11.222.333/0001-81", normalized: "11222333000181", documentType: DocumentCNPJ},
+		{name: "CNPJ sem pontuação", document: "11444777000161", normalized: "11444777000161", documentType: DocumentCNPJ},
+		{name: "CNPJ alfanumérico", document: "12.ABC.345/01DE-35", normalized: "12ABC34501DE35", documentType: DocumentCNPJ},
+		{name: "CNPJ alfanumérico em minúsculas", document: "12.abc.345/01de-35", normalized: "12ABC34501DE35", documentType: DocumentCNPJ},
+		{name: "CNPJ com dígito errado", document: "11.222.333/0001-80", err: ErrInvalidCNPJ},
+		{name: "CNPJ alfanumérico com dígito errado", document: "12.ABC.345/01DE-36", err: ErrInvalidCNPJ},
+		{name: "CNPJ com letra no dígito verificador", document: "12.ABC.345/01DE-3A", err: ErrInvalidCNPJ},
+		{name: "CNPJ com dígitos repetidos", document: "00.000.000/0000-00", err: ErrInvalidCNPJ},
+		{name: "tamanho inválido", document: "1234567890", err: ErrInvalidDocument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			normalized, documentType, err := ParseDocument(tt.document)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("ParseDocument(%q) erro = %v, esperado %v", tt.document, err, tt.err)
+			}
+			if normalized != tt.normalized || documentType != tt.documentType {
+				t.Errorf("ParseDocument(%q) = %q %s, esperado %q %s", tt.document, normalized, documentType, tt.normalized, tt.documentType)
+			}
+		})
+	}
+}
+
+func TestCNPJCheckDigits(t *testing.T) {
+	tests := []struct {
+		base     string
+		expected string
+	}{
+		{base: "112223330001", expected: "81"},
+		{base: "114447770001", expected: "61"},
+		{base: "12ABC34501DE", expected: "35"},
+		{base: "000000000001", expected: "91"},
+	}
+
+	for _, tt := range tests {
+		if got := CNPJCheckDigits(tt.base); got != tt.expected {
+			t.Errorf("CNPJCheckDigits(%q) = %q, esperado %q", tt.base, got, tt.expected)
+		}
+	}
+}
@@ -9,37 +9,120 @@ import (
 type InvoiceStatus string
 
 const (
-	StatusOpen   InvoiceStatus = "ABERTA"
-	StatusClosed InvoiceStatus = "FECHADA"
+	StatusOpen             InvoiceStatus = "ABERTA"
+	StatusClosed           InvoiceStatus = "FECHADA"
+	StatusAwaitingApproval InvoiceStatus = "AGUARDANDO_APROVACAO" // Retida pelas regras de aprovação
+	StatusDisapproved      InvoiceStatus = "REPROVADA"            // Recusada na aprovação; não pode ser impressa
 )
 
 // Invoice representa uma nota fiscal
 type Invoice struct {
-	ID        string          `json:"id"`
-	Number    int             `json:"number"`    // Numeração sequencial
-	Status    InvoiceStatus   `json:"status"`    // ABERTA ou FECHADA
-	Items     []InvoiceItem   `json:"items"`     // Produtos da nota
-	CreatedAt time.Time       `json:"created_at"`
-	UpdatedAt time.Time       `json:"updated_at"`
-	ClosedAt  *time.Time      `json:"closed_at,omitempty"` // Data de fechamento
+	ID             string            `json:"id"`
+	Number         int               `json:"number"`                   // Numeração sequencial
+	Model          string            `json:"model"`                    // 55 (NF-e), 65 (NFC-e) ou NFSE (NFS-e)
+	Series         int               `json:"series"`                   // Série do emitente no modelo
+	Status         InvoiceStatus     `json:"status"`                   // ABERTA, FECHADA, AGUARDANDO_APROVACAO ou REPROVADA
+	AccessKey      string            `json:"access_key,omitempty"`     // Chave de acesso (44 posições), gerada no fechamento
+	Authorization  *Authorization    `json:"authorization,omitempty"`  // Retorno da SEFAZ
+	Contingency    *Contingency      `json:"contingency,omitempty"`    // Preenchido quando emitida em contingência
+	ReturnOf       *InvoiceReference `json:"return_of,omitempty"`      // Nota original, nas notas de devolução
+	Returns        []string          `json:"returns,omitempty"`        // Notas de devolução emitidas para esta nota
+	SalesOrderID   string            `json:"sales_order_id,omitempty"` // Pedido de venda que gerou a nota
+	Backorders     []string          `json:"backorders,omitempty"`     // Pendências das quantidades sem estoque na criação
+	BackorderID    string            `json:"backorder_id,omitempty"`   // Pendência de entrega faturada pela nota
+	Batch          *BatchReference   `json:"batch,omitempty"`          // Grupo das notas divididas pelo limite de itens
+	IssuerID       string            `json:"issuer_id"`
+	Issuer         *Issuer           `json:"issuer,omitempty"` // Snapshot do emitente no fechamento
+	CustomerID     string            `json:"customer_id"`
+	Customer       *Customer         `json:"customer,omitempty"`        // Snapshot do destinatário na criação (opcional na NFC-e)
+	Items          []InvoiceItem     `json:"items"`                     // Mercadorias ou serviços da nota
+	Charges        Charges           `json:"charges"`                   // Frete, seguro e outras despesas, rateados entre os itens
+	Transport      *Transport        `json:"transport,omitempty"`       // Transportadora, veículo e volumes (sem transporte se ausente)
+	Totals         InvoiceTotals     `json:"totals"`                    // Totais de produtos e tributos
+	Payment        *PaymentTerms     `json:"payment,omitempty"`         // Condição de pagamento (sem pagamento se ausente)
+	Installments   []Installment     `json:"installments,omitempty"`    // Parcelas (duplicatas), com vencimentos a partir da emissão
+	Payments       []PaymentRecord   `json:"payments,omitempty"`        // Recebimentos registrados contra as parcelas
+	CreditOverride *CreditOverride   `json:"credit_override,omitempty"` // Liberação acima do limite de crédito do cliente
+	Approval       *InvoiceApproval  `json:"approval,omitempty"`        // Regras que retiveram a nota e a decisão do aprovador
+	CreatedBy      string            `json:"created_by,omitempty"`      // Usuário que criou a nota
+	CreatedAt      time.Time         `json:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at"`
+	ClosedAt       *time.Time        `json:"closed_at,omitempty"` // Data de fechamento
 }
 
-// InvoiceItem representa um item (produto) na nota fiscal
+// InvoiceItem representa um item (mercadoria ou serviço) na nota fiscal
 type InvoiceItem struct {
-	ProductID   string `json:"product_id"`
-	ProductCode string `json:"product_code"`
-	Description string `json:"description"`
-	Quantity    int    `json:"quantity"`
+	Type            ItemType  `json:"type"`                 // MERCADORIA (padrão) ou SERVICO
+	ProductID       string    `json:"product_id,omitempty"` // Mercadorias: produto do Stock Service
+	ServiceID       string    `json:"service_id,omitempty"` // Serviços: item do catálogo de serviços
+	ProductCode     string    `json:"product_code"`
+	Description     string    `json:"description"`
+	Quantity        int       `json:"quantity"`
+	NCM             string    `json:"ncm,omitempty"` // Classificação fiscal copiada do produto
+	CEST            string    `json:"cest,omitempty"`
+	Origin          int       `json:"origin"`
+	ServiceListItem string    `json:"service_list_item,omitempty"` // Subitem da LC 116/2003 copiado do serviço
+	MunicipalCode   string    `json:"municipal_code,omitempty"`    // Código de tributação municipal copiado do serviço
+	ISSRate         float64   `json:"iss_rate,omitempty"`          // Alíquota do ISS copiada do serviço
+	Unit            string    `json:"unit"`
+	UnitWeight      float64   `json:"unit_weight,omitempty"` // Peso líquido unitário (kg) copiado do produto
+	CFOP            string    `json:"cfop,omitempty"`        // Definido no fechamento conforme UF do destinatário
+	UnitPrice       Money     `json:"unit_price"`
+	Total           Money     `json:"total"`              // Quantidade x preço unitário
+	Discount        Money     `json:"discount,omitempty"` // Desconto concedido na linha (vDesc)
+	Charges         Charges   `json:"charges"`            // Parcela rateada das despesas do cabeçalho
+	Taxes           ItemTaxes `json:"taxes"`
+
+	OriginalItem     int `json:"original_item,omitempty"`     // Devoluções: número do item (nItem) na nota original
+	ReturnedQuantity int `json:"returned_quantity,omitempty"` // Quantidade já devolvida por notas de devolução fechadas
+	OrderLine        int `json:"order_line,omitempty"`        // Notas de pedido: número da linha no pedido de venda
+}
+
+// IsService indica se o item é um serviço
+func (item InvoiceItem) IsService() bool {
+	return item.Type == ItemService
+}
+
+// DiscountPercent retorna o desconto como percentual do valor da linha
+func (item InvoiceItem) DiscountPercent() float64 {
+	if item.Total <= 0 {
+		return 0
+	}
+	return float64(item.Discount) * 100 / float64(item.Total)
 }
 
 // Erros de domínio
 var (
-	ErrInvoiceNotFound       = errors.New("nota fiscal não encontrada")
-	ErrInvalidInvoice        = errors.New("nota fiscal inválida")
-	ErrInvoiceAlreadyClosed  = errors.New("nota fiscal já está fechada")
-	ErrInvoiceNoItems        = errors.New("nota fiscal deve ter ao menos um item")
-	ErrInvalidQuantity       = errors.New("quantidade inválida")
+	ErrInvoiceNotFound        = errors.New("nota fiscal não encontrada")
+	ErrInvalidInvoice         = errors.New("nota fiscal inválida")
+	ErrInvoiceAlreadyClosed   = errors.New("nota fiscal já está fechada")
+	ErrInvoiceNoItems         = errors.New("nota fiscal deve ter ao menos um item")
+	ErrInvalidQuantity        = errors.New("quantidade inválida")
 	ErrCannotPrintOpenInvoice = errors.New("não é possível imprimir nota em status diferente de ABERTA")
+	ErrInvalidUnitPrice       = errors.New("preço unitário inválido")
+	ErrInvoiceNotClosed       = errors.New("nota fiscal ainda não foi fechada")
+	ErrInvalidModel           = errors.New("modelo de documento deve ser 55 (NF-e), 65 (NFC-e) ou NFSE (NFS-e)")
+	ErrConsumerInterstate     = errors.New("NFC-e não admite destinatário de outra UF")
+	ErrConsumerContingency    = errors.New("NFC-e não pode ser emitida na contingência off-line de NF-e")
+	ErrInvalidItemType        = errors.New("tipo do item deve ser MERCADORIA ou SERVICO")
+	ErrMixedItems             = errors.New("nota não pode misturar mercadorias e serviços; emita notas separadas")
+	ErrServiceModel           = errors.New("serviços são emitidos em NFS-e (modelo NFSE), que não admite mercadorias")
+	ErrServiceCodeMismatch    = errors.New("NFS-e admite um único serviço (mesmo subitem da lista, código municipal e alíquota)")
+	ErrServiceInvoice         = errors.New("nota de serviço (NFS-e) não gera NF-e nem DANFE")
+	ErrNotServiceInvoice      = errors.New("somente notas de serviço geram NFS-e")
+	ErrInvalidDiscount        = errors.New("desconto do item deve estar entre zero e o valor da linha")
+	ErrServiceDiscount        = errors.New("NFS-e não admite desconto nos itens")
+	ErrInsufficientStock      = errors.New("saldo em estoque insuficiente para imprimir a nota")
+)
+
+// ModelNFSe identifica as notas de serviço, emitidas como NFS-e no padrão
+// nacional. Não compõe chave de acesso
+const ModelNFSe = "NFSE"
+
+// CFOPs de venda de mercadoria adquirida de terceiros
+const (
+	CFOPSaleInState    = "5102" // Operação dentro do estado
+	CFOPSaleInterstate = "6102" // Operação interestadual
 )
 
 // Validate valida os dados da nota fiscal
@@ -47,36 +130,237 @@ func (i *Invoice) Validate() error {
 	if i.Number <= 0 {
 		return ErrInvalidInvoice
 	}
+	return i.ValidateDraft()
+}
+
+// ValidateDraft valida os dados da nota ainda sem número. A numeração é
+// atribuída somente na gravação, para que notas recusadas na validação, no
+// crédito ou na aprovação não deixem lacunas na sequência
+func (i *Invoice) ValidateDraft() error {
+	if i.IssuerID == "" {
+		return ErrIssuerRequired
+	}
+	if i.Model != ModelNFe && i.Model != ModelNFCe && i.Model != ModelNFSe {
+		return ErrInvalidModel
+	}
+	// A NFC-e admite consumidor não identificado
+	if (i.CustomerID == "" || i.Customer == nil) && !(i.IsConsumer() && i.CustomerID == "") {
+		return ErrCustomerRequired
+	}
 	if len(i.Items) == 0 {
 		return ErrInvoiceNoItems
 	}
+	if err := i.Charges.Validate(); err != nil {
+		return err
+	}
+	if i.IsService() && !i.Charges.IsZero() {
+		return ErrServiceCharges
+	}
+	if t := i.Transport; t != nil {
+		if i.IsService() {
+			return ErrServiceTransport
+		}
+		if err := t.Validate(); err != nil {
+			return err
+		}
+		// Na NFC-e a mercadoria é entregue no ato, sem transporte
+		if i.IsConsumer() && t.FreightMode != FreightNoTransport {
+			return ErrConsumerFreight
+		}
+	}
+	if err := i.validatePayment(); err != nil {
+		return err
+	}
 	for _, item := range i.Items {
-		if item.ProductID == "" || item.Quantity <= 0 {
+		if item.Type != ItemGoods && item.Type != ItemService {
+			return ErrInvalidItemType
+		}
+		if (item.IsService() && item.ServiceID == "") || (!item.IsService() && item.ProductID == "") || item.Quantity <= 0 {
 			return ErrInvalidQuantity
 		}
+		if item.UnitPrice < 0 {
+			return ErrInvalidUnitPrice
+		}
+		if item.Discount < 0 || item.Discount > item.Total {
+			return ErrInvalidDiscount
+		}
+		if item.IsService() && item.Discount != 0 {
+			return ErrServiceDiscount
+		}
+		// Serviços só constam em NFS-e, e a NFS-e só admite serviços
+		if item.IsService() != i.IsService() {
+			return ErrServiceModel
+		}
+		if item.IsService() && !sameService(item, i.Items[0]) {
+			return ErrServiceCodeMismatch
+		}
+		// Itens de devolução apontam para o item da nota original
+		if i.IsReturn() && item.OriginalItem <= 0 {
+			return ErrInvalidReturnItem
+		}
 	}
 	return nil
 }
 
+// sameService indica se dois itens de serviço têm a mesma classificação
+// fiscal, como exige a NFS-e, que descreve um único serviço
+func sameService(a, b InvoiceItem) bool {
+	return a.ServiceListItem == b.ServiceListItem && a.MunicipalCode == b.MunicipalCode && a.ISSRate == b.ISSRate
+}
+
+// ApplyTaxes calcula o valor e os tributos de cada item, conforme o regime
+// tributário do emitente, e recalcula os totais. As despesas acessórias são
+// rateadas entre os itens e compõem a base de cálculo das mercadorias, da qual
+// o desconto da linha é abatido
+func (i *Invoice) ApplyTaxes(config TaxConfig, issuedAt time.Time, regime TaxRegime) {
+	for idx := range i.Items {
+		item := &i.Items[idx]
+		item.Total = item.UnitPrice.MulQuantity(item.Quantity)
+	}
+	i.ApportionCharges()
+
+	for idx := range i.Items {
+		item := &i.Items[idx]
+		if item.IsService() {
+			item.Taxes = config.CalculateServiceTaxes(item.Total, item.ISSRate, issuedAt, regime)
+		} else {
+			item.Taxes = config.CalculateItemTaxes(item.Total+item.Charges.Total()-item.Discount, issuedAt, regime)
+		}
+	}
+	i.CalculateTotals()
+}
+
+// CalculateTotals soma os valores e tributos dos itens nos totais da nota
+func (i *Invoice) CalculateTotals() {
+	totals := InvoiceTotals{}
+	for _, item := range i.Items {
+		if item.IsService() {
+			totals.Services += item.Total
+		} else {
+			totals.Products += item.Total
+		}
+		totals.Freight += item.Charges.Freight
+		totals.Insurance += item.Charges.Insurance
+		totals.Other += item.Charges.Other
+		totals.Discount += item.Discount
+		if iss := item.Taxes.ISS; iss != nil {
+			if totals.ISS == nil {
+				totals.ISS = &ISSTotals{}
+			}
+			totals.ISS.Base += iss.Base
+			totals.ISS.Value += iss.Value
+		}
+		totals.Legacy.ICMSBase += item.Taxes.Legacy.ICMSBase
+		totals.Legacy.ICMSValue += item.Taxes.Legacy.ICMSValue
+		totals.Legacy.PISValue += item.Taxes.Legacy.PISValue
+		totals.Legacy.COFINSValue += item.Taxes.Legacy.COFINSValue
+
+		if ibscbs := item.Taxes.IBSCBS; ibscbs != nil {
+			if totals.IBSCBS == nil {
+				totals.IBSCBS = &IBSCBSTotals{}
+			}
+			totals.IBSCBS.Base += ibscbs.Base
+			totals.IBSCBS.IBSUFValue += ibscbs.IBSUFValue
+			totals.IBSCBS.IBSMunValue += ibscbs.IBSMunValue
+			totals.IBSCBS.IBSValue += ibscbs.IBSValue()
+			totals.IBSCBS.CBSValue += ibscbs.CBSValue
+		}
+	}
+	totals.Total = totals.Products + totals.Services + totals.Freight + totals.Insurance + totals.Other - totals.Discount
+	i.Totals = totals
+}
+
 // CanBePrinted verifica se a nota pode ser impressa (fechada)
 func (i *Invoice) CanBePrinted() bool {
 	return i.Status == StatusOpen
 }
 
-// Close fecha a nota fiscal (equivalente a "imprimir")
-func (i *Invoice) Close() error {
+// Close fecha a nota fiscal (equivalente a "imprimir"), registrando uma cópia
+// dos dados do emitente vigentes no momento do fechamento e a chave de acesso.
+// Notas com contingência registrada recebem a forma de emissão correspondente.
+// Notas de serviço (NFS-e) não têm chave de acesso nem CFOP, e as devoluções
+// recebem o CFOP de entrada. As parcelas são reprogramadas a partir da emissão
+func (i *Invoice) Close(issuer *Issuer) error {
 	if !i.CanBePrinted() {
 		return ErrCannotPrintOpenInvoice
 	}
-	
+	if issuer == nil || issuer.ID != i.IssuerID {
+		return ErrIssuerRequired
+	}
+	if i.IsConsumer() && i.Contingency != nil {
+		return ErrConsumerContingency
+	}
+	if i.IsService() && i.Contingency != nil {
+		return ErrServiceInvoice
+	}
+
 	now := time.Now()
+	if !i.IsService() {
+		code, err := NewNumericCode(i.Number)
+		if err != nil {
+			return err
+		}
+		key, err := NewAccessKey(issuer.Address.UF, now, issuer.CNPJ, i.Model, i.Series, i.Number, i.EmissionType(), code)
+		if err != nil {
+			return err
+		}
+		i.AccessKey = key.String()
+	}
+
+	snapshot := *issuer
+	i.Issuer = &snapshot
+
+	if !i.IsService() {
+		cfop := CFOPSaleInState
+		switch {
+		case i.IsReturn() && i.IsInterstate():
+			cfop = CFOPReturnInterstate
+		case i.IsReturn():
+			cfop = CFOPReturnInState
+		case i.IsInterstate():
+			cfop = CFOPSaleInterstate
+		}
+		for idx := range i.Items {
+			i.Items[idx].CFOP = cfop
+		}
+	}
+
+	// Os vencimentos das parcelas contam a partir da data de emissão
+	i.ScheduleInstallments(now)
+
 	i.Status = StatusClosed
 	i.ClosedAt = &now
 	i.UpdatedAt = now
-	
+
 	return nil
 }
 
+// IsInterstate indica se emitente e destinatário estão em estados diferentes
+func (i *Invoice) IsInterstate() bool {
+	return i.Issuer != nil && i.Customer != nil && i.Issuer.Address.UF != i.Customer.Address.UF
+}
+
+// IsConsumer indica se a nota é uma NFC-e (modelo 65), emitida ao consumidor final
+func (i *Invoice) IsConsumer() bool {
+	return i.Model == ModelNFCe
+}
+
+// IsService indica se a nota é de serviços (NFS-e)
+func (i *Invoice) IsService() bool {
+	return i.Model == ModelNFSe
+}
+
+// GoodsItems retorna os itens de mercadoria, que movimentam estoque
+func (i *Invoice) GoodsItems() []InvoiceItem {
+	goods := make([]InvoiceItem, 0, len(i.Items))
+	for _, item := range i.Items {
+		if !item.IsService() {
+			goods = append(goods, item)
+		}
+	}
+	return goods
+}
+
 // IsOpen verifica se a nota está aberta
 func (i *Invoice) IsOpen() bool {
 	return i.Status == StatusOpen
@@ -87,27 +371,44 @@ func (i *Invoice) IsClosed() bool {
 	return i.Status == StatusClosed
 }
 
+// IsAwaitingApproval verifica se a nota está retida para aprovação
+func (i *Invoice) IsAwaitingApproval() bool {
+	return i.Status == StatusAwaitingApproval
+}
+
+// IsDisapproved verifica se a nota foi reprovada
+func (i *Invoice) IsDisapproved() bool {
+	return i.Status == StatusDisapproved
+}
+
 // InvoiceRepository define o contrato para persistência de notas fiscais
 type InvoiceRepository interface {
 	Create(invoice *Invoice) error
 	FindByID(id string) (*Invoice, error)
+	FindByAccessKey(key string) (*Invoice, error)
 	FindAll() ([]*Invoice, error)
 	Update(invoice *Invoice) error
-	GetNextNumber() (int, error) // Retorna o próximo número sequencial
+	GetNextNumber(issuerID, model string, series int) (int, error) // Retorna o próximo número sequencial do emitente/modelo/série
 }
 
 // StockClient define o contrato para comunicação com o Stock Service
 // (Interface Segregation Principle - ISP)
 type StockClient interface {
 	ReserveProducts(items []InvoiceItem) error
+	RestockProducts(items []InvoiceItem) error // Devolve ao estoque os itens de uma nota de devolução
 	CheckAvailability(productID string, quantity int) (bool, error)
 	GetProduct(productID string) (*ProductInfo, error)
 }
 
 // ProductInfo representa informações básicas de um produto
 type ProductInfo struct {
-	ID          string `json:"id"`
-	Code        string `json:"code"`
-	Description string `json:"description"`
-	Balance     int    `json:"balance"`
-}
\ No newline at end of file
+	ID          string  `json:"id"`
+	Code        string  `json:"code"`
+	Description string  `json:"description"`
+	Balance     int     `json:"balance"`
+	NCM         string  `json:"ncm"`
+	CEST        string  `json:"cest,omitempty"`
+	Origin      int     `json:"origin"`               // Origem da mercadoria (0 a 8)
+	Unit        string  `json:"unit"`                 // Unidade comercial
+	NetWeight   float64 `json:"net_weight,omitempty"` // Peso líquido unitário (kg)
+}
@@ -0,0 +1,145 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// MaxInvoiceItems é a quantidade máxima de itens (nItem) de uma NF-e ou NFC-e
+const MaxInvoiceItems = 990
+
+// BatchReference liga uma nota às demais notas geradas pela mesma criação,
+// quando a quantidade de itens excede o limite do documento
+type BatchReference struct {
+	ID       string `json:"id"`
+	Sequence int    `json:"sequence"` // Posição da nota no grupo, a partir de 1
+	Count    int    `json:"count"`    // Quantidade de notas do grupo
+}
+
+// InvoiceBatch reúne as notas de um grupo, na ordem de criação
+type InvoiceBatch struct {
+	ID       string     `json:"id"`
+	Total    Money      `json:"total"` // Soma dos totais das notas
+	Invoices []*Invoice `json:"invoices"`
+}
+
+// Erros de domínio do grupo de notas
+var (
+	ErrBatchNotFound = errors.New("grupo de notas não encontrado")
+)
+
+// SplitItems divide os itens em grupos de até limit itens, mantendo a ordem
+func SplitItems(items []InvoiceItem, limit int) [][]InvoiceItem {
+	if limit <= 0 || len(items) <= limit {
+		return [][]InvoiceItem{items}
+	}
+	groups := make([][]InvoiceItem, 0, (len(items)+limit-1)/limit)
+	for start := 0; start < len(items); start += limit {
+		end := min(start+limit, len(items))
+		groups = append(groups, items[start:end])
+	}
+	return groups
+}
+
+// SplitWeights calcula o peso de cada grupo de itens no rateio do cabeçalho:
+// o peso líquido em gramas quando todos os produtos têm peso, e o valor dos
+// itens (com desconto) do contrário
+func SplitWeights(groups [][]InvoiceItem) []Money {
+	weights := make([]Money, len(groups))
+	for idx, group := range groups {
+		weight, ok := ItemsNetWeight(group)
+		if !ok {
+			return splitValues(groups)
+		}
+		weights[idx] = Money(math.Round(weight * 1000))
+	}
+	return weights
+}
+
+// splitValues soma o valor dos itens de cada grupo
+func splitValues(groups [][]InvoiceItem) []Money {
+	values := make([]Money, len(groups))
+	for idx, group := range groups {
+		for _, item := range group {
+			values[idx] += item.UnitPrice*Money(item.Quantity) - item.Discount
+		}
+	}
+	return values
+}
+
+// Split rateia as despesas do cabeçalho entre as notas de um grupo,
+// proporcionalmente aos pesos informados
+func (c Charges) Split(weights []Money) []Charges {
+	freight := Apportion(c.Freight, weights)
+	insurance := Apportion(c.Insurance, weights)
+	other := Apportion(c.Other, weights)
+
+	parts := make([]Charges, len(weights))
+	for idx := range parts {
+		parts[idx] = Charges{Freight: freight[idx], Insurance: insurance[idx], Other: other[idx]}
+	}
+	return parts
+}
+
+// Split divide os volumes e os pesos informados entre as notas de um grupo,
+// proporcionalmente aos pesos do rateio. Os pesos são rateados em gramas, e
+// o peso líquido não informado segue calculado pelos produtos de cada nota.
+// Sem transporte, todas as notas saem sem transporte
+func (t *Transport) Split(weights []Money) []*Transport {
+	parts := make([]*Transport, len(weights))
+	if t == nil {
+		return parts
+	}
+
+	// Apportion trabalha com inteiros: volumes em unidades e pesos em gramas
+	volumes := Apportion(Money(t.Volumes), weights)
+	gross := Apportion(Money(math.Round(t.GrossWeight*1000)), weights)
+	net := Apportion(Money(math.Round(t.NetWeight*1000)), weights)
+	for idx := range parts {
+		part := *t
+		part.Volumes = int(volumes[idx])
+		part.GrossWeight = float64(gross[idx]) / 1000
+		part.NetWeight = float64(net[idx]) / 1000
+		parts[idx] = &part
+	}
+	return parts
+}
+
+// LinkBatch registra nas notas a referência do grupo. Uma nota sozinha não
+// forma grupo
+func LinkBatch(invoices []*Invoice, id string) {
+	if len(invoices) < 2 {
+		return
+	}
+	for idx, invoice := range invoices {
+		invoice.Batch = &BatchReference{ID: id, Sequence: idx + 1, Count: len(invoices)}
+	}
+}
+
+// NewInvoiceBatch monta o grupo com as notas que o referenciam
+func NewInvoiceBatch(id string, invoices []*Invoice) (*InvoiceBatch, error) {
+	batch := &InvoiceBatch{ID: id, Invoices: make([]*Invoice, 0)}
+	for _, invoice := range invoices {
+		if invoice.Batch != nil && invoice.Batch.ID == id {
+			batch.Invoices = append(batch.Invoices, invoice)
+		}
+	}
+	if len(batch.Invoices) == 0 {
+		return nil, ErrBatchNotFound
+	}
+	sort.Slice(batch.Invoices, func(i, j int) bool {
+		return batch.Invoices[i].Batch.Sequence < batch.Invoices[j].Batch.Sequence
+	})
+	batch.Total = BatchTotal(batch.Invoices)
+	return batch, nil
+}
+
+// BatchTotal soma os totais das notas
+func BatchTotal(invoices []*Invoice) Money {
+	var total Money
+	for _, invoice := range invoices {
+		total += invoice.Totals.Total
+	}
+	return total
+}
@@ -0,0 +1,115 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// DefaultSeries é a série usada quando o estabelecimento não informa uma
+const DefaultSeries = 1
+
+// Issuer representa o emitente (estabelecimento) das notas fiscais
+type Issuer struct {
+	ID                    string           `json:"id"`
+	Name                  string           `json:"name"`                 // Razão social
+	TradeName             string           `json:"trade_name,omitempty"` // Nome fantasia
+	CNPJ                  string           `json:"cnpj"`
+	StateRegistration     string           `json:"state_registration"`               // Inscrição estadual
+	MunicipalRegistration string           `json:"municipal_registration,omitempty"` // Inscrição municipal (prestador de serviços)
+	CRT                   TaxRegime        `json:"crt"`                              // Código de regime tributário
+	Series                int              `json:"series"`                           // Série das notas emitidas pelo estabelecimento
+	ConsumerSeries        int              `json:"consumer_series"`                  // Série das NFC-e (modelo 65)
+	ServiceSeries         int              `json:"service_series"`                   // Série das DPS que geram as NFS-e
+	Address               Address          `json:"address"`
+	PixKey                string           `json:"pix_key,omitempty"`   // Chave PIX para os recebimentos das notas
+	BankSlip              *BankSlipAccount `json:"bank_slip,omitempty"` // Conta de cobrança para emissão de boletos
+	CreatedAt             time.Time        `json:"created_at"`
+	UpdatedAt             time.Time        `json:"updated_at"`
+}
+
+// Erros de domínio do emitente
+var (
+	ErrIssuerNotFound  = errors.New("emitente não encontrado")
+	ErrInvalidIssuer   = errors.New("emitente inválido")
+	ErrDuplicateIssuer = errors.New("já existe emitente com este CNPJ")
+	ErrInvalidCRT      = errors.New("CRT deve ser 1, 2, 3 ou 4")
+	ErrInvalidSeries   = errors.New("série deve estar entre 1 e 999")
+	ErrIssuerRequired  = errors.New("emitente é obrigatório")
+)
+
+// Normalize padroniza CNPJ, inscrição estadual, série e endereço
+func (i *Issuer) Normalize() {
+	i.Name = strings.TrimSpace(i.Name)
+	i.TradeName = strings.TrimSpace(i.TradeName)
+	i.CNPJ = NormalizeDocument(i.CNPJ)
+	i.StateRegistration = normalizeStateRegistration(i.StateRegistration)
+	i.MunicipalRegistration = onlyDigits(i.MunicipalRegistration)
+	if i.Series == 0 {
+		i.Series = DefaultSeries
+	}
+	if i.ConsumerSeries == 0 {
+		i.ConsumerSeries = DefaultSeries
+	}
+	if i.ServiceSeries == 0 {
+		i.ServiceSeries = DefaultSeries
+	}
+	i.Address.Normalize()
+	i.PixKey = NormalizePixKey(i.PixKey)
+	if i.BankSlip != nil {
+		i.BankSlip.Normalize()
+	}
+}
+
+// Validate valida os dados do emitente
+func (i *Issuer) Validate() error {
+	if i.Name == "" {
+		return ErrInvalidIssuer
+	}
+	if err := ValidateCNPJ(i.CNPJ); err != nil {
+		return err
+	}
+	// O emitente deve possuir inscrição estadual (não pode ser ISENTO)
+	if i.StateRegistration == "" || i.StateRegistration == StateRegistrationExempt {
+		return ErrInvalidStateRegistration
+	}
+	if err := validateStateRegistration(i.StateRegistration); err != nil {
+		return err
+	}
+	if i.CRT < TaxRegimeSimples || i.CRT > TaxRegimeMEI {
+		return ErrInvalidCRT
+	}
+	if i.Series < 1 || i.Series > 999 || i.ConsumerSeries < 1 || i.ConsumerSeries > 999 || i.ServiceSeries < 1 || i.ServiceSeries > 999 {
+		return ErrInvalidSeries
+	}
+	if i.PixKey != "" {
+		if _, err := ParsePixKey(i.PixKey); err != nil {
+			return err
+		}
+	}
+	if i.BankSlip != nil {
+		if err := i.BankSlip.Validate(); err != nil {
+			return err
+		}
+	}
+	return i.Address.Validate()
+}
+
+// SeriesFor retorna a série usada pelo estabelecimento no modelo de documento
+func (i *Issuer) SeriesFor(model string) int {
+	switch model {
+	case ModelNFCe:
+		return i.ConsumerSeries
+	case ModelNFSe:
+		return i.ServiceSeries
+	}
+	return i.Series
+}
+
+// IssuerRepository define o contrato para persistência de emitentes
+type IssuerRepository interface {
+	Create(issuer *Issuer) error
+	FindByID(id string) (*Issuer, error)
+	FindAll() ([]*Issuer, error)
+	Update(issuer *Issuer) error
+}
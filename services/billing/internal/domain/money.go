@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Money representa um valor monetário em centavos, evitando erros de
+// arredondamento de ponto flutuante. Em JSON é serializado como decimal (10.50)
+type Money int64
+
+// NewMoneyFromFloat converte um valor decimal em reais para centavos
+func NewMoneyFromFloat(value float64) Money {
+	return Money(math.Round(value * 100))
+}
+
+// Float retorna o valor em reais
+func (m Money) Float() float64 {
+	return float64(m) / 100
+}
+
+// ApplyRate aplica uma alíquota percentual (ex: 18 para 18%) ao valor,
+// arredondando para o centavo mais próximo
+func (m Money) ApplyRate(rate float64) Money {
+	return Money(math.Round(float64(m) * rate / 100))
+}
+
+// MulQuantity multiplica o valor por uma quantidade
+func (m Money) MulQuantity(quantity int) Money {
+	return m * Money(quantity)
+}
+
+// String formata o valor com duas casas decimais (ex: 10.50)
+func (m Money) String() string {
+	sign := ""
+	value := int64(m)
+	if value < 0 {
+		sign = "-"
+		value = -value
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, value/100, value%100)
+}
+
+// MarshalJSON serializa o valor como número decimal
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON aceita o valor como número decimal em reais
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var number json.Number
+	if err := json.Unmarshal(data, &number); err != nil {
+		return fmt.Errorf("valor monetário inválido: %s", string(data))
+	}
+	value, err := strconv.ParseFloat(number.String(), 64)
+	if err != nil {
+		return fmt.Errorf("valor monetário inválido: %s", string(data))
+	}
+	*m = NewMoneyFromFloat(value)
+	return nil
+}
@@ -0,0 +1,114 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMoneyApplyRate(t *testing.T) {
+	// Arredonda ao centavo mais próximo, com o meio centavo para longe do zero
+	tests := []struct {
+		value    Money
+		rate     float64
+		expected Money
+	}{
+		{value: 10000, rate: 18, expected: 1800},
+		{value: 50, rate: 1, expected: 1},
+		{value: 149, rate: 1, expected: 1},
+		{value: 151, rate: 1, expected: 2},
+		{value: -50, rate: 1, expected: -1},
+		{value: 1250, rate: 1.65, expected: 21},
+		{value: 1000, rate: 0, expected: 0},
+	}
+	for _, tt := range tests {
+		if got := tt.value.ApplyRate(tt.rate); got != tt.expected {
+			t.Errorf("Money(%d).ApplyRate(%v) = %d, esperado %d", tt.value, tt.rate, got, tt.expected)
+		}
+	}
+}
+
+func TestMoneyMarshalJSON(t *testing.T) {
+	tests := []struct {
+		value    Money
+		expected string
+	}{
+		{value: 1050, expected: "10.50"},
+		{value: 100, expected: "1.00"},
+		{value: 7, expected: "0.07"},
+		{value: 0, expected: "0.00"},
+		{value: -5, expected: "-0.05"},
+		{value: -123456, expected: "-1234.56"},
+	}
+	for _, tt := range tests {
+		data, err := json.Marshal(tt.value)
+		if err != nil {
+			t.Fatalf("Marshal(%d) erro inesperado: %v", tt.value, err)
+		}
+		if string(data) != tt.expected {
+			t.Errorf("Marshal(%d) = %s, esperado %s", tt.value, data, tt.expected)
+		}
+	}
+}
+
+func TestMoneyUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		data     string
+		expected Money
+		hasError bool
+	}{
+		{data: `10.5`, expected: 1050},
+		{data: `10.50`, expected: 1050},
+		{data: `19.99`, expected: 1999},
+		{data: `0.1`, expected: 10},
+		{data: `3`, expected: 300},
+		{data: `-0.05`, expected: -5},
+		{data: `0.005`, expected: 1},
+		{data: `1e2`, expected: 10000},
+		{data: `"10.50"`, expected: 1050},
+		{data: `"abc"`, hasError: true},
+		{data: `true`, hasError: true},
+		{data: `{}`, hasError: true},
+	}
+	for _, tt := range tests {
+		var value Money
+		err := json.Unmarshal([]byte(tt.data), &value)
+		if tt.hasError {
+			if err == nil {
+				t.Errorf("Unmarshal(%s) = %d, esperava erro", tt.data, value)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Unmarshal(%s) erro inesperado: %v", tt.data, err)
+			continue
+		}
+		if value != tt.expected {
+			t.Errorf("Unmarshal(%s) = %d, esperado %d", tt.data, value, tt.expected)
+		}
+	}
+}
+
+func TestMoneyJSONRoundTrip(t *testing.T) {
+	type payload struct {
+		Price Money  `json:"price"`
+		Total *Money `json:"total,omitempty"`
+	}
+	total := Money(-1999)
+	original := payload{Price: 123456789, Total: &total}
+
+	data, err := json.Marshal(original)
+	if err != nil {
+		t.Fatalf("Marshal() erro inesperado: %v", err)
+	}
+	if string(data) != `{"price":1234567.89,"total":-19.99}` {
+		t.Errorf("Marshal() = %s", data)
+	}
+
+	var decoded payload
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal() erro inesperado: %v", err)
+	}
+	if decoded.Price != original.Price || decoded.Total == nil || *decoded.Total != total {
+		t.Errorf("Unmarshal() = %+v, esperado %+v", decoded, original)
+	}
+}
@@ -0,0 +1,202 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// PaymentMethod representa o meio de pagamento (tPag da NF-e)
+type PaymentMethod string
+
+const (
+	PaymentCash         PaymentMethod = "01" // Dinheiro
+	PaymentCheck        PaymentMethod = "02" // Cheque
+	PaymentCreditCard   PaymentMethod = "03" // Cartão de crédito
+	PaymentDebitCard    PaymentMethod = "04" // Cartão de débito
+	PaymentStoreCredit  PaymentMethod = "05" // Crédito loja
+	PaymentBankSlip     PaymentMethod = "15" // Boleto bancário
+	PaymentBankDeposit  PaymentMethod = "16" // Depósito bancário
+	PaymentPIX          PaymentMethod = "17" // Pagamento instantâneo (PIX)
+	PaymentBankTransfer PaymentMethod = "18" // Transferência bancária, carteira digital
+	PaymentNone         PaymentMethod = "90" // Sem pagamento (devoluções e notas sem cobrança)
+	PaymentOther        PaymentMethod = "99" // Outros, com descrição obrigatória
+)
+
+// MaxInstallments é o limite de duplicatas do grupo de cobrança da NF-e
+const MaxInstallments = 120
+
+// PaymentTerms reúne a condição de pagamento da nota: meio de pagamento,
+// quantidade de parcelas e intervalo em dias entre os vencimentos. Uma única
+// parcela sem intervalo é pagamento à vista
+type PaymentTerms struct {
+	Method       PaymentMethod `json:"method"`
+	Description  string        `json:"description,omitempty"`   // Obrigatória no meio 99 (xPag)
+	Installments int           `json:"installments"`            // Quantidade de parcelas (padrão 1)
+	IntervalDays int           `json:"interval_days,omitempty"` // Dias até cada vencimento
+}
+
+// Installment representa uma parcela (duplicata) a receber
+type Installment struct {
+	Number  string            `json:"number"`           // nDup: 001, 002...
+	DueDate time.Time         `json:"due_date"`         // Vencimento, contado a partir da emissão
+	Amount  Money             `json:"amount"`           // Valor da parcela
+	Paid    Money             `json:"paid"`             // Principal já recebido, sem encargos
+	Status  InstallmentStatus `json:"status,omitempty"` // Atualizada na consulta
+}
+
+// Erros de domínio do pagamento
+var (
+	ErrInvalidPaymentMethod   = errors.New("meio de pagamento deve ser 01, 02, 03, 04, 05, 15, 16, 17, 18 ou 99")
+	ErrPaymentDescription     = errors.New("meio de pagamento 99 (outros) exige descrição de até 60 caracteres")
+	ErrInvalidInstallments    = errors.New("quantidade de parcelas deve estar entre 1 e 120")
+	ErrInvalidPaymentInterval = errors.New("parcelamento exige intervalo positivo entre os vencimentos")
+	ErrCashOnlyMethod         = errors.New("dinheiro e cartão de débito admitem apenas pagamento à vista")
+	ErrConsumerInstallments   = errors.New("NFC-e admite apenas pagamento à vista")
+	ErrReturnPayment          = errors.New("nota de devolução não admite condição de pagamento")
+	ErrInstallmentAmount      = errors.New("valor da nota insuficiente para a quantidade de parcelas")
+)
+
+// paymentMethods lista os meios de pagamento aceitos na condição de pagamento.
+// Sem pagamento (90) é reservado às notas sem condição informada
+var paymentMethods = map[PaymentMethod]bool{
+	PaymentCash:         true,
+	PaymentCheck:        true,
+	PaymentCreditCard:   true,
+	PaymentDebitCard:    true,
+	PaymentStoreCredit:  true,
+	PaymentBankSlip:     true,
+	PaymentBankDeposit:  true,
+	PaymentPIX:          true,
+	PaymentBankTransfer: true,
+	PaymentOther:        true,
+}
+
+// Normalize padroniza a descrição e assume uma parcela quando não informada
+func (p *PaymentTerms) Normalize() {
+	p.Method = PaymentMethod(strings.TrimSpace(string(p.Method)))
+	p.Description = strings.TrimSpace(p.Description)
+	if p.Installments == 0 {
+		p.Installments = 1
+	}
+}
+
+// Validate valida a condição de pagamento conforme as regras da NF-e
+func (p *PaymentTerms) Validate() error {
+	if !paymentMethods[p.Method] {
+		return ErrInvalidPaymentMethod
+	}
+	if p.Method == PaymentOther && (len([]rune(p.Description)) < 2 || len([]rune(p.Description)) > 60) {
+		return ErrPaymentDescription
+	}
+	if p.Installments < 1 || p.Installments > MaxInstallments {
+		return ErrInvalidInstallments
+	}
+	if p.IntervalDays < 0 || (p.Installments > 1 && p.IntervalDays == 0) {
+		return ErrInvalidPaymentInterval
+	}
+	if (p.Method == PaymentCash || p.Method == PaymentDebitCard) && !p.IsCash() {
+		return ErrCashOnlyMethod
+	}
+	return nil
+}
+
+// IsCash indica se o pagamento é à vista (indPag 0)
+func (p *PaymentTerms) IsCash() bool {
+	return p.Installments <= 1 && p.IntervalDays == 0
+}
+
+// ScheduleInstallments gera as parcelas da condição de pagamento a partir da
+// data de emissão. O total é dividido em partes iguais truncadas no centavo, e
+// o resíduo fica na primeira parcela, de modo que a soma coincide com o total
+// da nota. Sem condição de pagamento, ou com quantidade fora de
+// 1..MaxInstallments (rejeitada na validação), a nota não gera parcelas
+func (i *Invoice) ScheduleInstallments(issuedAt time.Time) {
+	p := i.Payment
+	if p == nil || p.Installments <= 0 || p.Installments > MaxInstallments {
+		i.Installments = nil
+		return
+	}
+
+	weights := make([]Money, p.Installments)
+	for idx := range weights {
+		weights[idx] = 1
+	}
+	amounts := Apportion(i.Totals.Total, weights)
+
+	issueDate := time.Date(issuedAt.Year(), issuedAt.Month(), issuedAt.Day(), 0, 0, 0, 0, issuedAt.Location())
+	installments := make([]Installment, p.Installments)
+	for idx := range installments {
+		installments[idx] = Installment{
+			Number:  fmt.Sprintf("%03d", idx+1),
+			DueDate: issueDate.AddDate(0, 0, p.IntervalDays*(idx+1)),
+			Amount:  amounts[idx],
+		}
+	}
+	i.Installments = installments
+}
+
+// validatePayment valida a condição de pagamento no contexto da nota
+func (i *Invoice) validatePayment() error {
+	p := i.Payment
+	if p == nil {
+		return nil
+	}
+	if i.IsReturn() {
+		return ErrReturnPayment
+	}
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	if i.IsConsumer() && !p.IsCash() {
+		return ErrConsumerInstallments
+	}
+	if i.Totals.Total < Money(p.Installments) {
+		return ErrInstallmentAmount
+	}
+	return nil
+}
+
+// Receivable é uma parcela a receber de uma nota emitida
+type Receivable struct {
+	InvoiceID     string        `json:"invoice_id"`
+	InvoiceNumber int           `json:"invoice_number"`
+	Model         string        `json:"model"`
+	Series        int           `json:"series"`
+	CustomerID    string        `json:"customer_id,omitempty"`
+	CustomerName  string        `json:"customer_name,omitempty"`
+	Method        PaymentMethod `json:"method"`
+	Outstanding   Money         `json:"outstanding"` // Saldo em aberto da parcela, sem encargos
+	Installment
+}
+
+// Receivables retorna as parcelas a receber da nota. Só geram recebíveis as
+// notas fechadas cujo uso não foi denegado pela SEFAZ
+func (i *Invoice) Receivables() []Receivable {
+	if !i.IsClosed() || i.Payment == nil {
+		return nil
+	}
+	if i.Authorization != nil && i.Authorization.Status == AuthorizationDenied {
+		return nil
+	}
+
+	receivables := make([]Receivable, 0, len(i.Installments))
+	for _, installment := range i.Installments {
+		receivable := Receivable{
+			InvoiceID:     i.ID,
+			InvoiceNumber: i.Number,
+			Model:         i.Model,
+			Series:        i.Series,
+			CustomerID:    i.CustomerID,
+			Method:        i.Payment.Method,
+			Outstanding:   installment.Outstanding(),
+			Installment:   installment,
+		}
+		if i.Customer != nil {
+			receivable.CustomerName = i.Customer.Name
+		}
+		receivables = append(receivables, receivable)
+	}
+	return receivables
+}
@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// PixKeyType identifica o tipo da chave PIX do recebedor
+type PixKeyType string
+
+const (
+	PixKeyCPF    PixKeyType = "CPF"
+	PixKeyCNPJ   PixKeyType = "CNPJ"
+	PixKeyEmail  PixKeyType = "EMAIL"
+	PixKeyPhone  PixKeyType = "TELEFONE"
+	PixKeyRandom PixKeyType = "ALEATORIA" // Chave aleatória (EVP)
+)
+
+// PixMode indica se o BR Code é estático (chave e valor no próprio código) ou
+// dinâmico (URL da cobrança registrada no PSP)
+type PixMode string
+
+const (
+	PixStatic  PixMode = "ESTATICO"
+	PixDynamic PixMode = "DINAMICO"
+)
+
+// Erros de domínio do PIX
+var (
+	ErrInvalidPixKey           = errors.New("chave PIX deve ser CPF, CNPJ, e-mail, telefone (+55...) ou chave aleatória")
+	ErrPixKeyNotConfigured     = errors.New("emitente não possui chave PIX cadastrada")
+	ErrPixDynamicNotConfigured = errors.New("PIX dinâmico exige a URL de cobrança do PSP")
+	ErrInvalidPixMode          = errors.New("modo do PIX deve ser ESTATICO ou DINAMICO")
+)
+
+var (
+	pixPhonePattern  = regexp.MustCompile(`^\+[1-9][0-9]{1,14}$`)
+	pixRandomPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+)
+
+// PixCharge é uma cobrança PIX de uma nota ou parcela, com o payload "copia e
+// cola" do BR Code
+type PixCharge struct {
+	Mode        PixMode `json:"mode"`
+	Key         string  `json:"key,omitempty"`      // Chave do recebedor (PIX estático)
+	Location    string  `json:"location,omitempty"` // URL da cobrança no PSP (PIX dinâmico)
+	TxID        string  `json:"txid"`
+	Amount      Money   `json:"amount"`
+	Installment string  `json:"installment,omitempty"`
+	Payload     string  `json:"payload"`
+}
+
+// NormalizePixKey padroniza a chave conforme o tipo: e-mail e chave aleatória
+// em minúsculas, telefone só com "+" e dígitos e CPF/CNPJ sem pontuação
+func NormalizePixKey(key string) string {
+	key = strings.TrimSpace(key)
+	switch {
+	case key == "":
+		return ""
+	case strings.Contains(key, "@"):
+		return strings.ToLower(key)
+	case strings.HasPrefix(key, "+"):
+		return "+" + onlyDigits(key)
+	case pixRandomPattern.MatchString(strings.ToLower(key)):
+		return strings.ToLower(key)
+	default:
+		return NormalizeDocument(key)
+	}
+}
+
+// ParsePixKey identifica o tipo de uma chave PIX já normalizada
+func ParsePixKey(key string) (PixKeyType, error) {
+	switch {
+	case strings.Contains(key, "@"):
+		at := strings.Index(key, "@")
+		if utf8.RuneCountInString(key) > 77 || at < 1 || !strings.Contains(key[at+1:], ".") {
+			return "", ErrInvalidPixKey
+		}
+		return PixKeyEmail, nil
+	case pixPhonePattern.MatchString(key):
+		return PixKeyPhone, nil
+	case pixRandomPattern.MatchString(key):
+		return PixKeyRandom, nil
+	case len(key) == 11 && ValidateCPF(key) == nil:
+		return PixKeyCPF, nil
+	case len(key) == 14 && ValidateCNPJ(key) == nil:
+		return PixKeyCNPJ, nil
+	default:
+		return "", ErrInvalidPixKey
+	}
+}
+
+// ParsePixMode interpreta o modo informado, assumindo o PIX estático quando vazio
+func ParsePixMode(value string) (PixMode, error) {
+	switch PixMode(strings.ToUpper(strings.TrimSpace(value))) {
+	case "", PixStatic:
+		return PixStatic, nil
+	case PixDynamic:
+		return PixDynamic, nil
+	default:
+		return "", ErrInvalidPixMode
+	}
+}
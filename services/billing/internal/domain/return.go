@@ -0,0 +1,175 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// CFOPs de entrada por devolução de venda de mercadoria adquirida de terceiros
+const (
+	CFOPReturnInState    = "1202" // Operação dentro do estado
+	CFOPReturnInterstate = "2202" // Operação interestadual
+)
+
+// InvoiceReference identifica a nota original de uma devolução
+type InvoiceReference struct {
+	InvoiceID string `json:"invoice_id"`
+	Model     string `json:"model"`
+	Series    int    `json:"series"`
+	Number    int    `json:"number"`
+	AccessKey string `json:"access_key"`
+}
+
+// ReturnLine indica o item da nota original (nItem, a partir de 1) e a
+// quantidade devolvida
+type ReturnLine struct {
+	Item     int `json:"item"`
+	Quantity int `json:"quantity"`
+}
+
+// Erros de domínio da devolução
+var (
+	ErrReturnOriginalNotClosed = errors.New("somente notas fechadas podem ser devolvidas")
+	ErrReturnNotAllowed        = errors.New("notas de serviço e notas de devolução não admitem devolução")
+	ErrReturnCustomerRequired  = errors.New("devolução exige nota original com destinatário identificado")
+	ErrInvalidReturnItem       = errors.New("item inexistente na nota original")
+	ErrReturnQuantityExceeded  = errors.New("quantidade devolvida excede o saldo a devolver do item")
+	ErrNothingToReturn         = errors.New("todos os itens da nota já foram devolvidos")
+)
+
+// IsReturn indica se a nota é uma devolução (finalidade 4, entrada)
+func (i *Invoice) IsReturn() bool {
+	return i.ReturnOf != nil
+}
+
+// Reference retorna a referência usada pelas notas de devolução desta nota
+func (i *Invoice) Reference() *InvoiceReference {
+	return &InvoiceReference{
+		InvoiceID: i.ID,
+		Model:     i.Model,
+		Series:    i.Series,
+		Number:    i.Number,
+		AccessKey: i.AccessKey,
+	}
+}
+
+// CanBeReturned verifica se a nota aceita devoluções: precisa estar fechada,
+// ser de mercadorias, não ser ela mesma uma devolução e ter destinatário, que
+// passa a ser o remetente da mercadoria devolvida
+func (i *Invoice) CanBeReturned() error {
+	if !i.IsClosed() {
+		return ErrReturnOriginalNotClosed
+	}
+	if i.IsService() || i.IsReturn() {
+		return ErrReturnNotAllowed
+	}
+	if i.Customer == nil {
+		return ErrReturnCustomerRequired
+	}
+	return nil
+}
+
+// ReturnableQuantity retorna o saldo a devolver do item (nItem, a partir de 1)
+func (i *Invoice) ReturnableQuantity(item int) int {
+	if item < 1 || item > len(i.Items) {
+		return 0
+	}
+	line := i.Items[item-1]
+	return line.Quantity - line.ReturnedQuantity
+}
+
+// RemainingReturnLines lista os itens com saldo a devolver e suas quantidades,
+// usado quando a devolução é total
+func (i *Invoice) RemainingReturnLines() []ReturnLine {
+	var lines []ReturnLine
+	for idx := range i.Items {
+		if quantity := i.ReturnableQuantity(idx + 1); quantity > 0 {
+			lines = append(lines, ReturnLine{Item: idx + 1, Quantity: quantity})
+		}
+	}
+	return lines
+}
+
+// CheckReturnLines valida as linhas de uma devolução contra o saldo a
+// devolver, descontadas as quantidades já comprometidas em devoluções abertas
+func (i *Invoice) CheckReturnLines(lines []ReturnLine, pending map[int]int) error {
+	requested := make(map[int]int, len(lines))
+	for _, line := range lines {
+		if line.Item < 1 || line.Item > len(i.Items) {
+			return fmt.Errorf("%w: %d", ErrInvalidReturnItem, line.Item)
+		}
+		if line.Quantity <= 0 {
+			return ErrInvalidQuantity
+		}
+		requested[line.Item] += line.Quantity
+	}
+	for item, quantity := range requested {
+		available := i.ReturnableQuantity(item) - pending[item]
+		if quantity > available {
+			return fmt.Errorf("%w: item %d (disponível: %d, solicitado: %d)", ErrReturnQuantityExceeded, item, available, quantity)
+		}
+	}
+	return nil
+}
+
+// ReturnLines retorna as linhas (item original e quantidade) de uma nota de
+// devolução
+func (i *Invoice) ReturnLines() []ReturnLine {
+	lines := make([]ReturnLine, 0, len(i.Items))
+	for _, item := range i.Items {
+		lines = append(lines, ReturnLine{Item: item.OriginalItem, Quantity: item.Quantity})
+	}
+	return lines
+}
+
+// RegisterReturn abate do saldo a devolver da nota original as quantidades de
+// uma nota de devolução fechada. Nada é alterado se alguma linha exceder o saldo
+func (i *Invoice) RegisterReturn(ret *Invoice) error {
+	if ret.ReturnOf == nil || ret.ReturnOf.InvoiceID != i.ID {
+		return ErrInvalidReturnItem
+	}
+	lines := ret.ReturnLines()
+	if err := i.CheckReturnLines(lines, nil); err != nil {
+		return err
+	}
+	for _, line := range lines {
+		i.Items[line.Item-1].ReturnedQuantity += line.Quantity
+	}
+	return nil
+}
+
+// ReturnCharges calcula as despesas acessórias devolvidas: a parcela rateada
+// de cada item original, proporcional à quantidade devolvida
+func (i *Invoice) ReturnCharges(lines []ReturnLine) Charges {
+	var charges Charges
+	for _, line := range lines {
+		original := i.Items[line.Item-1]
+		quantity := Money(line.Quantity)
+		sold := Money(original.Quantity)
+		charges.Freight += original.Charges.Freight * quantity / sold
+		charges.Insurance += original.Charges.Insurance * quantity / sold
+		charges.Other += original.Charges.Other * quantity / sold
+	}
+	return charges
+}
+
+// ReturnItem monta o item da devolução a partir do item original, preservando
+// classificação fiscal e preço unitário. O desconto devolvido é proporcional à
+// quantidade
+func (i *Invoice) ReturnItem(line ReturnLine) InvoiceItem {
+	original := i.Items[line.Item-1]
+	return InvoiceItem{
+		Type:         ItemGoods,
+		ProductID:    original.ProductID,
+		ProductCode:  original.ProductCode,
+		Description:  original.Description,
+		Quantity:     line.Quantity,
+		NCM:          original.NCM,
+		CEST:         original.CEST,
+		Origin:       original.Origin,
+		Unit:         original.Unit,
+		UnitPrice:    original.UnitPrice,
+		Discount:     original.Discount * Money(line.Quantity) / Money(original.Quantity),
+		OriginalItem: line.Item,
+	}
+}
@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+// returnOriginal monta uma nota fechada com dois itens: 10 unidades, das
+// quais 4 já devolvidas, e 5 unidades sem devolução
+func returnOriginal() *Invoice {
+	return &Invoice{
+		ID:     "original",
+		Status: StatusClosed,
+		Items: []InvoiceItem{
+			{ProductID: "P1", Quantity: 10, ReturnedQuantity: 4},
+			{ProductID: "P2", Quantity: 5},
+		},
+	}
+}
+
+func TestCheckReturnLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		lines   []ReturnLine
+		pending map[int]int // Quantidades em devoluções abertas, por item
+		err     error
+	}{
+		{name: "saldo integral dos dois itens", lines: []ReturnLine{{Item: 1, Quantity: 6}, {Item: 2, Quantity: 5}}},
+		{name: "item repetido dentro do saldo", lines: []ReturnLine{{Item: 1, Quantity: 3}, {Item: 1, Quantity: 3}}},
+		{name: "item repetido acima do saldo", lines: []ReturnLine{{Item: 1, Quantity: 3}, {Item: 1, Quantity: 4}}, err: ErrReturnQuantityExceeded},
+		{name: "acima do saldo já devolvido", lines: []ReturnLine{{Item: 1, Quantity: 7}}, err: ErrReturnQuantityExceeded},
+		{name: "saldo comprometido em devolução aberta", lines: []ReturnLine{{Item: 2, Quantity: 3}}, pending: map[int]int{2: 2}},
+		{name: "acima do saldo não comprometido", lines: []ReturnLine{{Item: 2, Quantity: 4}}, pending: map[int]int{2: 2}, err: ErrReturnQuantityExceeded},
+		{name: "item zero", lines: []ReturnLine{{Item: 0, Quantity: 1}}, err: ErrInvalidReturnItem},
+		{name: "item inexistente", lines: []ReturnLine{{Item: 3, Quantity: 1}}, err: ErrInvalidReturnItem},
+		{name: "quantidade zero", lines: []ReturnLine{{Item: 2, Quantity: 0}}, err: ErrInvalidQuantity},
+		{name: "quantidade negativa", lines: []ReturnLine{{Item: 2, Quantity: -1}}, err: ErrInvalidQuantity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := returnOriginal().CheckReturnLines(tt.lines, tt.pending); !errors.Is(err, tt.err) {
+				t.Errorf("CheckReturnLines() erro = %v, esperado %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestRemainingReturnLines(t *testing.T) {
+	original := returnOriginal()
+	lines := original.RemainingReturnLines()
+	if len(lines) != 2 || lines[0] != (ReturnLine{Item: 1, Quantity: 6}) || lines[1] != (ReturnLine{Item: 2, Quantity: 5}) {
+		t.Fatalf("RemainingReturnLines() = %+v", lines)
+	}
+
+	// Após devolver todo o saldo, nada resta a devolver
+	ret := &Invoice{ReturnOf: original.Reference()}
+	for _, line := range lines {
+		ret.Items = append(ret.Items, original.ReturnItem(line))
+	}
+	if err := original.RegisterReturn(ret); err != nil {
+		t.Fatalf("RegisterReturn() erro inesperado: %v", err)
+	}
+	if remaining := original.RemainingReturnLines(); len(remaining) != 0 {
+		t.Errorf("RemainingReturnLines() após devolução total = %+v, esperado vazio", remaining)
+	}
+
+	// Uma segunda devolução excede o saldo e não altera a nota
+	if err := original.RegisterReturn(ret); !errors.Is(err, ErrReturnQuantityExceeded) {
+		t.Errorf("RegisterReturn() repetido erro = %v, esperado %v", err, ErrReturnQuantityExceeded)
+	}
+	if original.Items[0].ReturnedQuantity != 10 || original.Items[1].ReturnedQuantity != 5 {
+		t.Errorf("quantidades devolvidas = %d e %d, esperadas 10 e 5", original.Items[0].ReturnedQuantity, original.Items[1].ReturnedQuantity)
+	}
+}
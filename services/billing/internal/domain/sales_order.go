@@ -0,0 +1,267 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// SalesOrderStatus representa a situação de um pedido de venda
+type SalesOrderStatus string
+
+const (
+	OrderDraft             SalesOrderStatus = "RASCUNHO"         // Criado, sem reserva de estoque
+	OrderConfirmed         SalesOrderStatus = "CONFIRMADO"       // Estoque reservado, nada faturado
+	OrderPartiallyInvoiced SalesOrderStatus = "FATURADO_PARCIAL" // Parte das quantidades já faturada
+	OrderInvoiced          SalesOrderStatus = "FATURADO"         // Todas as quantidades faturadas
+	OrderCancelled         SalesOrderStatus = "CANCELADO"        // Saldo não faturado liberado
+)
+
+// SalesOrder representa um pedido de venda de mercadorias. Ao ser confirmado,
+// o saldo não faturado das linhas fica reservado no estoque, e o pedido gera
+// uma ou mais notas até que todas as quantidades sejam faturadas
+type SalesOrder struct {
+	ID          string           `json:"id"`
+	Number      int              `json:"number"` // Numeração sequencial dos pedidos
+	Status      SalesOrderStatus `json:"status"`
+	IssuerID    string           `json:"issuer_id"`
+	CustomerID  string           `json:"customer_id"`
+	Lines       []SalesOrderLine `json:"lines"`
+	Payment     *PaymentTerms    `json:"payment,omitempty"` // Condição de pagamento copiada para as notas
+	Notes       string           `json:"notes,omitempty"`
+	Invoices    []string         `json:"invoices,omitempty"` // Notas geradas a partir do pedido
+	CreatedBy   string           `json:"created_by,omitempty"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+	ConfirmedAt *time.Time       `json:"confirmed_at,omitempty"`
+	CancelledAt *time.Time       `json:"cancelled_at,omitempty"`
+}
+
+// SalesOrderLine é uma linha do pedido, com as quantidades já faturadas e já
+// entregues (notas impressas, com baixa no estoque)
+type SalesOrderLine struct {
+	Number      int    `json:"number"` // Número da linha, a partir de 1
+	ProductID   string `json:"product_id"`
+	ProductCode string `json:"product_code"`
+	Description string `json:"description"`
+	Quantity    int    `json:"quantity"`
+	UnitPrice   Money  `json:"unit_price"`
+	Invoiced    int    `json:"invoiced"`  // Em notas geradas pelo pedido, exceto as reprovadas
+	Delivered   int    `json:"delivered"` // Em notas impressas
+}
+
+// OrderLine indica a linha do pedido (a partir de 1) e a quantidade a faturar
+type OrderLine struct {
+	Line     int `json:"line"`
+	Quantity int `json:"quantity"`
+}
+
+// Erros de domínio do pedido de venda
+var (
+	ErrSalesOrderNotFound     = errors.New("pedido de venda não encontrado")
+	ErrSalesOrderNoLines      = errors.New("pedido de venda deve ter ao menos uma linha")
+	ErrInvalidOrderLine       = errors.New("linha inexistente no pedido")
+	ErrOrderQuantityExceeded  = errors.New("quantidade excede o saldo a faturar da linha")
+	ErrNothingToInvoice       = errors.New("todas as linhas do pedido já foram faturadas")
+	ErrOrderNotDraft          = errors.New("somente pedidos em rascunho podem ser confirmados")
+	ErrOrderNotActive         = errors.New("somente pedidos confirmados podem ser faturados")
+	ErrOrderNotCancellable    = errors.New("pedido faturado ou cancelado não pode ser cancelado")
+	ErrOrderInsufficientStock = errors.New("estoque insuficiente para reservar o pedido")
+)
+
+// Validate valida os dados do pedido
+func (o *SalesOrder) Validate() error {
+	if o.IssuerID == "" {
+		return ErrIssuerRequired
+	}
+	if o.CustomerID == "" {
+		return ErrCustomerRequired
+	}
+	if len(o.Lines) == 0 {
+		return ErrSalesOrderNoLines
+	}
+	for _, line := range o.Lines {
+		if line.ProductID == "" || line.Quantity <= 0 {
+			return ErrInvalidQuantity
+		}
+		if line.UnitPrice < 0 {
+			return ErrInvalidUnitPrice
+		}
+	}
+	if o.Payment != nil {
+		return o.Payment.Validate()
+	}
+	return nil
+}
+
+// IsActive indica se o pedido mantém reserva do saldo a faturar
+func (o *SalesOrder) IsActive() bool {
+	return o.Status == OrderConfirmed || o.Status == OrderPartiallyInvoiced
+}
+
+// Confirm confirma o pedido em rascunho, passando a reservar o estoque
+func (o *SalesOrder) Confirm(now time.Time) error {
+	if o.Status != OrderDraft {
+		return ErrOrderNotDraft
+	}
+	o.Status = OrderConfirmed
+	o.ConfirmedAt = &now
+	o.UpdatedAt = now
+	return nil
+}
+
+// Cancel cancela o pedido, liberando o saldo ainda não faturado. As notas já
+// geradas seguem válidas e mantêm a reserva até serem impressas
+func (o *SalesOrder) Cancel(now time.Time) error {
+	if o.Status == OrderInvoiced || o.Status == OrderCancelled {
+		return ErrOrderNotCancellable
+	}
+	o.Status = OrderCancelled
+	o.CancelledAt = &now
+	o.UpdatedAt = now
+	return nil
+}
+
+// Remaining retorna o saldo a faturar da linha
+func (l SalesOrderLine) Remaining() int {
+	return l.Quantity - l.Invoiced
+}
+
+// HeldQuantities soma, por produto, a quantidade reservada pelo pedido: o
+// saldo a faturar, enquanto o pedido está ativo, e o que foi faturado em notas
+// ainda não impressas
+func (o *SalesOrder) HeldQuantities() map[string]int {
+	held := make(map[string]int, len(o.Lines))
+	for _, line := range o.Lines {
+		quantity := line.Invoiced - line.Delivered
+		if o.IsActive() {
+			quantity += line.Remaining()
+		}
+		if quantity > 0 {
+			held[line.ProductID] += quantity
+		}
+	}
+	return held
+}
+
+// HeldStock soma as reservas de estoque dos pedidos, por produto, ignorando o
+// pedido informado (que consome a própria reserva ao gerar notas)
+func HeldStock(orders []*SalesOrder, exceptID string) map[string]int {
+	held := make(map[string]int)
+	for _, order := range orders {
+		if order.ID == exceptID {
+			continue
+		}
+		for productID, quantity := range order.HeldQuantities() {
+			held[productID] += quantity
+		}
+	}
+	return held
+}
+
+// InvoiceLines valida as linhas a faturar contra o saldo de cada linha do
+// pedido. Sem linhas, todo o saldo a faturar é incluído
+func (o *SalesOrder) InvoiceLines(lines []OrderLine) ([]OrderLine, error) {
+	if len(lines) == 0 {
+		for _, line := range o.Lines {
+			if remaining := line.Remaining(); remaining > 0 {
+				lines = append(lines, OrderLine{Line: line.Number, Quantity: remaining})
+			}
+		}
+		if len(lines) == 0 {
+			return nil, ErrNothingToInvoice
+		}
+		return lines, nil
+	}
+
+	requested := make(map[int]int, len(lines))
+	for _, line := range lines {
+		if line.Line < 1 || line.Line > len(o.Lines) {
+			return nil, fmt.Errorf("%w: %d", ErrInvalidOrderLine, line.Line)
+		}
+		if line.Quantity <= 0 {
+			return nil, ErrInvalidQuantity
+		}
+		requested[line.Line] += line.Quantity
+	}
+	for number, quantity := range requested {
+		if available := o.Lines[number-1].Remaining(); quantity > available {
+			return nil, fmt.Errorf("%w: linha %d (disponível: %d, solicitado: %d)", ErrOrderQuantityExceeded, number, available, quantity)
+		}
+	}
+	return lines, nil
+}
+
+// ClaimItems abate do saldo a faturar das linhas os itens das notas a gerar.
+// O saldo é abatido antes da criação das notas, para que outro faturamento
+// do pedido não conte as mesmas quantidades
+func (o *SalesOrder) ClaimItems(items []InvoiceItem, now time.Time) {
+	o.addInvoiced(items, 1)
+	o.UpdatedAt = now
+}
+
+// ReleaseItems devolve ao saldo a faturar os itens de notas que não foram
+// geradas
+func (o *SalesOrder) ReleaseItems(items []InvoiceItem, now time.Time) {
+	o.addInvoiced(items, -1)
+	o.UpdatedAt = now
+}
+
+// RegisterInvoice vincula ao pedido uma nota gerada a partir dos itens já
+// abatidos do saldo a faturar
+func (o *SalesOrder) RegisterInvoice(invoice *Invoice, now time.Time) {
+	o.Invoices = append(o.Invoices, invoice.ID)
+	o.UpdatedAt = now
+}
+
+// ReleaseInvoice devolve ao saldo a faturar as quantidades de uma nota
+// reprovada, que não será impressa
+func (o *SalesOrder) ReleaseInvoice(invoice *Invoice, now time.Time) {
+	o.ReleaseItems(invoice.Items, now)
+}
+
+// RegisterDelivery soma às linhas as quantidades de uma nota impressa, que
+// deixam de ser reservadas por já terem sido baixadas do estoque
+func (o *SalesOrder) RegisterDelivery(invoice *Invoice, now time.Time) {
+	for _, item := range invoice.Items {
+		if item.OrderLine >= 1 && item.OrderLine <= len(o.Lines) {
+			o.Lines[item.OrderLine-1].Delivered += item.Quantity
+		}
+	}
+	o.UpdatedAt = now
+}
+
+// addInvoiced ajusta as quantidades faturadas e a situação do pedido
+func (o *SalesOrder) addInvoiced(items []InvoiceItem, sign int) {
+	for _, item := range items {
+		if item.OrderLine >= 1 && item.OrderLine <= len(o.Lines) {
+			o.Lines[item.OrderLine-1].Invoiced += sign * item.Quantity
+		}
+	}
+	if o.Status == OrderCancelled || o.Status == OrderDraft {
+		return
+	}
+
+	invoiced, pending := 0, 0
+	for _, line := range o.Lines {
+		invoiced += line.Invoiced
+		pending += line.Remaining()
+	}
+	switch {
+	case pending == 0:
+		o.Status = OrderInvoiced
+	case invoiced > 0:
+		o.Status = OrderPartiallyInvoiced
+	default:
+		o.Status = OrderConfirmed
+	}
+}
+
+// SalesOrderRepository define o contrato para persistência de pedidos de venda
+type SalesOrderRepository interface {
+	Create(order *SalesOrder) error
+	FindByID(id string) (*SalesOrder, error)
+	FindAll() ([]*SalesOrder, error)
+	Update(order *SalesOrder) error
+	GetNextNumber() (int, error) // Retorna o próximo número sequencial de pedido
+}
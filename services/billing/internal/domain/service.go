@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ItemType distingue mercadorias (NF-e/NFC-e, com baixa de estoque) de
+// serviços (NFS-e, tributados pelo ISS)
+type ItemType string
+
+const (
+	ItemGoods   ItemType = "MERCADORIA"
+	ItemService ItemType = "SERVICO"
+)
+
+// Limites da alíquota do ISS (LC 116/2003, arts. 8º e 8º-A)
+const (
+	MinISSRate = 2.0
+	MaxISSRate = 5.0
+)
+
+// Service representa um serviço do catálogo, com a classificação usada na NFS-e
+type Service struct {
+	ID              string    `json:"id"`
+	Code            string    `json:"code"` // Código interno do serviço
+	Description     string    `json:"description"`
+	ServiceListItem string    `json:"service_list_item"`        // Subitem da lista anexa à LC 116/2003 (ex.: 01.07)
+	MunicipalCode   string    `json:"municipal_code,omitempty"` // Código de tributação municipal (cTribMun)
+	ISSRate         float64   `json:"iss_rate"`                 // Alíquota do ISS no município (%)
+	Unit            string    `json:"unit"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// Erros de domínio do catálogo de serviços
+var (
+	ErrServiceNotFound        = errors.New("serviço não encontrado")
+	ErrInvalidService         = errors.New("serviço inválido: código e descrição são obrigatórios")
+	ErrDuplicateServiceCode   = errors.New("já existe serviço com este código")
+	ErrInvalidServiceListItem = errors.New("subitem da lista de serviços deve ter o formato 00.00 (LC 116/2003)")
+	ErrInvalidMunicipalCode   = errors.New("código de tributação municipal deve ter 3 dígitos")
+	ErrInvalidISSRate         = errors.New("alíquota do ISS deve estar entre 2% e 5%")
+)
+
+var (
+	serviceListItemPattern = regexp.MustCompile(`^[0-9]{2}\.[0-9]{2}$`)
+	municipalCodePattern   = regexp.MustCompile(`^[0-9]{3}$`)
+)
+
+// Normalize padroniza código, descrição, subitem da lista e unidade
+func (s *Service) Normalize() {
+	s.Code = strings.TrimSpace(s.Code)
+	s.Description = strings.TrimSpace(s.Description)
+	s.MunicipalCode = strings.TrimSpace(s.MunicipalCode)
+	// Aceita o subitem sem pontuação (0107)
+	item := strings.TrimSpace(s.ServiceListItem)
+	if digits := onlyDigits(item); len(digits) == 4 && digits == item {
+		item = digits[:2] + "." + digits[2:]
+	}
+	s.ServiceListItem = item
+	s.Unit = strings.ToUpper(strings.TrimSpace(s.Unit))
+	if s.Unit == "" {
+		s.Unit = "UN"
+	}
+}
+
+// Validate valida os dados do serviço
+func (s *Service) Validate() error {
+	if s.Code == "" || s.Description == "" {
+		return ErrInvalidService
+	}
+	if !serviceListItemPattern.MatchString(s.ServiceListItem) {
+		return ErrInvalidServiceListItem
+	}
+	if s.MunicipalCode != "" && !municipalCodePattern.MatchString(s.MunicipalCode) {
+		return ErrInvalidMunicipalCode
+	}
+	if s.ISSRate < MinISSRate || s.ISSRate > MaxISSRate {
+		return ErrInvalidISSRate
+	}
+	return nil
+}
+
+// NationalTaxCode monta o código de tributação nacional (cTribNac) da NFS-e a
+// partir do subitem da lista: item (2) + subitem (2) + desdobramento "01"
+func NationalTaxCode(serviceListItem string) string {
+	return strings.ReplaceAll(serviceListItem, ".", "") + "01"
+}
+
+// ServiceRepository define o contrato para persistência do catálogo de serviços
+type ServiceRepository interface {
+	Create(service *Service) error
+	FindByID(id string) (*Service, error)
+	FindAll() ([]*Service, error)
+	Update(service *Service) error
+	Delete(id string) error
+}
@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestServiceValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		service Service
+		err     error
+	}{
+		{name: "válido", service: Service{Code: "S1", Description: "Suporte técnico", ServiceListItem: "01.07", ISSRate: 5}},
+		{name: "subitem sem pontuação", service: Service{Code: "S1", Description: "Suporte técnico", ServiceListItem: "0107", ISSRate: 2}},
+		{name: "com código municipal", service: Service{Code: "S1", Description: "Suporte técnico", ServiceListItem: "01.07", MunicipalCode: "001", ISSRate: 2.5}},
+		{name: "sem descrição", service: Service{Code: "S1", ServiceListItem: "01.07", ISSRate: 5}, err: ErrInvalidService},
+		{name: "subitem inválido", service: Service{Code: "S1", Description: "Suporte técnico", ServiceListItem: "1.07", ISSRate: 5}, err: ErrInvalidServiceListItem},
+		{name: "código municipal inválido", service: Service{Code: "S1", Description: "Suporte técnico", ServiceListItem: "01.07", MunicipalCode: "01", ISSRate: 5}, err: ErrInvalidMunicipalCode},
+		{name: "ISS abaixo do mínimo", service: Service{Code: "S1", Description: "Suporte técnico", ServiceListItem: "01.07", ISSRate: 1.99}, err: ErrInvalidISSRate},
+		{name: "ISS acima do máximo", service: Service{Code: "S1", Description: "Suporte técnico", ServiceListItem: "01.07", ISSRate: 5.01}, err: ErrInvalidISSRate},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := tt.service
+			service.Normalize()
+			if err := service.Validate(); !errors.Is(err, tt.err) {
+				t.Errorf("Validate() erro = %v, esperado %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestNationalTaxCode(t *testing.T) {
+	if code := NationalTaxCode("01.07"); code != "010701" {
+		t.Errorf("NationalTaxCode(01.07) = %s, esperado 010701", code)
+	}
+	if code := NationalTaxCode("17.01"); code != "170101" {
+		t.Errorf("NationalTaxCode(17.01) = %s, esperado 170101", code)
+	}
+}
+
+func TestCalculateServiceTaxes(t *testing.T) {
+	config := DefaultTaxConfig()
+	issuedAt := time.Date(2026, 3, 10, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		regime TaxRegime
+		legacy LegacyTaxes
+		base   Money // Base do IBS/CBS
+		ibs    Money
+		cbs    Money
+	}{
+		{
+			// ISS de 5% no lugar do ICMS; a base do IBS/CBS exclui ISS, PIS e COFINS
+			name:   "regime normal",
+			regime: TaxRegimeNormal,
+			legacy: LegacyTaxes{
+				PISCST: PISCOFINSCSTTaxed, COFINSCST: PISCOFINSCSTTaxed,
+				PISRate: 1.65, PISValue: 165, COFINSRate: 7.6, COFINSValue: 760,
+			},
+			base: 8575, ibs: 9, cbs: 77,
+		},
+		{
+			// IBS de R$ 0,095 e CBS de R$ 0,855 arredondam para cima
+			name:   "Simples Nacional",
+			regime: TaxRegimeSimples,
+			legacy: LegacyTaxes{PISCST: PISCOFINSCSTOther, COFINSCST: PISCOFINSCSTOther},
+			base:   9500, ibs: 10, cbs: 86,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			taxes := config.CalculateServiceTaxes(10000, 5, issuedAt, tt.regime)
+			if taxes.Legacy != tt.legacy {
+				t.Errorf("tributos atuais = %+v, esperado %+v", taxes.Legacy, tt.legacy)
+			}
+			if iss := taxes.ISS; iss == nil || *iss != (ISSTaxes{Base: 10000, Rate: 5, Value: 500}) {
+				t.Errorf("ISS = %+v, esperado base 10000, alíquota 5 e valor 500", iss)
+			}
+			if ibscbs := taxes.IBSCBS; ibscbs == nil || ibscbs.Base != tt.base || ibscbs.IBSValue() != tt.ibs || ibscbs.CBSValue != tt.cbs {
+				t.Errorf("IBS/CBS = %+v, esperado base %d, IBS %d e CBS %d", ibscbs, tt.base, tt.ibs, tt.cbs)
+			}
+		})
+	}
+}
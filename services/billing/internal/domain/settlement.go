@@ -0,0 +1,279 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"time"
+)
+
+// InstallmentStatus representa a situação de uma parcela a receber
+type InstallmentStatus string
+
+const (
+	InstallmentOpen          InstallmentStatus = "ABERTA"
+	InstallmentPartiallyPaid InstallmentStatus = "PARCIALMENTE_PAGA"
+	InstallmentPaid          InstallmentStatus = "PAGA"
+	InstallmentOverdue       InstallmentStatus = "VENCIDA" // Vencida com saldo em aberto, paga em parte ou não
+)
+
+// LateFeeConfig define os encargos cobrados no pagamento em atraso: multa
+// única sobre o principal e juros simples ao mês, calculados pro rata die
+type LateFeeConfig struct {
+	PenaltyRate         float64 // Multa em % (ex: 2 para 2%)
+	MonthlyInterestRate float64 // Juros de mora em % ao mês (ex: 1 para 1% a.m.)
+}
+
+// DefaultLateFeeConfig retorna multa de 2% e juros de 1% ao mês
+func DefaultLateFeeConfig() LateFeeConfig {
+	return LateFeeConfig{PenaltyRate: 2, MonthlyInterestRate: 1}
+}
+
+// PaymentRecord registra um recebimento e como ele foi distribuído entre as
+// parcelas da nota
+type PaymentRecord struct {
+	ID          string              `json:"id"`
+	Amount      Money               `json:"amount"` // Valor recebido, encargos inclusos
+	PaidAt      time.Time           `json:"paid_at"`
+	Method      PaymentMethod       `json:"method,omitempty"`
+	Allocations []PaymentAllocation `json:"allocations"`
+	CreatedAt   time.Time           `json:"created_at"`
+}
+
+// PaymentAllocation é a parte de um recebimento aplicada a uma parcela
+type PaymentAllocation struct {
+	Installment string `json:"installment"` // nDup da parcela
+	Principal   Money  `json:"principal"`   // Abatido do saldo da parcela
+	Penalty     Money  `json:"penalty"`
+	Interest    Money  `json:"interest"`
+	DaysLate    int    `json:"days_late,omitempty"`
+}
+
+// PaymentInput descreve um recebimento a registrar. Sem parcela, o valor é
+// aplicado às parcelas em aberto pela ordem de vencimento
+type PaymentInput struct {
+	ID          string // Identificador do recebimento, gerado pelo serviço
+	Installment string
+	Amount      Money
+	PaidAt      time.Time
+	Method      PaymentMethod
+}
+
+// Erros de domínio dos recebimentos
+var (
+	ErrPaymentNotAllowed     = errors.New("nota fiscal não admite recebimento: deve estar fechada e autorizada pela SEFAZ")
+	ErrNoInstallments        = errors.New("nota fiscal não possui parcelas a receber")
+	ErrInstallmentNotFound   = errors.New("parcela não encontrada")
+	ErrInstallmentPaid       = errors.New("parcela já está paga")
+	ErrInvoicePaid           = errors.New("todas as parcelas da nota já estão pagas")
+	ErrInvalidPaymentAmount  = errors.New("valor recebido deve ser positivo")
+	ErrInvalidPaymentDate    = errors.New("data do recebimento não pode ser anterior à emissão nem futura")
+	ErrPaymentExceedsBalance = errors.New("valor recebido excede o saldo devedor com encargos")
+)
+
+// Outstanding retorna o saldo em aberto da parcela, sem encargos
+func (inst Installment) Outstanding() Money {
+	if inst.Paid >= inst.Amount {
+		return 0
+	}
+	return inst.Amount - inst.Paid
+}
+
+// StatusAt classifica a parcela na data informada
+func (inst Installment) StatusAt(date time.Time) InstallmentStatus {
+	switch {
+	case inst.Outstanding() == 0:
+		return InstallmentPaid
+	case DaysBetween(inst.DueDate, date) > 0:
+		return InstallmentOverdue
+	case inst.Paid > 0:
+		return InstallmentPartiallyPaid
+	default:
+		return InstallmentOpen
+	}
+}
+
+// RefreshInstallments atualiza a situação das parcelas na data informada
+func (i *Invoice) RefreshInstallments(date time.Time) {
+	for idx := range i.Installments {
+		i.Installments[idx].Status = i.Installments[idx].StatusAt(date)
+	}
+}
+
+// WithInstallmentStatus retorna uma cópia da nota com a situação das parcelas
+// na data informada, sem alterar a nota original, que pode estar sendo
+// atualizada por um recebimento
+func (i *Invoice) WithInstallmentStatus(date time.Time) *Invoice {
+	view := *i
+	view.Installments = slices.Clone(i.Installments)
+	view.RefreshInstallments(date)
+	return &view
+}
+
+// CanReceivePayment verifica se a nota admite recebimentos: só notas
+// emitidas e válidas perante o fisco geram valores a receber. Com pedido de
+// autorização, a nota precisa estar autorizada; pendentes, rejeitadas e
+// denegadas não recebem. Notas fechadas sem SEFAZ configurada não têm pedido
+func (i *Invoice) CanReceivePayment() error {
+	if !i.IsClosed() || i.ClosedAt == nil {
+		return ErrPaymentNotAllowed
+	}
+	if i.Authorization != nil && i.Authorization.Status != AuthorizationAuthorized {
+		return ErrPaymentNotAllowed
+	}
+	if len(i.Installments) == 0 {
+		return ErrNoInstallments
+	}
+	return nil
+}
+
+// LateCharges calcula multa e juros sobre o principal pago com atraso
+func (c LateFeeConfig) LateCharges(principal Money, daysLate int) (penalty, interest Money) {
+	if daysLate <= 0 || principal <= 0 {
+		return 0, 0
+	}
+	penalty = principal.ApplyRate(c.PenaltyRate)
+	interest = principal.ApplyRate(c.MonthlyInterestRate * float64(daysLate) / 30)
+	return penalty, interest
+}
+
+// RegisterPayment distribui um recebimento entre as parcelas em aberto. Em
+// cada parcela vencida o valor cobre primeiro o principal com seus encargos;
+// um valor menor que o devido quita o principal proporcional. O recebimento
+// que excede o saldo devedor é recusado sem alterar a nota
+func (i *Invoice) RegisterPayment(input PaymentInput, fees LateFeeConfig) (*PaymentRecord, error) {
+	if err := i.CanReceivePayment(); err != nil {
+		return nil, err
+	}
+	if input.Amount <= 0 {
+		return nil, ErrInvalidPaymentAmount
+	}
+	if DaysBetween(*i.ClosedAt, input.PaidAt) < 0 || input.PaidAt.After(time.Now()) {
+		return nil, ErrInvalidPaymentDate
+	}
+
+	targets, err := i.paymentTargets(input.Installment)
+	if err != nil {
+		return nil, err
+	}
+
+	remaining := input.Amount
+	var allocations []PaymentAllocation
+	for _, idx := range targets {
+		if remaining == 0 {
+			break
+		}
+		inst := i.Installments[idx]
+		allocation := allocate(inst, remaining, input.PaidAt, fees)
+		remaining -= allocation.Principal + allocation.Penalty + allocation.Interest
+		allocations = append(allocations, allocation)
+	}
+	if remaining > 0 {
+		return nil, ErrPaymentExceedsBalance
+	}
+
+	for _, allocation := range allocations {
+		for idx := range i.Installments {
+			if i.Installments[idx].Number == allocation.Installment {
+				i.Installments[idx].Paid += allocation.Principal
+			}
+		}
+	}
+	record := &PaymentRecord{
+		ID:          input.ID,
+		Amount:      input.Amount,
+		PaidAt:      input.PaidAt,
+		Method:      input.Method,
+		Allocations: allocations,
+		CreatedAt:   time.Now(),
+	}
+	i.Payments = append(i.Payments, *record)
+	return record, nil
+}
+
+// AmountDue retorna o valor devido na data, com multa e juros, da parcela
+// informada ou, sem ela, de todas as parcelas em aberto
+func (i *Invoice) AmountDue(number string, date time.Time, fees LateFeeConfig) (Money, error) {
+	if err := i.CanReceivePayment(); err != nil {
+		return 0, err
+	}
+	targets, err := i.paymentTargets(number)
+	if err != nil {
+		return 0, err
+	}
+
+	var total Money
+	for _, idx := range targets {
+		inst := i.Installments[idx]
+		penalty, interest := fees.LateCharges(inst.Outstanding(), DaysBetween(inst.DueDate, date))
+		total += inst.Outstanding() + penalty + interest
+	}
+	return total, nil
+}
+
+// paymentTargets retorna os índices das parcelas que recebem o pagamento:
+// a parcela informada ou, sem ela, as parcelas em aberto por vencimento
+func (i *Invoice) paymentTargets(number string) ([]int, error) {
+	if number != "" {
+		for idx, inst := range i.Installments {
+			if inst.Number != number {
+				continue
+			}
+			if inst.Outstanding() == 0 {
+				return nil, ErrInstallmentPaid
+			}
+			return []int{idx}, nil
+		}
+		return nil, ErrInstallmentNotFound
+	}
+
+	var targets []int
+	for idx, inst := range i.Installments {
+		if inst.Outstanding() > 0 {
+			targets = append(targets, idx)
+		}
+	}
+	if len(targets) == 0 {
+		return nil, ErrInvoicePaid
+	}
+	return targets, nil
+}
+
+// allocate aplica até o valor disponível a uma parcela. Quando o valor não
+// quita a parcela com encargos, o principal é o valor dividido pelo fator de
+// encargos e a diferença de arredondamento fica nos juros
+func allocate(inst Installment, available Money, paidAt time.Time, fees LateFeeConfig) PaymentAllocation {
+	daysLate := DaysBetween(inst.DueDate, paidAt)
+	if daysLate < 0 {
+		daysLate = 0
+	}
+	principal := inst.Outstanding()
+	penalty, interest := fees.LateCharges(principal, daysLate)
+	if available < principal+penalty+interest {
+		factor := 1.0
+		if daysLate > 0 {
+			factor += fees.PenaltyRate/100 + fees.MonthlyInterestRate*float64(daysLate)/3000
+		}
+		principal = Money(float64(available) / factor)
+		penalty, _ = fees.LateCharges(principal, daysLate)
+		interest = available - principal - penalty
+		if interest < 0 {
+			penalty += interest
+			interest = 0
+		}
+	}
+	return PaymentAllocation{
+		Installment: inst.Number,
+		Principal:   principal,
+		Penalty:     penalty,
+		Interest:    interest,
+		DaysLate:    daysLate,
+	}
+}
+
+// DaysBetween conta os dias corridos entre as datas de from e to,
+// desconsiderando o horário
+func DaysBetween(from, to time.Time) int {
+	start := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	end := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	return int(end.Sub(start).Hours() / 24)
+}
@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCanReceivePayment(t *testing.T) {
+	closedAt := time.Date(2025, 3, 10, 10, 0, 0, 0, time.UTC)
+	authorization := func(status AuthorizationStatus) *Authorization {
+		return &Authorization{Status: status}
+	}
+
+	tests := []struct {
+		name          string
+		status        InvoiceStatus
+		authorization *Authorization
+		err           error
+	}{
+		{name: "autorizada", status: StatusClosed, authorization: authorization(AuthorizationAuthorized)},
+		{name: "fechada sem pedido de autorização", status: StatusClosed},
+		{name: "autorização pendente", status: StatusClosed, authorization: authorization(AuthorizationPending), err: ErrPaymentNotAllowed},
+		{name: "rejeitada", status: StatusClosed, authorization: authorization(AuthorizationRejected), err: ErrPaymentNotAllowed},
+		{name: "denegada", status: StatusClosed, authorization: authorization(AuthorizationDenied), err: ErrPaymentNotAllowed},
+		{name: "aberta", status: StatusOpen, err: ErrPaymentNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			invoice := &Invoice{
+				Status:        tt.status,
+				ClosedAt:      &closedAt,
+				Authorization: tt.authorization,
+				Installments:  []Installment{{Number: "001", DueDate: closedAt.AddDate(0, 0, 30), Amount: 1000}},
+			}
+			if err := invoice.CanReceivePayment(); !errors.Is(err, tt.err) {
+				t.Errorf("CanReceivePayment() erro = %v, esperado %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestWithInstallmentStatus(t *testing.T) {
+	due := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	invoice := &Invoice{Installments: []Installment{
+		{Number: "001", DueDate: due, Amount: 1000, Status: InstallmentOpen},
+		{Number: "002", DueDate: due.AddDate(0, 0, 30), Amount: 1000, Paid: 1000, Status: InstallmentPaid},
+	}}
+
+	view := invoice.WithInstallmentStatus(due.AddDate(0, 0, 1))
+	if view.Installments[0].Status != InstallmentOverdue || view.Installments[1].Status != InstallmentPaid {
+		t.Errorf("situação das parcelas = %s, %s, esperado %s, %s",
+			view.Installments[0].Status, view.Installments[1].Status, InstallmentOverdue, InstallmentPaid)
+	}
+	// A consulta não altera a nota armazenada
+	if invoice.Installments[0].Status != InstallmentOpen {
+		t.Errorf("situação original = %s, esperado %s", invoice.Installments[0].Status, InstallmentOpen)
+	}
+}
@@ -0,0 +1,235 @@
+package domain
+
+import "time"
+
+// LegacyTaxConfig define as alíquotas dos tributos atuais (ICMS, PIS e COFINS)
+type LegacyTaxConfig struct {
+	ICMSRate   float64 `json:"icms_rate"`   // Alíquota de ICMS (%)
+	PISRate    float64 `json:"pis_rate"`    // Alíquota de PIS (%)
+	COFINSRate float64 `json:"cofins_rate"` // Alíquota de COFINS (%)
+}
+
+// TaxReformRates define as alíquotas de IBS/CBS vigentes em um ano de transição
+type TaxReformRates struct {
+	IBSUFRate  float64 `json:"ibs_uf_rate"`  // Alíquota do IBS estadual (%)
+	IBSMunRate float64 `json:"ibs_mun_rate"` // Alíquota do IBS municipal (%)
+	CBSRate    float64 `json:"cbs_rate"`     // Alíquota da CBS (%)
+}
+
+// TaxReformConfig parametriza o cálculo do IBS/CBS da reforma tributária
+type TaxReformConfig struct {
+	CST       string                 `json:"cst"`        // Código de situação tributária do IBS/CBS
+	ClassTrib string                 `json:"class_trib"` // Código de classificação tributária (cClassTrib)
+	Rates     map[int]TaxReformRates `json:"rates"`      // Alíquotas por ano de início de vigência
+}
+
+// TaxConfig agrupa a configuração dos tributos atuais e da reforma tributária
+type TaxConfig struct {
+	Legacy LegacyTaxConfig `json:"legacy"`
+	Reform TaxReformConfig `json:"reform"`
+}
+
+// DefaultTaxConfig retorna a configuração padrão do período de teste de 2026
+// (CBS 0,9% e IBS 0,1%)
+func DefaultTaxConfig() TaxConfig {
+	return TaxConfig{
+		Legacy: LegacyTaxConfig{
+			ICMSRate:   18,
+			PISRate:    1.65,
+			COFINSRate: 7.6,
+		},
+		Reform: TaxReformConfig{
+			CST:       "000",
+			ClassTrib: "000001",
+			Rates: map[int]TaxReformRates{
+				2026: {IBSUFRate: 0.1, IBSMunRate: 0, CBSRate: 0.9},
+			},
+		},
+	}
+}
+
+// RatesFor retorna as alíquotas vigentes no ano informado, ou seja,
+// as do maior ano configurado que seja menor ou igual a ele
+func (c TaxReformConfig) RatesFor(year int) (TaxReformRates, bool) {
+	best := 0
+	for y := range c.Rates {
+		if y <= year && y > best {
+			best = y
+		}
+	}
+	if best == 0 {
+		return TaxReformRates{}, false
+	}
+	return c.Rates[best], true
+}
+
+// Códigos de situação tributária usados no cálculo dos tributos atuais
+const (
+	ICMSCSTTaxed           = "00"  // Tributada integralmente (regime normal)
+	ICMSCSOSNWithoutCredit = "102" // Simples Nacional sem permissão de crédito
+	PISCOFINSCSTTaxed      = "01"  // Operação tributável com alíquota básica
+	PISCOFINSCSTOther      = "49"  // Outras operações de saída (Simples Nacional)
+)
+
+// LegacyTaxes representa os tributos atuais calculados para um item
+type LegacyTaxes struct {
+	ICMSCST     string  `json:"icms_cst"` // CST (regime normal) ou CSOSN (Simples Nacional)
+	PISCST      string  `json:"pis_cst"`
+	COFINSCST   string  `json:"cofins_cst"`
+	ICMSBase    Money   `json:"icms_base"`
+	ICMSRate    float64 `json:"icms_rate"`
+	ICMSValue   Money   `json:"icms_value"`
+	PISRate     float64 `json:"pis_rate"`
+	PISValue    Money   `json:"pis_value"`
+	COFINSRate  float64 `json:"cofins_rate"`
+	COFINSValue Money   `json:"cofins_value"`
+}
+
+// IBSCBSTaxes representa o grupo IBS/CBS calculado para um item
+type IBSCBSTaxes struct {
+	CST         string  `json:"cst"`
+	ClassTrib   string  `json:"class_trib"`
+	Base        Money   `json:"base"`
+	IBSUFRate   float64 `json:"ibs_uf_rate"`
+	IBSUFValue  Money   `json:"ibs_uf_value"`
+	IBSMunRate  float64 `json:"ibs_mun_rate"`
+	IBSMunValue Money   `json:"ibs_mun_value"`
+	CBSRate     float64 `json:"cbs_rate"`
+	CBSValue    Money   `json:"cbs_value"`
+}
+
+// IBSValue retorna o IBS total (estadual + municipal)
+func (t IBSCBSTaxes) IBSValue() Money {
+	return t.IBSUFValue + t.IBSMunValue
+}
+
+// ISSTaxes representa o ISS calculado para um item de serviço
+type ISSTaxes struct {
+	Base  Money   `json:"base"`
+	Rate  float64 `json:"rate"`
+	Value Money   `json:"value"`
+}
+
+// ItemTaxes agrupa os tributos de um item, mantendo legado e reforma separados
+type ItemTaxes struct {
+	Legacy LegacyTaxes  `json:"legacy"`
+	ISS    *ISSTaxes    `json:"iss,omitempty"`     // Somente em itens de serviço
+	IBSCBS *IBSCBSTaxes `json:"ibs_cbs,omitempty"` // Ausente se não houver alíquota vigente
+}
+
+// CalculateItemTaxes calcula os tributos de um item com base no valor da linha
+// (somadas as despesas acessórias rateadas), na data de emissão e no regime
+// tributário do emitente. A base do IBS/CBS exclui ICMS, PIS e COFINS,
+// conforme regra do período de transição
+func (c TaxConfig) CalculateItemTaxes(lineTotal Money, issuedAt time.Time, regime TaxRegime) ItemTaxes {
+	var legacy LegacyTaxes
+	if regime.IsSimples() {
+		// No Simples Nacional os tributos são recolhidos no DAS e não são destacados
+		legacy = LegacyTaxes{
+			ICMSCST:   ICMSCSOSNWithoutCredit,
+			PISCST:    PISCOFINSCSTOther,
+			COFINSCST: PISCOFINSCSTOther,
+		}
+	} else {
+		legacy = LegacyTaxes{
+			ICMSCST:     ICMSCSTTaxed,
+			PISCST:      PISCOFINSCSTTaxed,
+			COFINSCST:   PISCOFINSCSTTaxed,
+			ICMSBase:    lineTotal,
+			ICMSRate:    c.Legacy.ICMSRate,
+			ICMSValue:   lineTotal.ApplyRate(c.Legacy.ICMSRate),
+			PISRate:     c.Legacy.PISRate,
+			PISValue:    lineTotal.ApplyRate(c.Legacy.PISRate),
+			COFINSRate:  c.Legacy.COFINSRate,
+			COFINSValue: lineTotal.ApplyRate(c.Legacy.COFINSRate),
+		}
+	}
+	taxes := ItemTaxes{Legacy: legacy}
+	taxes.IBSCBS = c.reformTaxes(lineTotal-legacy.ICMSValue-legacy.PISValue-legacy.COFINSValue, issuedAt)
+	return taxes
+}
+
+// CalculateServiceTaxes calcula os tributos de um item de serviço: ISS com a
+// alíquota do serviço no lugar do ICMS, PIS/COFINS como nas mercadorias e o
+// IBS/CBS sobre o valor sem os tributos atuais
+func (c TaxConfig) CalculateServiceTaxes(lineTotal Money, issRate float64, issuedAt time.Time, regime TaxRegime) ItemTaxes {
+	legacy := LegacyTaxes{
+		PISCST:    PISCOFINSCSTOther,
+		COFINSCST: PISCOFINSCSTOther,
+	}
+	if !regime.IsSimples() {
+		legacy = LegacyTaxes{
+			PISCST:      PISCOFINSCSTTaxed,
+			COFINSCST:   PISCOFINSCSTTaxed,
+			PISRate:     c.Legacy.PISRate,
+			PISValue:    lineTotal.ApplyRate(c.Legacy.PISRate),
+			COFINSRate:  c.Legacy.COFINSRate,
+			COFINSValue: lineTotal.ApplyRate(c.Legacy.COFINSRate),
+		}
+	}
+	iss := &ISSTaxes{Base: lineTotal, Rate: issRate, Value: lineTotal.ApplyRate(issRate)}
+
+	return ItemTaxes{
+		Legacy: legacy,
+		ISS:    iss,
+		IBSCBS: c.reformTaxes(lineTotal-iss.Value-legacy.PISValue-legacy.COFINSValue, issuedAt),
+	}
+}
+
+// reformTaxes calcula o grupo IBS/CBS sobre a base informada, ou retorna nil
+// se não houver alíquota vigente na data de emissão
+func (c TaxConfig) reformTaxes(base Money, issuedAt time.Time) *IBSCBSTaxes {
+	rates, ok := c.Reform.RatesFor(issuedAt.Year())
+	if !ok {
+		return nil
+	}
+	return &IBSCBSTaxes{
+		CST:         c.Reform.CST,
+		ClassTrib:   c.Reform.ClassTrib,
+		Base:        base,
+		IBSUFRate:   rates.IBSUFRate,
+		IBSUFValue:  base.ApplyRate(rates.IBSUFRate),
+		IBSMunRate:  rates.IBSMunRate,
+		IBSMunValue: base.ApplyRate(rates.IBSMunRate),
+		CBSRate:     rates.CBSRate,
+		CBSValue:    base.ApplyRate(rates.CBSRate),
+	}
+}
+
+// LegacyTotals totaliza os tributos atuais da nota
+type LegacyTotals struct {
+	ICMSBase    Money `json:"icms_base"`
+	ICMSValue   Money `json:"icms_value"`
+	PISValue    Money `json:"pis_value"`
+	COFINSValue Money `json:"cofins_value"`
+}
+
+// IBSCBSTotals totaliza o grupo IBS/CBS da nota
+type IBSCBSTotals struct {
+	Base        Money `json:"base"`
+	IBSUFValue  Money `json:"ibs_uf_value"`
+	IBSMunValue Money `json:"ibs_mun_value"`
+	IBSValue    Money `json:"ibs_value"`
+	CBSValue    Money `json:"cbs_value"`
+}
+
+// ISSTotals totaliza o ISS dos itens de serviço
+type ISSTotals struct {
+	Base  Money `json:"base"`
+	Value Money `json:"value"`
+}
+
+// InvoiceTotals representa os totais da nota fiscal. No período de transição
+// o IBS/CBS é apenas informativo e não compõe o valor total da nota
+type InvoiceTotals struct {
+	Products  Money         `json:"products"`
+	Services  Money         `json:"services"`
+	Freight   Money         `json:"freight"`
+	Insurance Money         `json:"insurance"`
+	Other     Money         `json:"other"`    // Outras despesas acessórias
+	Discount  Money         `json:"discount"` // Descontos concedidos nos itens
+	Legacy    LegacyTotals  `json:"legacy"`
+	ISS       *ISSTotals    `json:"iss,omitempty"`
+	IBSCBS    *IBSCBSTotals `json:"ibs_cbs,omitempty"`
+	Total     Money         `json:"total"` // Produtos, serviços e despesas acessórias, menos os descontos
+}
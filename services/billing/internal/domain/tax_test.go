@@ -0,0 +1,157 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRatesFor(t *testing.T) {
+	config := TaxReformConfig{Rates: map[int]TaxReformRates{
+		2026: {IBSUFRate: 0.1, CBSRate: 0.9},
+		2027: {IBSUFRate: 0.05, IBSMunRate: 0.05, CBSRate: 8.8},
+		2029: {IBSUFRate: 1.77, IBSMunRate: 0.1, CBSRate: 8.8},
+	}}
+
+	tests := []struct {
+		year     int
+		expected TaxReformRates
+		found    bool
+	}{
+		{year: 2025, found: false},
+		{year: 2026, expected: config.Rates[2026], found: true},
+		{year: 2027, expected: config.Rates[2027], found: true},
+		{year: 2028, expected: config.Rates[2027], found: true},
+		{year: 2033, expected: config.Rates[2029], found: true},
+	}
+	for _, tt := range tests {
+		rates, found := config.RatesFor(tt.year)
+		if found != tt.found || rates != tt.expected {
+			t.Errorf("RatesFor(%d) = %+v, %t; esperado %+v, %t", tt.year, rates, found, tt.expected, tt.found)
+		}
+	}
+
+	if _, found := (TaxReformConfig{}).RatesFor(2026); found {
+		t.Errorf("RatesFor() sem alíquotas configuradas encontrou vigência")
+	}
+}
+
+func TestCalculateItemTaxes(t *testing.T) {
+	config := DefaultTaxConfig()
+	issued2026 := time.Date(2026, 3, 10, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		lineTotal Money
+		issuedAt  time.Time
+		regime    TaxRegime
+		legacy    LegacyTaxes
+		ibscbs    *IBSCBSTaxes
+	}{
+		{
+			// ICMS 18%, PIS 1,65% e COFINS 7,6% sobre R$ 100,00; IBS/CBS sobre
+			// R$ 100,00 - R$ 27,25
+			name:      "regime normal",
+			lineTotal: 10000,
+			issuedAt:  issued2026,
+			regime:    TaxRegimeNormal,
+			legacy: LegacyTaxes{
+				ICMSCST: ICMSCSTTaxed, PISCST: PISCOFINSCSTTaxed, COFINSCST: PISCOFINSCSTTaxed,
+				ICMSBase: 10000, ICMSRate: 18, ICMSValue: 1800,
+				PISRate: 1.65, PISValue: 165, COFINSRate: 7.6, COFINSValue: 760,
+			},
+			ibscbs: &IBSCBSTaxes{
+				CST: "000", ClassTrib: "000001", Base: 7275,
+				IBSUFRate: 0.1, IBSUFValue: 7, CBSRate: 0.9, CBSValue: 65,
+			},
+		},
+		{
+			// PIS de R$ 0,20625 arredonda para cima; IBS de R$ 0,00909 para R$ 0,01
+			// e CBS de R$ 0,08181 para R$ 0,08
+			name:      "arredondamento ao centavo mais próximo",
+			lineTotal: 1250,
+			issuedAt:  issued2026,
+			regime:    TaxRegimeNormal,
+			legacy: LegacyTaxes{
+				ICMSCST: ICMSCSTTaxed, PISCST: PISCOFINSCSTTaxed, COFINSCST: PISCOFINSCSTTaxed,
+				ICMSBase: 1250, ICMSRate: 18, ICMSValue: 225,
+				PISRate: 1.65, PISValue: 21, COFINSRate: 7.6, COFINSValue: 95,
+			},
+			ibscbs: &IBSCBSTaxes{
+				CST: "000", ClassTrib: "000001", Base: 909,
+				IBSUFRate: 0.1, IBSUFValue: 1, CBSRate: 0.9, CBSValue: 8,
+			},
+		},
+		{
+			// ICMS de R$ 0,5994 para R$ 0,60, PIS de R$ 0,054945 para R$ 0,05 e
+			// IBS de R$ 0,00243 para zero
+			name:      "valores abaixo de meio centavo",
+			lineTotal: 333,
+			issuedAt:  issued2026,
+			regime:    TaxRegimeNormal,
+			legacy: LegacyTaxes{
+				ICMSCST: ICMSCSTTaxed, PISCST: PISCOFINSCSTTaxed, COFINSCST: PISCOFINSCSTTaxed,
+				ICMSBase: 333, ICMSRate: 18, ICMSValue: 60,
+				PISRate: 1.65, PISValue: 5, COFINSRate: 7.6, COFINSValue: 25,
+			},
+			ibscbs: &IBSCBSTaxes{
+				CST: "000", ClassTrib: "000001", Base: 243,
+				IBSUFRate: 0.1, IBSUFValue: 0, CBSRate: 0.9, CBSValue: 2,
+			},
+		},
+		{
+			// No Simples Nacional os tributos atuais não são destacados e a base
+			// do IBS/CBS é o valor integral da linha
+			name:      "Simples Nacional",
+			lineTotal: 10000,
+			issuedAt:  issued2026,
+			regime:    TaxRegimeSimples,
+			legacy:    LegacyTaxes{ICMSCST: ICMSCSOSNWithoutCredit, PISCST: PISCOFINSCSTOther, COFINSCST: PISCOFINSCSTOther},
+			ibscbs: &IBSCBSTaxes{
+				CST: "000", ClassTrib: "000001", Base: 10000,
+				IBSUFRate: 0.1, IBSUFValue: 10, CBSRate: 0.9, CBSValue: 90,
+			},
+		},
+		{
+			name:      "MEI",
+			lineTotal: 500,
+			issuedAt:  issued2026,
+			regime:    TaxRegimeMEI,
+			legacy:    LegacyTaxes{ICMSCST: ICMSCSOSNWithoutCredit, PISCST: PISCOFINSCSTOther, COFINSCST: PISCOFINSCSTOther},
+			ibscbs: &IBSCBSTaxes{
+				CST: "000", ClassTrib: "000001", Base: 500,
+				IBSUFRate: 0.1, IBSUFValue: 1, CBSRate: 0.9, CBSValue: 5,
+			},
+		},
+		{
+			name:      "sem alíquota vigente do IBS/CBS",
+			lineTotal: 10000,
+			issuedAt:  time.Date(2025, 12, 31, 23, 0, 0, 0, time.UTC),
+			regime:    TaxRegimeNormal,
+			legacy: LegacyTaxes{
+				ICMSCST: ICMSCSTTaxed, PISCST: PISCOFINSCSTTaxed, COFINSCST: PISCOFINSCSTTaxed,
+				ICMSBase: 10000, ICMSRate: 18, ICMSValue: 1800,
+				PISRate: 1.65, PISValue: 165, COFINSRate: 7.6, COFINSValue: 760,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			taxes := config.CalculateItemTaxes(tt.lineTotal, tt.issuedAt, tt.regime)
+			if taxes.Legacy != tt.legacy {
+				t.Errorf("tributos atuais = %+v, esperado %+v", taxes.Legacy, tt.legacy)
+			}
+			if taxes.ISS != nil {
+				t.Errorf("ISS = %+v em item de mercadoria", taxes.ISS)
+			}
+			switch {
+			case tt.ibscbs == nil && taxes.IBSCBS != nil:
+				t.Errorf("IBS/CBS = %+v, esperado ausente", taxes.IBSCBS)
+			case tt.ibscbs != nil && taxes.IBSCBS == nil:
+				t.Errorf("IBS/CBS ausente, esperado %+v", tt.ibscbs)
+			case tt.ibscbs != nil && *taxes.IBSCBS != *tt.ibscbs:
+				t.Errorf("IBS/CBS = %+v, esperado %+v", taxes.IBSCBS, tt.ibscbs)
+			}
+		})
+	}
+}
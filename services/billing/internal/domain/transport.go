@@ -0,0 +1,133 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"regexp"
+	"strings"
+)
+
+// FreightMode representa a modalidade do frete (modFrete da NF-e)
+type FreightMode int
+
+const (
+	FreightBySender       FreightMode = 0 // Contratação por conta do remetente (CIF)
+	FreightByRecipient    FreightMode = 1 // Contratação por conta do destinatário (FOB)
+	FreightByThirdParty   FreightMode = 2 // Contratação por conta de terceiros
+	FreightOwnBySender    FreightMode = 3 // Transporte próprio por conta do remetente
+	FreightOwnByRecipient FreightMode = 4 // Transporte próprio por conta do destinatário
+	FreightNoTransport    FreightMode = 9 // Sem ocorrência de transporte
+)
+
+// IsValid verifica se a modalidade do frete é conhecida
+func (m FreightMode) IsValid() bool {
+	return (m >= FreightBySender && m <= FreightOwnByRecipient) || m == FreightNoTransport
+}
+
+// Vehicle identifica o veículo que transporta a mercadoria
+type Vehicle struct {
+	Plate string `json:"plate"`          // Placa (padrão antigo ou Mercosul)
+	UF    string `json:"uf"`             // UF de licenciamento
+	RNTC  string `json:"rntc,omitempty"` // Registro na ANTT
+}
+
+// Transport reúne os dados de transporte da nota de mercadorias: modalidade
+// do frete, transportadora, veículo, volumes e pesos (em kg)
+type Transport struct {
+	FreightMode FreightMode `json:"freight_mode"`
+	CarrierID   string      `json:"carrier_id,omitempty"`
+	Carrier     *Carrier    `json:"carrier,omitempty"` // Snapshot da transportadora na criação
+	Vehicle     *Vehicle    `json:"vehicle,omitempty"`
+	Volumes     int         `json:"volumes,omitempty"` // Quantidade de volumes transportados
+	Species     string      `json:"species,omitempty"` // Espécie dos volumes (caixa, palete...)
+	GrossWeight float64     `json:"gross_weight,omitempty"`
+	NetWeight   float64     `json:"net_weight,omitempty"`
+}
+
+// Erros de domínio do transporte
+var (
+	ErrInvalidFreightMode      = errors.New("modalidade do frete deve ser 0, 1, 2, 3, 4 ou 9")
+	ErrConsumerFreight         = errors.New("NFC-e não admite transporte; a modalidade do frete deve ser 9 (sem transporte)")
+	ErrServiceTransport        = errors.New("NFS-e não admite dados de transporte")
+	ErrTransportWithoutFreight = errors.New("transportadora e veículo exigem modalidade de frete diferente de 9")
+	ErrInvalidVehiclePlate     = errors.New("placa do veículo inválida (AAA9999 ou AAA9A99)")
+	ErrInterstateVehicle       = errors.New("veículo não pode ser informado em operação interestadual")
+	ErrInvalidVolumes          = errors.New("quantidade de volumes e pesos não podem ser negativos")
+	ErrNetWeightExceedsGross   = errors.New("peso líquido não pode exceder o peso bruto")
+)
+
+// vehiclePlatePattern aceita a placa no padrão antigo (AAA9999) e no Mercosul (AAA9A99)
+var vehiclePlatePattern = regexp.MustCompile(`^[A-Z]{3}[0-9][A-Z0-9][0-9]{2}$`)
+
+// Normalize padroniza placa, UF, RNTC e espécie, e arredonda os pesos ao grama
+func (t *Transport) Normalize() {
+	t.CarrierID = strings.TrimSpace(t.CarrierID)
+	t.Species = strings.TrimSpace(t.Species)
+	t.GrossWeight = RoundWeight(t.GrossWeight)
+	t.NetWeight = RoundWeight(t.NetWeight)
+	if v := t.Vehicle; v != nil {
+		v.Plate = strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(v.Plate))
+		v.UF = strings.ToUpper(strings.TrimSpace(v.UF))
+		v.RNTC = strings.ToUpper(strings.TrimSpace(v.RNTC))
+	}
+}
+
+// Validate valida os dados de transporte
+func (t *Transport) Validate() error {
+	if !t.FreightMode.IsValid() {
+		return ErrInvalidFreightMode
+	}
+	if t.FreightMode == FreightNoTransport && (t.CarrierID != "" || t.Vehicle != nil) {
+		return ErrTransportWithoutFreight
+	}
+	if v := t.Vehicle; v != nil {
+		if !vehiclePlatePattern.MatchString(v.Plate) {
+			return ErrInvalidVehiclePlate
+		}
+		if !IsValidUF(v.UF) {
+			return ErrInvalidUF
+		}
+		if len(v.RNTC) > 20 {
+			return ErrInvalidRNTC
+		}
+	}
+	if t.Volumes < 0 || t.GrossWeight < 0 || t.NetWeight < 0 {
+		return ErrInvalidVolumes
+	}
+	if t.GrossWeight > 0 && t.NetWeight > t.GrossWeight {
+		return ErrNetWeightExceedsGross
+	}
+	return nil
+}
+
+// CheckRoute valida o transporte conforme a rota: o veículo só é informado
+// em operações dentro do estado
+func (t *Transport) CheckRoute(interstate bool) error {
+	if interstate && t.Vehicle != nil {
+		return ErrInterstateVehicle
+	}
+	return nil
+}
+
+// HasVolumes indica se há volumes ou pesos a informar
+func (t *Transport) HasVolumes() bool {
+	return t.Volumes > 0 || t.Species != "" || t.GrossWeight > 0 || t.NetWeight > 0
+}
+
+// ItemsNetWeight soma o peso líquido dos itens a partir do peso unitário
+// copiado dos produtos. Retorna false se algum item não tiver peso cadastrado
+func ItemsNetWeight(items []InvoiceItem) (float64, bool) {
+	var total float64
+	for _, item := range items {
+		if item.UnitWeight <= 0 {
+			return 0, false
+		}
+		total += item.UnitWeight * float64(item.Quantity)
+	}
+	return RoundWeight(total), len(items) > 0
+}
+
+// RoundWeight arredonda um peso em kg para três casas decimais (gramas)
+func RoundWeight(weight float64) float64 {
+	return math.Round(weight*1000) / 1000
+}
@@ -0,0 +1,126 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTransportValidate(t *testing.T) {
+	vehicle := func(plate, uf string) *Vehicle { return &Vehicle{Plate: plate, UF: uf} }
+
+	tests := []struct {
+		name      string
+		transport Transport
+		err       error
+	}{
+		{name: "sem transporte", transport: Transport{FreightMode: FreightNoTransport}},
+		{name: "CIF com transportadora", transport: Transport{FreightMode: FreightBySender, CarrierID: "carrier-1", Volumes: 2, Species: "CAIXA", GrossWeight: 12.5, NetWeight: 10}},
+		{name: "transporte próprio", transport: Transport{FreightMode: FreightOwnByRecipient, Vehicle: vehicle("abc-1234", "sp")}},
+		{name: "placa Mercosul", transport: Transport{FreightMode: FreightByRecipient, Vehicle: vehicle("BRA2E19", "RJ")}},
+		{name: "modalidade 5", transport: Transport{FreightMode: 5}, err: ErrInvalidFreightMode},
+		{name: "modalidade negativa", transport: Transport{FreightMode: -1}, err: ErrInvalidFreightMode},
+		{name: "transportadora sem frete", transport: Transport{FreightMode: FreightNoTransport, CarrierID: "carrier-1"}, err: ErrTransportWithoutFreight},
+		{name: "veículo sem frete", transport: Transport{FreightMode: FreightNoTransport, Vehicle: vehicle("ABC1234", "SP")}, err: ErrTransportWithoutFreight},
+		{name: "placa curta", transport: Transport{FreightMode: FreightBySender, Vehicle: vehicle("ABC123", "SP")}, err: ErrInvalidVehiclePlate},
+		{name: "placa com letra no fim", transport: Transport{FreightMode: FreightBySender, Vehicle: vehicle("ABC12D3", "SP")}, err: ErrInvalidVehiclePlate},
+		{name: "UF do veículo", transport: Transport{FreightMode: FreightBySender, Vehicle: vehicle("ABC1234", "XX")}, err: ErrInvalidUF},
+		{name: "RNTC do veículo", transport: Transport{FreightMode: FreightBySender, Vehicle: &Vehicle{Plate: "ABC1234", UF: "SP", RNTC: "123456789012345678901"}}, err: ErrInvalidRNTC},
+		{name: "volumes negativos", transport: Transport{FreightMode: FreightBySender, Volumes: -1}, err: ErrInvalidVolumes},
+		{name: "peso negativo", transport: Transport{FreightMode: FreightBySender, NetWeight: -0.5}, err: ErrInvalidVolumes},
+		{name: "líquido acima do bruto", transport: Transport{FreightMode: FreightBySender, GrossWeight: 10, NetWeight: 10.001}, err: ErrNetWeightExceedsGross},
+		{name: "líquido sem bruto", transport: Transport{FreightMode: FreightBySender, NetWeight: 10}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := tt.transport
+			transport.Normalize()
+			if err := transport.Validate(); !errors.Is(err, tt.err) {
+				t.Errorf("Validate() erro = %v, esperado %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestTransportNormalize(t *testing.T) {
+	transport := Transport{
+		CarrierID:   " carrier-1 ",
+		Species:     " CAIXA ",
+		GrossWeight: 12.34567,
+		NetWeight:   10.0004,
+		Vehicle:     &Vehicle{Plate: "abc-1d23", UF: " sp ", RNTC: " antt1 "},
+	}
+	transport.Normalize()
+
+	if transport.CarrierID != "carrier-1" || transport.Species != "CAIXA" || transport.GrossWeight != 12.346 || transport.NetWeight != 10 {
+		t.Errorf("Normalize() = %+v", transport)
+	}
+	if expected := (Vehicle{Plate: "ABC1D23", UF: "SP", RNTC: "ANTT1"}); *transport.Vehicle != expected {
+		t.Errorf("Normalize() veículo = %+v, esperado %+v", *transport.Vehicle, expected)
+	}
+}
+
+func TestTransportCheckRoute(t *testing.T) {
+	withVehicle := Transport{FreightMode: FreightBySender, Vehicle: &Vehicle{Plate: "ABC1234", UF: "SP"}}
+	if err := withVehicle.CheckRoute(false); err != nil {
+		t.Errorf("CheckRoute(interna) erro inesperado: %v", err)
+	}
+	if err := withVehicle.CheckRoute(true); !errors.Is(err, ErrInterstateVehicle) {
+		t.Errorf("CheckRoute(interestadual) erro = %v, esperado %v", err, ErrInterstateVehicle)
+	}
+	withoutVehicle := Transport{FreightMode: FreightBySender, CarrierID: "carrier-1"}
+	if err := withoutVehicle.CheckRoute(true); err != nil {
+		t.Errorf("CheckRoute(interestadual) sem veículo erro inesperado: %v", err)
+	}
+}
+
+func TestItemsNetWeight(t *testing.T) {
+	tests := []struct {
+		name     string
+		items    []InvoiceItem
+		expected float64
+		ok       bool
+	}{
+		{name: "pesos cadastrados", items: []InvoiceItem{{UnitWeight: 0.5, Quantity: 3}, {UnitWeight: 0.1234, Quantity: 10}}, expected: 2.734, ok: true},
+		{name: "item sem peso", items: []InvoiceItem{{UnitWeight: 0.5, Quantity: 3}, {Quantity: 1}}},
+		{name: "sem itens"},
+	}
+	for _, tt := range tests {
+		weight, ok := ItemsNetWeight(tt.items)
+		if weight != tt.expected || ok != tt.ok {
+			t.Errorf("%s: ItemsNetWeight() = %v, %t; esperado %v, %t", tt.name, weight, ok, tt.expected, tt.ok)
+		}
+	}
+}
+
+func TestInvoiceTransportByModel(t *testing.T) {
+	draft := func(model string, itemType ItemType, transport *Transport) *Invoice {
+		item := InvoiceItem{Type: itemType, ProductID: "product-1", ServiceID: "service-1", Quantity: 1, UnitPrice: 1000}
+		return &Invoice{
+			IssuerID:   "issuer-1",
+			Model:      model,
+			CustomerID: "customer-1",
+			Customer:   &Customer{ID: "customer-1"},
+			Items:      []InvoiceItem{item},
+			Transport:  transport,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		invoice *Invoice
+		err     error
+	}{
+		{name: "NF-e com transportadora", invoice: draft(ModelNFe, ItemGoods, &Transport{FreightMode: FreightBySender, CarrierID: "carrier-1"})},
+		{name: "NF-e com transporte inválido", invoice: draft(ModelNFe, ItemGoods, &Transport{FreightMode: 7}), err: ErrInvalidFreightMode},
+		{name: "NFC-e sem transporte", invoice: draft(ModelNFCe, ItemGoods, &Transport{FreightMode: FreightNoTransport})},
+		{name: "NFC-e com frete", invoice: draft(ModelNFCe, ItemGoods, &Transport{FreightMode: FreightBySender}), err: ErrConsumerFreight},
+		{name: "NFS-e com transporte", invoice: draft(ModelNFSe, ItemService, &Transport{FreightMode: FreightNoTransport}), err: ErrServiceTransport},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.invoice.ValidateDraft(); !errors.Is(err, tt.err) {
+				t.Errorf("ValidateDraft() erro = %v, esperado %v", err, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,509 @@
+// Package nfe gera o documento XML da Nota Fiscal Eletrônica (modelos 55 e 65,
+// leiaute 4.00) a partir de uma nota fechada e o valida contra os schemas
+// embarcados em schemas/.
+package nfe
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+)
+
+// Ambientes de emissão (tpAmb)
+const (
+	EnvironmentProduction   = 1
+	EnvironmentHomologation = 2
+)
+
+// Valores fixos das notas emitidas pelo serviço
+const (
+	NatureSale               = "VENDA DE MERCADORIA"
+	NatureReturn             = "DEVOLUCAO DE VENDA DE MERCADORIA"
+	PurposeNormal            = "1"  // finNFe: NF-e normal
+	PurposeReturn            = "4"  // finNFe: devolução de mercadoria
+	FreightNone              = "9"  // modFrete: sem ocorrência de transporte
+	PaymentNone              = "90" // tPag: sem pagamento
+	PaymentCash              = "01" // tPag: dinheiro
+	WithoutGTIN              = "SEM GTIN"
+	dateTimeLayout           = "2006-01-02T15:04:05-07:00"
+	dateLayout               = "2006-01-02"
+	homologationName         = "NF-E EMITIDA EM AMBIENTE DE HOMOLOGACAO - SEM VALOR FISCAL"
+	homologationConsumerItem = "NOTA FISCAL EMITIDA EM AMBIENTE DE HOMOLOGACAO - SEM VALOR FISCAL"
+	defaultAppVersion        = "korp-billing 1.0"
+)
+
+// Config parametriza a geração do documento
+type Config struct {
+	Environment int     // tpAmb: 1 produção, 2 homologação
+	AppVersion  string  // verProc: versão do aplicativo emissor
+	Signer      *Signer // Certificado do emitente; sem ele o XML não é assinado
+
+	// Dados da NFC-e (modelo 65)
+	CSCID             string // Identificador do CSC cadastrado na SEFAZ
+	CSC               string // Código de segurança do contribuinte
+	ConsumerQRCodeURL string // Substitui a URL do QR Code conhecida para a UF
+	ConsumerQueryURL  string // Substitui a URL de consulta por chave conhecida para a UF
+}
+
+// ErrIncompleteInvoice indica que a nota não possui os dados necessários ao XML
+var ErrIncompleteInvoice = errors.New("nota fiscal sem dados de emitente ou destinatário para gerar o XML")
+
+// Build monta o documento NF-e de uma nota fechada
+func Build(invoice *domain.Invoice, config Config) (*NFe, error) {
+	if !invoice.IsClosed() || invoice.ClosedAt == nil {
+		return nil, domain.ErrInvoiceNotClosed
+	}
+	if invoice.Issuer == nil || (invoice.Customer == nil && !invoice.IsConsumer()) {
+		return nil, ErrIncompleteInvoice
+	}
+	if config.Environment != EnvironmentProduction {
+		config.Environment = EnvironmentHomologation
+	}
+	if config.AppVersion == "" {
+		config.AppVersion = defaultAppVersion
+	}
+
+	if invoice.AccessKey == "" {
+		return nil, domain.ErrAccessKeyNotAssigned
+	}
+	key, err := domain.ParseAccessKey(invoice.AccessKey)
+	if err != nil {
+		return nil, err
+	}
+
+	issuer := invoice.Issuer
+	ide := Ide{
+		CUF:      key.UFCode,
+		CNF:      key.NumericCode,
+		NatOp:    NatureSale,
+		Mod:      key.Model,
+		Serie:    strconv.Itoa(key.Series),
+		NNF:      strconv.Itoa(key.Number),
+		DhEmi:    invoice.ClosedAt.Format(dateTimeLayout),
+		TpNF:     "1", // Saída
+		IdDest:   "1",
+		CMunFG:   issuer.Address.CityCode,
+		TpImp:    "1", // DANFE retrato
+		TpEmis:   strconv.Itoa(int(key.EmissionType)),
+		CDV:      strconv.Itoa(key.CheckDigit),
+		TpAmb:    strconv.Itoa(config.Environment),
+		FinNFe:   PurposeNormal,
+		IndFinal: "0",
+		IndPres:  "1", // Operação presencial
+		ProcEmi:  "0", // Aplicativo do contribuinte
+		VerProc:  config.AppVersion,
+	}
+	if invoice.IsInterstate() {
+		ide.IdDest = "2"
+	}
+	// A devolução é uma nota de entrada que referencia a NF-e original
+	if ref := invoice.ReturnOf; ref != nil {
+		ide.NatOp = NatureReturn
+		ide.TpNF = "0" // Entrada
+		ide.FinNFe = PurposeReturn
+		ide.IndPres = "0" // Não se aplica
+		ide.NFref = []NFref{{RefNFe: ref.AccessKey}}
+	}
+	if contingency := invoice.Contingency; contingency != nil {
+		ide.DhCont = contingency.StartedAt.Format(dateTimeLayout)
+		ide.XJust = truncate(contingency.Justification, 256)
+	}
+	if invoice.Customer != nil && invoice.Customer.IEIndicator() == domain.IENonContributor {
+		ide.IndFinal = "1"
+	}
+	// A NFC-e é sempre venda presencial a consumidor final dentro do estado,
+	// com o DANFE NFC-e impresso em bobina
+	if invoice.IsConsumer() {
+		ide.IdDest = "1"
+		ide.IndFinal = "1"
+		ide.TpImp = "4"
+	}
+
+	doc := &NFe{
+		InfNFe: InfNFe{
+			Versao: Version,
+			ID:     "NFe" + invoice.AccessKey,
+			Ide:    ide,
+			Emit:   buildEmit(issuer),
+			Total:  buildTotal(invoice.Totals),
+			Transp: buildTransp(invoice.Transport),
+			Cobr:   buildCobr(invoice),
+			Pag:    buildPag(invoice),
+		},
+	}
+	if invoice.Customer != nil {
+		doc.InfNFe.Dest = buildDest(invoice.Customer, config.Environment)
+	}
+	for idx, item := range invoice.Items {
+		doc.InfNFe.Det = append(doc.InfNFe.Det, buildDet(idx+1, item))
+	}
+
+	if invoice.IsConsumer() {
+		// A NFC-e exige o meio de pagamento; sem condição de pagamento na nota,
+		// o total é registrado como recebido em dinheiro
+		if invoice.Payment == nil {
+			doc.InfNFe.Pag.DetPag = []DetPag{{TPag: PaymentCash, VPag: money(invoice.Totals.Total)}}
+		}
+		// Em homologação a SEFAZ exige o texto padrão na descrição do primeiro item
+		if config.Environment == EnvironmentHomologation {
+			doc.InfNFe.Det[0].Prod.XProd = homologationConsumerItem
+		}
+		if doc.InfNFeSupl, err = buildSupl(invoice.AccessKey, issuer.Address.UF, config); err != nil {
+			return nil, err
+		}
+	}
+	return doc, nil
+}
+
+// Generate monta, assina (quando há certificado configurado) e valida contra o
+// schema o XML da NF-e de uma nota fechada
+func Generate(invoice *domain.Invoice, config Config) ([]byte, error) {
+	document, err := Build(invoice, config)
+	if err != nil {
+		return nil, err
+	}
+	data, err := document.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	if config.Signer != nil {
+		if data, err = config.Signer.Sign(data); err != nil {
+			return nil, err
+		}
+	}
+	if err := Validate(data); err != nil {
+		return nil, fmt.Errorf("XML da nota %d inválido: %w", invoice.Number, err)
+	}
+	return data, nil
+}
+
+// Marshal serializa o documento com a declaração XML
+func (n *NFe) Marshal() ([]byte, error) {
+	data, err := xml.Marshal(n)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao serializar NF-e: %w", err)
+	}
+	return append([]byte(xml.Header), data...), nil
+}
+
+// AccessKey retorna a chave de acesso (44 dígitos) contida no atributo Id
+func (n *NFe) AccessKey() string {
+	return strings.TrimPrefix(n.InfNFe.ID, "NFe")
+}
+
+// buildPag monta o grupo de pagamento. Sem condição de pagamento (e sempre nas
+// devoluções) a nota sai sem pagamento, com tPag 90 e valor zero
+func buildPag(invoice *domain.Invoice) Pag {
+	terms := invoice.Payment
+	if terms == nil {
+		return Pag{DetPag: []DetPag{{TPag: PaymentNone, VPag: money(0)}}}
+	}
+	indPag := "1" // A prazo
+	if terms.IsCash() {
+		indPag = "0"
+	}
+	detPag := DetPag{IndPag: indPag, TPag: string(terms.Method), VPag: money(invoice.Totals.Total)}
+	if terms.Method == domain.PaymentOther {
+		detPag.XPag = truncate(terms.Description, 60)
+	}
+	return Pag{DetPag: []DetPag{detPag}}
+}
+
+// buildCobr monta a fatura e as duplicatas das vendas a prazo. Pagamentos à
+// vista não geram o grupo de cobrança
+func buildCobr(invoice *domain.Invoice) *Cobr {
+	if invoice.Payment == nil || invoice.Payment.IsCash() || len(invoice.Installments) == 0 {
+		return nil
+	}
+	total := money(invoice.Totals.Total)
+	cobr := &Cobr{Fat: &Fat{
+		NFat:  strconv.Itoa(invoice.Number),
+		VOrig: total,
+		VDesc: money(0),
+		VLiq:  total,
+	}}
+	for _, installment := range invoice.Installments {
+		cobr.Dup = append(cobr.Dup, Dup{
+			NDup:  installment.Number,
+			DVenc: installment.DueDate.Format(dateLayout),
+			VDup:  money(installment.Amount),
+		})
+	}
+	return cobr
+}
+
+// buildSupl monta as informações suplementares da NFC-e: o QR Code e a URL
+// de consulta por chave de acesso
+func buildSupl(accessKey, uf string, config Config) (*InfNFeSupl, error) {
+	qrCodeURL, queryURL, err := ConsumerURLs(uf, config)
+	if err != nil {
+		return nil, err
+	}
+	qrCode, err := ConsumerQRCode(qrCodeURL, accessKey, config.Environment, config.CSCID, config.CSC)
+	if err != nil {
+		return nil, err
+	}
+	return &InfNFeSupl{QRCode: qrCode, URLChave: queryURL}, nil
+}
+
+func buildEmit(issuer *domain.Issuer) Emit {
+	return Emit{
+		CNPJ:      issuer.CNPJ,
+		XNome:     truncate(issuer.Name, 60),
+		XFant:     truncate(issuer.TradeName, 60),
+		EnderEmit: buildAddress(issuer.Address),
+		IE:        issuer.StateRegistration,
+		CRT:       strconv.Itoa(int(issuer.CRT)),
+	}
+}
+
+func buildDest(customer *domain.Customer, environment int) *Dest {
+	address := buildAddress(customer.Address)
+	dest := &Dest{
+		XNome:     truncate(customer.Name, 60),
+		EnderDest: &address,
+		IndIEDest: strconv.Itoa(int(customer.IEIndicator())),
+		Email:     truncate(customer.Email, 60),
+	}
+	// Em homologação a SEFAZ exige este texto no nome do destinatário
+	if environment == EnvironmentHomologation {
+		dest.XNome = homologationName
+	}
+	if customer.DocumentType == domain.DocumentCNPJ {
+		dest.CNPJ = customer.Document
+	} else {
+		dest.CPF = customer.Document
+	}
+	if customer.IEIndicator() == domain.IEContributor {
+		dest.IE = customer.StateRegistration
+	}
+	return dest
+}
+
+// buildTransp monta o grupo de transporte; sem dados de transporte, a nota
+// sai sem ocorrência de transporte (modFrete 9)
+func buildTransp(transport *domain.Transport) Transp {
+	if transport == nil {
+		return Transp{ModFrete: FreightNone}
+	}
+
+	transp := Transp{ModFrete: strconv.Itoa(int(transport.FreightMode))}
+	if carrier := transport.Carrier; carrier != nil {
+		address := carrier.Address
+		transp.Transporta = &Transporta{
+			XNome:  truncate(carrier.Name, 60),
+			IE:     carrier.StateRegistration,
+			XEnder: truncate(strings.Join(nonEmpty(address.Street, address.Number, address.District), ", "), 60),
+			XMun:   truncate(address.City, 60),
+			UF:     address.UF,
+		}
+		if carrier.DocumentType == domain.DocumentCNPJ {
+			transp.Transporta.CNPJ = carrier.Document
+		} else {
+			transp.Transporta.CPF = carrier.Document
+		}
+	}
+	if vehicle := transport.Vehicle; vehicle != nil {
+		transp.VeicTransp = &VeicTransp{Placa: vehicle.Plate, UF: vehicle.UF, RNTC: vehicle.RNTC}
+	}
+	if transport.HasVolumes() {
+		vol := Vol{
+			Esp:   truncate(transport.Species, 60),
+			PesoL: weight(transport.NetWeight),
+			PesoB: weight(transport.GrossWeight),
+		}
+		if transport.Volumes > 0 {
+			vol.QVol = strconv.Itoa(transport.Volumes)
+		}
+		transp.Vol = []Vol{vol}
+	}
+	return transp
+}
+
+// nonEmpty descarta as partes vazias de um texto composto
+func nonEmpty(parts ...string) []string {
+	filled := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			filled = append(filled, part)
+		}
+	}
+	return filled
+}
+
+func buildAddress(address domain.Address) Endereco {
+	return Endereco{
+		XLgr:    truncate(address.Street, 60),
+		Nro:     truncate(address.Number, 60),
+		XCpl:    truncate(address.Complement, 60),
+		XBairro: truncate(address.District, 60),
+		CMun:    address.CityCode,
+		XMun:    truncate(address.City, 60),
+		UF:      address.UF,
+		CEP:     address.CEP,
+		CPais:   "1058",
+		XPais:   "BRASIL",
+		Fone:    address.Phone,
+	}
+}
+
+func buildDet(number int, item domain.InvoiceItem) Det {
+	quantity := strconv.Itoa(item.Quantity) + ".0000"
+	unit := item.Unit
+	if unit == "" {
+		unit = "UN"
+	}
+
+	det := Det{
+		NItem: number,
+		Prod: Prod{
+			CProd:    truncate(item.ProductCode, 60),
+			CEAN:     WithoutGTIN,
+			XProd:    truncate(item.Description, 120),
+			NCM:      item.NCM,
+			CEST:     item.CEST,
+			CFOP:     item.CFOP,
+			UCom:     unit,
+			QCom:     quantity,
+			VUnCom:   money(item.UnitPrice),
+			VProd:    money(item.Total),
+			CEANTrib: WithoutGTIN,
+			UTrib:    unit,
+			QTrib:    quantity,
+			VUnTrib:  money(item.UnitPrice),
+			VFrete:   optionalMoney(item.Charges.Freight),
+			VSeg:     optionalMoney(item.Charges.Insurance),
+			VDesc:    optionalMoney(item.Discount),
+			VOutro:   optionalMoney(item.Charges.Other),
+			IndTot:   "1",
+		},
+	}
+
+	legacy := item.Taxes.Legacy
+	origin := strconv.Itoa(item.Origin)
+	if legacy.ICMSCST == domain.ICMSCSOSNWithoutCredit {
+		det.Imposto.ICMS.ICMSSN102 = &ICMSSN102{Orig: origin, CSOSN: legacy.ICMSCST}
+	} else {
+		det.Imposto.ICMS.ICMS00 = &ICMS00{
+			Orig:  origin,
+			CST:   legacy.ICMSCST,
+			ModBC: "3", // Valor da operação
+			VBC:   money(legacy.ICMSBase),
+			PICMS: rate(legacy.ICMSRate),
+			VICMS: money(legacy.ICMSValue),
+		}
+	}
+
+	pis := &PISValues{CST: legacy.PISCST, VBC: money(legacy.ICMSBase), PPIS: rate(legacy.PISRate), VPIS: money(legacy.PISValue)}
+	cofins := &COFINSValues{CST: legacy.COFINSCST, VBC: money(legacy.ICMSBase), PCOFINS: rate(legacy.COFINSRate), VCOFINS: money(legacy.COFINSValue)}
+	if legacy.PISCST == domain.PISCOFINSCSTTaxed {
+		det.Imposto.PIS.PISAliq = pis
+		det.Imposto.COFINS.COFINSAliq = cofins
+	} else {
+		det.Imposto.PIS.PISOutr = pis
+		det.Imposto.COFINS.COFINSOutr = cofins
+	}
+
+	if taxes := item.Taxes.IBSCBS; taxes != nil {
+		det.Imposto.IBSCBS = &IBSCBS{
+			CST:        taxes.CST,
+			CClassTrib: taxes.ClassTrib,
+			GIBSCBS: &GIBSCBS{
+				VBC:     money(taxes.Base),
+				GIBSUF:  GIBSUF{PIBSUF: rate(taxes.IBSUFRate), VIBSUF: money(taxes.IBSUFValue)},
+				GIBSMun: GIBSMun{PIBSMun: rate(taxes.IBSMunRate), VIBSMun: money(taxes.IBSMunValue)},
+				VIBS:    money(taxes.IBSValue()),
+				GCBS:    GCBS{PCBS: rate(taxes.CBSRate), VCBS: money(taxes.CBSValue)},
+			},
+		}
+	}
+	return det
+}
+
+func buildTotal(totals domain.InvoiceTotals) Total {
+	zero := money(0)
+	total := Total{
+		ICMSTot: ICMSTot{
+			VBC:        money(totals.Legacy.ICMSBase),
+			VICMS:      money(totals.Legacy.ICMSValue),
+			VICMSDeson: zero,
+			VFCP:       zero,
+			VBCST:      zero,
+			VST:        zero,
+			VFCPST:     zero,
+			VFCPSTRet:  zero,
+			VProd:      money(totals.Products),
+			VFrete:     money(totals.Freight),
+			VSeg:       money(totals.Insurance),
+			VDesc:      money(totals.Discount),
+			VII:        zero,
+			VIPI:       zero,
+			VIPIDevol:  zero,
+			VPIS:       money(totals.Legacy.PISValue),
+			VCOFINS:    money(totals.Legacy.COFINSValue),
+			VOutro:     money(totals.Other),
+			VNF:        money(totals.Total),
+		},
+	}
+
+	if ibscbs := totals.IBSCBS; ibscbs != nil {
+		total.IBSCBSTot = &IBSCBSTot{
+			VBCIBSCBS: money(ibscbs.Base),
+			GIBS: GIBSTot{
+				GIBSUF:           GIBSUFTot{VDif: zero, VDevTrib: zero, VIBSUF: money(ibscbs.IBSUFValue)},
+				GIBSMun:          GIBSMunTot{VDif: zero, VDevTrib: zero, VIBSMun: money(ibscbs.IBSMunValue)},
+				VIBS:             money(ibscbs.IBSValue),
+				VCredPres:        zero,
+				VCredPresCondSus: zero,
+			},
+			GCBS: GCBSTot{
+				VDif:             zero,
+				VDevTrib:         zero,
+				VCBS:             money(ibscbs.CBSValue),
+				VCredPres:        zero,
+				VCredPresCondSus: zero,
+			},
+		}
+	}
+	return total
+}
+
+// money formata valores com duas casas decimais (TDec_1302)
+func money(value domain.Money) string {
+	return value.String()
+}
+
+// optionalMoney formata valores opcionais (TDec_1302Opc), omitindo os zerados
+func optionalMoney(value domain.Money) string {
+	if value == 0 {
+		return ""
+	}
+	return money(value)
+}
+
+// weight formata pesos em kg com três casas decimais (TDec_1203), omitindo os zerados
+func weight(value float64) string {
+	if value <= 0 {
+		return ""
+	}
+	return strconv.FormatFloat(value, 'f', 3, 64)
+}
+
+// rate formata alíquotas com quatro casas decimais (TDec_0302a04)
+func rate(value float64) string {
+	return strconv.FormatFloat(value, 'f', 4, 64)
+}
+
+// truncate limita o texto ao tamanho máximo do campo, sem cortar caracteres
+func truncate(value string, max int) string {
+	value = strings.TrimSpace(value)
+	if utf8.RuneCountInString(value) <= max {
+		return value
+	}
+	return strings.TrimSpace(string([]rune(value)[:max]))
+}
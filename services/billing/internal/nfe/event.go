@@ -0,0 +1,115 @@
+package nfe
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+)
+
+// EventVersion é a versão do leiaute de eventos gerado
+const EventVersion = "1.00"
+
+// Valores fixos do evento de carta de correção
+const (
+	correctionDescription = "Carta de Correcao"
+	correctionConditions  = "A Carta de Correcao e disciplinada pelo paragrafo 1o-A do art. 7o do Convenio S/N, de 15 de dezembro de 1970 e pode ser utilizada para regularizacao de erro ocorrido na emissao de documento fiscal, desde que o erro nao esteja relacionado com: I - as variaveis que determinam o valor do imposto tais como: base de calculo, aliquota, diferenca de preco, quantidade, valor da operacao ou da prestacao; II - a correcao de dados cadastrais que implique mudanca do remetente ou do destinatario; III - a data de emissao ou de saida."
+)
+
+// Evento é o elemento raiz de um evento da NF-e (leiaute 1.00)
+type Evento struct {
+	XMLName   xml.Name  `xml:"http://www.portalfiscal.inf.br/nfe evento"`
+	Versao    string    `xml:"versao,attr"`
+	InfEvento InfEvento `xml:"infEvento"`
+}
+
+// InfEvento agrupa as informações do evento
+type InfEvento struct {
+	ID         string    `xml:"Id,attr"`
+	COrgao     string    `xml:"cOrgao"`
+	TpAmb      string    `xml:"tpAmb"`
+	CNPJ       string    `xml:"CNPJ"`
+	ChNFe      string    `xml:"chNFe"`
+	DhEvento   string    `xml:"dhEvento"`
+	TpEvento   string    `xml:"tpEvento"`
+	NSeqEvento string    `xml:"nSeqEvento"`
+	VerEvento  string    `xml:"verEvento"`
+	DetEvento  DetEvento `xml:"detEvento"`
+}
+
+// DetEvento é o detalhamento da carta de correção
+type DetEvento struct {
+	Versao     string `xml:"versao,attr"`
+	DescEvento string `xml:"descEvento"`
+	XCorrecao  string `xml:"xCorrecao"`
+	XCondUso   string `xml:"xCondUso"`
+}
+
+// EventID monta o Id do evento: "ID" + tpEvento + chave de acesso + nSeqEvento
+func EventID(eventType, accessKey string, sequence int) string {
+	return fmt.Sprintf("ID%s%s%02d", eventType, accessKey, sequence)
+}
+
+// BuildCorrectionLetter monta o evento de carta de correção de uma nota
+func BuildCorrectionLetter(letter *domain.CorrectionLetter, config Config) (*Evento, error) {
+	key, err := domain.ParseAccessKey(letter.AccessKey)
+	if err != nil {
+		return nil, err
+	}
+	if config.Environment != EnvironmentProduction {
+		config.Environment = EnvironmentHomologation
+	}
+
+	return &Evento{
+		Versao: EventVersion,
+		InfEvento: InfEvento{
+			ID:         EventID(domain.EventCorrectionLetter, letter.AccessKey, letter.Sequence),
+			COrgao:     key.UFCode,
+			TpAmb:      strconv.Itoa(config.Environment),
+			CNPJ:       key.IssuerCNPJ,
+			ChNFe:      letter.AccessKey,
+			DhEvento:   letter.CreatedAt.Format(dateTimeLayout),
+			TpEvento:   domain.EventCorrectionLetter,
+			NSeqEvento: strconv.Itoa(letter.Sequence),
+			VerEvento:  EventVersion,
+			DetEvento: DetEvento{
+				Versao:     EventVersion,
+				DescEvento: correctionDescription,
+				XCorrecao:  letter.Text,
+				XCondUso:   correctionConditions,
+			},
+		},
+	}, nil
+}
+
+// GenerateCorrectionLetter monta, assina (quando há certificado configurado) e
+// valida contra o schema o XML do evento de carta de correção
+func GenerateCorrectionLetter(letter *domain.CorrectionLetter, config Config) ([]byte, error) {
+	event, err := BuildCorrectionLetter(letter, config)
+	if err != nil {
+		return nil, err
+	}
+	data, err := event.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	if config.Signer != nil {
+		if data, err = config.Signer.SignElement(data, "infEvento"); err != nil {
+			return nil, err
+		}
+	}
+	if err := ValidateEvent(data); err != nil {
+		return nil, fmt.Errorf("XML da carta de correção %d inválido: %w", letter.Sequence, err)
+	}
+	return data, nil
+}
+
+// Marshal serializa o evento com a declaração XML
+func (e *Evento) Marshal() ([]byte, error) {
+	data, err := xml.Marshal(e)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao serializar evento: %w", err)
+	}
+	return append([]byte(xml.Header), data...), nil
+}
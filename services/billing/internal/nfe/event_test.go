@@ -0,0 +1,95 @@
+package nfe
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+)
+
+// testAccessKey é uma chave de acesso válida de NF-e emitida em SP pelo CNPJ
+// 11.222.333/0001-81
+const testAccessKey = "35250311222333000181550010000000011123456787"
+
+func TestEventID(t *testing.T) {
+	// Id do evento: "ID" + tpEvento + chave + nSeqEvento com dois dígitos
+	tests := []struct {
+		sequence int
+		expected string
+	}{
+		{sequence: 1, expected: "ID1101103525031122233300018155001000000001112345678701"},
+		{sequence: 9, expected: "ID1101103525031122233300018155001000000001112345678709"},
+		{sequence: 20, expected: "ID1101103525031122233300018155001000000001112345678720"},
+	}
+	for _, tt := range tests {
+		id := EventID(domain.EventCorrectionLetter, testAccessKey, tt.sequence)
+		if id != tt.expected {
+			t.Errorf("EventID(%d) = %s, esperado %s", tt.sequence, id, tt.expected)
+		}
+		if len(id) != 54 {
+			t.Errorf("EventID(%d) com %d posições, esperado 54", tt.sequence, len(id))
+		}
+	}
+}
+
+func TestBuildCorrectionLetter(t *testing.T) {
+	letter := &domain.CorrectionLetter{
+		AccessKey: testAccessKey,
+		Sequence:  2,
+		Text:      "Endereço de entrega: Rua das Flores, 100",
+		CreatedAt: time.Date(2025, 3, 10, 10, 0, 0, 0, time.FixedZone("BRT", -3*60*60)),
+	}
+	event, err := BuildCorrectionLetter(letter, Config{})
+	if err != nil {
+		t.Fatalf("BuildCorrectionLetter() erro inesperado: %v", err)
+	}
+
+	// Órgão e CNPJ vêm da chave; nSeqEvento sem zeros à esquerda
+	inf := event.InfEvento
+	expected := InfEvento{
+		ID:         "ID1101103525031122233300018155001000000001112345678702",
+		COrgao:     "35",
+		TpAmb:      "2",
+		CNPJ:       "11222333000181",
+		ChNFe:      testAccessKey,
+		DhEvento:   "2025-03-10T10:00:00-03:00",
+		TpEvento:   "110110",
+		NSeqEvento: "2",
+		VerEvento:  EventVersion,
+		DetEvento: DetEvento{
+			Versao:     EventVersion,
+			DescEvento: "Carta de Correcao",
+			XCorrecao:  letter.Text,
+			XCondUso:   correctionConditions,
+		},
+	}
+	if inf != expected {
+		t.Errorf("BuildCorrectionLetter() = %+v, esperado %+v", inf, expected)
+	}
+
+	letter.AccessKey = "35250311222333000181550010000000011123456780"
+	if _, err := BuildCorrectionLetter(letter, Config{}); err == nil {
+		t.Errorf("BuildCorrectionLetter() sem erro com dígito verificador inválido")
+	}
+}
+
+func TestGenerateCorrectionLetter(t *testing.T) {
+	tests := []struct {
+		name     string
+		sequence int
+		text     string
+	}{
+		{name: "primeira carta", sequence: 1, text: strings.Repeat("a", domain.CorrectionMinLength)},
+		{name: "última carta", sequence: domain.MaxCorrectionsPerNote, text: "Endereço de entrega: Rua das Flores, 100"},
+		{name: "texto no tamanho máximo", sequence: 3, text: strings.Repeat("ç", domain.CorrectionMaxLength)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			letter := &domain.CorrectionLetter{AccessKey: testAccessKey, Sequence: tt.sequence, Text: tt.text, CreatedAt: time.Now()}
+			if _, err := GenerateCorrectionLetter(letter, Config{}); err != nil {
+				t.Errorf("GenerateCorrectionLetter() erro inesperado: %v", err)
+			}
+		})
+	}
+}
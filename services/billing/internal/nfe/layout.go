@@ -0,0 +1,371 @@
+package nfe
+
+import "encoding/xml"
+
+// Namespace é o namespace dos documentos da NF-e
+const Namespace = "http://www.portalfiscal.inf.br/nfe"
+
+// Version é a versão do leiaute gerado
+const Version = "4.00"
+
+// NFe é o elemento raiz do documento (leiaute 4.00). Os campos seguem os nomes
+// das tags do Manual de Orientação do Contribuinte para facilitar a conferência
+type NFe struct {
+	XMLName    xml.Name    `xml:"http://www.portalfiscal.inf.br/nfe NFe"`
+	InfNFe     InfNFe      `xml:"infNFe"`
+	InfNFeSupl *InfNFeSupl `xml:"infNFeSupl,omitempty"` // Exclusivo da NFC-e
+}
+
+// InfNFeSupl traz as informações suplementares da NFC-e, fora da área assinada
+type InfNFeSupl struct {
+	QRCode   string `xml:"qrCode"`
+	URLChave string `xml:"urlChave"`
+}
+
+// InfNFe agrupa as informações da nota
+type InfNFe struct {
+	Versao  string   `xml:"versao,attr"`
+	ID      string   `xml:"Id,attr"`
+	Ide     Ide      `xml:"ide"`
+	Emit    Emit     `xml:"emit"`
+	Dest    *Dest    `xml:"dest,omitempty"`
+	Det     []Det    `xml:"det"`
+	Total   Total    `xml:"total"`
+	Transp  Transp   `xml:"transp"`
+	Cobr    *Cobr    `xml:"cobr,omitempty"`
+	Pag     Pag      `xml:"pag"`
+	InfAdic *InfAdic `xml:"infAdic,omitempty"`
+}
+
+// Ide é o grupo de identificação da nota
+type Ide struct {
+	CUF      string  `xml:"cUF"`
+	CNF      string  `xml:"cNF"`
+	NatOp    string  `xml:"natOp"`
+	Mod      string  `xml:"mod"`
+	Serie    string  `xml:"serie"`
+	NNF      string  `xml:"nNF"`
+	DhEmi    string  `xml:"dhEmi"`
+	DhSaiEnt string  `xml:"dhSaiEnt,omitempty"`
+	TpNF     string  `xml:"tpNF"`
+	IdDest   string  `xml:"idDest"`
+	CMunFG   string  `xml:"cMunFG"`
+	TpImp    string  `xml:"tpImp"`
+	TpEmis   string  `xml:"tpEmis"`
+	CDV      string  `xml:"cDV"`
+	TpAmb    string  `xml:"tpAmb"`
+	FinNFe   string  `xml:"finNFe"`
+	IndFinal string  `xml:"indFinal"`
+	IndPres  string  `xml:"indPres"`
+	ProcEmi  string  `xml:"procEmi"`
+	VerProc  string  `xml:"verProc"`
+	DhCont   string  `xml:"dhCont,omitempty"` // Entrada em contingência
+	XJust    string  `xml:"xJust,omitempty"`  // Justificativa da contingência
+	NFref    []NFref `xml:"NFref,omitempty"`  // Documentos referenciados
+}
+
+// NFref referencia outra NF-e pela chave de acesso
+type NFref struct {
+	RefNFe string `xml:"refNFe"`
+}
+
+// Emit é o grupo do emitente
+type Emit struct {
+	CNPJ      string   `xml:"CNPJ,omitempty"`
+	CPF       string   `xml:"CPF,omitempty"`
+	XNome     string   `xml:"xNome"`
+	XFant     string   `xml:"xFant,omitempty"`
+	EnderEmit Endereco `xml:"enderEmit"`
+	IE        string   `xml:"IE"`
+	CRT       string   `xml:"CRT"`
+}
+
+// Dest é o grupo do destinatário
+type Dest struct {
+	CNPJ      string    `xml:"CNPJ,omitempty"`
+	CPF       string    `xml:"CPF,omitempty"`
+	XNome     string    `xml:"xNome,omitempty"`
+	EnderDest *Endereco `xml:"enderDest,omitempty"`
+	IndIEDest string    `xml:"indIEDest"`
+	IE        string    `xml:"IE,omitempty"`
+	Email     string    `xml:"email,omitempty"`
+}
+
+// Endereco é usado tanto no emitente quanto no destinatário
+type Endereco struct {
+	XLgr    string `xml:"xLgr"`
+	Nro     string `xml:"nro"`
+	XCpl    string `xml:"xCpl,omitempty"`
+	XBairro string `xml:"xBairro"`
+	CMun    string `xml:"cMun"`
+	XMun    string `xml:"xMun"`
+	UF      string `xml:"UF"`
+	CEP     string `xml:"CEP,omitempty"`
+	CPais   string `xml:"cPais,omitempty"`
+	XPais   string `xml:"xPais,omitempty"`
+	Fone    string `xml:"fone,omitempty"`
+}
+
+// Det é o detalhamento de um item
+type Det struct {
+	NItem   int     `xml:"nItem,attr"`
+	Prod    Prod    `xml:"prod"`
+	Imposto Imposto `xml:"imposto"`
+}
+
+// Prod é o grupo de dados do produto
+type Prod struct {
+	CProd    string `xml:"cProd"`
+	CEAN     string `xml:"cEAN"`
+	XProd    string `xml:"xProd"`
+	NCM      string `xml:"NCM"`
+	CEST     string `xml:"CEST,omitempty"`
+	CFOP     string `xml:"CFOP"`
+	UCom     string `xml:"uCom"`
+	QCom     string `xml:"qCom"`
+	VUnCom   string `xml:"vUnCom"`
+	VProd    string `xml:"vProd"`
+	CEANTrib string `xml:"cEANTrib"`
+	UTrib    string `xml:"uTrib"`
+	QTrib    string `xml:"qTrib"`
+	VUnTrib  string `xml:"vUnTrib"`
+	VFrete   string `xml:"vFrete,omitempty"` // Parcela rateada do frete
+	VSeg     string `xml:"vSeg,omitempty"`
+	VDesc    string `xml:"vDesc,omitempty"` // Desconto da linha
+	VOutro   string `xml:"vOutro,omitempty"`
+	IndTot   string `xml:"indTot"`
+}
+
+// Imposto agrupa os tributos do item
+type Imposto struct {
+	ICMS   ICMS    `xml:"ICMS"`
+	PIS    PIS     `xml:"PIS"`
+	COFINS COFINS  `xml:"COFINS"`
+	IBSCBS *IBSCBS `xml:"IBSCBS,omitempty"`
+}
+
+// ICMS contém apenas um dos grupos de tributação
+type ICMS struct {
+	ICMS00    *ICMS00    `xml:"ICMS00,omitempty"`
+	ICMSSN102 *ICMSSN102 `xml:"ICMSSN102,omitempty"`
+}
+
+// ICMS00 é a tributação integral do regime normal
+type ICMS00 struct {
+	Orig  string `xml:"orig"`
+	CST   string `xml:"CST"`
+	ModBC string `xml:"modBC"`
+	VBC   string `xml:"vBC"`
+	PICMS string `xml:"pICMS"`
+	VICMS string `xml:"vICMS"`
+}
+
+// ICMSSN102 é a tributação do Simples Nacional sem permissão de crédito
+type ICMSSN102 struct {
+	Orig  string `xml:"orig"`
+	CSOSN string `xml:"CSOSN"`
+}
+
+// PIS contém apenas um dos grupos de tributação
+type PIS struct {
+	PISAliq *PISValues `xml:"PISAliq,omitempty"`
+	PISOutr *PISValues `xml:"PISOutr,omitempty"`
+}
+
+// PISValues é o conteúdo comum aos grupos PISAliq e PISOutr
+type PISValues struct {
+	CST  string `xml:"CST"`
+	VBC  string `xml:"vBC"`
+	PPIS string `xml:"pPIS"`
+	VPIS string `xml:"vPIS"`
+}
+
+// COFINS contém apenas um dos grupos de tributação
+type COFINS struct {
+	COFINSAliq *COFINSValues `xml:"COFINSAliq,omitempty"`
+	COFINSOutr *COFINSValues `xml:"COFINSOutr,omitempty"`
+}
+
+// COFINSValues é o conteúdo comum aos grupos COFINSAliq e COFINSOutr
+type COFINSValues struct {
+	CST     string `xml:"CST"`
+	VBC     string `xml:"vBC"`
+	PCOFINS string `xml:"pCOFINS"`
+	VCOFINS string `xml:"vCOFINS"`
+}
+
+// IBSCBS é o grupo do IBS e da CBS do item (NT 2025.002)
+type IBSCBS struct {
+	CST        string   `xml:"CST"`
+	CClassTrib string   `xml:"cClassTrib"`
+	GIBSCBS    *GIBSCBS `xml:"gIBSCBS,omitempty"`
+}
+
+// GIBSCBS contém base, alíquotas e valores do IBS e da CBS
+type GIBSCBS struct {
+	VBC     string  `xml:"vBC"`
+	GIBSUF  GIBSUF  `xml:"gIBSUF"`
+	GIBSMun GIBSMun `xml:"gIBSMun"`
+	VIBS    string  `xml:"vIBS"`
+	GCBS    GCBS    `xml:"gCBS"`
+}
+
+// GIBSUF é o IBS de competência do estado
+type GIBSUF struct {
+	PIBSUF string `xml:"pIBSUF"`
+	VIBSUF string `xml:"vIBSUF"`
+}
+
+// GIBSMun é o IBS de competência do município
+type GIBSMun struct {
+	PIBSMun string `xml:"pIBSMun"`
+	VIBSMun string `xml:"vIBSMun"`
+}
+
+// GCBS é a contribuição federal
+type GCBS struct {
+	PCBS string `xml:"pCBS"`
+	VCBS string `xml:"vCBS"`
+}
+
+// Total é o grupo de totais da nota
+type Total struct {
+	ICMSTot   ICMSTot    `xml:"ICMSTot"`
+	IBSCBSTot *IBSCBSTot `xml:"IBSCBSTot,omitempty"`
+}
+
+// ICMSTot totaliza os valores da nota
+type ICMSTot struct {
+	VBC        string `xml:"vBC"`
+	VICMS      string `xml:"vICMS"`
+	VICMSDeson string `xml:"vICMSDeson"`
+	VFCP       string `xml:"vFCP"`
+	VBCST      string `xml:"vBCST"`
+	VST        string `xml:"vST"`
+	VFCPST     string `xml:"vFCPST"`
+	VFCPSTRet  string `xml:"vFCPSTRet"`
+	VProd      string `xml:"vProd"`
+	VFrete     string `xml:"vFrete"`
+	VSeg       string `xml:"vSeg"`
+	VDesc      string `xml:"vDesc"`
+	VII        string `xml:"vII"`
+	VIPI       string `xml:"vIPI"`
+	VIPIDevol  string `xml:"vIPIDevol"`
+	VPIS       string `xml:"vPIS"`
+	VCOFINS    string `xml:"vCOFINS"`
+	VOutro     string `xml:"vOutro"`
+	VNF        string `xml:"vNF"`
+}
+
+// IBSCBSTot totaliza o IBS e a CBS da nota
+type IBSCBSTot struct {
+	VBCIBSCBS string  `xml:"vBCIBSCBS"`
+	GIBS      GIBSTot `xml:"gIBS"`
+	GCBS      GCBSTot `xml:"gCBS"`
+}
+
+// GIBSTot totaliza o IBS estadual e municipal
+type GIBSTot struct {
+	GIBSUF           GIBSUFTot  `xml:"gIBSUF"`
+	GIBSMun          GIBSMunTot `xml:"gIBSMun"`
+	VIBS             string     `xml:"vIBS"`
+	VCredPres        string     `xml:"vCredPres"`
+	VCredPresCondSus string     `xml:"vCredPresCondSus"`
+}
+
+// GIBSUFTot totaliza o IBS estadual
+type GIBSUFTot struct {
+	VDif     string `xml:"vDif"`
+	VDevTrib string `xml:"vDevTrib"`
+	VIBSUF   string `xml:"vIBSUF"`
+}
+
+// GIBSMunTot totaliza o IBS municipal
+type GIBSMunTot struct {
+	VDif     string `xml:"vDif"`
+	VDevTrib string `xml:"vDevTrib"`
+	VIBSMun  string `xml:"vIBSMun"`
+}
+
+// GCBSTot totaliza a CBS
+type GCBSTot struct {
+	VDif             string `xml:"vDif"`
+	VDevTrib         string `xml:"vDevTrib"`
+	VCBS             string `xml:"vCBS"`
+	VCredPres        string `xml:"vCredPres"`
+	VCredPresCondSus string `xml:"vCredPresCondSus"`
+}
+
+// Transp é o grupo de transporte
+type Transp struct {
+	ModFrete   string      `xml:"modFrete"`
+	Transporta *Transporta `xml:"transporta,omitempty"`
+	VeicTransp *VeicTransp `xml:"veicTransp,omitempty"`
+	Vol        []Vol       `xml:"vol,omitempty"`
+}
+
+// Transporta identifica a transportadora
+type Transporta struct {
+	CNPJ   string `xml:"CNPJ,omitempty"`
+	CPF    string `xml:"CPF,omitempty"`
+	XNome  string `xml:"xNome,omitempty"`
+	IE     string `xml:"IE,omitempty"`
+	XEnder string `xml:"xEnder,omitempty"`
+	XMun   string `xml:"xMun,omitempty"`
+	UF     string `xml:"UF,omitempty"`
+}
+
+// VeicTransp identifica o veículo de transporte
+type VeicTransp struct {
+	Placa string `xml:"placa"`
+	UF    string `xml:"UF,omitempty"`
+	RNTC  string `xml:"RNTC,omitempty"`
+}
+
+// Vol descreve os volumes transportados, com pesos em kg
+type Vol struct {
+	QVol  string `xml:"qVol,omitempty"`
+	Esp   string `xml:"esp,omitempty"`
+	PesoL string `xml:"pesoL,omitempty"`
+	PesoB string `xml:"pesoB,omitempty"`
+}
+
+// Cobr é o grupo de cobrança: fatura e duplicatas das vendas a prazo
+type Cobr struct {
+	Fat *Fat  `xml:"fat,omitempty"`
+	Dup []Dup `xml:"dup,omitempty"`
+}
+
+// Fat identifica a fatura
+type Fat struct {
+	NFat  string `xml:"nFat"`
+	VOrig string `xml:"vOrig"`
+	VDesc string `xml:"vDesc"`
+	VLiq  string `xml:"vLiq"`
+}
+
+// Dup é uma parcela (duplicata) da fatura
+type Dup struct {
+	NDup  string `xml:"nDup"`
+	DVenc string `xml:"dVenc"`
+	VDup  string `xml:"vDup"`
+}
+
+// Pag é o grupo de pagamento
+type Pag struct {
+	DetPag []DetPag `xml:"detPag"`
+}
+
+// DetPag detalha uma forma de pagamento
+type DetPag struct {
+	IndPag string `xml:"indPag,omitempty"`
+	TPag   string `xml:"tPag"`
+	XPag   string `xml:"xPag,omitempty"` // Descrição do meio 99 (outros)
+	VPag   string `xml:"vPag"`
+}
+
+// InfAdic contém informações adicionais
+type InfAdic struct {
+	InfAdFisco string `xml:"infAdFisco,omitempty"`
+	InfCpl     string `xml:"infCpl,omitempty"`
+}
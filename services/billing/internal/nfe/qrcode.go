@@ -0,0 +1,99 @@
+package nfe
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Versão do QR Code da NFC-e gerado (NT 2015.002, versão 2)
+const qrCodeVersion = "2"
+
+// Erros da NFC-e
+var (
+	ErrCSCNotConfigured   = errors.New("CSC (código de segurança do contribuinte) não configurado para NFC-e")
+	ErrConsumerURLUnknown = errors.New("URL de consulta da NFC-e não conhecida para a UF")
+)
+
+// consumerURLs são os endereços de consulta por QR Code e por chave de acesso
+// publicados pelas SEFAZ, por UF e ambiente
+type consumerURLs struct {
+	QRCode        string
+	QRCodeHomolog string
+	Query         string
+	QueryHomolog  string
+}
+
+var consumerURLsByUF = map[string]consumerURLs{
+	"SP": {
+		QRCode:        "https://www.nfce.fazenda.sp.gov.br/NFCeConsultaPublica/Paginas/ConsultaQRCode.aspx",
+		QRCodeHomolog: "https://www.homologacao.nfce.fazenda.sp.gov.br/NFCeConsultaPublica/Paginas/ConsultaQRCode.aspx",
+		Query:         "https://www.nfce.fazenda.sp.gov.br/consulta",
+		QueryHomolog:  "https://www.homologacao.nfce.fazenda.sp.gov.br/consulta",
+	},
+	"RJ": {
+		QRCode:        "https://consultadfe.fazenda.rj.gov.br/consultaNFCe/QRCode",
+		QRCodeHomolog: "https://consultadfe.fazenda.rj.gov.br/consultaNFCe/QRCode",
+		Query:         "www.fazenda.rj.gov.br/nfce/consulta",
+		QueryHomolog:  "www.fazenda.rj.gov.br/nfce/consulta",
+	},
+	"MG": {
+		QRCode:        "https://portalsped.fazenda.mg.gov.br/portalnfce/sistema/qrcode.xhtml",
+		QRCodeHomolog: "https://portalsped.fazenda.mg.gov.br/portalnfce/sistema/qrcode.xhtml",
+		Query:         "https://portalsped.fazenda.mg.gov.br/portalnfce",
+		QueryHomolog:  "https://hportalsped.fazenda.mg.gov.br/portalnfce",
+	},
+	"PR": {
+		QRCode:        "http://www.fazenda.pr.gov.br/nfce/qrcode",
+		QRCodeHomolog: "http://www.fazenda.pr.gov.br/nfce/qrcode",
+		Query:         "http://www.fazenda.pr.gov.br/nfce/consulta",
+		QueryHomolog:  "http://www.fazenda.pr.gov.br/nfce/consulta",
+	},
+	"RS": {
+		QRCode:        "https://www.sefaz.rs.gov.br/NFCE/NFCE-COM.aspx",
+		QRCodeHomolog: "https://www.sefaz.rs.gov.br/NFCE/NFCE-COM.aspx",
+		Query:         "https://www.sefaz.rs.gov.br/nfce/consulta",
+		QueryHomolog:  "https://www.sefaz.rs.gov.br/nfce/consulta",
+	},
+}
+
+// ConsumerURLs retorna as URLs do QR Code e da consulta por chave da UF no
+// ambiente informado. As URLs configuradas em Config têm precedência
+func ConsumerURLs(uf string, config Config) (qrCode, query string, err error) {
+	qrCode, query = config.ConsumerQRCodeURL, config.ConsumerQueryURL
+	if qrCode != "" && query != "" {
+		return qrCode, query, nil
+	}
+
+	urls, ok := consumerURLsByUF[strings.ToUpper(uf)]
+	if !ok {
+		return "", "", fmt.Errorf("%w: %s", ErrConsumerURLUnknown, uf)
+	}
+	known, knownQuery := urls.QRCode, urls.Query
+	if config.Environment != EnvironmentProduction {
+		known, knownQuery = urls.QRCodeHomolog, urls.QueryHomolog
+	}
+	if qrCode == "" {
+		qrCode = known
+	}
+	if query == "" {
+		query = knownQuery
+	}
+	return qrCode, query, nil
+}
+
+// ConsumerQRCode monta o conteúdo do QR Code da NFC-e emitida on-line:
+// URL?p=chave|versão|tpAmb|idCSC|hash, em que o hash é o SHA-1 (hexadecimal,
+// maiúsculo) dos parâmetros concatenados ao CSC
+func ConsumerQRCode(baseURL, accessKey string, environment int, cscID, csc string) (string, error) {
+	cscID = strings.TrimLeft(strings.TrimSpace(cscID), "0")
+	if cscID == "" || csc == "" {
+		return "", ErrCSCNotConfigured
+	}
+
+	params := fmt.Sprintf("%s|%s|%d|%s", accessKey, qrCodeVersion, environment, cscID)
+	hash := sha1.Sum([]byte(params + csc))
+	return baseURL + "?p=" + params + "|" + strings.ToUpper(hex.EncodeToString(hash[:])), nil
+}
@@ -0,0 +1,101 @@
+package nfe
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestConsumerQRCode(t *testing.T) {
+	// Hashes de referência calculados como SHA-1 de "chave|2|tpAmb|idCSC" seguido
+	// do CSC, em hexadecimal maiúsculo (NT 2015.002, QR Code versão 2)
+	tests := []struct {
+		name        string
+		accessKey   string
+		environment int
+		cscID       string
+		csc         string
+		expected    string
+		err         error
+	}{
+		{
+			name:        "produção",
+			accessKey:   "28170800156225000131650110000151341562040824",
+			environment: EnvironmentProduction,
+			cscID:       "1",
+			csc:         "SEU-CODIGO-CSC-CONTRIBUINTE-36-CARACTERES",
+			expected:    "https://qrcode.example?p=28170800156225000131650110000151341562040824|2|1|1|DC6AE2C2B9A992BE59679AC365E29922DE6B7511",
+		},
+		{
+			name:        "homologação com zeros à esquerda no idCSC",
+			accessKey:   "35250311222333000181650010000000011123456785",
+			environment: EnvironmentHomologation,
+			cscID:       " 000001 ",
+			csc:         "0123456789ABCDEF0123456789ABCDEF0123",
+			expected:    "https://qrcode.example?p=35250311222333000181650010000000011123456785|2|2|1|9F12CCF07DC80F3B3CBB64EFD0CA330D3639BE42",
+		},
+		{name: "sem idCSC", accessKey: "35250311222333000181650010000000011123456785", cscID: "000", csc: "0123456789ABCDEF", err: ErrCSCNotConfigured},
+		{name: "sem CSC", accessKey: "35250311222333000181650010000000011123456785", cscID: "1", err: ErrCSCNotConfigured},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			qrCode, err := ConsumerQRCode("https://qrcode.example", tt.accessKey, tt.environment, tt.cscID, tt.csc)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("ConsumerQRCode() erro = %v, esperado %v", err, tt.err)
+			}
+			if qrCode != tt.expected {
+				t.Errorf("ConsumerQRCode() = %s, esperado %s", qrCode, tt.expected)
+			}
+		})
+	}
+}
+
+func TestConsumerURLs(t *testing.T) {
+	tests := []struct {
+		name   string
+		uf     string
+		config Config
+		qrCode string
+		query  string
+		err    error
+	}{
+		{
+			name:   "SP em produção",
+			uf:     "SP",
+			config: Config{Environment: EnvironmentProduction},
+			qrCode: "https://www.nfce.fazenda.sp.gov.br/NFCeConsultaPublica/Paginas/ConsultaQRCode.aspx",
+			query:  "https://www.nfce.fazenda.sp.gov.br/consulta",
+		},
+		{
+			name:   "SP em homologação",
+			uf:     "sp",
+			qrCode: "https://www.homologacao.nfce.fazenda.sp.gov.br/NFCeConsultaPublica/Paginas/ConsultaQRCode.aspx",
+			query:  "https://www.homologacao.nfce.fazenda.sp.gov.br/consulta",
+		},
+		{
+			name:   "URL do QR Code configurada",
+			uf:     "MG",
+			config: Config{Environment: EnvironmentProduction, ConsumerQRCodeURL: "https://qrcode.example"},
+			qrCode: "https://qrcode.example",
+			query:  "https://portalsped.fazenda.mg.gov.br/portalnfce",
+		},
+		{
+			name:   "URLs configuradas em UF sem tabela",
+			uf:     "AC",
+			config: Config{ConsumerQRCodeURL: "https://qrcode.example", ConsumerQueryURL: "https://consulta.example"},
+			qrCode: "https://qrcode.example",
+			query:  "https://consulta.example",
+		},
+		{name: "UF sem tabela", uf: "AC", err: ErrConsumerURLUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			qrCode, query, err := ConsumerURLs(tt.uf, tt.config)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("ConsumerURLs() erro = %v, esperado %v", err, tt.err)
+			}
+			if qrCode != tt.qrCode || query != tt.query {
+				t.Errorf("ConsumerURLs() = %s, %s; esperado %s, %s", qrCode, query, tt.qrCode, tt.query)
+			}
+		})
+	}
+}
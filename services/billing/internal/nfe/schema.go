@@ -0,0 +1,48 @@
+package nfe
+
+import (
+	"embed"
+	"io/fs"
+	"sync"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/xsd"
+)
+
+//go:embed schemas/*.xsd
+var schemaFiles embed.FS
+
+// loadSchema compila os schemas embarcados uma única vez
+var loadSchema = sync.OnceValues(func() (*xsd.Schema, error) {
+	schemas, err := fs.Sub(schemaFiles, "schemas")
+	if err != nil {
+		return nil, err
+	}
+	return xsd.Load(schemas, "nfe_v"+Version+".xsd")
+})
+
+// loadEventSchema compila o schema dos eventos uma única vez
+var loadEventSchema = sync.OnceValues(func() (*xsd.Schema, error) {
+	schemas, err := fs.Sub(schemaFiles, "schemas")
+	if err != nil {
+		return nil, err
+	}
+	return xsd.Load(schemas, "leiauteCCe_v"+EventVersion+".xsd")
+})
+
+// Validate valida um documento NF-e contra os schemas do leiaute 4.00
+func Validate(document []byte) error {
+	schema, err := loadSchema()
+	if err != nil {
+		return err
+	}
+	return schema.Validate(document)
+}
+
+// ValidateEvent valida um evento de carta de correção contra o leiaute 1.00
+func ValidateEvent(document []byte) error {
+	schema, err := loadEventSchema()
+	if err != nil {
+		return err
+	}
+	return schema.Validate(document)
+}
@@ -0,0 +1,187 @@
+package nfe
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/xsd"
+)
+
+// testInvoice monta uma NF-e fechada de venda interna, com um item
+func testInvoice(t *testing.T) *domain.Invoice {
+	t.Helper()
+	address := domain.Address{
+		Street:   "Avenida Paulista",
+		Number:   "1000",
+		District: "Bela Vista",
+		CityCode: "3550308",
+		City:     "São Paulo",
+		UF:       "SP",
+		CEP:      "01310100",
+	}
+	issuer := &domain.Issuer{
+		ID:                "issuer-1",
+		Name:              "EMPRESA TESTE LTDA",
+		CNPJ:              "11222333000181",
+		StateRegistration: "110042490114",
+		CRT:               domain.TaxRegimeNormal,
+		Series:            1,
+		Address:           address,
+	}
+	invoice := &domain.Invoice{
+		ID:         "invoice-1",
+		Number:     1,
+		Model:      domain.ModelNFe,
+		Series:     1,
+		Status:     domain.StatusOpen,
+		IssuerID:   issuer.ID,
+		CustomerID: "customer-1",
+		Customer: &domain.Customer{
+			ID:           "customer-1",
+			Name:         "CLIENTE TESTE SA",
+			Document:     "11444777000161",
+			DocumentType: domain.DocumentCNPJ,
+			TaxRegime:    domain.TaxRegimeNormal,
+			Address:      address,
+		},
+		Items: []domain.InvoiceItem{{
+			Type:        domain.ItemGoods,
+			ProductID:   "product-1",
+			ProductCode: "P001",
+			Description: "Parafuso sextavado",
+			Quantity:    10,
+			NCM:         "73181500",
+			Unit:        "UN",
+			UnitPrice:   1250,
+		}},
+		CreatedAt: time.Now(),
+	}
+	invoice.ApplyTaxes(domain.DefaultTaxConfig(), invoice.CreatedAt, issuer.CRT)
+	if err := invoice.Close(issuer); err != nil {
+		t.Fatalf("erro ao fechar nota: %v", err)
+	}
+	return invoice
+}
+
+func TestValidate(t *testing.T) {
+	document, err := Generate(testInvoice(t), Config{})
+	if err != nil {
+		t.Fatalf("Generate() erro inesperado: %v", err)
+	}
+	valid := string(document)
+
+	tests := []struct {
+		name    string
+		tamper  func(string) string
+		message string // Trecho esperado na violação; vazio para documento válido
+	}{
+		{name: "documento gerado", tamper: func(doc string) string { return doc }},
+		{
+			name:    "grupo obrigatório ausente",
+			tamper:  func(doc string) string { return cut(doc, "<emit>", "</emit>") },
+			message: "encontrado <dest>, esperado <emit>",
+		},
+		{
+			name:    "itens ausentes",
+			tamper:  func(doc string) string { return cutAll(doc, "<det ", "</det>") },
+			message: "encontrado <total>, esperado <det>",
+		},
+		{
+			name:    "ICMS do item ausente",
+			tamper:  func(doc string) string { return cut(doc, "<ICMS>", "</ICMS>") },
+			message: "det/imposto: conteúdo inválido: encontrado <PIS>, esperado <vTotTrib> | <ICMS>",
+		},
+		{
+			name:    "totais ausentes",
+			tamper:  func(doc string) string { return cut(doc, "<total>", "</total>") },
+			message: "encontrado <transp>, esperado <det> | <total>",
+		},
+		{
+			name:    "transporte ausente",
+			tamper:  func(doc string) string { return cut(doc, "<transp>", "</transp>") },
+			message: "esperado <transp>",
+		},
+		{
+			name:    "pagamento ausente",
+			tamper:  func(doc string) string { return cut(doc, "<pag>", "</pag>") },
+			message: "encontrado fim do elemento, esperado <cobr> | <pag>",
+		},
+		{
+			name: "padrão inválido",
+			tamper: func(doc string) string {
+				return strings.Replace(doc, "<CNPJ>11222333000181</CNPJ>", "<CNPJ>1122233300018A</CNPJ>", 1)
+			},
+			message: "emit/CNPJ: valor \"1122233300018A\" não corresponde ao padrão",
+		},
+		{
+			name:    "valor fora da enumeração",
+			tamper:  func(doc string) string { return strings.Replace(doc, "<mod>55</mod>", "<mod>57</mod>", 1) },
+			message: "ide/mod: valor \"57\" não permitido",
+		},
+		{
+			name: "ordem dos elementos",
+			tamper: func(doc string) string {
+				emit := between(doc, "<emit>", "</emit>")
+				return strings.Replace(cut(doc, "<emit>", "</emit>"), "<ide>", emit+"<ide>", 1)
+			},
+			message: "encontrado <emit>, esperado <ide>",
+		},
+		{
+			name: "elemento não previsto",
+			tamper: func(doc string) string {
+				return strings.Replace(doc, "</ide>", "<xPed>123</xPed></ide>", 1)
+			},
+			message: "encontrado <xPed>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := tt.tamper(valid)
+			if doc == valid && tt.message != "" {
+				t.Fatalf("alteração não encontrou o trecho esperado no documento")
+			}
+
+			err := Validate([]byte(doc))
+			if tt.message == "" {
+				if err != nil {
+					t.Fatalf("Validate() erro inesperado: %v", err)
+				}
+				return
+			}
+			var validation *xsd.ValidationError
+			if !errors.As(err, &validation) {
+				t.Fatalf("Validate() erro = %v, esperado violação do schema", err)
+			}
+			if !strings.Contains(err.Error(), tt.message) {
+				t.Errorf("Validate() erro = %q, esperado menção a %q", err, tt.message)
+			}
+		})
+	}
+}
+
+// between retorna o trecho do documento do início de open ao fim de close
+func between(doc, open, close string) string {
+	start := strings.Index(doc, open)
+	end := strings.Index(doc, close)
+	if start < 0 || end < start {
+		return ""
+	}
+	return doc[start : end+len(close)]
+}
+
+// cut remove do documento o trecho de open a close
+func cut(doc, open, close string) string {
+	return strings.Replace(doc, between(doc, open, close), "", 1)
+}
+
+// cutAll remove do documento todos os trechos de open a close
+func cutAll(doc, open, close string) string {
+	for strings.Contains(doc, open) {
+		doc = cut(doc, open, close)
+	}
+	return doc
+}
@@ -0,0 +1,302 @@
+package nfe
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/beevik/etree"
+	dsig "github.com/russellhaering/goxmldsig"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// Erros de certificado e assinatura
+var (
+	ErrCertificateNotRSA    = errors.New("certificado A1 deve possuir chave RSA")
+	ErrCertificateExpired   = errors.New("certificado digital fora do prazo de validade")
+	ErrElementToSignMissing = errors.New("elemento a ser assinado não encontrado no documento")
+	ErrSignatureMissing     = errors.New("documento não possui assinatura digital")
+	ErrInvalidSignature     = errors.New("assinatura digital inválida")
+)
+
+// xmldsigNamespace é o namespace da assinatura XMLDSig
+const xmldsigNamespace = "http://www.w3.org/2000/09/xmldsig#"
+
+// Signer assina documentos fiscais com o certificado digital do emitente
+// (A1, PKCS#12), no padrão exigido pela SEFAZ: assinatura envelopada,
+// canonicalização C14N 1.0, digest SHA-1 e RSA-SHA1
+type Signer struct {
+	key         *rsa.PrivateKey
+	certificate *x509.Certificate
+}
+
+// LoadCertificate lê um certificado A1 (arquivo .pfx/.p12) protegido por senha
+func LoadCertificate(path, password string) (*Signer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler certificado: %w", err)
+	}
+	return ParseCertificate(data, password)
+}
+
+// ParseCertificate decodifica um certificado A1 em formato PKCS#12
+func ParseCertificate(pfxData []byte, password string) (*Signer, error) {
+	privateKey, certificate, _, err := pkcs12.DecodeChain(pfxData, password)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao decodificar certificado PKCS#12: %w", err)
+	}
+	return NewSigner(privateKey, certificate)
+}
+
+// NewSigner cria um assinador a partir da chave privada e do certificado
+func NewSigner(privateKey interface{}, certificate *x509.Certificate) (*Signer, error) {
+	key, ok := privateKey.(*rsa.PrivateKey)
+	if !ok || certificate == nil {
+		return nil, ErrCertificateNotRSA
+	}
+	return &Signer{key: key, certificate: certificate}, nil
+}
+
+// Certificate retorna o certificado usado na assinatura
+func (s *Signer) Certificate() *x509.Certificate {
+	return s.certificate
+}
+
+// TLSCertificate retorna o certificado no formato usado na autenticação TLS
+// mútua com os web services da SEFAZ
+func (s *Signer) TLSCertificate() tls.Certificate {
+	return tls.Certificate{
+		Certificate: [][]byte{s.certificate.Raw},
+		PrivateKey:  s.key,
+		Leaf:        s.certificate,
+	}
+}
+
+// Sign assina o elemento infNFe do documento
+func (s *Signer) Sign(document []byte) ([]byte, error) {
+	return s.SignElement(document, "infNFe")
+}
+
+// SignElement assina o primeiro elemento com o nome informado (que deve ter
+// atributo Id), incluindo a assinatura como último filho do elemento pai
+func (s *Signer) SignElement(document []byte, tag string) ([]byte, error) {
+	now := time.Now()
+	if now.Before(s.certificate.NotBefore) || now.After(s.certificate.NotAfter) {
+		return nil, ErrCertificateExpired
+	}
+
+	doc := etree.NewDocument()
+	if err := doc.ReadFromBytes(document); err != nil {
+		return nil, fmt.Errorf("XML malformado: %w", err)
+	}
+	el := doc.FindElement("//" + tag)
+	if el == nil || el.Parent() == nil {
+		return nil, ErrElementToSignMissing
+	}
+
+	ctx, err := dsig.NewSigningContext(s.key, [][]byte{s.certificate.Raw})
+	if err != nil {
+		return nil, err
+	}
+	ctx.IdAttribute = "Id"
+	ctx.Prefix = ""
+	ctx.Canonicalizer = dsig.MakeC14N10RecCanonicalizer()
+	if err := ctx.SetSignatureMethod(dsig.RSASHA1SignatureMethod); err != nil {
+		return nil, err
+	}
+	ctx.Hash = crypto.SHA1
+
+	signature, err := ctx.ConstructSignature(el, true)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao assinar documento: %w", err)
+	}
+	el.Parent().AddChild(signature)
+
+	return doc.WriteToBytes()
+}
+
+// Verify verifica a assinatura do elemento infNFe (ou infEvento, nos eventos
+// como a carta de correção, e infDPS, nas declarações da NFS-e) e retorna o
+// certificado do signatário contido em KeyInfo
+func Verify(document []byte) (*x509.Certificate, error) {
+	certificate, err := VerifyElement(document, "infNFe")
+	for _, tag := range []string{"infEvento", "infDPS"} {
+		if !errors.Is(err, ErrElementToSignMissing) {
+			break
+		}
+		certificate, err = VerifyElement(document, tag)
+	}
+	return certificate, err
+}
+
+// VerifyElement verifica a assinatura que referencia o elemento informado.
+// São conferidos o digest do elemento e a assinatura do SignedInfo com a chave
+// pública do certificado embarcado; a cadeia ICP-Brasil não é validada.
+//
+// A conferência é feita diretamente para o perfil da SEFAZ (C14N 1.0,
+// SHA-1 e RSA-SHA1): a validação genérica do goxmldsig limita o número de
+// elementos percorridos e recusa notas com muitos itens
+func VerifyElement(document []byte, tag string) (*x509.Certificate, error) {
+	doc := etree.NewDocument()
+	if err := doc.ReadFromBytes(document); err != nil {
+		return nil, fmt.Errorf("XML malformado: %w", err)
+	}
+	el := doc.FindElement("//" + tag)
+	if el == nil || el.Parent() == nil {
+		return nil, ErrElementToSignMissing
+	}
+
+	signature, err := referencingSignature(doc, el)
+	if err != nil {
+		return nil, err
+	}
+
+	certificate, err := embeddedCertificate(signature)
+	if err != nil {
+		return nil, err
+	}
+	publicKey, ok := certificate.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return nil, ErrCertificateNotRSA
+	}
+
+	signedInfo := signature.FindElement("./SignedInfo")
+	if signedInfo == nil {
+		return nil, fmt.Errorf("%w: SignedInfo ausente", ErrInvalidSignature)
+	}
+	if err := checkAlgorithms(signedInfo); err != nil {
+		return nil, err
+	}
+	reference := signedInfo.FindElement("./Reference")
+
+	// Digest do elemento referenciado. A assinatura é irmã do elemento, então a
+	// transformação enveloped-signature não remove nada do conteúdo
+	canonicalizer := dsig.MakeC14N10RecCanonicalizer()
+	canonical, err := canonicalizer.Canonicalize(el)
+	if err != nil {
+		return nil, err
+	}
+	expected, err := decodeBase64(reference.FindElement("./DigestValue"))
+	if err != nil {
+		return nil, err
+	}
+	digest := sha1.Sum(canonical)
+	if !bytes.Equal(digest[:], expected) {
+		return nil, fmt.Errorf("%w: digest do elemento %s não confere", ErrInvalidSignature, tag)
+	}
+
+	// Assinatura do SignedInfo canonicalizado
+	canonical, err = canonicalizer.Canonicalize(signedInfo)
+	if err != nil {
+		return nil, err
+	}
+	value, err := decodeBase64(signature.FindElement("./SignatureValue"))
+	if err != nil {
+		return nil, err
+	}
+	hashed := sha1.Sum(canonical)
+	if err := rsa.VerifyPKCS1v15(publicKey, crypto.SHA1, hashed[:], value); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	return certificate, nil
+}
+
+// referencingSignature localiza a assinatura do elemento: o documento deve ter
+// uma única assinatura, irmã do elemento, com uma única referência apontando
+// para o Id dele. Assinaturas adicionais ou deslocadas permitiriam apresentar
+// como assinado um conteúdo diferente do conferido
+func referencingSignature(doc *etree.Document, el *etree.Element) (*etree.Element, error) {
+	var signatures []*etree.Element
+	for _, candidate := range doc.FindElements("//Signature") {
+		if candidate.NamespaceURI() == xmldsigNamespace {
+			signatures = append(signatures, candidate)
+		}
+	}
+	switch {
+	case len(signatures) == 0:
+		return nil, ErrSignatureMissing
+	case len(signatures) > 1:
+		return nil, fmt.Errorf("%w: documento com mais de uma assinatura", ErrInvalidSignature)
+	}
+	signature := signatures[0]
+
+	id := el.SelectAttrValue("Id", "")
+	references := signature.FindElements("./SignedInfo/Reference")
+	if id == "" || len(references) != 1 || references[0].SelectAttrValue("URI", "") != "#"+id {
+		return nil, fmt.Errorf("%w: referência não corresponde ao elemento %s", ErrInvalidSignature, el.Tag)
+	}
+	if len(doc.FindElements("//*[@Id='"+id+"']")) != 1 {
+		return nil, fmt.Errorf("%w: Id %s repetido no documento", ErrInvalidSignature, id)
+	}
+	if signature.Parent() != el.Parent() {
+		return nil, fmt.Errorf("%w: assinatura fora do elemento pai de %s", ErrInvalidSignature, el.Tag)
+	}
+	return signature, nil
+}
+
+// Algoritmos aceitos na assinatura dos documentos fiscais
+const (
+	algorithmC14N      = "http://www.w3.org/TR/2001/REC-xml-c14n-20010315"
+	algorithmEnveloped = "http://www.w3.org/2000/09/xmldsig#enveloped-signature"
+	algorithmSHA1      = "http://www.w3.org/2000/09/xmldsig#sha1"
+	algorithmRSASHA1   = "http://www.w3.org/2000/09/xmldsig#rsa-sha1"
+)
+
+// checkAlgorithms confere se a assinatura usa os algoritmos do padrão da SEFAZ
+func checkAlgorithms(signedInfo *etree.Element) error {
+	expected := map[string]string{
+		"./CanonicalizationMethod":            algorithmC14N,
+		"./SignatureMethod":                   algorithmRSASHA1,
+		"./Reference/DigestMethod":            algorithmSHA1,
+		"./Reference/Transforms/Transform[1]": algorithmEnveloped,
+	}
+	for path, algorithm := range expected {
+		el := signedInfo.FindElement(path)
+		if el == nil || el.SelectAttrValue("Algorithm", "") != algorithm {
+			return fmt.Errorf("%w: algoritmo não suportado em %s", ErrInvalidSignature, strings.TrimPrefix(path, "./"))
+		}
+	}
+	for _, transform := range signedInfo.FindElements("./Reference/Transforms/Transform") {
+		if algorithm := transform.SelectAttrValue("Algorithm", ""); algorithm != algorithmEnveloped && algorithm != algorithmC14N {
+			return fmt.Errorf("%w: transformação %s não suportada", ErrInvalidSignature, algorithm)
+		}
+	}
+	return nil
+}
+
+// decodeBase64 decodifica o conteúdo base64 de um elemento da assinatura
+func decodeBase64(el *etree.Element) ([]byte, error) {
+	if el == nil {
+		return nil, fmt.Errorf("%w: elemento da assinatura ausente", ErrInvalidSignature)
+	}
+	data, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(el.Text()), ""))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s não está em base64", ErrInvalidSignature, el.Tag)
+	}
+	return data, nil
+}
+
+func embeddedCertificate(signature *etree.Element) (*x509.Certificate, error) {
+	el := signature.FindElement("./KeyInfo/X509Data/X509Certificate")
+	if el == nil {
+		return nil, fmt.Errorf("%w: certificado ausente em KeyInfo", ErrInvalidSignature)
+	}
+	raw, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(el.Text()), ""))
+	if err != nil {
+		return nil, fmt.Errorf("%w: certificado em KeyInfo não está em base64", ErrInvalidSignature)
+	}
+	certificate, err := x509.ParseCertificate(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	return certificate, nil
+}
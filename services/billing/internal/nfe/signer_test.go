@@ -0,0 +1,177 @@
+package nfe
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// testSigner gera uma única vez um certificado A1 autoassinado, passando pelo
+// mesmo PKCS#12 lido de NFE_CERT_FILE
+var testSigner = sync.OnceValues(func() (*Signer, error) {
+	return newTestSigner(time.Now().Add(-time.Hour), time.Now().AddDate(0, 0, 30))
+})
+
+func newTestSigner(notBefore, notAfter time.Time) (*Signer, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "EMPRESA TESTE LTDA:11222333000181", Country: []string{"BR"}},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageContentCommitment,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, err
+	}
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	pfx, err := pkcs12.Modern.Encode(key, certificate, nil, "1234")
+	if err != nil {
+		return nil, err
+	}
+	return ParseCertificate(pfx, "1234")
+}
+
+// testNote monta uma NF-e mínima com a quantidade de itens informada
+func testNote(items int) []byte {
+	var b strings.Builder
+	b.WriteString(`<NFe xmlns="http://www.portalfiscal.inf.br/nfe">`)
+	b.WriteString(`<infNFe Id="NFe35250111222333000181550010000000011000000010" versao="4.00">`)
+	b.WriteString(`<ide><cUF>35</cUF><nNF>1</nNF></ide>`)
+	for idx := 1; idx <= items; idx++ {
+		fmt.Fprintf(&b, `<det nItem="%d"><prod><cProd>P%d</cProd><qCom>1.0000</qCom><vProd>10.00</vProd></prod></det>`, idx, idx)
+	}
+	b.WriteString(`<total><ICMSTot><vNF>10.00</vNF></ICMSTot></total></infNFe></NFe>`)
+	return []byte(b.String())
+}
+
+func signTestNote(t *testing.T, items int) string {
+	t.Helper()
+	signer, err := testSigner()
+	if err != nil {
+		t.Fatalf("erro ao gerar certificado: %v", err)
+	}
+	signed, err := signer.Sign(testNote(items))
+	if err != nil {
+		t.Fatalf("erro ao assinar: %v", err)
+	}
+	return string(signed)
+}
+
+var (
+	signatureElement = regexp.MustCompile(`(?s)<Signature .*</Signature>`)
+	digestValue      = regexp.MustCompile(`<DigestValue>[^<]+</DigestValue>`)
+)
+
+func TestVerify(t *testing.T) {
+	tests := []struct {
+		name   string
+		items  int
+		tamper func(string) string
+		err    error
+	}{
+		{name: "assinatura íntegra", items: 1},
+		{
+			// Cada item tem 5 elementos: a nota passa de 1500, e a validação
+			// genérica do goxmldsig para após 1000
+			name:  "nota com mais de 1000 elementos",
+			items: 300,
+		},
+		{
+			name:  "valor alterado após a assinatura",
+			items: 1,
+			tamper: func(doc string) string {
+				return strings.Replace(doc, "<vProd>10.00</vProd>", "<vProd>1.00</vProd>", 1)
+			},
+			err: ErrInvalidSignature,
+		},
+		{
+			// O digest confere com o conteúdo alterado, mas o SignedInfo não
+			// confere com o SignatureValue
+			name:  "digest alterado",
+			items: 1,
+			tamper: func(doc string) string {
+				return digestValue.ReplaceAllString(doc, "<DigestValue>AAAAAAAAAAAAAAAAAAAAAAAAAAA=</DigestValue>")
+			},
+			err: ErrInvalidSignature,
+		},
+		{
+			name:  "assinatura duplicada",
+			items: 1,
+			tamper: func(doc string) string {
+				signature := signatureElement.FindString(doc)
+				return strings.Replace(doc, signature, signature+signature, 1)
+			},
+			err: ErrInvalidSignature,
+		},
+		{
+			name:  "referência para outro elemento",
+			items: 1,
+			tamper: func(doc string) string {
+				return strings.Replace(doc, `<infNFe Id="NFe35`, `<infNFe Id="NFe41`, 1)
+			},
+			err: ErrInvalidSignature,
+		},
+		{
+			name:  "sem assinatura",
+			items: 1,
+			tamper: func(doc string) string {
+				return signatureElement.ReplaceAllString(doc, "")
+			},
+			err: ErrSignatureMissing,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := signTestNote(t, tt.items)
+			if tt.tamper != nil {
+				doc = tt.tamper(doc)
+			}
+
+			certificate, err := Verify([]byte(doc))
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("Verify() erro = %v, esperado %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Verify() erro inesperado: %v", err)
+			}
+			signer, _ := testSigner()
+			if !certificate.Equal(signer.Certificate()) {
+				t.Errorf("Verify() retornou certificado diferente do signatário")
+			}
+		})
+	}
+}
+
+func TestSignExpiredCertificate(t *testing.T) {
+	signer, err := newTestSigner(time.Now().AddDate(-2, 0, 0), time.Now().AddDate(-1, 0, 0))
+	if err != nil {
+		t.Fatalf("erro ao gerar certificado: %v", err)
+	}
+	if _, err := signer.Sign(testNote(1)); !errors.Is(err, ErrCertificateExpired) {
+		t.Errorf("Sign() erro = %v, esperado %v", err, ErrCertificateExpired)
+	}
+}
@@ -0,0 +1,295 @@
+// Package nfse gera a Declaração de Prestação de Serviço (DPS) do padrão
+// nacional da NFS-e (leiaute 1.00) a partir de uma nota de serviço fechada e a
+// valida contra o schema embarcado em schemas/.
+package nfse
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/nfe"
+)
+
+// Version é a versão do leiaute da DPS gerado
+const Version = "1.00"
+
+// Valores fixos das DPS emitidas pelo serviço
+const (
+	EmitterProvider     = "1" // tpEmit: DPS emitida pelo prestador
+	ISSQNTaxable        = "1" // tribISSQN: operação tributável
+	ISSQNNotWithheld    = "1" // tpRetISSQN: ISSQN não retido
+	TotalTaxNotInformed = "0" // indTotTrib: não informa o valor estimado dos tributos
+	dateTimeLayout      = "2006-01-02T15:04:05-07:00"
+	dateLayout          = "2006-01-02"
+	defaultAppVersion   = "korp-billing 1.0"
+)
+
+// ErrIncompleteInvoice indica que a nota não possui os dados necessários à DPS
+var ErrIncompleteInvoice = errors.New("nota de serviço sem dados de prestador, tomador ou itens para gerar a DPS")
+
+// DPS é o elemento raiz da Declaração de Prestação de Serviço
+type DPS struct {
+	XMLName xml.Name `xml:"http://www.sped.fazenda.gov.br/nfse DPS"`
+	Versao  string   `xml:"versao,attr"`
+	InfDPS  InfDPS   `xml:"infDPS"`
+}
+
+// InfDPS agrupa as informações da declaração
+type InfDPS struct {
+	ID       string  `xml:"Id,attr"`
+	TpAmb    string  `xml:"tpAmb"`
+	DhEmi    string  `xml:"dhEmi"`
+	VerAplic string  `xml:"verAplic"`
+	Serie    string  `xml:"serie"`
+	NDPS     string  `xml:"nDPS"`
+	DCompet  string  `xml:"dCompet"`
+	TpEmit   string  `xml:"tpEmit"`
+	CLocEmi  string  `xml:"cLocEmi"`
+	Prest    Prest   `xml:"prest"`
+	Toma     *Toma   `xml:"toma,omitempty"`
+	Serv     Serv    `xml:"serv"`
+	Valores  Valores `xml:"valores"`
+}
+
+// Prest identifica o prestador do serviço (emitente da DPS)
+type Prest struct {
+	CNPJ    string  `xml:"CNPJ"`
+	IM      string  `xml:"IM,omitempty"`
+	RegTrib RegTrib `xml:"regTrib"`
+}
+
+// RegTrib informa o regime tributário do prestador
+type RegTrib struct {
+	OpSimpNac  string `xml:"opSimpNac"`
+	RegEspTrib string `xml:"regEspTrib"`
+}
+
+// Toma identifica o tomador do serviço
+type Toma struct {
+	CNPJ  string    `xml:"CNPJ,omitempty"`
+	CPF   string    `xml:"CPF,omitempty"`
+	XNome string    `xml:"xNome"`
+	End   *Endereco `xml:"end,omitempty"`
+	Email string    `xml:"email,omitempty"`
+}
+
+// Endereco é o endereço nacional do tomador
+type Endereco struct {
+	EndNac  EndNac `xml:"endNac"`
+	XLgr    string `xml:"xLgr"`
+	Nro     string `xml:"nro"`
+	XCpl    string `xml:"xCpl,omitempty"`
+	XBairro string `xml:"xBairro"`
+}
+
+// EndNac informa município e CEP do endereço nacional
+type EndNac struct {
+	CMun string `xml:"cMun"`
+	CEP  string `xml:"CEP"`
+}
+
+// Serv descreve o serviço prestado
+type Serv struct {
+	LocPrest LocPrest `xml:"locPrest"`
+	CServ    CServ    `xml:"cServ"`
+}
+
+// LocPrest informa o município onde o serviço foi prestado
+type LocPrest struct {
+	CLocPrestacao string `xml:"cLocPrestacao"`
+}
+
+// CServ classifica o serviço na lista nacional e no município
+type CServ struct {
+	CTribNac  string `xml:"cTribNac"`
+	CTribMun  string `xml:"cTribMun,omitempty"`
+	XDescServ string `xml:"xDescServ"`
+}
+
+// Valores agrupa o valor do serviço e a tributação
+type Valores struct {
+	VServPrest VServPrest `xml:"vServPrest"`
+	Trib       Trib       `xml:"trib"`
+}
+
+// VServPrest informa o valor do serviço prestado
+type VServPrest struct {
+	VServ string `xml:"vServ"`
+}
+
+// Trib agrupa a tributação municipal e o total dos tributos
+type Trib struct {
+	TribMun TribMun `xml:"tribMun"`
+	TotTrib TotTrib `xml:"totTrib"`
+}
+
+// TribMun informa a tributação do ISSQN
+type TribMun struct {
+	TribISSQN  string `xml:"tribISSQN"`
+	TpRetISSQN string `xml:"tpRetISSQN"`
+	PAliq      string `xml:"pAliq,omitempty"`
+}
+
+// TotTrib informa o valor aproximado dos tributos
+type TotTrib struct {
+	IndTotTrib string `xml:"indTotTrib"`
+}
+
+// DPSID monta o Id da DPS: "DPS" + município emissor (7) + tipo de inscrição
+// federal (1 = CPF, 2 = CNPJ) + inscrição federal (14) + série (5) + número (15)
+func DPSID(cityCode, cnpj string, series, number int) string {
+	return fmt.Sprintf("DPS%s2%014s%05d%015d", cityCode, cnpj, series, number)
+}
+
+// Build monta a DPS de uma nota de serviço fechada. Os itens compartilham a
+// mesma classificação (validada na criação), então a DPS descreve todos eles
+// em um único serviço com o valor total da nota
+func Build(invoice *domain.Invoice, config nfe.Config) (*DPS, error) {
+	if !invoice.IsClosed() || invoice.ClosedAt == nil {
+		return nil, domain.ErrInvoiceNotClosed
+	}
+	if !invoice.IsService() {
+		return nil, domain.ErrNotServiceInvoice
+	}
+	if invoice.Issuer == nil || invoice.Customer == nil || len(invoice.Items) == 0 {
+		return nil, ErrIncompleteInvoice
+	}
+	if config.Environment != nfe.EnvironmentProduction {
+		config.Environment = nfe.EnvironmentHomologation
+	}
+	if config.AppVersion == "" {
+		config.AppVersion = defaultAppVersion
+	}
+
+	issuer := invoice.Issuer
+	service := invoice.Items[0]
+	cityCode := issuer.Address.CityCode
+
+	descriptions := make([]string, 0, len(invoice.Items))
+	for _, item := range invoice.Items {
+		descriptions = append(descriptions, fmt.Sprintf("%d x %s", item.Quantity, item.Description))
+	}
+
+	return &DPS{
+		Versao: Version,
+		InfDPS: InfDPS{
+			ID:       DPSID(cityCode, issuer.CNPJ, invoice.Series, invoice.Number),
+			TpAmb:    strconv.Itoa(config.Environment),
+			DhEmi:    invoice.ClosedAt.Format(dateTimeLayout),
+			VerAplic: truncate(config.AppVersion, 20),
+			Serie:    strconv.Itoa(invoice.Series),
+			NDPS:     strconv.Itoa(invoice.Number),
+			DCompet:  invoice.ClosedAt.Format(dateLayout),
+			TpEmit:   EmitterProvider,
+			CLocEmi:  cityCode,
+			Prest: Prest{
+				CNPJ: issuer.CNPJ,
+				IM:   issuer.MunicipalRegistration,
+				RegTrib: RegTrib{
+					OpSimpNac:  simplesOption(issuer.CRT),
+					RegEspTrib: "0", // Nenhum regime especial
+				},
+			},
+			Toma: buildToma(invoice.Customer),
+			Serv: Serv{
+				LocPrest: LocPrest{CLocPrestacao: cityCode},
+				CServ: CServ{
+					CTribNac:  domain.NationalTaxCode(service.ServiceListItem),
+					CTribMun:  service.MunicipalCode,
+					XDescServ: truncate(strings.Join(descriptions, "; "), 2000),
+				},
+			},
+			Valores: Valores{
+				VServPrest: VServPrest{VServ: invoice.Totals.Services.String()},
+				Trib: Trib{
+					TribMun: TribMun{
+						TribISSQN:  ISSQNTaxable,
+						TpRetISSQN: ISSQNNotWithheld,
+						PAliq:      strconv.FormatFloat(service.ISSRate, 'f', 2, 64),
+					},
+					TotTrib: TotTrib{IndTotTrib: TotalTaxNotInformed},
+				},
+			},
+		},
+	}, nil
+}
+
+// Generate monta, assina (quando há certificado configurado) e valida contra o
+// schema o XML da DPS de uma nota de serviço fechada
+func Generate(invoice *domain.Invoice, config nfe.Config) ([]byte, error) {
+	document, err := Build(invoice, config)
+	if err != nil {
+		return nil, err
+	}
+	data, err := document.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	if config.Signer != nil {
+		if data, err = config.Signer.SignElement(data, "infDPS"); err != nil {
+			return nil, err
+		}
+	}
+	if err := Validate(data); err != nil {
+		return nil, fmt.Errorf("XML da DPS %d inválido: %w", invoice.Number, err)
+	}
+	return data, nil
+}
+
+// Marshal serializa a DPS com a declaração XML
+func (d *DPS) Marshal() ([]byte, error) {
+	data, err := xml.Marshal(d)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao serializar DPS: %w", err)
+	}
+	return append([]byte(xml.Header), data...), nil
+}
+
+// simplesOption converte o CRT do emitente na situação perante o Simples
+// Nacional (opSimpNac): 1 não optante, 2 MEI, 3 ME/EPP
+func simplesOption(crt domain.TaxRegime) string {
+	switch crt {
+	case domain.TaxRegimeMEI:
+		return "2"
+	case domain.TaxRegimeSimples, domain.TaxRegimeSimplesExcess:
+		return "3"
+	default:
+		return "1"
+	}
+}
+
+func buildToma(customer *domain.Customer) *Toma {
+	toma := &Toma{
+		XNome: truncate(customer.Name, 300),
+		Email: truncate(customer.Email, 80),
+	}
+	if customer.DocumentType == domain.DocumentCNPJ {
+		toma.CNPJ = customer.Document
+	} else {
+		toma.CPF = customer.Document
+	}
+	if address := customer.Address; address.CityCode != "" && address.CEP != "" {
+		toma.End = &Endereco{
+			EndNac:  EndNac{CMun: address.CityCode, CEP: address.CEP},
+			XLgr:    truncate(address.Street, 255),
+			Nro:     truncate(address.Number, 60),
+			XCpl:    truncate(address.Complement, 156),
+			XBairro: truncate(address.District, 60),
+		}
+	}
+	return toma
+}
+
+// truncate limita o texto ao tamanho máximo do campo, sem cortar caracteres
+func truncate(value string, max int) string {
+	value = strings.TrimSpace(value)
+	if utf8.RuneCountInString(value) <= max {
+		return value
+	}
+	return strings.TrimSpace(string([]rune(value)[:max]))
+}
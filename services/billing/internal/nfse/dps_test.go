@@ -0,0 +1,264 @@
+package nfse
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/nfe"
+)
+
+// testInvoice monta uma NFS-e fechada com dois itens do mesmo serviço
+// (subitem 01.07, ISS de 5%). change, quando informado, ajusta a nota antes
+// do fechamento
+func testInvoice(t *testing.T, change func(*domain.Invoice, *domain.Issuer)) *domain.Invoice {
+	t.Helper()
+	address := domain.Address{
+		Street:   "Avenida Paulista",
+		Number:   "1000",
+		District: "Bela Vista",
+		CityCode: "3550308",
+		City:     "São Paulo",
+		UF:       "SP",
+		CEP:      "01310100",
+	}
+	issuer := &domain.Issuer{
+		ID:                    "issuer-1",
+		Name:                  "EMPRESA TESTE LTDA",
+		CNPJ:                  "11222333000181",
+		StateRegistration:     "110042490114",
+		MunicipalRegistration: "12345678",
+		CRT:                   domain.TaxRegimeNormal,
+		Series:                1,
+		Address:               address,
+	}
+	service := domain.InvoiceItem{
+		Type:            domain.ItemService,
+		ServiceID:       "service-1",
+		Description:     "Suporte técnico",
+		Quantity:        2,
+		Unit:            "H",
+		UnitPrice:       15000,
+		ServiceListItem: "01.07",
+		MunicipalCode:   "001",
+		ISSRate:         5,
+	}
+	installation := service
+	installation.Description = "Instalação de sistema"
+	installation.Quantity = 1
+	installation.UnitPrice = 20000
+
+	invoice := &domain.Invoice{
+		ID:         "invoice-1",
+		Number:     42,
+		Model:      domain.ModelNFSe,
+		Series:     1,
+		Status:     domain.StatusOpen,
+		IssuerID:   issuer.ID,
+		CustomerID: "customer-1",
+		Customer: &domain.Customer{
+			ID:           "customer-1",
+			Name:         "CLIENTE TESTE SA",
+			Document:     "11444777000161",
+			DocumentType: domain.DocumentCNPJ,
+			TaxRegime:    domain.TaxRegimeNormal,
+			Email:        "fiscal@cliente.com.br",
+			Address:      address,
+		},
+		Items:     []domain.InvoiceItem{service, installation},
+		CreatedAt: time.Now(),
+	}
+	if change != nil {
+		change(invoice, issuer)
+	}
+	invoice.ApplyTaxes(domain.DefaultTaxConfig(), invoice.CreatedAt, issuer.CRT)
+	if err := invoice.Close(issuer); err != nil {
+		t.Fatalf("erro ao fechar nota: %v", err)
+	}
+	return invoice
+}
+
+func TestDPSID(t *testing.T) {
+	// "DPS" + município (7) + tipo de inscrição (1) + CNPJ (14) + série (5) + número (15)
+	id := DPSID("3550308", "11222333000181", 1, 42)
+	if expected := "DPS355030821122233300018100001000000000000042"; id != expected {
+		t.Errorf("DPSID() = %s, esperado %s", id, expected)
+	}
+	if len(id) != 45 {
+		t.Errorf("DPSID() com %d posições, esperado 45", len(id))
+	}
+}
+
+func TestBuild(t *testing.T) {
+	invoice := testInvoice(t, nil)
+	dps, err := Build(invoice, nfe.Config{})
+	if err != nil {
+		t.Fatalf("Build() erro inesperado: %v", err)
+	}
+
+	inf := dps.InfDPS
+	if inf.ID != "DPS355030821122233300018100001000000000000042" || inf.TpAmb != "2" || inf.NDPS != "42" || inf.Serie != "1" {
+		t.Errorf("identificação = %s, tpAmb %s, série %s, número %s", inf.ID, inf.TpAmb, inf.Serie, inf.NDPS)
+	}
+	if inf.DCompet != invoice.ClosedAt.Format("2006-01-02") || inf.CLocEmi != "3550308" || inf.Serv.LocPrest.CLocPrestacao != "3550308" {
+		t.Errorf("competência %s, município emissor %s, local da prestação %s", inf.DCompet, inf.CLocEmi, inf.Serv.LocPrest.CLocPrestacao)
+	}
+	expectedPrest := Prest{CNPJ: "11222333000181", IM: "12345678", RegTrib: RegTrib{OpSimpNac: "1", RegEspTrib: "0"}}
+	if inf.Prest != expectedPrest {
+		t.Errorf("prestador = %+v, esperado %+v", inf.Prest, expectedPrest)
+	}
+	expectedServ := CServ{CTribNac: "010701", CTribMun: "001", XDescServ: "2 x Suporte técnico; 1 x Instalação de sistema"}
+	if inf.Serv.CServ != expectedServ {
+		t.Errorf("serviço = %+v, esperado %+v", inf.Serv.CServ, expectedServ)
+	}
+
+	// R$ 300,00 de suporte e R$ 200,00 de instalação, com ISS de 5%
+	expectedValues := Valores{
+		VServPrest: VServPrest{VServ: "500.00"},
+		Trib: Trib{
+			TribMun: TribMun{TribISSQN: ISSQNTaxable, TpRetISSQN: ISSQNNotWithheld, PAliq: "5.00"},
+			TotTrib: TotTrib{IndTotTrib: TotalTaxNotInformed},
+		},
+	}
+	if inf.Valores != expectedValues {
+		t.Errorf("valores = %+v, esperado %+v", inf.Valores, expectedValues)
+	}
+	if invoice.Totals.ISS == nil || invoice.Totals.ISS.Base != 50000 || invoice.Totals.ISS.Value != 2500 {
+		t.Errorf("total do ISS = %+v, esperado base 50000 e valor 2500", invoice.Totals.ISS)
+	}
+
+	toma := inf.Toma
+	if toma == nil || toma.CNPJ != "11444777000161" || toma.CPF != "" || toma.Email != "fiscal@cliente.com.br" || toma.End == nil || toma.End.EndNac.CEP != "01310100" {
+		t.Errorf("tomador = %+v", toma)
+	}
+}
+
+func TestBuildVariants(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(*domain.Invoice, *domain.Issuer)
+		check  func(*testing.T, *DPS)
+	}{
+		{
+			name:   "prestador do Simples Nacional",
+			change: func(_ *domain.Invoice, issuer *domain.Issuer) { issuer.CRT = domain.TaxRegimeSimples },
+			check: func(t *testing.T, dps *DPS) {
+				if option := dps.InfDPS.Prest.RegTrib.OpSimpNac; option != "3" {
+					t.Errorf("opSimpNac = %s, esperado 3", option)
+				}
+			},
+		},
+		{
+			name:   "prestador MEI",
+			change: func(_ *domain.Invoice, issuer *domain.Issuer) { issuer.CRT = domain.TaxRegimeMEI },
+			check: func(t *testing.T, dps *DPS) {
+				if option := dps.InfDPS.Prest.RegTrib.OpSimpNac; option != "2" {
+					t.Errorf("opSimpNac = %s, esperado 2", option)
+				}
+			},
+		},
+		{
+			name: "tomador pessoa física sem endereço",
+			change: func(invoice *domain.Invoice, _ *domain.Issuer) {
+				invoice.Customer.Document = "52998224725"
+				invoice.Customer.DocumentType = domain.DocumentCPF
+				invoice.Customer.Address = domain.Address{}
+			},
+			check: func(t *testing.T, dps *DPS) {
+				if toma := dps.InfDPS.Toma; toma.CPF != "52998224725" || toma.CNPJ != "" || toma.End != nil {
+					t.Errorf("tomador = %+v, esperado CPF sem endereço", toma)
+				}
+			},
+		},
+		{
+			name: "alíquota fracionária",
+			change: func(invoice *domain.Invoice, _ *domain.Issuer) {
+				for idx := range invoice.Items {
+					invoice.Items[idx].ISSRate = 2.5
+				}
+			},
+			check: func(t *testing.T, dps *DPS) {
+				if rate := dps.InfDPS.Valores.Trib.TribMun.PAliq; rate != "2.50" {
+					t.Errorf("pAliq = %s, esperado 2.50", rate)
+				}
+			},
+		},
+		{
+			name: "descrição longa",
+			change: func(invoice *domain.Invoice, _ *domain.Issuer) {
+				invoice.Items[0].Description = strings.Repeat("ç", 2100)
+			},
+			check: func(t *testing.T, dps *DPS) {
+				if length := len([]rune(dps.InfDPS.Serv.CServ.XDescServ)); length != 2000 {
+					t.Errorf("xDescServ com %d caracteres, esperado 2000", length)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			invoice := testInvoice(t, tt.change)
+			dps, err := Build(invoice, nfe.Config{Environment: nfe.EnvironmentProduction})
+			if err != nil {
+				t.Fatalf("Build() erro inesperado: %v", err)
+			}
+			tt.check(t, dps)
+			if dps.InfDPS.TpAmb != "1" {
+				t.Errorf("tpAmb = %s, esperado 1", dps.InfDPS.TpAmb)
+			}
+			if _, err := Generate(invoice, nfe.Config{}); err != nil {
+				t.Errorf("Generate() erro inesperado: %v", err)
+			}
+		})
+	}
+}
+
+func TestBuildRejects(t *testing.T) {
+	open := testInvoice(t, nil)
+	open.Status = domain.StatusOpen
+	if _, err := Build(open, nfe.Config{}); !errors.Is(err, domain.ErrInvoiceNotClosed) {
+		t.Errorf("Build() em nota aberta erro = %v, esperado %v", err, domain.ErrInvoiceNotClosed)
+	}
+
+	goods := testInvoice(t, nil)
+	goods.Model = domain.ModelNFe
+	for idx := range goods.Items {
+		goods.Items[idx].Type = domain.ItemGoods
+	}
+	if _, err := Build(goods, nfe.Config{}); !errors.Is(err, domain.ErrNotServiceInvoice) {
+		t.Errorf("Build() em nota de mercadorias erro = %v, esperado %v", err, domain.ErrNotServiceInvoice)
+	}
+
+	withoutCustomer := testInvoice(t, nil)
+	withoutCustomer.Customer = nil
+	if _, err := Build(withoutCustomer, nfe.Config{}); !errors.Is(err, ErrIncompleteInvoice) {
+		t.Errorf("Build() sem tomador erro = %v, esperado %v", err, ErrIncompleteInvoice)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	document, err := Generate(testInvoice(t, nil), nfe.Config{})
+	if err != nil {
+		t.Fatalf("Generate() erro inesperado: %v", err)
+	}
+	for _, fragment := range []string{
+		`<DPS xmlns="http://www.sped.fazenda.gov.br/nfse" versao="1.00">`,
+		`<infDPS Id="DPS355030821122233300018100001000000000000042">`,
+		`<cTribNac>010701</cTribNac>`,
+		`<vServ>500.00</vServ>`,
+		`<tribMun><tribISSQN>1</tribISSQN><tpRetISSQN>1</tpRetISSQN><pAliq>5.00</pAliq></tribMun>`,
+	} {
+		if !strings.Contains(string(document), fragment) {
+			t.Errorf("DPS sem o trecho %s", fragment)
+		}
+	}
+
+	// O schema recusa a DPS sem o grupo de valores
+	valid := string(document)
+	start, end := strings.Index(valid, "<valores>"), strings.Index(valid, "</valores>")
+	if err := Validate([]byte(valid[:start] + valid[end+len("</valores>"):])); err == nil {
+		t.Errorf("Validate() aceitou DPS sem o grupo de valores")
+	}
+}
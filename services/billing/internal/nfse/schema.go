@@ -0,0 +1,30 @@
+package nfse
+
+import (
+	"embed"
+	"io/fs"
+	"sync"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/xsd"
+)
+
+//go:embed schemas/*.xsd
+var schemaFiles embed.FS
+
+// loadSchema compila o schema embarcado uma única vez
+var loadSchema = sync.OnceValues(func() (*xsd.Schema, error) {
+	schemas, err := fs.Sub(schemaFiles, "schemas")
+	if err != nil {
+		return nil, err
+	}
+	return xsd.Load(schemas, "DPS_v"+Version+".xsd")
+})
+
+// Validate valida uma DPS contra o schema do leiaute 1.00
+func Validate(document []byte) error {
+	schema, err := loadSchema()
+	if err != nil {
+		return err
+	}
+	return schema.Validate(document)
+}
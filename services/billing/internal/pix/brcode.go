@@ -0,0 +1,158 @@
+// Package pix gera o BR Code do PIX (padrão EMV QRCPS-MPM do Banco Central)
+// das cobranças de notas e parcelas: o payload "copia e cola" e a imagem do
+// QR Code correspondente.
+package pix
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image/png"
+	"strings"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+	"github.com/boombuler/barcode"
+	"github.com/boombuler/barcode/qr"
+)
+
+// Valores fixos do BR Code
+const (
+	gui                  = "br.gov.bcb.pix" // Identificador do arranjo PIX
+	payloadFormat        = "01"
+	singleUse            = "12" // Point of Initiation Method: QR Code de uso único
+	merchantCategoryCode = "0000"
+	currencyBRL          = "986" // ISO 4217
+	countryCode          = "BR"
+	maxMerchantName      = 25
+	maxMerchantCity      = 15
+	maxLocation          = 77
+)
+
+// Limites do QR Code gerado, em pixels
+const (
+	DefaultImageSize = 300
+	MinImageSize     = 100
+	MaxImageSize     = 1000
+)
+
+// Erros da geração do BR Code
+var (
+	ErrLocationTooLong  = errors.New("URL da cobrança PIX excede 77 caracteres")
+	ErrInvalidImageSize = fmt.Errorf("tamanho do QR Code deve estar entre %d e %d pixels", MinImageSize, MaxImageSize)
+)
+
+// Payload reúne os campos do BR Code. Com Location o código é dinâmico e a
+// chave, o valor e o txid ficam na cobrança registrada no PSP
+type Payload struct {
+	Key          string
+	Location     string // URL da cobrança sem o esquema (PIX dinâmico)
+	MerchantName string
+	MerchantCity string
+	Amount       domain.Money
+	TxID         string
+}
+
+// Encode monta o payload "copia e cola", terminado pelo CRC16 do conteúdo
+func (p Payload) Encode() string {
+	var account strings.Builder
+	account.WriteString(field("00", gui))
+	if p.Location != "" {
+		account.WriteString(field("25", p.Location))
+	} else {
+		account.WriteString(field("01", p.Key))
+	}
+
+	var b strings.Builder
+	b.WriteString(field("00", payloadFormat))
+	if p.Location != "" {
+		b.WriteString(field("01", singleUse))
+	}
+	b.WriteString(field("26", account.String()))
+	b.WriteString(field("52", merchantCategoryCode))
+	b.WriteString(field("53", currencyBRL))
+	if p.Amount > 0 {
+		b.WriteString(field("54", p.Amount.String()))
+	}
+	b.WriteString(field("58", countryCode))
+	b.WriteString(field("59", merchantText(p.MerchantName, maxMerchantName)))
+	b.WriteString(field("60", merchantText(p.MerchantCity, maxMerchantCity)))
+	b.WriteString(field("62", field("05", p.TxID)))
+	b.WriteString("6304")
+
+	return b.String() + fmt.Sprintf("%04X", CRC16([]byte(b.String())))
+}
+
+// CRC16 calcula o CRC-16/CCITT-FALSE (polinômio 0x1021, valor inicial 0xFFFF)
+// exigido no campo 63 do BR Code
+func CRC16(data []byte) uint16 {
+	crc := uint16(0xFFFF)
+	for _, b := range data {
+		crc ^= uint16(b) << 8
+		for bit := 0; bit < 8; bit++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// Image gera o QR Code do payload em PNG, com o lado informado em pixels
+func Image(payload string, size int) ([]byte, error) {
+	if size < MinImageSize || size > MaxImageSize {
+		return nil, ErrInvalidImageSize
+	}
+	code, err := qr.Encode(payload, qr.M, qr.Auto)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao gerar QR Code: %w", err)
+	}
+	code, err = barcode.Scale(code, size, size)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao gerar QR Code: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, code); err != nil {
+		return nil, fmt.Errorf("erro ao gerar QR Code: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// field codifica um campo EMV: identificador, tamanho com dois dígitos e valor
+func field(id, value string) string {
+	return fmt.Sprintf("%s%02d%s", id, len(value), value)
+}
+
+// accents mapeia as letras acentuadas para o equivalente sem acento
+var accents = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "õ", "o", "ö", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ç", "c", "ñ", "n",
+	"Á", "A", "À", "A", "Â", "A", "Ã", "A", "Ä", "A",
+	"É", "E", "È", "E", "Ê", "E", "Ë", "E",
+	"Í", "I", "Ì", "I", "Î", "I", "Ï", "I",
+	"Ó", "O", "Ò", "O", "Ô", "O", "Õ", "O", "Ö", "O",
+	"Ú", "U", "Ù", "U", "Û", "U", "Ü", "U",
+	"Ç", "C", "Ñ", "N",
+)
+
+// merchantText limita nome e cidade do recebedor aos caracteres ASCII
+// imprimíveis, sem acentos, e ao tamanho máximo do campo
+func merchantText(value string, max int) string {
+	var b strings.Builder
+	for _, r := range accents.Replace(strings.TrimSpace(value)) {
+		if r >= ' ' && r <= '~' {
+			b.WriteRune(r)
+		}
+	}
+	text := b.String()
+	if len(text) > max {
+		text = text[:max]
+	}
+	return strings.TrimSpace(text)
+}
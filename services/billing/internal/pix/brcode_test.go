@@ -0,0 +1,95 @@
+package pix
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCRC16(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		expected uint16
+	}{
+		// Valor de conferência do CRC-16/CCITT-FALSE
+		{name: "valor de conferência", data: "123456789", expected: 0x29B1},
+		{name: "vazio mantém o valor inicial", data: "", expected: 0xFFFF},
+		// Exemplo de BR Code estático do Manual de Padrões para Iniciação do Pix
+		{
+			name:     "exemplo do Banco Central",
+			data:     "00020126580014br.gov.bcb.pix0136123e4567-e12b-12d1-a456-4266554400005204000053039865802BR5913Fulano de Tal6008BRASILIA62070503***6304",
+			expected: 0x1D3D,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CRC16([]byte(tt.data)); got != tt.expected {
+				t.Errorf("CRC16(%q) = %04X, esperado %04X", tt.data, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPayloadEncode(t *testing.T) {
+	tests := []struct {
+		name     string
+		payload  Payload
+		expected string // Conteúdo até o identificador do CRC (6304), inclusive
+	}{
+		{
+			// BR Code estático do Manual de Padrões para Iniciação do Pix, cujo
+			// CRC publicado é 1D3D
+			name: "exemplo do Banco Central",
+			payload: Payload{
+				Key:          "123e4567-e12b-12d1-a456-426655440000",
+				MerchantName: "Fulano de Tal",
+				MerchantCity: "BRASILIA",
+				TxID:         "***",
+			},
+			expected: "00020126580014br.gov.bcb.pix0136123e4567-e12b-12d1-a456-4266554400005204000053039865802BR5913Fulano de Tal6008BRASILIA62070503***6304",
+		},
+		{
+			name: "valor e nome com acentos acima do limite",
+			payload: Payload{
+				Key:          "11222333000181",
+				MerchantName: "Comércio de Peças São João Ltda",
+				MerchantCity: "São José dos Campos",
+				Amount:       12345,
+				TxID:         "NF55S1N1P001",
+			},
+			expected: "00020126360014br.gov.bcb.pix0114112223330001815204000053039865406123.455802BR" +
+				"5925Comercio de Pecas Sao Joa6015Sao Jose dos Ca62160512NF55S1N1P0016304",
+		},
+		{
+			name: "dinâmico",
+			payload: Payload{
+				Location:     "pix.example.com/qr/v2/NF55S1N1P001",
+				MerchantName: "EMPRESA TESTE LTDA",
+				MerchantCity: "Sao Paulo",
+				TxID:         "***",
+			},
+			expected: "00020101021226560014br.gov.bcb.pix2534pix.example.com/qr/v2/NF55S1N1P001" +
+				"5204000053039865802BR5918EMPRESA TESTE LTDA6009Sao Paulo62070503***6304",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.payload.Encode()
+			content, crc := got[:len(got)-4], got[len(got)-4:]
+			if content != tt.expected {
+				t.Errorf("Encode() =\n%s\nesperado\n%s", content, tt.expected)
+			}
+			if expected := fmt.Sprintf("%04X", CRC16([]byte(content))); crc != expected {
+				t.Errorf("Encode() CRC = %s, esperado %s", crc, expected)
+			}
+		})
+	}
+
+	// O exemplo do Banco Central é reproduzido integralmente
+	example := tests[0].payload.Encode()
+	if published := tests[0].expected + "1D3D"; example != published {
+		t.Errorf("Encode() = %s, esperado o exemplo publicado %s", example, published)
+	}
+}
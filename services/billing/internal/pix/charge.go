@@ -0,0 +1,78 @@
+package pix
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+)
+
+// Config reúne a configuração do PIX dinâmico
+type Config struct {
+	LocationURL string // URL base das cobranças no PSP; cada cobrança fica em <URL>/<txid>
+}
+
+// NewCharge monta a cobrança PIX de uma nota ou de uma de suas parcelas. O
+// txid é derivado do modelo, da série e do número da nota e da parcela; o PIX
+// dinâmico usa a cobrança de mesmo txid registrada no PSP
+func NewCharge(invoice *domain.Invoice, issuer *domain.Issuer, installment string, amount domain.Money, mode domain.PixMode, config Config) (*domain.PixCharge, error) {
+	if issuer == nil || issuer.PixKey == "" {
+		return nil, domain.ErrPixKeyNotConfigured
+	}
+
+	charge := &domain.PixCharge{
+		Mode:        mode,
+		Amount:      amount,
+		Installment: installment,
+	}
+	payload := Payload{
+		MerchantName: issuer.Name,
+		MerchantCity: issuer.Address.City,
+	}
+	if issuer.TradeName != "" {
+		payload.MerchantName = issuer.TradeName
+	}
+
+	switch mode {
+	case domain.PixDynamic:
+		if config.LocationURL == "" {
+			return nil, domain.ErrPixDynamicNotConfigured
+		}
+		charge.TxID = TxID(invoice, issuer, installment, true)
+		charge.Location = location(config.LocationURL, charge.TxID)
+		if len(charge.Location) > maxLocation {
+			return nil, ErrLocationTooLong
+		}
+		payload.Location = charge.Location
+		payload.TxID = "***" // O txid da cobrança dinâmica é consultado no PSP
+	default:
+		charge.TxID = TxID(invoice, issuer, installment, false)
+		charge.Key = issuer.PixKey
+		payload.Key = issuer.PixKey
+		payload.Amount = amount
+		payload.TxID = charge.TxID
+	}
+
+	charge.Payload = payload.Encode()
+	return charge, nil
+}
+
+// TxID deriva o identificador da transação da nota: modelo, série, número e
+// parcela ("000" para a nota inteira). O PIX estático admite até 25
+// caracteres; o dinâmico exige de 26 a 35 e recebe o CNPJ do emitente à frente
+func TxID(invoice *domain.Invoice, issuer *domain.Issuer, installment string, dynamic bool) string {
+	if installment == "" {
+		installment = "000"
+	}
+	txid := fmt.Sprintf("%s%03d%09d%s", invoice.Model, invoice.Series, invoice.Number, installment)
+	if dynamic {
+		return issuer.CNPJ + txid
+	}
+	return "NF" + txid
+}
+
+// location monta a URL da cobrança sem o esquema, como exige o campo 26-25
+func location(baseURL, txid string) string {
+	base := strings.TrimPrefix(strings.TrimPrefix(baseURL, "https://"), "http://")
+	return strings.TrimSuffix(base, "/") + "/" + txid
+}
@@ -0,0 +1,79 @@
+package mem
+
+import (
+	"sync"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+)
+
+// BackorderMemRepository implementa BackorderRepository em memória
+type BackorderMemRepository struct {
+	mu         sync.RWMutex
+	backorders map[string]*domain.Backorder
+	order      []string // IDs na ordem de criação, que define a fila de atendimento
+}
+
+// NewBackorderMemRepository cria uma nova instância do repositório
+func NewBackorderMemRepository() *BackorderMemRepository {
+	return &BackorderMemRepository{
+		backorders: make(map[string]*domain.Backorder),
+	}
+}
+
+// Create adiciona uma nova pendência de entrega
+func (r *BackorderMemRepository) Create(backorder *domain.Backorder) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.backorders[backorder.ID] = backorder
+	r.order = append(r.order, backorder.ID)
+	return nil
+}
+
+// FindByID busca uma pendência por ID
+func (r *BackorderMemRepository) FindByID(id string) (*domain.Backorder, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	backorder, exists := r.backorders[id]
+	if !exists {
+		return nil, domain.ErrBackorderNotFound
+	}
+	return backorder, nil
+}
+
+// FindAll retorna todas as pendências, ordenadas pela criação
+func (r *BackorderMemRepository) FindAll() ([]*domain.Backorder, error) {
+	return r.filter(func(*domain.Backorder) bool { return true }), nil
+}
+
+// FindByProduct retorna as pendências do produto, ordenadas pela criação
+func (r *BackorderMemRepository) FindByProduct(productID string) ([]*domain.Backorder, error) {
+	return r.filter(func(b *domain.Backorder) bool { return b.ProductID == productID }), nil
+}
+
+// Update atualiza uma pendência existente
+func (r *BackorderMemRepository) Update(backorder *domain.Backorder) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.backorders[backorder.ID]; !exists {
+		return domain.ErrBackorderNotFound
+	}
+	r.backorders[backorder.ID] = backorder
+	return nil
+}
+
+// filter retorna as pendências aceitas, da mais antiga para a mais nova
+func (r *BackorderMemRepository) filter(accept func(*domain.Backorder) bool) []*domain.Backorder {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	backorders := make([]*domain.Backorder, 0, len(r.order))
+	for _, id := range r.order {
+		if backorder := r.backorders[id]; accept(backorder) {
+			backorders = append(backorders, backorder)
+		}
+	}
+	return backorders
+}
@@ -0,0 +1,100 @@
+package mem
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+)
+
+// CarrierMemRepository implementa CarrierRepository em memória
+type CarrierMemRepository struct {
+	mu        sync.RWMutex
+	carriers  map[string]*domain.Carrier
+	documents map[string]string // documento -> ID
+}
+
+// NewCarrierMemRepository cria uma nova instância do repositório
+func NewCarrierMemRepository() *CarrierMemRepository {
+	return &CarrierMemRepository{
+		carriers:  make(map[string]*domain.Carrier),
+		documents: make(map[string]string),
+	}
+}
+
+// Create adiciona uma nova transportadora
+func (r *CarrierMemRepository) Create(carrier *domain.Carrier) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.documents[carrier.Document]; exists {
+		return domain.ErrDuplicateCarrierDocument
+	}
+
+	r.carriers[carrier.ID] = carrier
+	r.documents[carrier.Document] = carrier.ID
+	return nil
+}
+
+// FindByID busca uma transportadora por ID
+func (r *CarrierMemRepository) FindByID(id string) (*domain.Carrier, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	carrier, exists := r.carriers[id]
+	if !exists {
+		return nil, domain.ErrCarrierNotFound
+	}
+	return carrier, nil
+}
+
+// FindAll retorna todas as transportadoras, ordenadas pelo nome
+func (r *CarrierMemRepository) FindAll() ([]*domain.Carrier, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	carriers := make([]*domain.Carrier, 0, len(r.carriers))
+	for _, carrier := range r.carriers {
+		carriers = append(carriers, carrier)
+	}
+	sort.Slice(carriers, func(i, j int) bool { return carriers[i].Name < carriers[j].Name })
+	return carriers, nil
+}
+
+// Update atualiza uma transportadora existente
+func (r *CarrierMemRepository) Update(carrier *domain.Carrier) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, exists := r.carriers[carrier.ID]
+	if !exists {
+		return domain.ErrCarrierNotFound
+	}
+
+	// Se o documento mudou, atualiza o índice de documentos
+	if existing.Document != carrier.Document {
+		if _, docExists := r.documents[carrier.Document]; docExists {
+			return domain.ErrDuplicateCarrierDocument
+		}
+		delete(r.documents, existing.Document)
+		r.documents[carrier.Document] = carrier.ID
+	}
+
+	r.carriers[carrier.ID] = carrier
+	return nil
+}
+
+// Delete remove uma transportadora
+func (r *CarrierMemRepository) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	carrier, exists := r.carriers[id]
+	if !exists {
+		return domain.ErrCarrierNotFound
+	}
+
+	delete(r.carriers, id)
+	delete(r.documents, carrier.Document)
+	return nil
+}
@@ -0,0 +1,49 @@
+package mem
+
+import (
+	"sync"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+)
+
+// ContingencyMemRepository implementa ContingencyRepository em memória
+type ContingencyMemRepository struct {
+	mu          sync.RWMutex
+	contingency *domain.Contingency
+}
+
+// NewContingencyMemRepository cria uma nova instância do repositório
+func NewContingencyMemRepository() *ContingencyMemRepository {
+	return &ContingencyMemRepository{}
+}
+
+// Get retorna a contingência vigente ou nil em emissão normal
+func (r *ContingencyMemRepository) Get() (*domain.Contingency, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.contingency == nil {
+		return nil, nil
+	}
+	contingency := *r.contingency
+	return &contingency, nil
+}
+
+// Save registra a entrada em contingência
+func (r *ContingencyMemRepository) Save(contingency *domain.Contingency) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	saved := *contingency
+	r.contingency = &saved
+	return nil
+}
+
+// Clear encerra a contingência vigente
+func (r *ContingencyMemRepository) Clear() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.contingency = nil
+	return nil
+}
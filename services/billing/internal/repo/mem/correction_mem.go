@@ -0,0 +1,68 @@
+package mem
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+)
+
+// CorrectionMemRepository implementa CorrectionRepository em memória
+type CorrectionMemRepository struct {
+	mu            sync.RWMutex
+	letters       map[string][]*domain.CorrectionLetter // ID da nota -> cartas
+	lastSequences map[string]int                        // Controla o nSeqEvento por nota
+}
+
+// NewCorrectionMemRepository cria uma nova instância do repositório
+func NewCorrectionMemRepository() *CorrectionMemRepository {
+	return &CorrectionMemRepository{
+		letters:       make(map[string][]*domain.CorrectionLetter),
+		lastSequences: make(map[string]int),
+	}
+}
+
+// Create adiciona uma nova carta de correção
+func (r *CorrectionMemRepository) Create(letter *domain.CorrectionLetter) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	letters := append(r.letters[letter.InvoiceID], letter)
+	sort.Slice(letters, func(i, j int) bool {
+		return letters[i].Sequence < letters[j].Sequence
+	})
+	r.letters[letter.InvoiceID] = letters
+	return nil
+}
+
+// FindByInvoice retorna as cartas de correção da nota, pela ordem da sequência
+func (r *CorrectionMemRepository) FindByInvoice(invoiceID string) ([]*domain.CorrectionLetter, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	letters := make([]*domain.CorrectionLetter, len(r.letters[invoiceID]))
+	copy(letters, r.letters[invoiceID])
+	return letters, nil
+}
+
+// FindBySequence busca a carta de correção da nota com o nSeqEvento informado
+func (r *CorrectionMemRepository) FindBySequence(invoiceID string, sequence int) (*domain.CorrectionLetter, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, letter := range r.letters[invoiceID] {
+		if letter.Sequence == sequence {
+			return letter, nil
+		}
+	}
+	return nil, domain.ErrCorrectionNotFound
+}
+
+// GetNextSequence retorna o próximo nSeqEvento da nota
+func (r *CorrectionMemRepository) GetNextSequence(invoiceID string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lastSequences[invoiceID]++
+	return r.lastSequences[invoiceID], nil
+}
@@ -0,0 +1,110 @@
+package mem
+
+import (
+	"sync"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+)
+
+// CustomerMemRepository implementa CustomerRepository em memória
+type CustomerMemRepository struct {
+	mu        sync.RWMutex
+	customers map[string]*domain.Customer
+	documents map[string]string // documento -> ID
+}
+
+// NewCustomerMemRepository cria uma nova instância do repositório
+func NewCustomerMemRepository() *CustomerMemRepository {
+	return &CustomerMemRepository{
+		customers: make(map[string]*domain.Customer),
+		documents: make(map[string]string),
+	}
+}
+
+// Create adiciona um novo cliente
+func (r *CustomerMemRepository) Create(customer *domain.Customer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.documents[customer.Document]; exists {
+		return domain.ErrDuplicateDocument
+	}
+
+	r.customers[customer.ID] = customer
+	r.documents[customer.Document] = customer.ID
+	return nil
+}
+
+// FindByID busca um cliente por ID
+func (r *CustomerMemRepository) FindByID(id string) (*domain.Customer, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	customer, exists := r.customers[id]
+	if !exists {
+		return nil, domain.ErrCustomerNotFound
+	}
+	return customer, nil
+}
+
+// FindByDocument busca um cliente pelo CPF/CNPJ
+func (r *CustomerMemRepository) FindByDocument(document string) (*domain.Customer, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	id, exists := r.documents[document]
+	if !exists {
+		return nil, domain.ErrCustomerNotFound
+	}
+	return r.customers[id], nil
+}
+
+// FindAll retorna todos os clientes
+func (r *CustomerMemRepository) FindAll() ([]*domain.Customer, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	customers := make([]*domain.Customer, 0, len(r.customers))
+	for _, customer := range r.customers {
+		customers = append(customers, customer)
+	}
+	return customers, nil
+}
+
+// Update atualiza um cliente existente
+func (r *CustomerMemRepository) Update(customer *domain.Customer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, exists := r.customers[customer.ID]
+	if !exists {
+		return domain.ErrCustomerNotFound
+	}
+
+	// Se o documento mudou, atualiza o índice de documentos
+	if existing.Document != customer.Document {
+		if _, docExists := r.documents[customer.Document]; docExists {
+			return domain.ErrDuplicateDocument
+		}
+		delete(r.documents, existing.Document)
+		r.documents[customer.Document] = customer.ID
+	}
+
+	r.customers[customer.ID] = customer
+	return nil
+}
+
+// Delete remove um cliente
+func (r *CustomerMemRepository) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	customer, exists := r.customers[id]
+	if !exists {
+		return domain.ErrCustomerNotFound
+	}
+
+	delete(r.customers, id)
+	delete(r.documents, customer.Document)
+	return nil
+}
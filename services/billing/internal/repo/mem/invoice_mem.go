@@ -1,6 +1,7 @@
 package mem
 
 import (
+	"fmt"
 	"sync"
 	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
 )
@@ -9,14 +10,16 @@ import (
 type InvoiceMemRepository struct {
 	mu           sync.RWMutex
 	invoices     map[string]*domain.Invoice
-	lastNumber   int // Controla a numeração sequencial
+	accessKeys   map[string]string // Índice de chave de acesso -> ID
+	lastNumbers  map[string]int // Controla a numeração sequencial por emitente/modelo/série
 }
 
 // NewInvoiceMemRepository cria uma nova instância do repositório
 func NewInvoiceMemRepository() *InvoiceMemRepository {
 	return &InvoiceMemRepository{
-		invoices:   make(map[string]*domain.Invoice),
-		lastNumber: 0,
+		invoices:    make(map[string]*domain.Invoice),
+		accessKeys:  make(map[string]string),
+		lastNumbers: make(map[string]int),
 	}
 }
 
@@ -26,6 +29,9 @@ func (r *InvoiceMemRepository) Create(invoice *domain.Invoice) error {
 	defer r.mu.Unlock()
 
 	r.invoices[invoice.ID] = invoice
+	if invoice.AccessKey != "" {
+		r.accessKeys[invoice.AccessKey] = invoice.ID
+	}
 	return nil
 }
 
@@ -41,6 +47,18 @@ func (r *InvoiceMemRepository) FindByID(id string) (*domain.Invoice, error) {
 	return invoice, nil
 }
 
+// FindByAccessKey busca uma nota fiscal pela chave de acesso
+func (r *InvoiceMemRepository) FindByAccessKey(key string) (*domain.Invoice, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	id, exists := r.accessKeys[key]
+	if !exists {
+		return nil, domain.ErrInvoiceNotFound
+	}
+	return r.invoices[id], nil
+}
+
 // FindAll retorna todas as notas fiscais
 func (r *InvoiceMemRepository) FindAll() ([]*domain.Invoice, error) {
 	r.mu.RLock()
@@ -63,14 +81,18 @@ func (r *InvoiceMemRepository) Update(invoice *domain.Invoice) error {
 	}
 
 	r.invoices[invoice.ID] = invoice
+	if invoice.AccessKey != "" {
+		r.accessKeys[invoice.AccessKey] = invoice.ID
+	}
 	return nil
 }
 
-// GetNextNumber retorna o próximo número sequencial
-func (r *InvoiceMemRepository) GetNextNumber() (int, error) {
+// GetNextNumber retorna o próximo número sequencial do emitente no modelo e série
+func (r *InvoiceMemRepository) GetNextNumber(issuerID, model string, series int) (int, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	r.lastNumber++
-	return r.lastNumber, nil
+	key := fmt.Sprintf("%s/%s/%d", issuerID, model, series)
+	r.lastNumbers[key]++
+	return r.lastNumbers[key], nil
 }
\ No newline at end of file
@@ -0,0 +1,82 @@
+package mem
+
+import (
+	"sync"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+)
+
+// IssuerMemRepository implementa IssuerRepository em memória
+type IssuerMemRepository struct {
+	mu      sync.RWMutex
+	issuers map[string]*domain.Issuer
+	cnpjs   map[string]string // CNPJ -> ID
+}
+
+// NewIssuerMemRepository cria uma nova instância do repositório
+func NewIssuerMemRepository() *IssuerMemRepository {
+	return &IssuerMemRepository{
+		issuers: make(map[string]*domain.Issuer),
+		cnpjs:   make(map[string]string),
+	}
+}
+
+// Create adiciona um novo emitente
+func (r *IssuerMemRepository) Create(issuer *domain.Issuer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.cnpjs[issuer.CNPJ]; exists {
+		return domain.ErrDuplicateIssuer
+	}
+
+	r.issuers[issuer.ID] = issuer
+	r.cnpjs[issuer.CNPJ] = issuer.ID
+	return nil
+}
+
+// FindByID busca um emitente por ID
+func (r *IssuerMemRepository) FindByID(id string) (*domain.Issuer, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	issuer, exists := r.issuers[id]
+	if !exists {
+		return nil, domain.ErrIssuerNotFound
+	}
+	return issuer, nil
+}
+
+// FindAll retorna todos os emitentes
+func (r *IssuerMemRepository) FindAll() ([]*domain.Issuer, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	issuers := make([]*domain.Issuer, 0, len(r.issuers))
+	for _, issuer := range r.issuers {
+		issuers = append(issuers, issuer)
+	}
+	return issuers, nil
+}
+
+// Update atualiza um emitente existente
+func (r *IssuerMemRepository) Update(issuer *domain.Issuer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, exists := r.issuers[issuer.ID]
+	if !exists {
+		return domain.ErrIssuerNotFound
+	}
+
+	if existing.CNPJ != issuer.CNPJ {
+		if _, cnpjExists := r.cnpjs[issuer.CNPJ]; cnpjExists {
+			return domain.ErrDuplicateIssuer
+		}
+		delete(r.cnpjs, existing.CNPJ)
+		r.cnpjs[issuer.CNPJ] = issuer.ID
+	}
+
+	r.issuers[issuer.ID] = issuer
+	return nil
+}
@@ -0,0 +1,77 @@
+package mem
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+)
+
+// SalesOrderMemRepository implementa SalesOrderRepository em memória
+type SalesOrderMemRepository struct {
+	mu         sync.RWMutex
+	orders     map[string]*domain.SalesOrder
+	lastNumber int // Controla a numeração sequencial dos pedidos
+}
+
+// NewSalesOrderMemRepository cria uma nova instância do repositório
+func NewSalesOrderMemRepository() *SalesOrderMemRepository {
+	return &SalesOrderMemRepository{
+		orders: make(map[string]*domain.SalesOrder),
+	}
+}
+
+// Create adiciona um novo pedido de venda
+func (r *SalesOrderMemRepository) Create(order *domain.SalesOrder) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.orders[order.ID] = order
+	return nil
+}
+
+// FindByID busca um pedido de venda por ID
+func (r *SalesOrderMemRepository) FindByID(id string) (*domain.SalesOrder, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	order, exists := r.orders[id]
+	if !exists {
+		return nil, domain.ErrSalesOrderNotFound
+	}
+	return order, nil
+}
+
+// FindAll retorna todos os pedidos de venda, ordenados pelo número
+func (r *SalesOrderMemRepository) FindAll() ([]*domain.SalesOrder, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	orders := make([]*domain.SalesOrder, 0, len(r.orders))
+	for _, order := range r.orders {
+		orders = append(orders, order)
+	}
+	sort.Slice(orders, func(i, j int) bool { return orders[i].Number < orders[j].Number })
+	return orders, nil
+}
+
+// Update atualiza um pedido de venda existente
+func (r *SalesOrderMemRepository) Update(order *domain.SalesOrder) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.orders[order.ID]; !exists {
+		return domain.ErrSalesOrderNotFound
+	}
+	r.orders[order.ID] = order
+	return nil
+}
+
+// GetNextNumber retorna o próximo número sequencial de pedido
+func (r *SalesOrderMemRepository) GetNextNumber() (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lastNumber++
+	return r.lastNumber, nil
+}
@@ -0,0 +1,100 @@
+package mem
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+)
+
+// ServiceMemRepository implementa ServiceRepository em memória
+type ServiceMemRepository struct {
+	mu       sync.RWMutex
+	services map[string]*domain.Service
+	codes    map[string]string // código -> ID
+}
+
+// NewServiceMemRepository cria uma nova instância do repositório
+func NewServiceMemRepository() *ServiceMemRepository {
+	return &ServiceMemRepository{
+		services: make(map[string]*domain.Service),
+		codes:    make(map[string]string),
+	}
+}
+
+// Create adiciona um novo serviço ao catálogo
+func (r *ServiceMemRepository) Create(service *domain.Service) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.codes[service.Code]; exists {
+		return domain.ErrDuplicateServiceCode
+	}
+
+	r.services[service.ID] = service
+	r.codes[service.Code] = service.ID
+	return nil
+}
+
+// FindByID busca um serviço por ID
+func (r *ServiceMemRepository) FindByID(id string) (*domain.Service, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	service, exists := r.services[id]
+	if !exists {
+		return nil, domain.ErrServiceNotFound
+	}
+	return service, nil
+}
+
+// FindAll retorna todos os serviços, ordenados pelo código
+func (r *ServiceMemRepository) FindAll() ([]*domain.Service, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	services := make([]*domain.Service, 0, len(r.services))
+	for _, service := range r.services {
+		services = append(services, service)
+	}
+	sort.Slice(services, func(i, j int) bool { return services[i].Code < services[j].Code })
+	return services, nil
+}
+
+// Update atualiza um serviço existente
+func (r *ServiceMemRepository) Update(service *domain.Service) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, exists := r.services[service.ID]
+	if !exists {
+		return domain.ErrServiceNotFound
+	}
+
+	// Se o código mudou, atualiza o índice de códigos
+	if existing.Code != service.Code {
+		if _, codeExists := r.codes[service.Code]; codeExists {
+			return domain.ErrDuplicateServiceCode
+		}
+		delete(r.codes, existing.Code)
+		r.codes[service.Code] = service.ID
+	}
+
+	r.services[service.ID] = service
+	return nil
+}
+
+// Delete remove um serviço do catálogo
+func (r *ServiceMemRepository) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	service, exists := r.services[id]
+	if !exists {
+		return domain.ErrServiceNotFound
+	}
+
+	delete(r.services, id)
+	delete(r.codes, service.Code)
+	return nil
+}
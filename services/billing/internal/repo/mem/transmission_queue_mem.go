@@ -0,0 +1,79 @@
+package mem
+
+import (
+	"sync"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+)
+
+// TransmissionQueueMemRepository implementa TransmissionQueue em memória
+type TransmissionQueueMemRepository struct {
+	mu      sync.RWMutex
+	entries []*domain.QueuedTransmission // Em ordem de entrada na fila
+}
+
+// NewTransmissionQueueMemRepository cria uma nova instância do repositório
+func NewTransmissionQueueMemRepository() *TransmissionQueueMemRepository {
+	return &TransmissionQueueMemRepository{}
+}
+
+// Enqueue adiciona uma nota ao fim da fila, ignorando notas já enfileiradas
+func (r *TransmissionQueueMemRepository) Enqueue(entry *domain.QueuedTransmission) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.indexOf(entry.InvoiceID) >= 0 {
+		return nil
+	}
+	r.entries = append(r.entries, entry)
+	return nil
+}
+
+// Pending retorna cópias das entradas na ordem da fila
+func (r *TransmissionQueueMemRepository) Pending() ([]*domain.QueuedTransmission, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entries := make([]*domain.QueuedTransmission, 0, len(r.entries))
+	for _, entry := range r.entries {
+		copied := *entry
+		entries = append(entries, &copied)
+	}
+	return entries, nil
+}
+
+// Update atualiza as tentativas de uma entrada da fila
+func (r *TransmissionQueueMemRepository) Update(entry *domain.QueuedTransmission) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	idx := r.indexOf(entry.InvoiceID)
+	if idx < 0 {
+		return domain.ErrTransmissionNotQueued
+	}
+	updated := *entry
+	r.entries[idx] = &updated
+	return nil
+}
+
+// Remove retira a nota da fila
+func (r *TransmissionQueueMemRepository) Remove(invoiceID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	idx := r.indexOf(invoiceID)
+	if idx < 0 {
+		return domain.ErrTransmissionNotQueued
+	}
+	r.entries = append(r.entries[:idx], r.entries[idx+1:]...)
+	return nil
+}
+
+func (r *TransmissionQueueMemRepository) indexOf(invoiceID string) int {
+	for idx, entry := range r.entries {
+		if entry.InvoiceID == invoiceID {
+			return idx
+		}
+	}
+	return -1
+}
@@ -0,0 +1,215 @@
+package sefaz
+
+import (
+	"bytes"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+)
+
+// Caminhos dos web services a partir da URL base do autorizador
+const (
+	AuthorizationPath    = "/ws/NFeAutorizacao4.asmx"
+	RetAuthorizationPath = "/ws/NFeRetAutorizacao4.asmx"
+)
+
+// maxResponseSize limita o tamanho das respostas lidas da SEFAZ
+const maxResponseSize = 10 << 20
+
+// ErrUnexpectedResponse indica um retorno fora do contrato do web service
+var ErrUnexpectedResponse = errors.New("resposta inesperada da SEFAZ")
+
+// Config reúne os endereços e limites de comunicação com o autorizador
+type Config struct {
+	BaseURL      string        // URL do autorizador (ex.: https://homologacao.nfe.fazenda.sp.gov.br)
+	Environment  int           // tpAmb: 1 produção, 2 homologação
+	Timeout      time.Duration // Tempo máximo de cada requisição
+	PollInterval time.Duration // Intervalo entre as consultas do recibo
+	MaxPolls     int           // Consultas do recibo antes de devolver a nota como pendente
+	// Certificate é o certificado A1 usado na autenticação TLS mútua exigida
+	// pelos web services da SEFAZ
+	Certificate *tls.Certificate
+}
+
+// Client implementa domain.AuthorityClient para os web services SOAP de
+// autorização da NF-e (NFeAutorizacao4 e NFeRetAutorizacao4)
+type Client struct {
+	config     Config
+	httpClient *http.Client
+}
+
+// NewClient cria um cliente para o autorizador configurado
+func NewClient(config Config) *Client {
+	if config.Timeout <= 0 {
+		config.Timeout = 30 * time.Second
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = time.Second
+	}
+	if config.MaxPolls <= 0 {
+		config.MaxPolls = 5
+	}
+	config.BaseURL = strings.TrimRight(config.BaseURL, "/")
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if config.Certificate != nil {
+		transport.TLSClientConfig = &tls.Config{
+			Certificates: []tls.Certificate{*config.Certificate},
+			MinVersion:   tls.VersionTLS12,
+		}
+	}
+
+	return &Client{
+		config: config,
+		httpClient: &http.Client{
+			Timeout:   config.Timeout,
+			Transport: transport,
+		},
+	}
+}
+
+// Authorize envia a nota em um lote assíncrono e consulta o recibo até que o
+// lote seja processado ou se esgotem as consultas. Se a consulta falhar depois
+// do envio, o erro é devolvido junto com a autorização pendente, que guarda o
+// recibo para uma nova consulta
+func (c *Client) Authorize(document []byte) (*domain.Authorization, error) {
+	batch := EnviNFe{
+		Versao:    messageVersion,
+		IDLote:    newBatchID(),
+		IndSinc:   0,
+		Documents: stripHeader(document),
+	}
+
+	var ret RetEnviNFe
+	if err := c.call(AuthorizationPath, authorizationNamespace, authorizationAction, batch, &ret); err != nil {
+		return nil, err
+	}
+
+	// Rejeições do lote (schema, certificado, etc.) vêm no próprio retorno do envio
+	if ret.CStat != domain.SEFAZBatchReceived || ret.InfRec == nil {
+		return &domain.Authorization{
+			Status:     domain.AuthorizationRejected,
+			StatusCode: ret.CStat,
+			Reason:     ret.XMotivo,
+			ReceivedAt: parseTime(ret.DhRecbto),
+		}, nil
+	}
+
+	wait := c.config.PollInterval
+	if median := time.Duration(ret.InfRec.TMed) * time.Second; median > wait {
+		wait = median
+	}
+	for poll := 1; ; poll++ {
+		time.Sleep(wait)
+		result, err := c.QueryReceipt(ret.InfRec.NRec)
+		if err != nil {
+			return &domain.Authorization{
+				Status:     domain.AuthorizationPending,
+				Receipt:    ret.InfRec.NRec,
+				StatusCode: ret.CStat,
+				Reason:     ret.XMotivo,
+			}, err
+		}
+		if result.Status != domain.AuthorizationPending || poll >= c.config.MaxPolls {
+			return result, err
+		}
+		wait = c.config.PollInterval
+	}
+}
+
+// QueryReceipt consulta o processamento do lote e retorna o protocolo da nota
+func (c *Client) QueryReceipt(receipt string) (*domain.Authorization, error) {
+	query := ConsReciNFe{
+		Versao: messageVersion,
+		TpAmb:  c.config.Environment,
+		NRec:   receipt,
+	}
+
+	var ret RetConsReciNFe
+	if err := c.call(RetAuthorizationPath, retAuthorizationNamespace, retAuthorizationAction, query, &ret); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case ret.CStat == domain.SEFAZBatchProcessing:
+		return &domain.Authorization{
+			Status:     domain.AuthorizationPending,
+			Receipt:    receipt,
+			StatusCode: ret.CStat,
+			Reason:     ret.XMotivo,
+		}, nil
+	case ret.CStat != domain.SEFAZBatchProcessed || len(ret.ProtNFe) == 0:
+		// Recibo inexistente, expirado ou lote rejeitado por inteiro
+		return &domain.Authorization{
+			Status:     domain.AuthorizationRejected,
+			Receipt:    receipt,
+			StatusCode: ret.CStat,
+			Reason:     ret.XMotivo,
+			ReceivedAt: parseTime(ret.DhRecbto),
+		}, nil
+	}
+
+	// O lote é enviado com uma única nota
+	protocol := ret.ProtNFe[0].InfProt
+	return &domain.Authorization{
+		Status:     domain.AuthorizationStatusFor(protocol.CStat),
+		Receipt:    receipt,
+		Protocol:   protocol.NProt,
+		StatusCode: protocol.CStat,
+		Reason:     protocol.XMotivo,
+		ReceivedAt: parseTime(protocol.DhRecbto),
+	}, nil
+}
+
+// call envia a mensagem ao web service e decodifica o retorno
+func (c *Client) call(path, serviceNamespace, action string, message, result interface{}) error {
+	payload, err := wrap(serviceNamespace, message)
+	if err != nil {
+		return fmt.Errorf("erro ao montar mensagem SOAP: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.config.BaseURL+path, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("erro ao criar requisição: %w", err)
+	}
+	req.Header.Set("Content-Type", fmt.Sprintf(`application/soap+xml; charset=utf-8; action="%s"`, action))
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", domain.ErrSEFAZUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return fmt.Errorf("%w: %v", domain.ErrSEFAZUnavailable, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: status HTTP %d", domain.ErrSEFAZUnavailable, resp.StatusCode)
+	}
+	if err := unwrap(data, result); err != nil {
+		return fmt.Errorf("%w: %v", ErrUnexpectedResponse, err)
+	}
+	return nil
+}
+
+// newBatchID gera o identificador do lote (até 15 dígitos)
+func newBatchID() string {
+	return strconv.FormatInt(time.Now().UnixNano()/int64(time.Microsecond)%1e15, 10)
+}
+
+// parseTime interpreta as datas no formato AAAA-MM-DDThh:mm:ssTZD
+func parseTime(value string) *time.Time {
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil
+	}
+	return &parsed
+}
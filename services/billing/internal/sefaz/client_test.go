@@ -0,0 +1,287 @@
+package sefaz
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/nfe"
+)
+
+// testSigner gera uma única vez um certificado autoassinado para as notas
+var testSigner = sync.OnceValues(func() (*nfe.Signer, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "EMPRESA TESTE LTDA:11222333000181"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().AddDate(0, 0, 30),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, err
+	}
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	return nfe.NewSigner(key, certificate)
+})
+
+// testInvoice monta uma NF-e fechada de venda interna com o número informado
+func testInvoice(t *testing.T, number int) *domain.Invoice {
+	t.Helper()
+	address := domain.Address{
+		Street:   "Avenida Paulista",
+		Number:   "1000",
+		District: "Bela Vista",
+		CityCode: "3550308",
+		City:     "São Paulo",
+		UF:       "SP",
+		CEP:      "01310100",
+	}
+	issuer := &domain.Issuer{
+		ID:                "issuer-1",
+		Name:              "EMPRESA TESTE LTDA",
+		CNPJ:              "11222333000181",
+		StateRegistration: "110042490114",
+		CRT:               domain.TaxRegimeNormal,
+		Series:            1,
+		Address:           address,
+	}
+	invoice := &domain.Invoice{
+		ID:         "invoice-1",
+		Number:     number,
+		Model:      domain.ModelNFe,
+		Series:     1,
+		Status:     domain.StatusOpen,
+		IssuerID:   issuer.ID,
+		CustomerID: "customer-1",
+		Customer: &domain.Customer{
+			ID:           "customer-1",
+			Name:         "CLIENTE TESTE SA",
+			Document:     "11444777000161",
+			DocumentType: domain.DocumentCNPJ,
+			TaxRegime:    domain.TaxRegimeNormal,
+			Address:      address,
+		},
+		Items: []domain.InvoiceItem{{
+			Type:        domain.ItemGoods,
+			ProductID:   "product-1",
+			ProductCode: "P001",
+			Description: "Parafuso sextavado",
+			Quantity:    10,
+			NCM:         "73181500",
+			Unit:        "UN",
+			UnitPrice:   1250,
+		}},
+		CreatedAt: time.Now(),
+	}
+	invoice.ApplyTaxes(domain.DefaultTaxConfig(), invoice.CreatedAt, issuer.CRT)
+	if err := invoice.Close(issuer); err != nil {
+		t.Fatalf("erro ao fechar nota: %v", err)
+	}
+	return invoice
+}
+
+// signedNote gera o XML assinado da nota, aceito pelo schema e pela
+// conferência de assinatura do autorizador simulado
+func signedNote(t *testing.T, number int) []byte {
+	t.Helper()
+	signer, err := testSigner()
+	if err != nil {
+		t.Fatalf("erro ao gerar certificado: %v", err)
+	}
+	document, err := nfe.Generate(testInvoice(t, number), nfe.Config{Environment: nfe.EnvironmentHomologation, Signer: signer})
+	if err != nil {
+		t.Fatalf("erro ao gerar XML: %v", err)
+	}
+	return document
+}
+
+// newTestClient sobe o autorizador simulado com o roteiro informado e um
+// cliente apontado para ele, sem espera entre as consultas do recibo
+func newTestClient(t *testing.T, script Script, timeout time.Duration) *Client {
+	t.Helper()
+	server := httptest.NewServer(NewMockServer(script))
+	t.Cleanup(server.Close)
+	return NewClient(Config{
+		BaseURL:      server.URL,
+		Environment:  nfe.EnvironmentHomologation,
+		Timeout:      timeout,
+		PollInterval: time.Millisecond,
+		MaxPolls:     3,
+	})
+}
+
+func TestClientAuthorize(t *testing.T) {
+	tests := []struct {
+		name       string
+		step       Step
+		status     domain.AuthorizationStatus
+		statusCode int
+		protocol   bool // Protocolo registrado (autorização e denegação)
+	}{
+		{
+			name:       "autorizada",
+			step:       Step{Outcome: OutcomeAuthorized},
+			status:     domain.AuthorizationAuthorized,
+			statusCode: domain.SEFAZAuthorized,
+			protocol:   true,
+		},
+		{
+			name:       "rejeitada",
+			step:       Step{Outcome: OutcomeRejected},
+			status:     domain.AuthorizationRejected,
+			statusCode: 778,
+		},
+		{
+			name:       "rejeitada com cStat do roteiro",
+			step:       Step{Outcome: OutcomeRejected, StatusCode: 539, Reason: "Rejeição: Duplicidade de NF-e com diferença na Chave de Acesso"},
+			status:     domain.AuthorizationRejected,
+			statusCode: 539,
+		},
+		{
+			name:       "denegada",
+			step:       Step{Outcome: OutcomeDenied},
+			status:     domain.AuthorizationDenied,
+			statusCode: 302,
+			protocol:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			client := newTestClient(t, Script{Default: tt.step, ProcessingPolls: 1}, 5*time.Second)
+
+			result, err := client.Authorize(signedNote(t, 1))
+			if err != nil {
+				t.Fatalf("Authorize() erro inesperado: %v", err)
+			}
+			if result.Status != tt.status || result.StatusCode != tt.statusCode {
+				t.Errorf("Authorize() = %s/%d, esperado %s/%d", result.Status, result.StatusCode, tt.status, tt.statusCode)
+			}
+			if (result.Protocol != "") != tt.protocol {
+				t.Errorf("Authorize() protocolo = %q, esperado protocolo: %v", result.Protocol, tt.protocol)
+			}
+			if tt.step.Reason != "" && result.Reason != tt.step.Reason {
+				t.Errorf("Authorize() motivo = %q, esperado %q", result.Reason, tt.step.Reason)
+			}
+			if result.Receipt == "" || result.ReceivedAt == nil {
+				t.Errorf("Authorize() sem recibo ou data de recebimento: %+v", result)
+			}
+		})
+	}
+}
+
+func TestClientAuthorizeTimeout(t *testing.T) {
+	t.Parallel()
+	// O envio fica sem resposta além do tempo máximo da requisição
+	client := newTestClient(t, Script{Default: Step{Outcome: OutcomeTimeout}, HangSeconds: 5}, 200*time.Millisecond)
+
+	result, err := client.Authorize(signedNote(t, 1))
+	if !errors.Is(err, domain.ErrSEFAZUnavailable) {
+		t.Fatalf("Authorize() erro = %v, esperado %v", err, domain.ErrSEFAZUnavailable)
+	}
+	if result != nil {
+		t.Errorf("Authorize() = %+v, esperado nenhum retorno sem recibo", result)
+	}
+}
+
+func TestClientAuthorizeStillProcessing(t *testing.T) {
+	t.Parallel()
+	// O lote segue em processamento após todas as consultas: a nota fica
+	// pendente com o recibo, e uma nova consulta traz o protocolo
+	client := newTestClient(t, Script{Default: Step{Outcome: OutcomeAuthorized}, ProcessingPolls: 3}, 5*time.Second)
+
+	result, err := client.Authorize(signedNote(t, 1))
+	if err != nil {
+		t.Fatalf("Authorize() erro inesperado: %v", err)
+	}
+	if result.Status != domain.AuthorizationPending || result.StatusCode != domain.SEFAZBatchProcessing || result.Receipt == "" {
+		t.Fatalf("Authorize() = %+v, esperado pendente em processamento com recibo", result)
+	}
+
+	result, err = client.QueryReceipt(result.Receipt)
+	if err != nil {
+		t.Fatalf("QueryReceipt() erro inesperado: %v", err)
+	}
+	if result.Status != domain.AuthorizationAuthorized || result.Protocol == "" {
+		t.Errorf("QueryReceipt() = %+v, esperado autorizada com protocolo", result)
+	}
+}
+
+func TestClientBatchRejected(t *testing.T) {
+	t.Parallel()
+	client := newTestClient(t, DefaultScript(), 5*time.Second)
+
+	// Sem assinatura a nota não atende ao schema, e o lote é rejeitado no envio
+	document, err := nfe.Generate(testInvoice(t, 1), nfe.Config{Environment: nfe.EnvironmentHomologation})
+	if err != nil {
+		t.Fatalf("erro ao gerar XML: %v", err)
+	}
+	result, err := client.Authorize(document)
+	if err != nil {
+		t.Fatalf("Authorize() erro inesperado: %v", err)
+	}
+	if result.Status != domain.AuthorizationRejected || result.StatusCode != 225 || result.Receipt != "" {
+		t.Errorf("Authorize() = %+v, esperado lote rejeitado (225) sem recibo", result)
+	}
+}
+
+func TestClientUnavailable(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		err     error
+	}{
+		{
+			name: "erro HTTP",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "manutenção", http.StatusServiceUnavailable)
+			},
+			err: domain.ErrSEFAZUnavailable,
+		},
+		{
+			name:    "resposta fora do contrato",
+			handler: func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("<html>erro</html>")) },
+			err:     ErrUnexpectedResponse,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+			client := NewClient(Config{BaseURL: server.URL, Timeout: time.Second})
+
+			if _, err := client.QueryReceipt("351000000000001"); !errors.Is(err, tt.err) {
+				t.Errorf("QueryReceipt() erro = %v, esperado %v", err, tt.err)
+			}
+		})
+	}
+
+	t.Run("servidor fora do ar", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		server.Close()
+		client := NewClient(Config{BaseURL: server.URL, Timeout: time.Second})
+
+		if _, err := client.QueryReceipt("351000000000001"); !errors.Is(err, domain.ErrSEFAZUnavailable) {
+			t.Errorf("QueryReceipt() erro = %v, esperado %v", err, domain.ErrSEFAZUnavailable)
+		}
+	})
+}
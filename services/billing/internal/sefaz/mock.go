@@ -0,0 +1,361 @@
+package sefaz
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/nfe"
+)
+
+// ScriptPath é o endpoint de controle do roteiro do autorizador simulado
+const ScriptPath = "/mock/script"
+
+// mockVersion é o verAplic informado nos retornos do autorizador simulado
+const mockVersion = "SEFAZ-MOCK-4.00"
+
+// Outcome é o resultado que o autorizador simulado dá a um lote
+type Outcome string
+
+const (
+	OutcomeAuthorized Outcome = "authorized" // cStat 100
+	OutcomeRejected   Outcome = "rejected"   // Rejeição (padrão 778)
+	OutcomeDenied     Outcome = "denied"     // Uso denegado (padrão 302)
+	OutcomeTimeout    Outcome = "timeout"    // Não responde ao envio do lote
+)
+
+// ErrInvalidScript indica um roteiro com resultado desconhecido
+var ErrInvalidScript = errors.New("roteiro do autorizador simulado inválido")
+
+// Step descreve a resposta para um lote. StatusCode e Reason substituem o
+// código e o motivo padrão de rejeições e denegações
+type Step struct {
+	Outcome    Outcome `json:"outcome"`
+	StatusCode int     `json:"status_code,omitempty"`
+	Reason     string  `json:"reason,omitempty"`
+}
+
+// Script controla as respostas do autorizador simulado. Cada lote recebido
+// consome o próximo passo de Steps; esgotados os passos, vale Default
+type Script struct {
+	Steps           []Step `json:"steps"`
+	Default         Step   `json:"default"`
+	ProcessingPolls int    `json:"processing_polls"` // Consultas respondidas com 105 antes do resultado
+	HangSeconds     int    `json:"hang_seconds"`     // Tempo que o envio fica sem resposta no resultado timeout
+}
+
+// Validate verifica os resultados informados no roteiro
+func (s Script) Validate() error {
+	for _, step := range append([]Step{s.Default}, s.Steps...) {
+		switch step.Outcome {
+		case OutcomeAuthorized, OutcomeRejected, OutcomeDenied, OutcomeTimeout:
+		default:
+			return fmt.Errorf("%w: resultado %q", ErrInvalidScript, step.Outcome)
+		}
+	}
+	if s.ProcessingPolls < 0 || s.HangSeconds < 0 {
+		return ErrInvalidScript
+	}
+	return nil
+}
+
+// DefaultScript autoriza todas as notas após uma consulta em processamento
+func DefaultScript() Script {
+	return Script{
+		Default:         Step{Outcome: OutcomeAuthorized},
+		ProcessingPolls: 1,
+		HangSeconds:     60,
+	}
+}
+
+// MockServer simula os web services de autorização da SEFAZ com o mesmo
+// contrato SOAP, para desenvolvimento e testes sem acesso à rede. O XML
+// recebido é validado contra os schemas e a assinatura é conferida
+type MockServer struct {
+	mu         sync.Mutex
+	script     Script
+	batches    map[string]*mockBatch
+	authorized map[string]string // Chave de acesso -> protocolo
+	sequence   int64
+}
+
+type mockBatch struct {
+	polls      int
+	receivedAt time.Time
+	protocols  []ProtNFe
+}
+
+// NewMockServer cria o autorizador simulado com o roteiro informado
+func NewMockServer(script Script) *MockServer {
+	return &MockServer{
+		script:     script,
+		batches:    make(map[string]*mockBatch),
+		authorized: make(map[string]string),
+	}
+}
+
+// SetScript substitui o roteiro de respostas
+func (m *MockServer) SetScript(script Script) error {
+	if err := script.Validate(); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.script = script
+	return nil
+}
+
+// Script retorna o roteiro vigente, com os passos ainda não consumidos
+func (m *MockServer) Script() Script {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	script := m.script
+	script.Steps = append([]Step(nil), m.script.Steps...)
+	return script
+}
+
+// ServeHTTP atende os web services NFeAutorizacao4, NFeRetAutorizacao4 e o
+// endpoint de controle do roteiro
+func (m *MockServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.URL.Path == ScriptPath && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, m.Script())
+	case r.URL.Path == ScriptPath && r.Method == http.MethodPut:
+		var script Script
+		if err := json.NewDecoder(r.Body).Decode(&script); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		if err := m.SetScript(script); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, script)
+	case strings.EqualFold(r.URL.Path, AuthorizationPath) && r.Method == http.MethodPost:
+		m.receiveBatch(w, r)
+	case strings.EqualFold(r.URL.Path, RetAuthorizationPath) && r.Method == http.MethodPost:
+		m.queryReceipt(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// receiveBatch trata o envio do lote (nfeAutorizacaoLote)
+func (m *MockServer) receiveBatch(w http.ResponseWriter, r *http.Request) {
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxResponseSize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	message, err := unwrapRaw(data)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	now := time.Now()
+	ret := RetEnviNFe{Versao: messageVersion, VerAplic: mockVersion, DhRecbto: now.Format(time.RFC3339)}
+
+	var batch EnviNFe
+	documents, err := elements(message, "NFe")
+	if err == nil {
+		err = xml.Unmarshal(message, &batch)
+	}
+	if err != nil || batch.IDLote == "" || len(documents) == 0 || len(documents) > 50 {
+		ret.CStat, ret.XMotivo = 225, "Rejeição: Falha no Schema XML do lote de NFe"
+		m.respond(w, authorizationNamespace, ret)
+		return
+	}
+
+	// O resultado timeout mantém a conexão aberta sem resposta
+	step, hang := m.nextStep()
+	if step.Outcome == OutcomeTimeout {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(hang):
+			http.Error(w, "tempo de processamento esgotado", http.StatusGatewayTimeout)
+		}
+		return
+	}
+
+	protocols := make([]ProtNFe, 0, len(documents))
+	for _, document := range documents {
+		protocol, ok := m.process(document, step, now)
+		if !ok {
+			// Documento fora do schema rejeita o lote inteiro, no próprio envio
+			ret.CStat, ret.XMotivo = 225, "Rejeição: Falha no Schema XML da NFe"
+			m.respond(w, authorizationNamespace, ret)
+			return
+		}
+		if ret.CUF == "" {
+			ret.CUF = protocol.InfProt.ChNFe[:2]
+		}
+		protocols = append(protocols, protocol)
+	}
+
+	m.mu.Lock()
+	m.sequence++
+	receipt := fmt.Sprintf("%s%013d", ret.CUF, m.sequence)
+	m.batches[receipt] = &mockBatch{receivedAt: now, protocols: protocols}
+	m.mu.Unlock()
+
+	ret.TpAmb = protocols[0].InfProt.TpAmb
+	ret.CStat, ret.XMotivo = domain.SEFAZBatchReceived, "Lote recebido com sucesso"
+	ret.InfRec = &InfRec{NRec: receipt, TMed: 1}
+	m.respond(w, authorizationNamespace, ret)
+}
+
+// process valida uma nota do lote e monta seu protocolo conforme o roteiro.
+// Retorna false quando a nota não atende ao schema (assinatura inclusive)
+func (m *MockServer) process(document []byte, step Step, now time.Time) (ProtNFe, bool) {
+	var parsed nfe.NFe
+	if err := xml.Unmarshal(document, &parsed); err != nil || nfe.Validate(document) != nil {
+		return ProtNFe{}, false
+	}
+	key := parsed.AccessKey()
+	tpAmb := 0
+	fmt.Sscan(parsed.InfNFe.Ide.TpAmb, &tpAmb)
+
+	info := InfProt{
+		TpAmb:    tpAmb,
+		VerAplic: mockVersion,
+		ChNFe:    key,
+		DhRecbto: now.Format(time.RFC3339),
+	}
+
+	_, err := nfe.Verify(document)
+	switch {
+	case errors.Is(err, nfe.ErrSignatureMissing):
+		return ProtNFe{}, false
+	case err != nil:
+		info.CStat, info.XMotivo = 297, "Rejeição: Assinatura difere do calculado"
+		return ProtNFe{Versao: messageVersion, InfProt: info}, true
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if protocol, exists := m.authorized[key]; exists {
+		info.CStat, info.XMotivo = 204, "Rejeição: Duplicidade de NF-e [nProt:"+protocol+"]"
+		return ProtNFe{Versao: messageVersion, InfProt: info}, true
+	}
+
+	switch step.Outcome {
+	case OutcomeAuthorized:
+		m.sequence++
+		info.NProt = fmt.Sprintf("1%s%s%010d", key[:2], now.Format("06"), m.sequence)
+		info.DigVal = digestValue(document)
+		info.CStat, info.XMotivo = domain.SEFAZAuthorized, "Autorizado o uso da NF-e"
+		m.authorized[key] = info.NProt
+	case OutcomeDenied:
+		m.sequence++
+		info.NProt = fmt.Sprintf("1%s%s%010d", key[:2], now.Format("06"), m.sequence)
+		info.DigVal = digestValue(document)
+		info.CStat, info.XMotivo = orDefault(step, 302, "Uso Denegado: Irregularidade fiscal do destinatário")
+		m.authorized[key] = info.NProt
+	default:
+		info.CStat, info.XMotivo = orDefault(step, 778, "Rejeição: Informado NCM inexistente")
+	}
+	return ProtNFe{Versao: messageVersion, InfProt: info}, true
+}
+
+// queryReceipt trata a consulta do recibo (nfeRetAutorizacaoLote)
+func (m *MockServer) queryReceipt(w http.ResponseWriter, r *http.Request) {
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxResponseSize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var query ConsReciNFe
+	if err := unwrap(data, &query); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	now := time.Now()
+	ret := RetConsReciNFe{
+		Versao:   messageVersion,
+		TpAmb:    query.TpAmb,
+		VerAplic: mockVersion,
+		NRec:     query.NRec,
+		DhRecbto: now.Format(time.RFC3339),
+	}
+	if len(query.NRec) >= 2 {
+		ret.CUF = query.NRec[:2]
+	}
+
+	m.mu.Lock()
+	batch, exists := m.batches[query.NRec]
+	switch {
+	case !exists:
+		ret.CStat, ret.XMotivo = 106, "Lote não localizado"
+	case batch.polls < m.script.ProcessingPolls:
+		batch.polls++
+		ret.CStat, ret.XMotivo = domain.SEFAZBatchProcessing, "Lote em processamento"
+	default:
+		ret.CStat, ret.XMotivo = domain.SEFAZBatchProcessed, "Lote processado"
+		ret.DhRecbto = batch.receivedAt.Format(time.RFC3339)
+		ret.ProtNFe = batch.protocols
+	}
+	m.mu.Unlock()
+
+	m.respond(w, retAuthorizationNamespace, ret)
+}
+
+// nextStep consome o próximo passo do roteiro
+func (m *MockServer) nextStep() (Step, time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	hang := time.Duration(m.script.HangSeconds) * time.Second
+	if len(m.script.Steps) == 0 {
+		return m.script.Default, hang
+	}
+	step := m.script.Steps[0]
+	m.script.Steps = m.script.Steps[1:]
+	return step, hang
+}
+
+func (m *MockServer) respond(w http.ResponseWriter, serviceNamespace string, message interface{}) {
+	payload, err := wrapResult(serviceNamespace, message)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/soap+xml; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(payload)
+}
+
+// digestValue retorna o DigestValue da assinatura, informado no protocolo
+func digestValue(document []byte) string {
+	values, err := elements(document, "DigestValue")
+	if err != nil || len(values) == 0 {
+		return ""
+	}
+	var value string
+	xml.Unmarshal(values[0], &value)
+	return strings.TrimSpace(value)
+}
+
+func orDefault(step Step, code int, reason string) (int, string) {
+	if step.StatusCode != 0 {
+		code = step.StatusCode
+	}
+	if step.Reason != "" {
+		reason = step.Reason
+	}
+	return code, reason
+}
+
+func writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
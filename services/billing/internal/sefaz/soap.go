@@ -0,0 +1,188 @@
+package sefaz
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+// Namespaces dos web services de autorização (NT 2016.002, leiaute 4.00)
+const (
+	soapNamespace             = "http://www.w3.org/2003/05/soap-envelope"
+	nfeNamespace              = "http://www.portalfiscal.inf.br/nfe"
+	authorizationNamespace    = "http://www.portalfiscal.inf.br/nfe/wsdl/NFeAutorizacao4"
+	retAuthorizationNamespace = "http://www.portalfiscal.inf.br/nfe/wsdl/NFeRetAutorizacao4"
+	authorizationAction       = authorizationNamespace + "/nfeAutorizacaoLote"
+	retAuthorizationAction    = retAuthorizationNamespace + "/nfeRetAutorizacaoLote"
+	messageVersion            = "4.00"
+)
+
+// envelope é o envelope SOAP 1.2 com a mensagem no corpo
+type envelope struct {
+	XMLName xml.Name `xml:"http://www.w3.org/2003/05/soap-envelope Envelope"`
+	Body    body     `xml:"http://www.w3.org/2003/05/soap-envelope Body"`
+}
+
+type body struct {
+	Content []byte `xml:",innerxml"`
+}
+
+// EnviNFe é o lote de envio de NF-e (assíncrono, indSinc 0)
+type EnviNFe struct {
+	XMLName xml.Name `xml:"http://www.portalfiscal.inf.br/nfe enviNFe"`
+	Versao  string   `xml:"versao,attr"`
+	IDLote  string   `xml:"idLote"`
+	IndSinc int      `xml:"indSinc"`
+	// Documents contém os elementos NFe assinados, gravados sem
+	// reserialização para não invalidar as assinaturas
+	Documents []byte `xml:",innerxml"`
+}
+
+// RetEnviNFe é o retorno do envio do lote
+type RetEnviNFe struct {
+	XMLName  xml.Name `xml:"http://www.portalfiscal.inf.br/nfe retEnviNFe"`
+	Versao   string   `xml:"versao,attr"`
+	TpAmb    int      `xml:"tpAmb"`
+	VerAplic string   `xml:"verAplic"`
+	CStat    int      `xml:"cStat"`
+	XMotivo  string   `xml:"xMotivo"`
+	CUF      string   `xml:"cUF"`
+	DhRecbto string   `xml:"dhRecbto"`
+	InfRec   *InfRec  `xml:"infRec,omitempty"`
+}
+
+// InfRec identifica o recibo do lote
+type InfRec struct {
+	NRec string `xml:"nRec"`
+	TMed int    `xml:"tMed"` // Tempo médio de resposta, em segundos
+}
+
+// ConsReciNFe é a consulta do recibo do lote
+type ConsReciNFe struct {
+	XMLName xml.Name `xml:"http://www.portalfiscal.inf.br/nfe consReciNFe"`
+	Versao  string   `xml:"versao,attr"`
+	TpAmb   int      `xml:"tpAmb"`
+	NRec    string   `xml:"nRec"`
+}
+
+// RetConsReciNFe é o retorno da consulta do recibo
+type RetConsReciNFe struct {
+	XMLName  xml.Name  `xml:"http://www.portalfiscal.inf.br/nfe retConsReciNFe"`
+	Versao   string    `xml:"versao,attr"`
+	TpAmb    int       `xml:"tpAmb"`
+	VerAplic string    `xml:"verAplic"`
+	NRec     string    `xml:"nRec"`
+	CStat    int       `xml:"cStat"`
+	XMotivo  string    `xml:"xMotivo"`
+	CUF      string    `xml:"cUF"`
+	DhRecbto string    `xml:"dhRecbto"`
+	ProtNFe  []ProtNFe `xml:"protNFe"`
+}
+
+// ProtNFe é o protocolo de autorização (ou rejeição) de uma nota do lote
+type ProtNFe struct {
+	Versao  string  `xml:"versao,attr"`
+	InfProt InfProt `xml:"infProt"`
+}
+
+// InfProt contém o resultado do processamento da nota
+type InfProt struct {
+	TpAmb    int    `xml:"tpAmb"`
+	VerAplic string `xml:"verAplic"`
+	ChNFe    string `xml:"chNFe"`
+	DhRecbto string `xml:"dhRecbto"`
+	NProt    string `xml:"nProt,omitempty"`
+	DigVal   string `xml:"digVal,omitempty"`
+	CStat    int    `xml:"cStat"`
+	XMotivo  string `xml:"xMotivo"`
+}
+
+// wrap monta o envelope SOAP com a mensagem dentro de nfeDadosMsg
+func wrap(serviceNamespace string, message interface{}) ([]byte, error) {
+	content, err := xml.Marshal(message)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	fmt.Fprintf(&buf, `<soap12:Envelope xmlns:soap12="%s"><soap12:Body><nfeDadosMsg xmlns="%s">`, soapNamespace, serviceNamespace)
+	buf.Write(content)
+	buf.WriteString(`</nfeDadosMsg></soap12:Body></soap12:Envelope>`)
+	return buf.Bytes(), nil
+}
+
+// wrapResult monta o envelope SOAP de resposta com a mensagem dentro de nfeResultMsg
+func wrapResult(serviceNamespace string, message interface{}) ([]byte, error) {
+	content, err := xml.Marshal(message)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	fmt.Fprintf(&buf, `<soap12:Envelope xmlns:soap12="%s"><soap12:Body><nfeResultMsg xmlns="%s">`, soapNamespace, serviceNamespace)
+	buf.Write(content)
+	buf.WriteString(`</nfeResultMsg></soap12:Body></soap12:Envelope>`)
+	return buf.Bytes(), nil
+}
+
+// unwrap extrai do corpo SOAP a mensagem contida em nfeDadosMsg ou nfeResultMsg
+func unwrap(data []byte, message interface{}) error {
+	content, err := unwrapRaw(data)
+	if err != nil {
+		return err
+	}
+	if err := xml.Unmarshal(content, message); err != nil {
+		return fmt.Errorf("mensagem inválida: %w", err)
+	}
+	return nil
+}
+
+// unwrapRaw retorna os bytes da mensagem contida no corpo SOAP
+func unwrapRaw(data []byte) ([]byte, error) {
+	var env envelope
+	if err := xml.Unmarshal(data, &env); err != nil {
+		return nil, fmt.Errorf("envelope SOAP inválido: %w", err)
+	}
+	var container struct {
+		Content []byte `xml:",innerxml"`
+	}
+	if err := xml.Unmarshal(env.Body.Content, &container); err != nil {
+		return nil, fmt.Errorf("corpo SOAP inválido: %w", err)
+	}
+	return container.Content, nil
+}
+
+// elements retorna os bytes originais de cada elemento com o nome informado
+func elements(data []byte, local string) ([][]byte, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	var found [][]byte
+	for {
+		start := decoder.InputOffset()
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return found, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if el, ok := token.(xml.StartElement); ok && el.Name.Local == local {
+			if err := decoder.Skip(); err != nil {
+				return nil, err
+			}
+			found = append(found, data[start:decoder.InputOffset()])
+		}
+	}
+}
+
+// stripHeader remove a declaração XML para que o documento possa ser
+// incluído no lote
+func stripHeader(document []byte) []byte {
+	document = bytes.TrimSpace(document)
+	if bytes.HasPrefix(document, []byte("<?xml")) {
+		if end := bytes.Index(document, []byte("?>")); end >= 0 {
+			document = bytes.TrimSpace(document[end+2:])
+		}
+	}
+	return document
+}
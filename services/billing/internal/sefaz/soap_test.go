@@ -0,0 +1,125 @@
+package sefaz
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWrapBatch(t *testing.T) {
+	// A nota entra no lote sem reserialização: espaços e a ordem dos
+	// atributos precisam chegar intactos para o digest da assinatura conferir
+	document := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<NFe xmlns="http://www.portalfiscal.inf.br/nfe"><infNFe versao="4.00" Id="NFe35"><ide> <cUF>35</cUF> </ide></infNFe></NFe>`)
+	batch := EnviNFe{Versao: messageVersion, IDLote: "123456789012345", IndSinc: 0, Documents: stripHeader(document)}
+
+	payload, err := wrap(authorizationNamespace, batch)
+	if err != nil {
+		t.Fatalf("wrap() erro inesperado: %v", err)
+	}
+	expected := `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+		`<soap12:Envelope xmlns:soap12="http://www.w3.org/2003/05/soap-envelope"><soap12:Body>` +
+		`<nfeDadosMsg xmlns="http://www.portalfiscal.inf.br/nfe/wsdl/NFeAutorizacao4">` +
+		`<enviNFe xmlns="http://www.portalfiscal.inf.br/nfe" versao="4.00"><idLote>123456789012345</idLote><indSinc>0</indSinc>` +
+		`<NFe xmlns="http://www.portalfiscal.inf.br/nfe"><infNFe versao="4.00" Id="NFe35"><ide> <cUF>35</cUF> </ide></infNFe></NFe>` +
+		`</enviNFe></nfeDadosMsg></soap12:Body></soap12:Envelope>`
+	if string(payload) != expected {
+		t.Fatalf("wrap() =\n%s\nesperado\n%s", payload, expected)
+	}
+
+	// O autorizador extrai a nota com os mesmos bytes enviados
+	message, err := unwrapRaw(payload)
+	if err != nil {
+		t.Fatalf("unwrapRaw() erro inesperado: %v", err)
+	}
+	documents, err := elements(message, "NFe")
+	if err != nil || len(documents) != 1 {
+		t.Fatalf("elements() = %d notas, erro %v; esperada 1 nota", len(documents), err)
+	}
+	if !bytes.Equal(documents[0], stripHeader(document)) {
+		t.Errorf("nota extraída do lote difere da enviada:\n%s", documents[0])
+	}
+}
+
+// Retornos no formato devolvido pelos autorizadores, com prefixo e
+// declarações de namespace diferentes dos usados no envio
+const (
+	retEnviResponse = `<?xml version="1.0" encoding="utf-8"?><soap:Envelope xmlns:soap="http://www.w3.org/2003/05/soap-envelope" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xmlns:xsd="http://www.w3.org/2001/XMLSchema"><soap:Body><nfeResultMsg xmlns="http://www.portalfiscal.inf.br/nfe/wsdl/NFeAutorizacao4"><retEnviNFe versao="4.00" xmlns="http://www.portalfiscal.inf.br/nfe"><tpAmb>2</tpAmb><verAplic>SP_NFE_PL009_V4</verAplic><cStat>103</cStat><xMotivo>Lote recebido com sucesso</xMotivo><cUF>35</cUF><dhRecbto>2025-03-10T10:15:30-03:00</dhRecbto><infRec><nRec>351000012345678</nRec><tMed>1</tMed></infRec></retEnviNFe></nfeResultMsg></soap:Body></soap:Envelope>`
+
+	retConsReciResponse = `<?xml version="1.0" encoding="utf-8"?><soap:Envelope xmlns:soap="http://www.w3.org/2003/05/soap-envelope"><soap:Body><nfeResultMsg xmlns="http://www.portalfiscal.inf.br/nfe/wsdl/NFeRetAutorizacao4"><retConsReciNFe versao="4.00" xmlns="http://www.portalfiscal.inf.br/nfe"><tpAmb>2</tpAmb><verAplic>SP_NFE_PL009_V4</verAplic><nRec>351000012345678</nRec><cStat>104</cStat><xMotivo>Lote processado</xMotivo><cUF>35</cUF><dhRecbto>2025-03-10T10:15:31-03:00</dhRecbto><protNFe versao="4.00"><infProt><tpAmb>2</tpAmb><verAplic>SP_NFE_PL009_V4</verAplic><chNFe>35250311222333000181550010000000011000000010</chNFe><dhRecbto>2025-03-10T10:15:31-03:00</dhRecbto><nProt>135250000012345</nProt><digVal>W2n2Jx0pNc3Ku0Rz3jTXx0p7Ub8=</digVal><cStat>100</cStat><xMotivo>Autorizado o uso da NF-e</xMotivo></infProt></protNFe></retConsReciNFe></nfeResultMsg></soap:Body></soap:Envelope>`
+)
+
+func TestUnwrapResponse(t *testing.T) {
+	var ret RetEnviNFe
+	if err := unwrap([]byte(retEnviResponse), &ret); err != nil {
+		t.Fatalf("unwrap(retEnviNFe) erro inesperado: %v", err)
+	}
+	if ret.CStat != 103 || ret.CUF != "35" || ret.InfRec == nil || ret.InfRec.NRec != "351000012345678" || ret.InfRec.TMed != 1 {
+		t.Errorf("unwrap(retEnviNFe) = %+v", ret)
+	}
+
+	var query RetConsReciNFe
+	if err := unwrap([]byte(retConsReciResponse), &query); err != nil {
+		t.Fatalf("unwrap(retConsReciNFe) erro inesperado: %v", err)
+	}
+	if query.CStat != 104 || query.NRec != "351000012345678" || len(query.ProtNFe) != 1 {
+		t.Fatalf("unwrap(retConsReciNFe) = %+v", query)
+	}
+	protocol := query.ProtNFe[0].InfProt
+	if protocol.CStat != 100 || protocol.NProt != "135250000012345" || protocol.ChNFe != "35250311222333000181550010000000011000000010" {
+		t.Errorf("protocolo = %+v", protocol)
+	}
+}
+
+func TestUnwrapInvalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		message  string
+	}{
+		{
+			name:     "SOAP 1.1",
+			response: `<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body><nfeResultMsg/></soap:Body></soap:Envelope>`,
+			message:  "envelope SOAP inválido",
+		},
+		{
+			name:     "página de erro",
+			response: `<html><body>Service Unavailable</body></html>`,
+			message:  "envelope SOAP inválido",
+		},
+		{
+			name:     "mensagem de outro serviço",
+			response: strings.Replace(retEnviResponse, "retEnviNFe", "retConsStatServ", 2),
+			message:  "mensagem inválida",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ret RetEnviNFe
+			err := unwrap([]byte(tt.response), &ret)
+			if err == nil || !strings.Contains(err.Error(), tt.message) {
+				t.Errorf("unwrap() erro = %v, esperado %q", err, tt.message)
+			}
+		})
+	}
+}
+
+func TestStripHeader(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		expected string
+	}{
+		{name: "com declaração", document: `<?xml version="1.0" encoding="UTF-8"?>` + "\n<NFe/>", expected: "<NFe/>"},
+		{name: "sem declaração", document: "  <NFe/>\n", expected: "<NFe/>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(stripHeader([]byte(tt.document))); got != tt.expected {
+				t.Errorf("stripHeader() = %q, esperado %q", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+	"github.com/go-chi/chi/v5"
+)
+
+// AccessKeyResponse representa o resultado da validação de uma chave de acesso
+type AccessKeyResponse struct {
+	AccessKey string           `json:"access_key"`
+	Valid     bool             `json:"valid"`
+	Fields    domain.AccessKey `json:"fields"`
+}
+
+// GetInvoiceByAccessKey busca uma nota fiscal pela chave de acesso
+func (h *Handler) GetInvoiceByAccessKey(w http.ResponseWriter, r *http.Request) {
+	key := chi.URLParam(r, "key")
+
+	invoice, err := h.invoiceService.GetInvoiceByAccessKey(key)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidAccessKey), errors.Is(err, domain.ErrAccessKeyCheckDigit):
+			respondError(w, http.StatusBadRequest, "Chave de acesso inválida", err.Error())
+		case errors.Is(err, domain.ErrInvoiceNotFound):
+			respondError(w, http.StatusNotFound, "Nota fiscal não encontrada", err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, "Erro ao buscar nota fiscal", err.Error())
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, invoice)
+}
+
+// ValidateAccessKey valida uma chave de acesso recebida externamente
+// (formato, UF, mês, CNPJ do emitente, modelo e dígito verificador)
+func (h *Handler) ValidateAccessKey(w http.ResponseWriter, r *http.Request) {
+	key := chi.URLParam(r, "key")
+
+	parsed, err := h.invoiceService.ValidateAccessKey(key)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Chave de acesso inválida", err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, AccessKeyResponse{
+		AccessKey: parsed.String(),
+		Valid:     true,
+		Fields:    parsed,
+	})
+}
@@ -0,0 +1,61 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+	"github.com/go-chi/chi/v5"
+)
+
+// ApprovalRequest é a decisão sobre uma nota retida para aprovação
+type ApprovalRequest struct {
+	Comment string `json:"comment"`
+}
+
+// ApproveInvoice aprova uma nota retida, liberando-a para impressão
+func (h *Handler) ApproveInvoice(w http.ResponseWriter, r *http.Request) {
+	h.decideApproval(w, r, domain.DecisionApproved)
+}
+
+// RejectInvoice reprova uma nota retida
+func (h *Handler) RejectInvoice(w http.ResponseWriter, r *http.Request) {
+	h.decideApproval(w, r, domain.DecisionDisapproved)
+}
+
+// decideApproval registra a decisão do usuário autenticado sobre a nota
+func (h *Handler) decideApproval(w http.ResponseWriter, r *http.Request, decision domain.ApprovalDecision) {
+	id := chi.URLParam(r, "id")
+
+	var req ApprovalRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Payload inválido", err.Error())
+		return
+	}
+
+	decide := h.invoiceService.ApproveInvoice
+	if decision == domain.DecisionDisapproved {
+		decide = h.invoiceService.RejectInvoice
+	}
+	invoice, err := decide(id, requestUser(r), req.Comment)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvoiceNotFound):
+			respondError(w, http.StatusNotFound, "Nota fiscal não encontrada", err.Error())
+		case errors.Is(err, domain.ErrInvoiceNotAwaiting):
+			respondError(w, http.StatusConflict, "Nota fiscal não aguarda aprovação", err.Error())
+		case errors.Is(err, domain.ErrApprovalUserRequired):
+			respondError(w, http.StatusUnauthorized, "Identidade do usuário não confirmada", err.Error())
+		case errors.Is(err, domain.ErrApprovalComment):
+			respondError(w, http.StatusBadRequest, "Decisão de aprovação inválida", err.Error())
+		case errors.Is(err, domain.ErrSelfApproval), errors.Is(err, domain.ErrApprovalUnauthorized):
+			respondError(w, http.StatusForbidden, "Aprovação não autorizada", err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, "Erro ao registrar aprovação", err.Error())
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, invoice)
+}
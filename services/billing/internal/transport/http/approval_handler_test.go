@@ -0,0 +1,84 @@
+package http
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+)
+
+func TestApproveInvoiceFourEyes(t *testing.T) {
+	router := newTestRouter(t, domain.ApprovalPolicy{TotalThreshold: 50000, Approvers: []string{"ana", "bruno"}}, map[string]int{"P1": 100})
+
+	// R$ 625,00 acima do limite de R$ 500,00: a nota criada por "ana" fica retida
+	rec := doRequest(t, router, http.MethodPost, "/api/invoices", "ana", "", CreateInvoiceRequest{
+		IssuerID:   "issuer-1",
+		CustomerID: "customer-1",
+		Items:      []InvoiceItemRequest{{ProductID: "P1", Quantity: 50, UnitPrice: 1250}},
+	})
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("criação status = %d, esperado %d (%s)", rec.Code, http.StatusCreated, rec.Body)
+	}
+	var invoice domain.Invoice
+	decodeBody(t, rec, &invoice)
+	if invoice.Status != domain.StatusAwaitingApproval {
+		t.Fatalf("criação status da nota = %s, esperado %s", invoice.Status, domain.StatusAwaitingApproval)
+	}
+	path := "/api/invoices/" + invoice.ID + "/approve"
+	decision := ApprovalRequest{Comment: "Pedido conferido com o cliente"}
+
+	tests := []struct {
+		name      string
+		user      string
+		signature string
+		status    int
+	}{
+		{name: "criadora aprovando a própria nota", user: "ana", status: http.StatusForbidden},
+		{name: "assinatura da criadora com o usuário trocado", user: "bruno", signature: SignUser(testSecret, "ana", time.Now()), status: http.StatusUnauthorized},
+		{name: "assinatura forjada", user: "bruno", signature: "0.abc", status: http.StatusUnauthorized},
+		{name: "sem usuário identificado", status: http.StatusUnauthorized},
+		{name: "usuário fora dos aprovadores", user: "carla", status: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := doRequest(t, router, http.MethodPost, path, tt.user, tt.signature, decision); rec.Code != tt.status {
+				t.Errorf("aprovação status = %d, esperado %d (%s)", rec.Code, tt.status, rec.Body)
+			}
+		})
+	}
+
+	// As tentativas recusadas não decidem a nota: outro usuário ainda aprova
+	rec = doRequest(t, router, http.MethodPost, path, "bruno", "", decision)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("aprovação por outro usuário status = %d, esperado %d (%s)", rec.Code, http.StatusOK, rec.Body)
+	}
+	decodeBody(t, rec, &invoice)
+	if invoice.Status != domain.StatusOpen {
+		t.Errorf("status da nota aprovada = %s, esperado %s", invoice.Status, domain.StatusOpen)
+	}
+}
+
+func TestApproveInvoiceWithoutApprovers(t *testing.T) {
+	router := newTestRouter(t, domain.ApprovalPolicy{TotalThreshold: 50000}, map[string]int{"P1": 100})
+
+	rec := doRequest(t, router, http.MethodPost, "/api/invoices", "ana", "", CreateInvoiceRequest{
+		IssuerID:   "issuer-1",
+		CustomerID: "customer-1",
+		Items:      []InvoiceItemRequest{{ProductID: "P1", Quantity: 50, UnitPrice: 1250}},
+	})
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("criação status = %d, esperado %d (%s)", rec.Code, http.StatusCreated, rec.Body)
+	}
+	var invoice domain.Invoice
+	decodeBody(t, rec, &invoice)
+
+	// Sem APPROVAL_APPROVERS ninguém decide: a nota continua retida
+	decision := ApprovalRequest{Comment: "Pedido conferido com o cliente"}
+	for _, action := range []string{"approve", "reject"} {
+		rec = doRequest(t, router, http.MethodPost, "/api/invoices/"+invoice.ID+"/"+action, "bruno", "", decision)
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("%s status = %d, esperado %d (%s)", action, rec.Code, http.StatusForbidden, rec.Body)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/nfe"
+	"github.com/go-chi/chi/v5"
+)
+
+// AuthorizeInvoice reenvia à SEFAZ uma nota fechada pendente ou rejeitada
+func (h *Handler) AuthorizeInvoice(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	invoice, err := h.invoiceService.AuthorizeInvoice(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvoiceNotFound):
+			respondError(w, http.StatusNotFound, "Nota fiscal não encontrada", err.Error())
+		case errors.Is(err, domain.ErrInvoiceNotClosed):
+			respondError(w, http.StatusConflict, "Nota fiscal precisa estar fechada", err.Error())
+		case errors.Is(err, domain.ErrInvoiceAlreadyAuthorized), errors.Is(err, domain.ErrInvoiceDenied), errors.Is(err, domain.ErrServiceInvoice):
+			respondError(w, http.StatusConflict, "Nota fiscal não pode ser reenviada", err.Error())
+		case errors.Is(err, domain.ErrSEFAZNotConfigured):
+			respondError(w, http.StatusNotImplemented, "Autorização na SEFAZ não configurada", err.Error())
+		case errors.Is(err, domain.ErrSEFAZUnavailable), errors.Is(err, nfe.ErrCertificateExpired):
+			respondError(w, http.StatusServiceUnavailable, "Falha na comunicação com a SEFAZ", err.Error())
+		default:
+			respondError(w, http.StatusBadGateway, "Erro ao solicitar autorização", err.Error())
+		}
+		return
+	}
+
+	success, message := authorizationMessage(invoice)
+	respondJSON(w, http.StatusOK, PrintResponse{
+		Success: success,
+		Message: message,
+		Invoice: invoice,
+	})
+}
+
+// authorizationMessage descreve o resultado da impressão conforme o retorno da SEFAZ
+func authorizationMessage(invoice *domain.Invoice) (bool, string) {
+	auth := invoice.Authorization
+	if invoice.IsService() {
+		return true, "Nota de serviço emitida; o XML da DPS está disponível para envio à NFS-e"
+	}
+	if auth == nil {
+		return true, "Nota fiscal impressa com sucesso"
+	}
+	switch auth.Status {
+	case domain.AuthorizationAuthorized:
+		return true, fmt.Sprintf("Nota fiscal autorizada (protocolo %s)", auth.Protocol)
+	case domain.AuthorizationPending:
+		if invoice.Contingency != nil {
+			return true, "Nota fiscal emitida em contingência; será transmitida à SEFAZ quando a comunicação for restabelecida"
+		}
+		return true, "Nota fiscal impressa; autorização pendente na SEFAZ"
+	default:
+		return false, fmt.Sprintf("SEFAZ: %d - %s", auth.StatusCode, auth.Reason)
+	}
+}
@@ -0,0 +1,125 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/usecase"
+	"github.com/go-chi/chi/v5"
+)
+
+// StockEntryRequest representa a entrada de estoque notificada pelo Stock Service
+type StockEntryRequest struct {
+	ProductID string `json:"product_id"`
+	Quantity  int    `json:"quantity"` // Quantidade que entrou
+	Balance   int    `json:"balance"`  // Saldo após a entrada
+}
+
+// BackorderInvoiceRequest representa a nota gerada para uma pendência
+type BackorderInvoiceRequest struct {
+	Charges        domain.Charges         `json:"charges"`
+	Transport      *TransportRequest      `json:"transport"`
+	CreditOverride *CreditOverrideRequest `json:"credit_override"`
+}
+
+// GetAllBackorders lista as pendências de entrega
+func (h *Handler) GetAllBackorders(w http.ResponseWriter, r *http.Request) {
+	backorders, err := h.backorderService.GetAllBackorders()
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Erro ao listar pendências de entrega", err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, backorders)
+}
+
+// GetBackorder busca uma pendência de entrega por ID
+func (h *Handler) GetBackorder(w http.ResponseWriter, r *http.Request) {
+	backorder, err := h.backorderService.GetBackorder(chi.URLParam(r, "id"))
+	if err != nil {
+		respondBackorderError(w, err, "Erro ao buscar pendência de entrega")
+		return
+	}
+	respondJSON(w, http.StatusOK, backorder)
+}
+
+// RegisterStockEntry recebe a entrada de estoque notificada pelo Stock Service
+// e reavalia as pendências do produto
+func (h *Handler) RegisterStockEntry(w http.ResponseWriter, r *http.Request) {
+	var req StockEntryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Payload inválido", err.Error())
+		return
+	}
+	if req.ProductID == "" {
+		respondError(w, http.StatusBadRequest, "Produto é obrigatório", "informe o product_id da entrada")
+		return
+	}
+
+	backorders, err := h.backorderService.RecheckProduct(req.ProductID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Erro ao reavaliar pendências de entrega", err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, backorders)
+}
+
+// RecheckBackorders reavalia as pendências de todos os produtos
+func (h *Handler) RecheckBackorders(w http.ResponseWriter, r *http.Request) {
+	backorders, err := h.backorderService.RecheckAll()
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Erro ao reavaliar pendências de entrega", err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, backorders)
+}
+
+// CreateBackorderInvoice gera a nota de uma pendência com estoque disponível
+func (h *Handler) CreateBackorderInvoice(w http.ResponseWriter, r *http.Request) {
+	var req BackorderInvoiceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		respondError(w, http.StatusBadRequest, "Payload inválido", err.Error())
+		return
+	}
+
+	invoice, err := h.backorderService.InvoiceBackorder(chi.URLParam(r, "id"), usecase.InvoiceBackorderInput{
+		Charges:        req.Charges,
+		Transport:      req.Transport.toDomain(),
+		CreditOverride: creditOverride(req.CreditOverride, r),
+		User:           requestUser(r),
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrBackorderNotFound) || errors.Is(err, domain.ErrBackorderNotAvailable) {
+			respondBackorderError(w, err, "Erro ao gerar nota da pendência")
+			return
+		}
+		respondCreateInvoiceError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, invoice)
+}
+
+// CancelBackorder cancela a pendência de entrega
+func (h *Handler) CancelBackorder(w http.ResponseWriter, r *http.Request) {
+	backorder, err := h.backorderService.CancelBackorder(chi.URLParam(r, "id"))
+	if err != nil {
+		respondBackorderError(w, err, "Erro ao cancelar pendência de entrega")
+		return
+	}
+	respondJSON(w, http.StatusOK, backorder)
+}
+
+// respondBackorderError converte erros das pendências em respostas HTTP
+func respondBackorderError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, domain.ErrBackorderNotFound):
+		respondError(w, http.StatusNotFound, "Pendência de entrega não encontrada", err.Error())
+	case errors.Is(err, domain.ErrBackorderNotAvailable), errors.Is(err, domain.ErrBackorderClosed):
+		respondError(w, http.StatusConflict, "Situação da pendência não permite a operação", err.Error())
+	default:
+		respondError(w, http.StatusInternalServerError, fallback, err.Error())
+	}
+}
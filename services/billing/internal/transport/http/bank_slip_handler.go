@@ -0,0 +1,36 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// GetBankSlip retorna o boleto de uma parcela em aberto: código de barras,
+// linha digitável e nosso número
+func (h *Handler) GetBankSlip(w http.ResponseWriter, r *http.Request) {
+	slip, err := h.receivableService.GetBankSlip(chi.URLParam(r, "id"), chi.URLParam(r, "number"))
+	if err != nil {
+		respondChargeError(w, err, "Erro ao gerar boleto")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, slip)
+}
+
+// GetBankSlipPDF retorna o boleto de uma parcela em aberto em PDF
+func (h *Handler) GetBankSlipPDF(w http.ResponseWriter, r *http.Request) {
+	id, number := chi.URLParam(r, "id"), chi.URLParam(r, "number")
+
+	data, err := h.receivableService.GenerateBankSlipPDF(id, number)
+	if err != nil {
+		respondChargeError(w, err, "Erro ao gerar boleto")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=%q", "boleto-"+id+"-"+number+".pdf"))
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
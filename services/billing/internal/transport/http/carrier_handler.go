@@ -0,0 +1,116 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+	"github.com/go-chi/chi/v5"
+)
+
+// CarrierRequest representa o payload de criação/atualização de transportadora
+type CarrierRequest struct {
+	Name              string         `json:"name"`
+	Document          string         `json:"document"`
+	StateRegistration string         `json:"state_registration"`
+	RNTC              string         `json:"rntc"`
+	Address           domain.Address `json:"address"`
+}
+
+func (req CarrierRequest) toDomain() domain.Carrier {
+	return domain.Carrier{
+		Name:              req.Name,
+		Document:          req.Document,
+		StateRegistration: req.StateRegistration,
+		RNTC:              req.RNTC,
+		Address:           req.Address,
+	}
+}
+
+// CreateCarrier cadastra uma nova transportadora
+func (h *Handler) CreateCarrier(w http.ResponseWriter, r *http.Request) {
+	var req CarrierRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Payload inválido", err.Error())
+		return
+	}
+
+	carrier, err := h.carrierService.CreateCarrier(req.toDomain())
+	if err != nil {
+		respondCarrierError(w, err, "Erro ao criar transportadora")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, carrier)
+}
+
+// GetCarrier busca uma transportadora por ID
+func (h *Handler) GetCarrier(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	carrier, err := h.carrierService.GetCarrier(id)
+	if err != nil {
+		respondCarrierError(w, err, "Erro ao buscar transportadora")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, carrier)
+}
+
+// GetAllCarriers lista as transportadoras
+func (h *Handler) GetAllCarriers(w http.ResponseWriter, r *http.Request) {
+	carriers, err := h.carrierService.GetAllCarriers()
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Erro ao listar transportadoras", err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, carriers)
+}
+
+// UpdateCarrier atualiza uma transportadora
+func (h *Handler) UpdateCarrier(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	var req CarrierRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Payload inválido", err.Error())
+		return
+	}
+
+	carrier, err := h.carrierService.UpdateCarrier(id, req.toDomain())
+	if err != nil {
+		respondCarrierError(w, err, "Erro ao atualizar transportadora")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, carrier)
+}
+
+// DeleteCarrier remove uma transportadora
+func (h *Handler) DeleteCarrier(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	if err := h.carrierService.DeleteCarrier(id); err != nil {
+		respondCarrierError(w, err, "Erro ao deletar transportadora")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Transportadora deletada com sucesso"})
+}
+
+// respondCarrierError traduz erros de domínio da transportadora em respostas HTTP
+func respondCarrierError(w http.ResponseWriter, err error, fallback string) {
+	switch err {
+	case domain.ErrCarrierNotFound:
+		respondError(w, http.StatusNotFound, "Transportadora não encontrada", err.Error())
+	case domain.ErrDuplicateCarrierDocument:
+		respondError(w, http.StatusConflict, "Documento já cadastrado", err.Error())
+	case domain.ErrInvalidCarrier, domain.ErrInvalidDocument, domain.ErrInvalidCPF, domain.ErrInvalidCNPJ,
+		domain.ErrInvalidStateRegistration, domain.ErrInvalidRNTC, domain.ErrInvalidAddress,
+		domain.ErrInvalidCityCode, domain.ErrInvalidUF, domain.ErrInvalidCEP:
+		respondError(w, http.StatusBadRequest, "Dados da transportadora inválidos", err.Error())
+	default:
+		respondError(w, http.StatusInternalServerError, fallback, err.Error())
+	}
+}
@@ -0,0 +1,136 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+)
+
+// ContingencyRequest representa o payload de entrada em contingência
+type ContingencyRequest struct {
+	EmissionType  domain.EmissionType `json:"emission_type"` // 2 (FS-IA) ou 5 (FS-DA); padrão FS-DA
+	Justification string              `json:"justification"`
+}
+
+// ContingencyResponse representa a situação da emissão e a fila de transmissão
+type ContingencyResponse struct {
+	Active      bool                         `json:"active"`
+	Contingency *domain.Contingency          `json:"contingency,omitempty"`
+	Queue       []*domain.QueuedTransmission `json:"queue"`
+}
+
+// TransmitResponse representa o resultado de uma rodada de transmissão da fila
+type TransmitResponse struct {
+	Transmitted int                          `json:"transmitted"`
+	Message     string                       `json:"message"`
+	Queue       []*domain.QueuedTransmission `json:"queue"`
+}
+
+// GetContingency retorna a contingência vigente e as notas aguardando transmissão
+func (h *Handler) GetContingency(w http.ResponseWriter, r *http.Request) {
+	response, err := h.contingencyResponse()
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Erro ao consultar contingência", err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, response)
+}
+
+// EnterContingency passa a emitir as notas em contingência off-line
+func (h *Handler) EnterContingency(w http.ResponseWriter, r *http.Request) {
+	var req ContingencyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Payload inválido", err.Error())
+		return
+	}
+	if req.EmissionType == 0 {
+		req.EmissionType = domain.EmissionFSDA
+	}
+
+	if _, err := h.invoiceService.EnterContingency(req.EmissionType, req.Justification); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrContingencyEmissionType), errors.Is(err, domain.ErrContingencyJustification):
+			respondError(w, http.StatusBadRequest, "Contingência inválida", err.Error())
+		case errors.Is(err, domain.ErrSEFAZNotConfigured):
+			respondError(w, http.StatusNotImplemented, "Autorização na SEFAZ não configurada", err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, "Erro ao entrar em contingência", err.Error())
+		}
+		return
+	}
+
+	response, err := h.contingencyResponse()
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Erro ao consultar contingência", err.Error())
+		return
+	}
+	respondJSON(w, http.StatusCreated, response)
+}
+
+// LeaveContingency retorna à emissão normal
+func (h *Handler) LeaveContingency(w http.ResponseWriter, r *http.Request) {
+	if err := h.invoiceService.LeaveContingency(); err != nil {
+		if errors.Is(err, domain.ErrContingencyInactive) {
+			respondError(w, http.StatusConflict, "Contingência não está ativa", err.Error())
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "Erro ao sair da contingência", err.Error())
+		return
+	}
+
+	response, err := h.contingencyResponse()
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Erro ao consultar contingência", err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, response)
+}
+
+// TransmitQueue transmite imediatamente as notas da fila de contingência
+func (h *Handler) TransmitQueue(w http.ResponseWriter, r *http.Request) {
+	transmitted, err := h.invoiceService.TransmitQueue()
+	if err != nil && !errors.Is(err, domain.ErrSEFAZUnavailable) {
+		if errors.Is(err, domain.ErrSEFAZNotConfigured) {
+			respondError(w, http.StatusNotImplemented, "Autorização na SEFAZ não configurada", err.Error())
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "Erro ao transmitir fila", err.Error())
+		return
+	}
+
+	queue, queueErr := h.invoiceService.GetTransmissionQueue()
+	if queueErr != nil {
+		respondError(w, http.StatusInternalServerError, "Erro ao consultar fila", queueErr.Error())
+		return
+	}
+
+	// SEFAZ ainda indisponível: as notas restantes permanecem na fila
+	status, message := http.StatusOK, fmt.Sprintf("%d nota(s) transmitida(s)", transmitted)
+	if err != nil {
+		status, message = http.StatusServiceUnavailable, fmt.Sprintf("%s; %v", message, err)
+	}
+	respondJSON(w, status, TransmitResponse{
+		Transmitted: transmitted,
+		Message:     message,
+		Queue:       queue,
+	})
+}
+
+func (h *Handler) contingencyResponse() (ContingencyResponse, error) {
+	contingency, err := h.invoiceService.GetContingency()
+	if err != nil {
+		return ContingencyResponse{}, err
+	}
+	queue, err := h.invoiceService.GetTransmissionQueue()
+	if err != nil {
+		return ContingencyResponse{}, err
+	}
+	return ContingencyResponse{
+		Active:      contingency != nil,
+		Contingency: contingency,
+		Queue:       queue,
+	}, nil
+}
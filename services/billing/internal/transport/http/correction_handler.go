@@ -0,0 +1,91 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/nfe"
+	"github.com/go-chi/chi/v5"
+)
+
+// CorrectionRequest representa o payload de uma carta de correção
+type CorrectionRequest struct {
+	Text string `json:"text"`
+}
+
+// CreateCorrection registra uma carta de correção para a nota autorizada
+func (h *Handler) CreateCorrection(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	var req CorrectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Payload inválido", err.Error())
+		return
+	}
+
+	letter, err := h.correctionService.CreateCorrection(id, req.Text)
+	if err != nil {
+		respondCorrectionError(w, err, "Erro ao registrar carta de correção")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, letter)
+}
+
+// GetCorrections lista as cartas de correção da nota
+func (h *Handler) GetCorrections(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	letters, err := h.correctionService.GetCorrections(id)
+	if err != nil {
+		respondCorrectionError(w, err, "Erro ao buscar cartas de correção")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, letters)
+}
+
+// GetCorrectionXML retorna o XML assinado do evento de carta de correção
+func (h *Handler) GetCorrectionXML(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	sequence, err := strconv.Atoi(chi.URLParam(r, "sequence"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Sequência inválida", err.Error())
+		return
+	}
+
+	data, err := h.correctionService.GetCorrectionXML(id, sequence)
+	if err != nil {
+		respondCorrectionError(w, err, "Erro ao buscar carta de correção")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fmt.Sprintf("cce-%s-%02d.xml", id, sequence)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+// respondCorrectionError converte erros de carta de correção em respostas HTTP
+func respondCorrectionError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, domain.ErrInvoiceNotFound):
+		respondError(w, http.StatusNotFound, "Nota fiscal não encontrada", err.Error())
+	case errors.Is(err, domain.ErrCorrectionNotFound):
+		respondError(w, http.StatusNotFound, "Carta de correção não encontrada", err.Error())
+	case errors.Is(err, domain.ErrCorrectionTextLength), errors.Is(err, domain.ErrCorrectionForbiddenField):
+		respondError(w, http.StatusBadRequest, "Texto da correção inválido", err.Error())
+	case errors.Is(err, domain.ErrInvoiceNotClosed), errors.Is(err, domain.ErrCorrectionRequiresAuthorization):
+		respondError(w, http.StatusConflict, "Nota fiscal precisa estar autorizada", err.Error())
+	case errors.Is(err, domain.ErrCorrectionLimitReached):
+		respondError(w, http.StatusConflict, "Limite de cartas de correção atingido", err.Error())
+	case errors.Is(err, nfe.ErrCertificateExpired):
+		respondError(w, http.StatusServiceUnavailable, "Certificado digital vencido", err.Error())
+	default:
+		respondError(w, http.StatusInternalServerError, fallback, err.Error())
+	}
+}
@@ -0,0 +1,73 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/usecase"
+)
+
+// CreditOverrideRequest é o pedido de liberação de uma nota acima do limite
+// de crédito do cliente
+type CreditOverrideRequest struct {
+	Reason string `json:"reason"`
+}
+
+// PrintInvoiceRequest é o corpo opcional da impressão
+type PrintInvoiceRequest struct {
+	CreditOverride *CreditOverrideRequest `json:"credit_override"`
+}
+
+// CreditLimitResponse é a recusa por limite de crédito, com os valores da verificação
+type CreditLimitResponse struct {
+	ErrorResponse
+	Credit domain.CreditCheck `json:"credit"`
+}
+
+// creditOverride monta a liberação de crédito com o usuário da requisição
+func creditOverride(req *CreditOverrideRequest, r *http.Request) *usecase.CreditOverrideInput {
+	if req == nil {
+		return nil
+	}
+	return &usecase.CreditOverrideInput{
+		User:   requestUser(r),
+		Reason: req.Reason,
+	}
+}
+
+// decodePrintRequest lê o corpo opcional da impressão; corpo vazio é aceito
+func decodePrintRequest(r *http.Request) (PrintInvoiceRequest, error) {
+	var req PrintInvoiceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		return req, err
+	}
+	return req, nil
+}
+
+// respondCreditError responde aos erros do limite de crédito e indica se o
+// erro foi tratado
+func respondCreditError(w http.ResponseWriter, err error) bool {
+	var limitErr *domain.CreditLimitError
+	switch {
+	case errors.As(err, &limitErr):
+		respondJSON(w, http.StatusUnprocessableEntity, CreditLimitResponse{
+			ErrorResponse: ErrorResponse{
+				Error:   "Limite de crédito do cliente excedido",
+				Message: err.Error(),
+			},
+			Credit: limitErr.Check,
+		})
+	case errors.Is(err, domain.ErrCreditOverrideUser):
+		respondError(w, http.StatusUnauthorized, "Identidade do usuário não confirmada", err.Error())
+	case errors.Is(err, domain.ErrCreditOverrideUnauthorized):
+		respondError(w, http.StatusForbidden, "Liberação de crédito não autorizada", err.Error())
+	case errors.Is(err, domain.ErrCreditOverrideReason):
+		respondError(w, http.StatusBadRequest, "Liberação de crédito inválida", err.Error())
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,120 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+	"github.com/go-chi/chi/v5"
+)
+
+// CustomerRequest representa o payload de criação/atualização de cliente
+type CustomerRequest struct {
+	Name              string           `json:"name"`
+	Document          string           `json:"document"`
+	StateRegistration string           `json:"state_registration"`
+	TaxRegime         domain.TaxRegime `json:"tax_regime"`
+	Email             string           `json:"email"`
+	Address           domain.Address   `json:"address"`
+	CreditLimit       *domain.Money    `json:"credit_limit"` // Ausente: sem limite de crédito
+}
+
+func (req CustomerRequest) toDomain() domain.Customer {
+	return domain.Customer{
+		Name:              req.Name,
+		Document:          req.Document,
+		StateRegistration: req.StateRegistration,
+		TaxRegime:         req.TaxRegime,
+		Email:             req.Email,
+		Address:           req.Address,
+		CreditLimit:       req.CreditLimit,
+	}
+}
+
+// CreateCustomer cadastra um novo cliente
+func (h *Handler) CreateCustomer(w http.ResponseWriter, r *http.Request) {
+	var req CustomerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Payload inválido", err.Error())
+		return
+	}
+
+	customer, err := h.customerService.CreateCustomer(req.toDomain())
+	if err != nil {
+		respondCustomerError(w, err, "Erro ao criar cliente")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, customer)
+}
+
+// GetCustomer busca um cliente por ID
+func (h *Handler) GetCustomer(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	customer, err := h.customerService.GetCustomer(id)
+	if err != nil {
+		respondCustomerError(w, err, "Erro ao buscar cliente")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, customer)
+}
+
+// GetAllCustomers lista todos os clientes
+func (h *Handler) GetAllCustomers(w http.ResponseWriter, r *http.Request) {
+	customers, err := h.customerService.GetAllCustomers()
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Erro ao listar clientes", err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, customers)
+}
+
+// UpdateCustomer atualiza um cliente
+func (h *Handler) UpdateCustomer(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	var req CustomerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Payload inválido", err.Error())
+		return
+	}
+
+	customer, err := h.customerService.UpdateCustomer(id, req.toDomain())
+	if err != nil {
+		respondCustomerError(w, err, "Erro ao atualizar cliente")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, customer)
+}
+
+// DeleteCustomer remove um cliente
+func (h *Handler) DeleteCustomer(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	if err := h.customerService.DeleteCustomer(id); err != nil {
+		respondCustomerError(w, err, "Erro ao deletar cliente")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Cliente deletado com sucesso"})
+}
+
+// respondCustomerError traduz erros de domínio do cliente em respostas HTTP
+func respondCustomerError(w http.ResponseWriter, err error, fallback string) {
+	switch err {
+	case domain.ErrCustomerNotFound:
+		respondError(w, http.StatusNotFound, "Cliente não encontrado", err.Error())
+	case domain.ErrDuplicateDocument:
+		respondError(w, http.StatusConflict, "Documento já cadastrado", err.Error())
+	case domain.ErrInvalidCustomer, domain.ErrInvalidDocument, domain.ErrInvalidCPF, domain.ErrInvalidCNPJ,
+		domain.ErrInvalidStateRegistration, domain.ErrInvalidTaxRegime, domain.ErrInvalidAddress,
+		domain.ErrInvalidCityCode, domain.ErrInvalidUF, domain.ErrInvalidCEP, domain.ErrInvalidCreditLimit:
+		respondError(w, http.StatusBadRequest, "Dados do cliente inválidos", err.Error())
+	default:
+		respondError(w, http.StatusInternalServerError, fallback, err.Error())
+	}
+}
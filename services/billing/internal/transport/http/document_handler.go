@@ -0,0 +1,120 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/nfe"
+	"github.com/go-chi/chi/v5"
+)
+
+// maxDocumentSize limita o tamanho dos XMLs recebidos para verificação
+const maxDocumentSize = 5 << 20
+
+// SignatureResponse representa o resultado da verificação de assinatura
+type SignatureResponse struct {
+	Valid     bool      `json:"valid"`
+	Subject   string    `json:"subject"`
+	Issuer    string    `json:"issuer"`
+	Serial    string    `json:"serial"`
+	NotBefore time.Time `json:"not_before"`
+	NotAfter  time.Time `json:"not_after"`
+}
+
+// GetInvoiceXML retorna o XML da NF-e (leiaute 4.00) de uma nota fechada
+func (h *Handler) GetInvoiceXML(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	data, err := h.invoiceService.GenerateXML(id)
+	if err != nil {
+		respondDocumentError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "nfe-"+id+".xml"))
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+// GetInvoiceDANFE retorna o DANFE em PDF de uma nota fechada
+func (h *Handler) GetInvoiceDANFE(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	data, err := h.invoiceService.GenerateDANFE(id)
+	if err != nil {
+		respondDocumentError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=%q", "danfe-"+id+".pdf"))
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+// GetInvoiceNFSe retorna o XML da DPS (padrão nacional da NFS-e) de uma nota
+// de serviço fechada
+func (h *Handler) GetInvoiceNFSe(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	data, err := h.invoiceService.GenerateNFSe(id)
+	if err != nil {
+		respondDocumentError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "nfse-"+id+".xml"))
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+// VerifySignature verifica a assinatura digital de um XML de NF-e enviado no corpo
+func (h *Handler) VerifySignature(w http.ResponseWriter, r *http.Request) {
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxDocumentSize))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Payload inválido", err.Error())
+		return
+	}
+
+	certificate, err := h.invoiceService.VerifySignature(data)
+	if err != nil {
+		switch {
+		case errors.Is(err, nfe.ErrSignatureMissing), errors.Is(err, nfe.ErrInvalidSignature):
+			respondError(w, http.StatusUnprocessableEntity, "Assinatura digital inválida", err.Error())
+		default:
+			respondError(w, http.StatusBadRequest, "Documento inválido", err.Error())
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, SignatureResponse{
+		Valid:     true,
+		Subject:   certificate.Subject.String(),
+		Issuer:    certificate.Issuer.String(),
+		Serial:    certificate.SerialNumber.String(),
+		NotBefore: certificate.NotBefore,
+		NotAfter:  certificate.NotAfter,
+	})
+}
+
+// respondDocumentError converte erros de geração de documentos fiscais em respostas HTTP
+func respondDocumentError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrInvoiceNotFound):
+		respondError(w, http.StatusNotFound, "Nota fiscal não encontrada", err.Error())
+	case errors.Is(err, domain.ErrInvoiceNotClosed):
+		respondError(w, http.StatusConflict, "Nota fiscal precisa estar fechada", err.Error())
+	case errors.Is(err, domain.ErrServiceInvoice), errors.Is(err, domain.ErrNotServiceInvoice):
+		respondError(w, http.StatusConflict, "Documento não disponível para o modelo da nota", err.Error())
+	case errors.Is(err, nfe.ErrCertificateExpired):
+		respondError(w, http.StatusServiceUnavailable, "Certificado digital vencido", err.Error())
+	default:
+		respondError(w, http.StatusInternalServerError, "Erro ao gerar documento fiscal", err.Error())
+	}
+}
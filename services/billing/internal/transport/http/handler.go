@@ -2,6 +2,7 @@ package http
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"github.com/go-chi/chi/v5"
 	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
@@ -9,24 +10,83 @@ import (
 )
 
 type Handler struct {
-	invoiceService *usecase.InvoiceService
+	invoiceService    *usecase.InvoiceService
+	customerService   *usecase.CustomerService
+	issuerService     *usecase.IssuerService
+	correctionService *usecase.CorrectionService
+	serviceCatalog    *usecase.ServiceCatalogService
+	carrierService    *usecase.CarrierService
+	receivableService *usecase.ReceivableService
+	salesOrderService *usecase.SalesOrderService
+	backorderService  *usecase.BackorderService
 }
 
 // NewHandler cria um novo handler
-func NewHandler(invoiceService *usecase.InvoiceService) *Handler {
+func NewHandler(invoiceService *usecase.InvoiceService, customerService *usecase.CustomerService, issuerService *usecase.IssuerService, correctionService *usecase.CorrectionService, serviceCatalog *usecase.ServiceCatalogService, carrierService *usecase.CarrierService, receivableService *usecase.ReceivableService, salesOrderService *usecase.SalesOrderService, backorderService *usecase.BackorderService) *Handler {
 	return &Handler{
-		invoiceService: invoiceService,
+		invoiceService:    invoiceService,
+		customerService:   customerService,
+		issuerService:     issuerService,
+		correctionService: correctionService,
+		serviceCatalog:    serviceCatalog,
+		carrierService:    carrierService,
+		receivableService: receivableService,
+		salesOrderService: salesOrderService,
+		backorderService:  backorderService,
 	}
 }
 
 type CreateInvoiceRequest struct {
-	Items []InvoiceItemRequest `json:"items"`
+	Model          string                 `json:"model"` // 55 (NF-e, padrão), 65 (NFC-e) ou NFSE (padrão para serviços)
+	IssuerID       string                 `json:"issuer_id"`
+	CustomerID     string                 `json:"customer_id"`
+	Items          []InvoiceItemRequest   `json:"items"`
+	Charges        domain.Charges         `json:"charges"` // Frete, seguro e outras despesas, rateados entre os itens
+	Transport      *TransportRequest      `json:"transport"`
+	Payment        *domain.PaymentTerms   `json:"payment"`         // Condição de pagamento; sem ela a nota sai sem pagamento (tPag 90)
+	CreditOverride *CreditOverrideRequest `json:"credit_override"` // Liberação acima do limite de crédito (exige X-User aprovador)
+	AllowBackorder bool                   `json:"allow_backorder"` // Fatura o disponível e registra pendência do que faltar
+}
+
+// TransportRequest representa os dados de transporte no payload. Sem
+// modalidade, o frete é 9 (sem ocorrência de transporte)
+type TransportRequest struct {
+	FreightMode *domain.FreightMode `json:"freight_mode"`
+	CarrierID   string              `json:"carrier_id"`
+	Vehicle     *domain.Vehicle     `json:"vehicle"`
+	Volumes     int                 `json:"volumes"`
+	Species     string              `json:"species"`
+	GrossWeight float64             `json:"gross_weight"`
+	NetWeight   float64             `json:"net_weight"` // Se omitido, calculado pelos pesos dos produtos
+}
+
+func (req *TransportRequest) toDomain() *domain.Transport {
+	if req == nil {
+		return nil
+	}
+	mode := domain.FreightNoTransport
+	if req.FreightMode != nil {
+		mode = *req.FreightMode
+	}
+	return &domain.Transport{
+		FreightMode: mode,
+		CarrierID:   req.CarrierID,
+		Vehicle:     req.Vehicle,
+		Volumes:     req.Volumes,
+		Species:     req.Species,
+		GrossWeight: req.GrossWeight,
+		NetWeight:   req.NetWeight,
+	}
 }
 
 // InvoiceItemRequest representa um item no payload
 type InvoiceItemRequest struct {
-	ProductID string `json:"product_id"`
-	Quantity  int    `json:"quantity"`
+	Type      domain.ItemType `json:"type"` // MERCADORIA (padrão) ou SERVICO
+	ProductID string          `json:"product_id"`
+	ServiceID string          `json:"service_id"`
+	Quantity  int             `json:"quantity"`
+	UnitPrice domain.Money    `json:"unit_price"`
+	Discount  domain.Money    `json:"discount"` // Desconto em valor sobre a linha (somente mercadorias)
 }
 
 // ErrorResponse representa uma resposta de erro
@@ -54,26 +114,82 @@ func (h *Handler) CreateInvoice(w http.ResponseWriter, r *http.Request) {
 	items := make([]domain.InvoiceItem, len(req.Items))
 	for i, item := range req.Items {
 		items[i] = domain.InvoiceItem{
+			Type:      item.Type,
 			ProductID: item.ProductID,
+			ServiceID: item.ServiceID,
 			Quantity:  item.Quantity,
+			UnitPrice: item.UnitPrice,
+			Discount:  item.Discount,
 		}
 	}
 
-	// Cria a nota fiscal
-	invoice, err := h.invoiceService.CreateInvoice(items)
+	// Cria a nota fiscal, ou o grupo de notas quando os itens excedem o limite
+	invoices, err := h.invoiceService.CreateInvoices(usecase.CreateInvoiceInput{
+		Model:          req.Model,
+		IssuerID:       req.IssuerID,
+		CustomerID:     req.CustomerID,
+		Items:          items,
+		Charges:        req.Charges,
+		Transport:      req.Transport.toDomain(),
+		Payment:        req.Payment,
+		CreditOverride: creditOverride(req.CreditOverride, r),
+		User:           requestUser(r),
+		AllowBackorder: req.AllowBackorder,
+	})
 	if err != nil {
-		switch err {
-		case domain.ErrInvoiceNoItems:
-			respondError(w, http.StatusBadRequest, "Nota fiscal deve ter ao menos um item", err.Error())
-		case domain.ErrInvalidQuantity:
-			respondError(w, http.StatusBadRequest, "Quantidade inválida", err.Error())
-		default:
-			respondError(w, http.StatusInternalServerError, "Erro ao criar nota fiscal", err.Error())
-		}
+		respondCreateInvoiceError(w, err)
 		return
 	}
 
-	respondJSON(w, http.StatusCreated, invoice)
+	respondCreatedInvoices(w, invoices)
+}
+
+// respondCreateInvoiceError converte erros da criação de notas em respostas HTTP
+func respondCreateInvoiceError(w http.ResponseWriter, err error) {
+	if respondCreditError(w, err) {
+		return
+	}
+	switch err {
+	case domain.ErrInvoiceNoItems:
+		respondError(w, http.StatusBadRequest, "Nota fiscal deve ter ao menos um item", err.Error())
+	case domain.ErrInvalidQuantity:
+		respondError(w, http.StatusBadRequest, "Quantidade inválida", err.Error())
+	case domain.ErrNothingAvailable:
+		respondError(w, http.StatusConflict, "Estoque insuficiente", err.Error())
+	case domain.ErrInvalidDiscount, domain.ErrServiceDiscount:
+		respondError(w, http.StatusBadRequest, "Desconto inválido", err.Error())
+	case domain.ErrApprovalUserRequired:
+		respondError(w, http.StatusUnauthorized, "Usuário não identificado", err.Error())
+	case domain.ErrInvalidUnitPrice:
+		respondError(w, http.StatusBadRequest, "Preço unitário inválido", err.Error())
+	case domain.ErrCustomerRequired:
+		respondError(w, http.StatusBadRequest, "Cliente (destinatário) é obrigatório", err.Error())
+	case domain.ErrCustomerNotFound:
+		respondError(w, http.StatusNotFound, "Cliente não encontrado", err.Error())
+	case domain.ErrIssuerRequired:
+		respondError(w, http.StatusBadRequest, "Emitente é obrigatório", err.Error())
+	case domain.ErrIssuerNotFound:
+		respondError(w, http.StatusNotFound, "Emitente não encontrado", err.Error())
+	case domain.ErrInvalidModel, domain.ErrConsumerInterstate:
+		respondError(w, http.StatusBadRequest, "Modelo de documento inválido", err.Error())
+	case domain.ErrInvalidItemType, domain.ErrMixedItems, domain.ErrServiceModel, domain.ErrServiceCodeMismatch:
+		respondError(w, http.StatusBadRequest, "Itens incompatíveis com o documento", err.Error())
+	case domain.ErrInvalidCharges, domain.ErrServiceCharges:
+		respondError(w, http.StatusBadRequest, "Despesas acessórias inválidas", err.Error())
+	case domain.ErrServiceNotFound:
+		respondError(w, http.StatusNotFound, "Serviço não encontrado", err.Error())
+	case domain.ErrCarrierNotFound:
+		respondError(w, http.StatusNotFound, "Transportadora não encontrada", err.Error())
+	case domain.ErrInvalidFreightMode, domain.ErrConsumerFreight, domain.ErrServiceTransport, domain.ErrTransportWithoutFreight,
+		domain.ErrInvalidVehiclePlate, domain.ErrInvalidUF, domain.ErrInvalidRNTC, domain.ErrInterstateVehicle,
+		domain.ErrInvalidVolumes, domain.ErrNetWeightExceedsGross:
+		respondError(w, http.StatusBadRequest, "Dados de transporte inválidos", err.Error())
+	case domain.ErrInvalidPaymentMethod, domain.ErrPaymentDescription, domain.ErrInvalidInstallments, domain.ErrInvalidPaymentInterval,
+		domain.ErrCashOnlyMethod, domain.ErrConsumerInstallments, domain.ErrReturnPayment, domain.ErrInstallmentAmount:
+		respondError(w, http.StatusBadRequest, "Condição de pagamento inválida", err.Error())
+	default:
+		respondError(w, http.StatusInternalServerError, "Erro ao criar nota fiscal", err.Error())
+	}
 }
 
 // GetInvoice busca uma nota fiscal por ID
@@ -107,32 +223,56 @@ func (h *Handler) GetAllInvoices(w http.ResponseWriter, r *http.Request) {
 // PrintInvoice "imprime" uma nota fiscal (fecha e atualiza estoque)
 func (h *Handler) PrintInvoice(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
+	req, err := decodePrintRequest(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Payload inválido", err.Error())
+		return
+	}
 	
 	// Processa a impressão
-	invoice, err := h.invoiceService.PrintInvoice(id)
+	invoice, err := h.invoiceService.PrintInvoice(id, creditOverride(req.CreditOverride, r))
 	if err != nil {
-		switch err {
-		case domain.ErrInvoiceNotFound:
-			respondError(w, http.StatusNotFound, "Nota fiscal não encontrada", err.Error())
-		case domain.ErrCannotPrintOpenInvoice:
-			respondError(w, http.StatusBadRequest, "Nota fiscal já está fechada", err.Error())
-		default:
-			// Este é o cenário de falha do microsserviço
-			// Retorna um erro detalhado para o frontend
-			respondError(w, http.StatusServiceUnavailable, 
-				"Falha na comunicação com o serviço de estoque", 
-				"Não foi possível atualizar o estoque. Tente novamente.")
-		}
+		respondPrintError(w, err)
 		return
 	}
 
+	success, message := authorizationMessage(invoice)
 	respondJSON(w, http.StatusOK, PrintResponse{
-		Success: true,
-		Message: "Nota fiscal impressa com sucesso",
+		Success: success,
+		Message: message,
 		Invoice: invoice,
 	})
 }
 
+// respondPrintError converte erros da impressão de notas em respostas HTTP
+func respondPrintError(w http.ResponseWriter, err error) {
+	if respondCreditError(w, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, domain.ErrInvoiceNotFound):
+		respondError(w, http.StatusNotFound, "Nota fiscal não encontrada", err.Error())
+	case errors.Is(err, domain.ErrCannotPrintOpenInvoice):
+		respondError(w, http.StatusBadRequest, "Nota fiscal já está fechada", err.Error())
+	case errors.Is(err, domain.ErrInvoiceAwaitingApproval), errors.Is(err, domain.ErrInvoiceDisapproved):
+		respondError(w, http.StatusConflict, "Nota fiscal não liberada para impressão", err.Error())
+	case errors.Is(err, domain.ErrIssuerNotFound):
+		respondError(w, http.StatusNotFound, "Emitente da nota não encontrado", err.Error())
+	case errors.Is(err, domain.ErrCustomerNotFound):
+		respondError(w, http.StatusNotFound, "Cliente da nota não encontrado", err.Error())
+	case errors.Is(err, domain.ErrConsumerContingency):
+		respondError(w, http.StatusConflict, "NFC-e indisponível em contingência", err.Error())
+	case errors.Is(err, domain.ErrInsufficientStock):
+		respondError(w, http.StatusConflict, "Estoque insuficiente para imprimir a nota", err.Error())
+	default:
+		// Este é o cenário de falha do microsserviço
+		// Retorna um erro detalhado para o frontend
+		respondError(w, http.StatusServiceUnavailable,
+			"Falha na comunicação com o serviço de estoque",
+			"Não foi possível atualizar o estoque. Tente novamente.")
+	}
+}
+
 // Health endpoint para healthcheck
 func (h *Handler) Health(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, map[string]string{
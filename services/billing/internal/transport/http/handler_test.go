@@ -0,0 +1,190 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/nfe"
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/repo/mem"
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/usecase"
+)
+
+// testSecret é o segredo compartilhado com o gateway nos testes
+const testSecret = "segredo-do-gateway"
+
+// fakeStock simula o Stock Service em memória
+type fakeStock struct {
+	mu       sync.Mutex
+	balances map[string]int
+}
+
+func (f *fakeStock) ReserveProducts(items []domain.InvoiceItem) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	requested := make(map[string]int)
+	for _, item := range items {
+		requested[item.ProductID] += item.Quantity
+	}
+	for id, quantity := range requested {
+		if f.balances[id] < quantity {
+			return fmt.Errorf("%w: falha ao reservar produto %s", domain.ErrInsufficientStock, id)
+		}
+	}
+	for id, quantity := range requested {
+		f.balances[id] -= quantity
+	}
+	return nil
+}
+
+func (f *fakeStock) RestockProducts(items []domain.InvoiceItem) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, item := range items {
+		f.balances[item.ProductID] += item.Quantity
+	}
+	return nil
+}
+
+func (f *fakeStock) CheckAvailability(productID string, quantity int) (bool, error) {
+	product, err := f.GetProduct(productID)
+	if err != nil {
+		return false, err
+	}
+	return product.Balance >= quantity, nil
+}
+
+func (f *fakeStock) GetProduct(productID string) (*domain.ProductInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	balance, exists := f.balances[productID]
+	if !exists {
+		return nil, errors.New("produto não encontrado")
+	}
+	return &domain.ProductInfo{
+		ID:          productID,
+		Code:        productID,
+		Description: "Produto " + productID,
+		Balance:     balance,
+		NCM:         "84713012",
+		Unit:        "UN",
+		NetWeight:   0.5,
+	}, nil
+}
+
+// newTestRouter monta o roteador com o serviço de notas em memória, o
+// emitente "issuer-1", o cliente "customer-1" e identidade conferida por
+// assinatura com testSecret
+func newTestRouter(t *testing.T, approvals domain.ApprovalPolicy, balances map[string]int) http.Handler {
+	t.Helper()
+	address := domain.Address{
+		Street:   "Avenida Paulista",
+		Number:   "1000",
+		District: "Bela Vista",
+		CityCode: "3550308",
+		City:     "São Paulo",
+		UF:       "SP",
+		CEP:      "01310100",
+	}
+	issuers := mem.NewIssuerMemRepository()
+	if err := issuers.Create(&domain.Issuer{
+		ID:                "issuer-1",
+		Name:              "EMPRESA TESTE LTDA",
+		CNPJ:              "11222333000181",
+		StateRegistration: "110042490114",
+		CRT:               domain.TaxRegimeNormal,
+		Series:            1,
+		Address:           address,
+	}); err != nil {
+		t.Fatalf("erro ao cadastrar emitente: %v", err)
+	}
+	customers := mem.NewCustomerMemRepository()
+	if err := customers.Create(&domain.Customer{
+		ID:           "customer-1",
+		Name:         "CLIENTE TESTE SA",
+		Document:     "11444777000161",
+		DocumentType: domain.DocumentCNPJ,
+		Address:      address,
+	}); err != nil {
+		t.Fatalf("erro ao cadastrar cliente: %v", err)
+	}
+
+	invoiceService := usecase.NewInvoiceService(mem.NewInvoiceMemRepository(), customers, issuers,
+		mem.NewServiceMemRepository(), mem.NewCarrierMemRepository(), &fakeStock{balances: balances},
+		domain.DefaultTaxConfig(), nfe.Config{}, nil, mem.NewContingencyMemRepository(),
+		mem.NewTransmissionQueueMemRepository(), domain.CreditPolicy{}, approvals,
+		mem.NewSalesOrderMemRepository(), mem.NewBackorderMemRepository())
+	handler := NewHandler(invoiceService, nil, nil, nil, nil, nil, nil, nil, nil)
+	return NewRouter(handler, NewUserIdentity(testSecret))
+}
+
+// doRequest envia o payload em JSON com o usuário informado; signature
+// vazia assina o usuário com testSecret
+func doRequest(t *testing.T, router http.Handler, method, path, user, signature string, payload any) *httptest.ResponseRecorder {
+	t.Helper()
+	body, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("erro ao serializar payload: %v", err)
+	}
+	req := httptest.NewRequest(method, path, bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if user != "" {
+		if signature == "" {
+			signature = SignUser(testSecret, user, time.Now())
+		}
+		req.Header.Set(userHeader, user)
+		req.Header.Set(userSignatureHeader, signature)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+// decodeBody lê a resposta JSON em target
+func decodeBody(t *testing.T, rec *httptest.ResponseRecorder, target any) {
+	t.Helper()
+	if err := json.NewDecoder(rec.Body).Decode(target); err != nil {
+		t.Fatalf("erro ao ler resposta: %v", err)
+	}
+}
+
+func TestPrintInvoiceInsufficientStock(t *testing.T) {
+	router := newTestRouter(t, domain.ApprovalPolicy{}, map[string]int{"P1": 100})
+
+	// Cada nota cabe no saldo na criação; a segunda impressão não encontra mais estoque
+	ids := make([]string, 2)
+	for i := range ids {
+		rec := doRequest(t, router, http.MethodPost, "/api/invoices", "", "", CreateInvoiceRequest{
+			IssuerID:   "issuer-1",
+			CustomerID: "customer-1",
+			Items:      []InvoiceItemRequest{{ProductID: "P1", Quantity: 60, UnitPrice: 1250}},
+		})
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("criação status = %d, esperado %d (%s)", rec.Code, http.StatusCreated, rec.Body)
+		}
+		var invoice domain.Invoice
+		decodeBody(t, rec, &invoice)
+		ids[i] = invoice.ID
+	}
+
+	if rec := doRequest(t, router, http.MethodPost, "/api/invoices/"+ids[0]+"/print", "", "", nil); rec.Code != http.StatusOK {
+		t.Fatalf("primeira impressão status = %d, esperado %d (%s)", rec.Code, http.StatusOK, rec.Body)
+	}
+	rec := doRequest(t, router, http.MethodPost, "/api/invoices/"+ids[1]+"/print", "", "", nil)
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("segunda impressão status = %d, esperado %d (%s)", rec.Code, http.StatusConflict, rec.Body)
+	}
+	var body ErrorResponse
+	decodeBody(t, rec, &body)
+	if body.Error != "Estoque insuficiente para imprimir a nota" {
+		t.Errorf("erro = %q, esperado falta de estoque", body.Error)
+	}
+}
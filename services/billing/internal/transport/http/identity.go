@@ -0,0 +1,120 @@
+package http
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cabeçalhos com a identidade do usuário autenticado, definidos pelo gateway
+// de autenticação à frente do serviço
+const (
+	userHeader          = "X-User"
+	userSignatureHeader = "X-User-Signature"
+)
+
+// userSignatureTTL limita o reaproveitamento de uma assinatura capturada
+const userSignatureTTL = 5 * time.Minute
+
+// Erros da conferência de identidade
+var (
+	errUserSignatureMissing = errors.New("cabeçalho X-User exige X-User-Signature")
+	errUserSignatureInvalid = errors.New("assinatura do usuário inválida ou expirada")
+)
+
+type userContextKey struct{}
+
+// UserIdentity confere o usuário informado pelo gateway no cabeçalho X-User.
+//
+// Com segredo configurado, o cabeçalho só é aceito acompanhado de
+// X-User-Signature no formato "<unix>.<hmac>", em que hmac é o HMAC-SHA256
+// em hexadecimal de "<usuário>.<unix>" com o segredo compartilhado com o
+// gateway, emitido há no máximo cinco minutos.
+//
+// Sem segredo, nenhuma identidade é conferida: o X-User é descartado e as
+// operações que exigem usuário (liberação de crédito, criação de notas
+// retidas e aprovação) são recusadas
+type UserIdentity struct {
+	secret []byte
+	now    func() time.Time
+}
+
+// NewUserIdentity cria a conferência de identidade; segredo vazio não confere usuário algum
+func NewUserIdentity(secret string) *UserIdentity {
+	return &UserIdentity{secret: []byte(secret), now: time.Now}
+}
+
+// Verified indica se a identidade é conferida por assinatura
+func (u *UserIdentity) Verified() bool {
+	return len(u.secret) > 0
+}
+
+// Middleware registra no contexto o usuário conferido e remove os cabeçalhos
+// de identidade da requisição. Identidade com assinatura ausente, inválida ou
+// expirada é recusada com 401; sem segredo, o usuário é ignorado
+func (u *UserIdentity) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user := strings.TrimSpace(r.Header.Get(userHeader))
+		signature := r.Header.Get(userSignatureHeader)
+		r.Header.Del(userHeader)
+		r.Header.Del(userSignatureHeader)
+
+		if user != "" && u.Verified() {
+			if err := u.verify(user, signature); err != nil {
+				respondError(w, http.StatusUnauthorized, "Identidade do usuário não confirmada", err.Error())
+				return
+			}
+			r = r.WithContext(context.WithValue(r.Context(), userContextKey{}, user))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// verify confere a assinatura do usuário com o segredo configurado
+func (u *UserIdentity) verify(user, signature string) error {
+	if signature == "" {
+		return errUserSignatureMissing
+	}
+	timestamp, mac, found := strings.Cut(signature, ".")
+	if !found {
+		return errUserSignatureInvalid
+	}
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return errUserSignatureInvalid
+	}
+	if age := u.now().Sub(time.Unix(unix, 0)); age > userSignatureTTL || age < -userSignatureTTL {
+		return errUserSignatureInvalid
+	}
+	expected, err := hex.DecodeString(mac)
+	if err != nil || !hmac.Equal(expected, userMAC(u.secret, user, timestamp)) {
+		return errUserSignatureInvalid
+	}
+	return nil
+}
+
+// SignUser monta o valor de X-User-Signature para o usuário, como o gateway
+// deve fazer a cada requisição
+func SignUser(secret, user string, at time.Time) string {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	return timestamp + "." + hex.EncodeToString(userMAC([]byte(secret), user, timestamp))
+}
+
+func userMAC(secret []byte, user, timestamp string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(user + "." + timestamp))
+	return mac.Sum(nil)
+}
+
+// requestUser retorna o usuário autenticado da requisição, registrado pelo
+// middleware de identidade
+func requestUser(r *http.Request) string {
+	user, _ := r.Context().Value(userContextKey{}).(string)
+	return user
+}
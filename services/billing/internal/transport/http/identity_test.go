@@ -0,0 +1,89 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestUserIdentityMiddleware(t *testing.T) {
+	now := time.Date(2025, 3, 10, 10, 0, 0, 0, time.UTC)
+	const secret = "segredo-do-gateway"
+
+	tests := []struct {
+		name      string
+		secret    string
+		user      string
+		signature string
+		status    int
+		expected  string // Usuário visto pelo handler
+	}{
+		{name: "sem segredo ignora o usuário", user: "ana", status: http.StatusOK},
+		{name: "sem segredo ignora a assinatura", user: "ana", signature: SignUser("", "ana", now), status: http.StatusOK},
+		{name: "sem usuário", secret: secret, status: http.StatusOK},
+		{
+			name:      "assinatura válida",
+			secret:    secret,
+			user:      "ana",
+			signature: SignUser(secret, "ana", now.Add(-time.Minute)),
+			status:    http.StatusOK,
+			expected:  "ana",
+		},
+		{name: "sem assinatura", secret: secret, user: "ana", status: http.StatusUnauthorized},
+		{
+			name:      "assinatura de outro usuário",
+			secret:    secret,
+			user:      "bruno",
+			signature: SignUser(secret, "ana", now),
+			status:    http.StatusUnauthorized,
+		},
+		{
+			name:      "assinatura com outro segredo",
+			secret:    secret,
+			user:      "ana",
+			signature: SignUser("outro-segredo", "ana", now),
+			status:    http.StatusUnauthorized,
+		},
+		{
+			name:      "assinatura expirada",
+			secret:    secret,
+			user:      "ana",
+			signature: SignUser(secret, "ana", now.Add(-6*time.Minute)),
+			status:    http.StatusUnauthorized,
+		},
+		{name: "assinatura malformada", secret: secret, user: "ana", signature: "abc", status: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			identity := NewUserIdentity(tt.secret)
+			identity.now = func() time.Time { return now }
+
+			var seen string
+			handler := identity.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				seen = requestUser(r)
+				if r.Header.Get(userHeader) != "" || r.Header.Get(userSignatureHeader) != "" {
+					t.Errorf("cabeçalhos de identidade repassados ao handler")
+				}
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/api/invoices", nil)
+			if tt.user != "" {
+				req.Header.Set(userHeader, tt.user)
+			}
+			if tt.signature != "" {
+				req.Header.Set(userSignatureHeader, tt.signature)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, esperado %d (%s)", rec.Code, tt.status, rec.Body)
+			}
+			if seen != tt.expected {
+				t.Errorf("usuário = %q, esperado %q", seen, tt.expected)
+			}
+		})
+	}
+}
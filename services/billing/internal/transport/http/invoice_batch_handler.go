@@ -0,0 +1,81 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+	"github.com/go-chi/chi/v5"
+)
+
+// BatchPrintResponse representa a resposta da impressão de um grupo de notas
+type BatchPrintResponse struct {
+	Success bool                 `json:"success"`
+	Message string               `json:"message"`
+	Batch   *domain.InvoiceBatch `json:"batch,omitempty"`
+}
+
+// respondCreatedInvoices responde à criação de notas: a nota criada ou, quando
+// os itens foram divididos pelo limite do documento, o grupo com todas as notas
+func respondCreatedInvoices(w http.ResponseWriter, invoices []*domain.Invoice) {
+	if len(invoices) == 1 {
+		respondJSON(w, http.StatusCreated, invoices[0])
+		return
+	}
+	batch, err := domain.NewInvoiceBatch(invoices[0].Batch.ID, invoices)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Erro ao montar grupo de notas", err.Error())
+		return
+	}
+	w.Header().Set("Location", "/api/invoices/batches/"+batch.ID)
+	respondJSON(w, http.StatusCreated, batch)
+}
+
+// GetInvoiceBatch busca as notas de um grupo
+func (h *Handler) GetInvoiceBatch(w http.ResponseWriter, r *http.Request) {
+	batch, err := h.invoiceService.GetInvoiceBatch(chi.URLParam(r, "batch"))
+	if err != nil {
+		if errors.Is(err, domain.ErrBatchNotFound) {
+			respondError(w, http.StatusNotFound, "Grupo de notas não encontrado", err.Error())
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "Erro ao buscar grupo de notas", err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, batch)
+}
+
+// PrintInvoiceBatch imprime juntas as notas de um grupo
+func (h *Handler) PrintInvoiceBatch(w http.ResponseWriter, r *http.Request) {
+	req, err := decodePrintRequest(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Payload inválido", err.Error())
+		return
+	}
+
+	batch, err := h.invoiceService.PrintInvoiceBatch(chi.URLParam(r, "batch"), creditOverride(req.CreditOverride, r))
+	if err != nil {
+		if errors.Is(err, domain.ErrBatchNotFound) {
+			respondError(w, http.StatusNotFound, "Grupo de notas não encontrado", err.Error())
+			return
+		}
+		respondPrintError(w, err)
+		return
+	}
+
+	// O grupo é bem-sucedido quando todas as notas o são; do contrário, a
+	// mensagem é a da primeira nota com problema
+	success, message := true, fmt.Sprintf("%d notas fiscais impressas", len(batch.Invoices))
+	for _, invoice := range batch.Invoices {
+		if ok, detail := authorizationMessage(invoice); !ok {
+			success, message = false, fmt.Sprintf("Nota %d: %s", invoice.Number, detail)
+			break
+		}
+	}
+	respondJSON(w, http.StatusOK, BatchPrintResponse{
+		Success: success,
+		Message: message,
+		Batch:   batch,
+	})
+}
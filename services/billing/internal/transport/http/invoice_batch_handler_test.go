@@ -0,0 +1,77 @@
+package http
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+)
+
+func TestCreateInvoiceBatch(t *testing.T) {
+	router := newTestRouter(t, domain.ApprovalPolicy{}, map[string]int{"P1": 1000})
+
+	// 995 itens excedem o limite de 990 da NF-e: a criação gera duas notas
+	req := CreateInvoiceRequest{IssuerID: "issuer-1", CustomerID: "customer-1"}
+	for range 995 {
+		req.Items = append(req.Items, InvoiceItemRequest{ProductID: "P1", Quantity: 1, UnitPrice: 1250})
+	}
+	rec := doRequest(t, router, http.MethodPost, "/api/invoices", "", "", req)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("criação status = %d, esperado %d (%s)", rec.Code, http.StatusCreated, rec.Body)
+	}
+	var batch domain.InvoiceBatch
+	decodeBody(t, rec, &batch)
+	if location := rec.Header().Get("Location"); location != "/api/invoices/batches/"+batch.ID {
+		t.Errorf("Location = %q, esperado o grupo %s", location, batch.ID)
+	}
+	if len(batch.Invoices) != 2 {
+		t.Fatalf("notas do grupo = %d, esperadas 2", len(batch.Invoices))
+	}
+	for idx, expected := range []int{990, 5} {
+		invoice := batch.Invoices[idx]
+		if len(invoice.Items) != expected {
+			t.Errorf("nota %d com %d itens, esperados %d", idx+1, len(invoice.Items), expected)
+		}
+		if invoice.Batch == nil || invoice.Batch.ID != batch.ID || invoice.Batch.Sequence != idx+1 || invoice.Batch.Count != 2 {
+			t.Errorf("nota %d com grupo %+v, esperado %s %d/2", idx+1, invoice.Batch, batch.ID, idx+1)
+		}
+	}
+	if batch.Total != domain.BatchTotal(batch.Invoices) {
+		t.Errorf("total do grupo = %d, esperado %d", batch.Total, domain.BatchTotal(batch.Invoices))
+	}
+
+	// O grupo é impresso junto pelo id devolvido na criação
+	rec = doRequest(t, router, http.MethodPost, "/api/invoices/batches/"+batch.ID+"/print", "", "", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("impressão do grupo status = %d, esperado %d (%s)", rec.Code, http.StatusOK, rec.Body)
+	}
+	var printed BatchPrintResponse
+	decodeBody(t, rec, &printed)
+	if !printed.Success || printed.Batch == nil || len(printed.Batch.Invoices) != 2 {
+		t.Fatalf("impressão do grupo = %+v, esperadas 2 notas impressas", printed)
+	}
+	for _, invoice := range printed.Batch.Invoices {
+		if invoice.Status != domain.StatusClosed {
+			t.Errorf("nota %d em %s, esperada %s", invoice.Number, invoice.Status, domain.StatusClosed)
+		}
+	}
+}
+
+func TestCreateInvoiceSingle(t *testing.T) {
+	router := newTestRouter(t, domain.ApprovalPolicy{}, map[string]int{"P1": 10})
+
+	// Sem divisão, a resposta continua sendo a própria nota
+	rec := doRequest(t, router, http.MethodPost, "/api/invoices", "", "", CreateInvoiceRequest{
+		IssuerID:   "issuer-1",
+		CustomerID: "customer-1",
+		Items:      []InvoiceItemRequest{{ProductID: "P1", Quantity: 1, UnitPrice: 1250}},
+	})
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("criação status = %d, esperado %d (%s)", rec.Code, http.StatusCreated, rec.Body)
+	}
+	var invoice domain.Invoice
+	decodeBody(t, rec, &invoice)
+	if invoice.ID == "" || invoice.Number != 1 || invoice.Batch != nil {
+		t.Errorf("criação = nota %q número %d grupo %+v, esperada nota 1 sem grupo", invoice.ID, invoice.Number, invoice.Batch)
+	}
+}
@@ -0,0 +1,118 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+	"github.com/go-chi/chi/v5"
+)
+
+// IssuerRequest representa o payload de criação/atualização de emitente
+type IssuerRequest struct {
+	Name                  string                  `json:"name"`
+	TradeName             string                  `json:"trade_name"`
+	CNPJ                  string                  `json:"cnpj"`
+	StateRegistration     string                  `json:"state_registration"`
+	MunicipalRegistration string                  `json:"municipal_registration"`
+	CRT                   domain.TaxRegime        `json:"crt"`
+	Series                int                     `json:"series"`
+	ConsumerSeries        int                     `json:"consumer_series"`
+	ServiceSeries         int                     `json:"service_series"`
+	Address               domain.Address          `json:"address"`
+	PixKey                string                  `json:"pix_key"`
+	BankSlip              *domain.BankSlipAccount `json:"bank_slip"`
+}
+
+func (req IssuerRequest) toDomain() domain.Issuer {
+	return domain.Issuer{
+		Name:                  req.Name,
+		TradeName:             req.TradeName,
+		CNPJ:                  req.CNPJ,
+		StateRegistration:     req.StateRegistration,
+		MunicipalRegistration: req.MunicipalRegistration,
+		CRT:                   req.CRT,
+		Series:                req.Series,
+		ConsumerSeries:        req.ConsumerSeries,
+		ServiceSeries:         req.ServiceSeries,
+		Address:               req.Address,
+		PixKey:                req.PixKey,
+		BankSlip:              req.BankSlip,
+	}
+}
+
+// CreateIssuer cadastra um novo emitente
+func (h *Handler) CreateIssuer(w http.ResponseWriter, r *http.Request) {
+	var req IssuerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Payload inválido", err.Error())
+		return
+	}
+
+	issuer, err := h.issuerService.CreateIssuer(req.toDomain())
+	if err != nil {
+		respondIssuerError(w, err, "Erro ao criar emitente")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, issuer)
+}
+
+// GetIssuer busca um emitente por ID
+func (h *Handler) GetIssuer(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	issuer, err := h.issuerService.GetIssuer(id)
+	if err != nil {
+		respondIssuerError(w, err, "Erro ao buscar emitente")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, issuer)
+}
+
+// GetAllIssuers lista todos os emitentes
+func (h *Handler) GetAllIssuers(w http.ResponseWriter, r *http.Request) {
+	issuers, err := h.issuerService.GetAllIssuers()
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Erro ao listar emitentes", err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, issuers)
+}
+
+// UpdateIssuer atualiza um emitente
+func (h *Handler) UpdateIssuer(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	var req IssuerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Payload inválido", err.Error())
+		return
+	}
+
+	issuer, err := h.issuerService.UpdateIssuer(id, req.toDomain())
+	if err != nil {
+		respondIssuerError(w, err, "Erro ao atualizar emitente")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, issuer)
+}
+
+// respondIssuerError traduz erros de domínio do emitente em respostas HTTP
+func respondIssuerError(w http.ResponseWriter, err error, fallback string) {
+	switch err {
+	case domain.ErrIssuerNotFound:
+		respondError(w, http.StatusNotFound, "Emitente não encontrado", err.Error())
+	case domain.ErrDuplicateIssuer:
+		respondError(w, http.StatusConflict, "CNPJ já cadastrado", err.Error())
+	case domain.ErrInvalidIssuer, domain.ErrInvalidCNPJ, domain.ErrInvalidStateRegistration, domain.ErrInvalidCRT,
+		domain.ErrInvalidSeries, domain.ErrInvalidAddress, domain.ErrInvalidCityCode, domain.ErrInvalidUF, domain.ErrInvalidCEP,
+		domain.ErrInvalidPixKey, domain.ErrUnsupportedBank, domain.ErrInvalidBankSlipAccount:
+		respondError(w, http.StatusBadRequest, "Dados do emitente inválidos", err.Error())
+	default:
+		respondError(w, http.StatusInternalServerError, fallback, err.Error())
+	}
+}
@@ -0,0 +1,96 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/pix"
+	"github.com/go-chi/chi/v5"
+)
+
+// GetPixCharge retorna a cobrança PIX da nota com o payload "copia e cola".
+// Aceita a parcela (installment) e o modo (mode: ESTATICO ou DINAMICO)
+func (h *Handler) GetPixCharge(w http.ResponseWriter, r *http.Request) {
+	charge, ok := h.pixCharge(w, r)
+	if !ok {
+		return
+	}
+	respondJSON(w, http.StatusOK, charge)
+}
+
+// GetPixQRCode retorna o QR Code da cobrança PIX da nota em PNG. Além dos
+// filtros da cobrança, aceita o lado da imagem em pixels (size)
+func (h *Handler) GetPixQRCode(w http.ResponseWriter, r *http.Request) {
+	size := pix.DefaultImageSize
+	if value := r.URL.Query().Get("size"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "Tamanho inválido", pix.ErrInvalidImageSize.Error())
+			return
+		}
+		size = parsed
+	}
+
+	charge, ok := h.pixCharge(w, r)
+	if !ok {
+		return
+	}
+	data, err := pix.Image(charge.Payload, size)
+	if err != nil {
+		if errors.Is(err, pix.ErrInvalidImageSize) {
+			respondError(w, http.StatusBadRequest, "Tamanho inválido", err.Error())
+		} else {
+			respondError(w, http.StatusInternalServerError, "Erro ao gerar QR Code", err.Error())
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=%q", "pix-"+charge.TxID+".png"))
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+// pixCharge monta a cobrança PIX a partir da requisição e responde com o erro
+// correspondente quando ela não pode ser gerada
+func (h *Handler) pixCharge(w http.ResponseWriter, r *http.Request) (*domain.PixCharge, bool) {
+	query := r.URL.Query()
+	mode, err := domain.ParsePixMode(query.Get("mode"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Modo do PIX inválido", err.Error())
+		return nil, false
+	}
+
+	charge, err := h.receivableService.GetPixCharge(chi.URLParam(r, "id"), query.Get("installment"), mode)
+	if err != nil {
+		respondChargeError(w, err, "Erro ao gerar cobrança PIX")
+		return nil, false
+	}
+	return charge, true
+}
+
+// respondChargeError converte erros da geração de cobranças (PIX e boleto)
+// em respostas HTTP
+func respondChargeError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, domain.ErrInvoiceNotFound):
+		respondError(w, http.StatusNotFound, "Nota fiscal não encontrada", err.Error())
+	case errors.Is(err, domain.ErrInstallmentNotFound):
+		respondError(w, http.StatusNotFound, "Parcela não encontrada", err.Error())
+	case errors.Is(err, domain.ErrPaymentNotAllowed), errors.Is(err, domain.ErrNoInstallments),
+		errors.Is(err, domain.ErrInstallmentPaid), errors.Is(err, domain.ErrInvoicePaid):
+		respondError(w, http.StatusConflict, "Nota fiscal não admite recebimento", err.Error())
+	case errors.Is(err, domain.ErrPixKeyNotConfigured), errors.Is(err, domain.ErrPixDynamicNotConfigured),
+		errors.Is(err, pix.ErrLocationTooLong):
+		respondError(w, http.StatusUnprocessableEntity, "Cobrança PIX não configurada", err.Error())
+	case errors.Is(err, domain.ErrBankSlipNotConfigured), errors.Is(err, domain.ErrUnsupportedBank):
+		respondError(w, http.StatusUnprocessableEntity, "Boleto não configurado", err.Error())
+	case errors.Is(err, domain.ErrBankSlipInstallmentMissing), errors.Is(err, domain.ErrBankSlipAmount):
+		respondError(w, http.StatusBadRequest, "Boleto inválido", err.Error())
+	default:
+		respondError(w, http.StatusInternalServerError, fallback, err.Error())
+	}
+}
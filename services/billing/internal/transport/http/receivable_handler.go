@@ -0,0 +1,142 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/usecase"
+	"github.com/go-chi/chi/v5"
+)
+
+// dateLayout é o formato das datas recebidas na query string e nos payloads
+const dateLayout = "2006-01-02"
+
+// PaymentRequest representa o payload de um recebimento. Sem parcela, o valor
+// é aplicado às parcelas em aberto pela ordem de vencimento; sem data, vale a
+// data atual
+type PaymentRequest struct {
+	Installment string               `json:"installment"`
+	Amount      domain.Money         `json:"amount"`
+	PaidAt      string               `json:"paid_at"` // AAAA-MM-DD
+	Method      domain.PaymentMethod `json:"method"`  // Padrão: meio da condição de pagamento
+}
+
+// GetReceivables lista as parcelas a receber, ordenadas pelo vencimento.
+// Aceita os filtros from e to (AAAA-MM-DD), customer_id e status
+func (h *Handler) GetReceivables(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := usecase.ReceivableFilter{
+		CustomerID: query.Get("customer_id"),
+		Status:     domain.InstallmentStatus(query.Get("status")),
+	}
+
+	var err error
+	if filter.From, err = parseDate(query.Get("from")); err != nil {
+		respondError(w, http.StatusBadRequest, "Data inicial inválida", err.Error())
+		return
+	}
+	if filter.To, err = parseDate(query.Get("to")); err != nil {
+		respondError(w, http.StatusBadRequest, "Data final inválida", err.Error())
+		return
+	}
+
+	receivables, err := h.receivableService.GetReceivables(filter)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Erro ao listar recebíveis", err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, receivables)
+}
+
+// GetAging retorna o relatório de vencimentos do saldo em aberto na data
+// informada em date (AAAA-MM-DD), ou na data atual
+func (h *Handler) GetAging(w http.ResponseWriter, r *http.Request) {
+	date, err := parseDate(r.URL.Query().Get("date"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Data de referência inválida", err.Error())
+		return
+	}
+	if date.IsZero() {
+		date = time.Now()
+	}
+
+	report, err := h.receivableService.GetAging(date)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Erro ao gerar relatório de vencimentos", err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, report)
+}
+
+// RegisterPayment registra um recebimento contra as parcelas da nota
+func (h *Handler) RegisterPayment(w http.ResponseWriter, r *http.Request) {
+	var req PaymentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Payload inválido", err.Error())
+		return
+	}
+	paidAt, err := parseDate(req.PaidAt)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Data do recebimento inválida", err.Error())
+		return
+	}
+
+	record, err := h.receivableService.RegisterPayment(chi.URLParam(r, "id"), domain.PaymentInput{
+		Installment: req.Installment,
+		Amount:      req.Amount,
+		PaidAt:      paidAt,
+		Method:      req.Method,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvoiceNotFound):
+			respondError(w, http.StatusNotFound, "Nota fiscal não encontrada", err.Error())
+		case errors.Is(err, domain.ErrInstallmentNotFound):
+			respondError(w, http.StatusNotFound, "Parcela não encontrada", err.Error())
+		case errors.Is(err, domain.ErrPaymentNotAllowed), errors.Is(err, domain.ErrNoInstallments),
+			errors.Is(err, domain.ErrInstallmentPaid), errors.Is(err, domain.ErrInvoicePaid):
+			respondError(w, http.StatusConflict, "Nota fiscal não admite recebimento", err.Error())
+		case errors.Is(err, domain.ErrInvalidPaymentAmount), errors.Is(err, domain.ErrInvalidPaymentDate),
+			errors.Is(err, domain.ErrPaymentExceedsBalance):
+			respondError(w, http.StatusBadRequest, "Recebimento inválido", err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, "Erro ao registrar recebimento", err.Error())
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, record)
+}
+
+// GetPayments lista os recebimentos registrados para a nota
+func (h *Handler) GetPayments(w http.ResponseWriter, r *http.Request) {
+	payments, err := h.receivableService.GetPayments(chi.URLParam(r, "id"))
+	if err != nil {
+		if errors.Is(err, domain.ErrInvoiceNotFound) {
+			respondError(w, http.StatusNotFound, "Nota fiscal não encontrada", err.Error())
+		} else {
+			respondError(w, http.StatusInternalServerError, "Erro ao buscar recebimentos", err.Error())
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, payments)
+}
+
+// parseDate interpreta uma data AAAA-MM-DD no fuso local; vazia, retorna a
+// data zero
+func parseDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	date, err := time.ParseInLocation(dateLayout, value, time.Local)
+	if err != nil {
+		return time.Time{}, errors.New("use o formato AAAA-MM-DD")
+	}
+	return date, nil
+}
@@ -0,0 +1,87 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/usecase"
+	"github.com/go-chi/chi/v5"
+)
+
+// ReturnRequest representa o payload de uma nota de devolução. Sem itens,
+// todo o saldo a devolver da nota original é incluído
+type ReturnRequest struct {
+	Items []ReturnItemRequest `json:"items"`
+}
+
+// ReturnItemRequest indica o item da nota original (nItem) e a quantidade devolvida
+type ReturnItemRequest struct {
+	Item     int `json:"item"`
+	Quantity int `json:"quantity"`
+}
+
+// CreateReturn cria uma nota de devolução para a nota informada pelo ID
+func (h *Handler) CreateReturn(w http.ResponseWriter, r *http.Request) {
+	h.createReturn(w, r, usecase.CreateReturnInput{OriginalID: chi.URLParam(r, "id")})
+}
+
+// CreateReturnByAccessKey cria uma nota de devolução para a nota informada
+// pela chave de acesso
+func (h *Handler) CreateReturnByAccessKey(w http.ResponseWriter, r *http.Request) {
+	h.createReturn(w, r, usecase.CreateReturnInput{AccessKey: chi.URLParam(r, "key")})
+}
+
+func (h *Handler) createReturn(w http.ResponseWriter, r *http.Request, input usecase.CreateReturnInput) {
+	var req ReturnRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		respondError(w, http.StatusBadRequest, "Payload inválido", err.Error())
+		return
+	}
+	for _, item := range req.Items {
+		input.Lines = append(input.Lines, domain.ReturnLine{Item: item.Item, Quantity: item.Quantity})
+	}
+
+	invoice, err := h.invoiceService.CreateReturn(input)
+	if err != nil {
+		respondReturnError(w, err, "Erro ao criar nota de devolução")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, invoice)
+}
+
+// GetReturns lista as notas de devolução emitidas para a nota
+func (h *Handler) GetReturns(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	returns, err := h.invoiceService.GetReturns(id)
+	if err != nil {
+		respondReturnError(w, err, "Erro ao buscar notas de devolução")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, returns)
+}
+
+// respondReturnError converte erros de devolução em respostas HTTP
+func respondReturnError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, domain.ErrInvoiceNotFound):
+		respondError(w, http.StatusNotFound, "Nota fiscal não encontrada", err.Error())
+	case errors.Is(err, domain.ErrIssuerNotFound):
+		respondError(w, http.StatusNotFound, "Emitente da nota não encontrado", err.Error())
+	case errors.Is(err, domain.ErrInvalidAccessKey), errors.Is(err, domain.ErrAccessKeyCheckDigit):
+		respondError(w, http.StatusBadRequest, "Chave de acesso inválida", err.Error())
+	case errors.Is(err, domain.ErrReturnOriginalNotClosed), errors.Is(err, domain.ErrReturnNotAllowed),
+		errors.Is(err, domain.ErrReturnCustomerRequired), errors.Is(err, domain.ErrNothingToReturn):
+		respondError(w, http.StatusConflict, "Nota fiscal não admite devolução", err.Error())
+	case errors.Is(err, domain.ErrInvalidReturnItem), errors.Is(err, domain.ErrInvalidQuantity),
+		errors.Is(err, domain.ErrReturnQuantityExceeded):
+		respondError(w, http.StatusBadRequest, "Itens da devolução inválidos", err.Error())
+	default:
+		respondError(w, http.StatusInternalServerError, fallback, err.Error())
+	}
+}
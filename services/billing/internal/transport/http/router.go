@@ -8,8 +8,9 @@ import (
 	"github.com/go-chi/cors"
 )
 
-// cria e configura o roteador HTTP
-func NewRouter(handler *Handler) http.Handler {
+// cria e configura o roteador HTTP. O usuário das requisições é conferido
+// pela identidade informada
+func NewRouter(handler *Handler, identity *UserIdentity) http.Handler {
 	r := chi.NewRouter()
 
 	// Middlewares
@@ -29,6 +30,10 @@ func NewRouter(handler *Handler) http.Handler {
 		MaxAge:           300,
 	}))
 
+	// Identidade do usuário: X-User não é aceito do navegador (fora do CORS) e
+	// só vale assinado pelo gateway quando há segredo configurado
+	r.Use(identity.Middleware)
+
 	// Health check
 	r.Get("/health", handler.Health)
 
@@ -38,9 +43,116 @@ func NewRouter(handler *Handler) http.Handler {
 			r.Get("/", handler.GetAllInvoices)
 			r.Post("/", handler.CreateInvoice)
 			r.Get("/{id}", handler.GetInvoice)
+			r.Get("/by-key/{key}", handler.GetInvoiceByAccessKey)
+
+			// Grupos de notas divididas pelo limite de 990 itens
+			r.Get("/batches/{batch}", handler.GetInvoiceBatch)
+			r.Post("/batches/{batch}/print", handler.PrintInvoiceBatch)
 			
 			// Endpoint de impressão (fechamento) da nota fiscal
 			r.Post("/{id}/print", handler.PrintInvoice)
+			r.Post("/{id}/authorize", handler.AuthorizeInvoice)
+
+			// Aprovação das notas retidas pelas regras (quatro olhos)
+			r.Post("/{id}/approve", handler.ApproveInvoice)
+			r.Post("/{id}/reject", handler.RejectInvoice)
+
+			// Documento fiscal eletrônico da nota fechada
+			r.Get("/{id}/xml", handler.GetInvoiceXML)
+			r.Get("/{id}/danfe.pdf", handler.GetInvoiceDANFE)
+			r.Get("/{id}/nfse", handler.GetInvoiceNFSe)
+
+			// Cartas de correção (CC-e) da nota autorizada
+			r.Get("/{id}/corrections", handler.GetCorrections)
+			r.Post("/{id}/corrections", handler.CreateCorrection)
+			r.Get("/{id}/corrections/{sequence}/xml", handler.GetCorrectionXML)
+
+			// Notas de devolução que referenciam a nota fechada
+			r.Get("/{id}/returns", handler.GetReturns)
+			r.Post("/{id}/returns", handler.CreateReturn)
+			r.Post("/by-key/{key}/returns", handler.CreateReturnByAccessKey)
+
+			// Recebimentos das parcelas da nota emitida
+			r.Get("/{id}/payments", handler.GetPayments)
+			r.Post("/{id}/payments", handler.RegisterPayment)
+			r.Get("/{id}/pix", handler.GetPixCharge)
+			r.Get("/{id}/pix.png", handler.GetPixQRCode)
+			r.Get("/{id}/installments/{number}/boleto", handler.GetBankSlip)
+			r.Get("/{id}/installments/{number}/boleto.pdf", handler.GetBankSlipPDF)
+		})
+
+		// Pedidos de venda, que reservam estoque e geram notas
+		r.Route("/sales-orders", func(r chi.Router) {
+			r.Get("/", handler.GetAllSalesOrders)
+			r.Post("/", handler.CreateSalesOrder)
+			r.Get("/{id}", handler.GetSalesOrder)
+			r.Post("/{id}/confirm", handler.ConfirmSalesOrder)
+			r.Post("/{id}/cancel", handler.CancelSalesOrder)
+			r.Get("/{id}/invoices", handler.GetOrderInvoices)
+			r.Post("/{id}/invoices", handler.CreateOrderInvoice)
+		})
+
+		// Pendências de entrega das quantidades faturadas sem estoque
+		r.Route("/backorders", func(r chi.Router) {
+			r.Get("/", handler.GetAllBackorders)
+			r.Get("/{id}", handler.GetBackorder)
+			r.Post("/{id}/invoice", handler.CreateBackorderInvoice)
+			r.Post("/{id}/cancel", handler.CancelBackorder)
+
+			// Entradas de estoque notificadas pelo Stock Service
+			r.Post("/stock-entries", handler.RegisterStockEntry)
+			r.Post("/recheck", handler.RecheckBackorders)
+		})
+
+		// Parcelas (duplicatas) a receber das notas emitidas
+		r.Get("/receivables", handler.GetReceivables)
+		r.Get("/receivables/aging", handler.GetAging)
+
+		// Emissão em contingência e fila de transmissão à SEFAZ
+		r.Route("/contingency", func(r chi.Router) {
+			r.Get("/", handler.GetContingency)
+			r.Post("/", handler.EnterContingency)
+			r.Delete("/", handler.LeaveContingency)
+			r.Post("/transmit", handler.TransmitQueue)
+		})
+
+		// Validação de chaves de acesso e assinaturas recebidas de terceiros
+		r.Get("/access-keys/{key}", handler.ValidateAccessKey)
+		r.Post("/nfe/verify", handler.VerifySignature)
+
+		// Cadastro de clientes (destinatários)
+		r.Route("/customers", func(r chi.Router) {
+			r.Get("/", handler.GetAllCustomers)
+			r.Post("/", handler.CreateCustomer)
+			r.Get("/{id}", handler.GetCustomer)
+			r.Put("/{id}", handler.UpdateCustomer)
+			r.Delete("/{id}", handler.DeleteCustomer)
+		})
+
+		// Catálogo de serviços (itens de NFS-e)
+		r.Route("/services", func(r chi.Router) {
+			r.Get("/", handler.GetAllServices)
+			r.Post("/", handler.CreateService)
+			r.Get("/{id}", handler.GetService)
+			r.Put("/{id}", handler.UpdateService)
+			r.Delete("/{id}", handler.DeleteService)
+		})
+
+		// Cadastro de transportadoras (grupo de transporte das notas)
+		r.Route("/carriers", func(r chi.Router) {
+			r.Get("/", handler.GetAllCarriers)
+			r.Post("/", handler.CreateCarrier)
+			r.Get("/{id}", handler.GetCarrier)
+			r.Put("/{id}", handler.UpdateCarrier)
+			r.Delete("/{id}", handler.DeleteCarrier)
+		})
+
+		// Cadastro de emitentes (estabelecimentos)
+		r.Route("/issuers", func(r chi.Router) {
+			r.Get("/", handler.GetAllIssuers)
+			r.Post("/", handler.CreateIssuer)
+			r.Get("/{id}", handler.GetIssuer)
+			r.Put("/{id}", handler.UpdateIssuer)
 		})
 	})
 
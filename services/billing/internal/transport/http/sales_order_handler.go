@@ -0,0 +1,188 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/usecase"
+	"github.com/go-chi/chi/v5"
+)
+
+// SalesOrderRequest representa o payload de um pedido de venda
+type SalesOrderRequest struct {
+	IssuerID   string                  `json:"issuer_id"`
+	CustomerID string                  `json:"customer_id"`
+	Lines      []SalesOrderLineRequest `json:"lines"`
+	Payment    *domain.PaymentTerms    `json:"payment"` // Copiada para as notas geradas pelo pedido
+	Notes      string                  `json:"notes"`
+}
+
+// SalesOrderLineRequest representa uma linha do pedido no payload
+type SalesOrderLineRequest struct {
+	ProductID string       `json:"product_id"`
+	Quantity  int          `json:"quantity"`
+	UnitPrice domain.Money `json:"unit_price"`
+}
+
+// OrderInvoiceRequest representa a nota gerada por um pedido. Sem linhas,
+// todo o saldo a faturar do pedido é incluído
+type OrderInvoiceRequest struct {
+	Lines          []domain.OrderLine     `json:"lines"`
+	Model          string                 `json:"model"`
+	Charges        domain.Charges         `json:"charges"`
+	Transport      *TransportRequest      `json:"transport"`
+	CreditOverride *CreditOverrideRequest `json:"credit_override"`
+}
+
+// CreateSalesOrder cria um pedido de venda em rascunho
+func (h *Handler) CreateSalesOrder(w http.ResponseWriter, r *http.Request) {
+	var req SalesOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Payload inválido", err.Error())
+		return
+	}
+
+	lines := make([]domain.SalesOrderLine, len(req.Lines))
+	for i, line := range req.Lines {
+		lines[i] = domain.SalesOrderLine{
+			ProductID: line.ProductID,
+			Quantity:  line.Quantity,
+			UnitPrice: line.UnitPrice,
+		}
+	}
+
+	order, err := h.salesOrderService.CreateSalesOrder(usecase.CreateSalesOrderInput{
+		IssuerID:   req.IssuerID,
+		CustomerID: req.CustomerID,
+		Lines:      lines,
+		Payment:    req.Payment,
+		Notes:      req.Notes,
+		User:       requestUser(r),
+	})
+	if err != nil {
+		respondSalesOrderError(w, err, "Erro ao criar pedido de venda")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, order)
+}
+
+// GetSalesOrder busca um pedido de venda por ID
+func (h *Handler) GetSalesOrder(w http.ResponseWriter, r *http.Request) {
+	order, err := h.salesOrderService.GetSalesOrder(chi.URLParam(r, "id"))
+	if err != nil {
+		respondSalesOrderError(w, err, "Erro ao buscar pedido de venda")
+		return
+	}
+	respondJSON(w, http.StatusOK, order)
+}
+
+// GetAllSalesOrders lista os pedidos de venda
+func (h *Handler) GetAllSalesOrders(w http.ResponseWriter, r *http.Request) {
+	orders, err := h.salesOrderService.GetAllSalesOrders()
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Erro ao listar pedidos de venda", err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, orders)
+}
+
+// ConfirmSalesOrder confirma o pedido, reservando o estoque das linhas
+func (h *Handler) ConfirmSalesOrder(w http.ResponseWriter, r *http.Request) {
+	order, err := h.salesOrderService.ConfirmSalesOrder(chi.URLParam(r, "id"))
+	if err != nil {
+		respondSalesOrderError(w, err, "Erro ao confirmar pedido de venda")
+		return
+	}
+	respondJSON(w, http.StatusOK, order)
+}
+
+// CancelSalesOrder cancela o pedido, liberando a reserva do saldo não faturado
+func (h *Handler) CancelSalesOrder(w http.ResponseWriter, r *http.Request) {
+	order, err := h.salesOrderService.CancelSalesOrder(chi.URLParam(r, "id"))
+	if err != nil {
+		respondSalesOrderError(w, err, "Erro ao cancelar pedido de venda")
+		return
+	}
+	respondJSON(w, http.StatusOK, order)
+}
+
+// CreateOrderInvoice gera uma nota a partir das linhas do pedido
+func (h *Handler) CreateOrderInvoice(w http.ResponseWriter, r *http.Request) {
+	var req OrderInvoiceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		respondError(w, http.StatusBadRequest, "Payload inválido", err.Error())
+		return
+	}
+
+	invoices, err := h.salesOrderService.GenerateInvoice(chi.URLParam(r, "id"), usecase.GenerateInvoiceInput{
+		Lines:          req.Lines,
+		Model:          req.Model,
+		Charges:        req.Charges,
+		Transport:      req.Transport.toDomain(),
+		CreditOverride: creditOverride(req.CreditOverride, r),
+		User:           requestUser(r),
+	})
+	if err != nil {
+		if isSalesOrderError(err) {
+			respondSalesOrderError(w, err, "Erro ao gerar nota do pedido")
+			return
+		}
+		respondCreateInvoiceError(w, err)
+		return
+	}
+
+	respondCreatedInvoices(w, invoices)
+}
+
+// GetOrderInvoices lista as notas geradas pelo pedido
+func (h *Handler) GetOrderInvoices(w http.ResponseWriter, r *http.Request) {
+	invoices, err := h.salesOrderService.GetOrderInvoices(chi.URLParam(r, "id"))
+	if err != nil {
+		respondSalesOrderError(w, err, "Erro ao buscar notas do pedido")
+		return
+	}
+	respondJSON(w, http.StatusOK, invoices)
+}
+
+// isSalesOrderError indica se o erro é próprio do pedido de venda
+func isSalesOrderError(err error) bool {
+	for _, target := range []error{domain.ErrSalesOrderNotFound, domain.ErrOrderNotActive, domain.ErrNothingToInvoice,
+		domain.ErrInvalidOrderLine, domain.ErrOrderQuantityExceeded} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// respondSalesOrderError converte erros do pedido de venda em respostas HTTP
+func respondSalesOrderError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, domain.ErrSalesOrderNotFound):
+		respondError(w, http.StatusNotFound, "Pedido de venda não encontrado", err.Error())
+	case errors.Is(err, domain.ErrCustomerNotFound):
+		respondError(w, http.StatusNotFound, "Cliente não encontrado", err.Error())
+	case errors.Is(err, domain.ErrIssuerNotFound):
+		respondError(w, http.StatusNotFound, "Emitente não encontrado", err.Error())
+	case errors.Is(err, domain.ErrOrderNotDraft), errors.Is(err, domain.ErrOrderNotActive),
+		errors.Is(err, domain.ErrOrderNotCancellable), errors.Is(err, domain.ErrNothingToInvoice):
+		respondError(w, http.StatusConflict, "Situação do pedido não permite a operação", err.Error())
+	case errors.Is(err, domain.ErrOrderInsufficientStock):
+		respondError(w, http.StatusConflict, "Estoque insuficiente para o pedido", err.Error())
+	case errors.Is(err, domain.ErrIssuerRequired), errors.Is(err, domain.ErrCustomerRequired),
+		errors.Is(err, domain.ErrSalesOrderNoLines), errors.Is(err, domain.ErrInvalidQuantity),
+		errors.Is(err, domain.ErrInvalidUnitPrice), errors.Is(err, domain.ErrInvalidOrderLine),
+		errors.Is(err, domain.ErrOrderQuantityExceeded):
+		respondError(w, http.StatusBadRequest, "Dados do pedido inválidos", err.Error())
+	case errors.Is(err, domain.ErrInvalidPaymentMethod), errors.Is(err, domain.ErrPaymentDescription),
+		errors.Is(err, domain.ErrInvalidInstallments), errors.Is(err, domain.ErrInvalidPaymentInterval),
+		errors.Is(err, domain.ErrCashOnlyMethod):
+		respondError(w, http.StatusBadRequest, "Condição de pagamento inválida", err.Error())
+	default:
+		respondError(w, http.StatusInternalServerError, fallback, err.Error())
+	}
+}
@@ -0,0 +1,116 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+	"github.com/go-chi/chi/v5"
+)
+
+// ServiceRequest representa o payload de criação/atualização de serviço
+type ServiceRequest struct {
+	Code            string  `json:"code"`
+	Description     string  `json:"description"`
+	ServiceListItem string  `json:"service_list_item"`
+	MunicipalCode   string  `json:"municipal_code"`
+	ISSRate         float64 `json:"iss_rate"`
+	Unit            string  `json:"unit"`
+}
+
+func (req ServiceRequest) toDomain() domain.Service {
+	return domain.Service{
+		Code:            req.Code,
+		Description:     req.Description,
+		ServiceListItem: req.ServiceListItem,
+		MunicipalCode:   req.MunicipalCode,
+		ISSRate:         req.ISSRate,
+		Unit:            req.Unit,
+	}
+}
+
+// CreateService cadastra um novo serviço no catálogo
+func (h *Handler) CreateService(w http.ResponseWriter, r *http.Request) {
+	var req ServiceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Payload inválido", err.Error())
+		return
+	}
+
+	service, err := h.serviceCatalog.CreateService(req.toDomain())
+	if err != nil {
+		respondServiceError(w, err, "Erro ao criar serviço")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, service)
+}
+
+// GetService busca um serviço por ID
+func (h *Handler) GetService(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	service, err := h.serviceCatalog.GetService(id)
+	if err != nil {
+		respondServiceError(w, err, "Erro ao buscar serviço")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, service)
+}
+
+// GetAllServices lista o catálogo de serviços
+func (h *Handler) GetAllServices(w http.ResponseWriter, r *http.Request) {
+	services, err := h.serviceCatalog.GetAllServices()
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Erro ao listar serviços", err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, services)
+}
+
+// UpdateService atualiza um serviço
+func (h *Handler) UpdateService(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	var req ServiceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Payload inválido", err.Error())
+		return
+	}
+
+	service, err := h.serviceCatalog.UpdateService(id, req.toDomain())
+	if err != nil {
+		respondServiceError(w, err, "Erro ao atualizar serviço")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, service)
+}
+
+// DeleteService remove um serviço do catálogo
+func (h *Handler) DeleteService(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	if err := h.serviceCatalog.DeleteService(id); err != nil {
+		respondServiceError(w, err, "Erro ao deletar serviço")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Serviço deletado com sucesso"})
+}
+
+// respondServiceError traduz erros de domínio do catálogo em respostas HTTP
+func respondServiceError(w http.ResponseWriter, err error, fallback string) {
+	switch err {
+	case domain.ErrServiceNotFound:
+		respondError(w, http.StatusNotFound, "Serviço não encontrado", err.Error())
+	case domain.ErrDuplicateServiceCode:
+		respondError(w, http.StatusConflict, "Código de serviço já cadastrado", err.Error())
+	case domain.ErrInvalidService, domain.ErrInvalidServiceListItem, domain.ErrInvalidMunicipalCode, domain.ErrInvalidISSRate:
+		respondError(w, http.StatusBadRequest, "Dados do serviço inválidos", err.Error())
+	default:
+		respondError(w, http.StatusInternalServerError, fallback, err.Error())
+	}
+}
@@ -0,0 +1,175 @@
+package usecase
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+)
+
+// BackorderService contém a lógica de negócio das pendências de entrega
+type BackorderService struct {
+	repo        domain.BackorderRepository
+	stockClient domain.StockClient
+	invoices    *InvoiceService // Calcula as reservas e gera as notas das pendências
+	recheckMu   sync.Mutex      // Impede reavaliações simultâneas, que reservariam o mesmo saldo
+}
+
+// NewBackorderService cria uma nova instância do serviço
+func NewBackorderService(repo domain.BackorderRepository, stockClient domain.StockClient, invoices *InvoiceService) *BackorderService {
+	return &BackorderService{
+		repo:        repo,
+		stockClient: stockClient,
+		invoices:    invoices,
+	}
+}
+
+// InvoiceBackorderInput agrupa os dados da nota gerada para uma pendência
+type InvoiceBackorderInput struct {
+	Charges        domain.Charges
+	Transport      *domain.Transport
+	CreditOverride *CreditOverrideInput
+	User           string
+}
+
+// GetBackorder busca uma pendência por ID
+func (s *BackorderService) GetBackorder(id string) (*domain.Backorder, error) {
+	return s.repo.FindByID(id)
+}
+
+// GetAllBackorders retorna todas as pendências, da mais antiga para a mais nova
+func (s *BackorderService) GetAllBackorders() ([]*domain.Backorder, error) {
+	return s.repo.FindAll()
+}
+
+// RecheckProduct reavalia as pendências do produto após uma entrada no
+// estoque. O saldo, descontadas as reservas, é alocado às pendências por ordem
+// de criação; retorna as pendências que passaram a ter estoque disponível
+func (s *BackorderService) RecheckProduct(productID string) ([]*domain.Backorder, error) {
+	s.recheckMu.Lock()
+	defer s.recheckMu.Unlock()
+	return s.recheckProduct(productID)
+}
+
+// RecheckAll reavalia as pendências de todos os produtos, para entradas que
+// não foram notificadas pelo Stock Service
+func (s *BackorderService) RecheckAll() ([]*domain.Backorder, error) {
+	s.recheckMu.Lock()
+	defer s.recheckMu.Unlock()
+
+	backorders, err := s.repo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	checked := make(map[string]bool)
+	allocated := make([]*domain.Backorder, 0)
+	for _, backorder := range backorders {
+		if backorder.Status != domain.BackorderPending || checked[backorder.ProductID] {
+			continue
+		}
+		checked[backorder.ProductID] = true
+
+		available, err := s.recheckProduct(backorder.ProductID)
+		if err != nil {
+			return nil, err
+		}
+		allocated = append(allocated, available...)
+	}
+	return allocated, nil
+}
+
+// recheckProduct aloca o saldo livre do produto às pendências em aberto
+func (s *BackorderService) recheckProduct(productID string) ([]*domain.Backorder, error) {
+	pending, err := s.repo.FindByProduct(productID)
+	if err != nil {
+		return nil, err
+	}
+	waiting := false
+	for _, backorder := range pending {
+		waiting = waiting || backorder.Status == domain.BackorderPending
+	}
+	if !waiting {
+		return []*domain.Backorder{}, nil
+	}
+
+	s.invoices.holdMu.Lock()
+	defer s.invoices.holdMu.Unlock()
+
+	product, err := s.stockClient.GetProduct(productID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar produto %s: %w", productID, err)
+	}
+	held, err := s.invoices.heldStock("", "")
+	if err != nil {
+		return nil, err
+	}
+
+	allocated := domain.AllocateBackorders(pending, product.Balance-held[productID], time.Now())
+	for _, backorder := range allocated {
+		if err := s.repo.Update(backorder); err != nil {
+			return nil, fmt.Errorf("erro ao atualizar pendência de entrega: %w", err)
+		}
+	}
+	if allocated == nil {
+		allocated = []*domain.Backorder{}
+	}
+	return allocated, nil
+}
+
+// InvoiceBackorder gera a nota da pendência com estoque disponível, com o
+// preço, o desconto e a condição de pagamento da nota original
+func (s *BackorderService) InvoiceBackorder(id string, input InvoiceBackorderInput) (*domain.Invoice, error) {
+	backorder, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if backorder.Status != domain.BackorderAvailable {
+		return nil, domain.ErrBackorderNotAvailable
+	}
+
+	invoice, err := s.invoices.CreateInvoice(CreateInvoiceInput{
+		Model:      backorder.Model,
+		IssuerID:   backorder.IssuerID,
+		CustomerID: backorder.CustomerID,
+		Items: []domain.InvoiceItem{{
+			Type:      domain.ItemGoods,
+			ProductID: backorder.ProductID,
+			Quantity:  backorder.Quantity,
+			UnitPrice: backorder.UnitPrice,
+			Discount:  backorder.Discount,
+		}},
+		Charges:        input.Charges,
+		Transport:      input.Transport,
+		Payment:        backorder.Payment,
+		CreditOverride: input.CreditOverride,
+		User:           input.User,
+		BackorderID:    backorder.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := backorder.Fulfil(invoice.ID, time.Now()); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(backorder); err != nil {
+		return nil, fmt.Errorf("erro ao atualizar pendência de entrega: %w", err)
+	}
+	return invoice, nil
+}
+
+// CancelBackorder cancela a pendência, liberando a reserva se houver
+func (s *BackorderService) CancelBackorder(id string) (*domain.Backorder, error) {
+	backorder, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := backorder.Cancel(time.Now()); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(backorder); err != nil {
+		return nil, fmt.Errorf("erro ao atualizar pendência de entrega: %w", err)
+	}
+	return backorder, nil
+}
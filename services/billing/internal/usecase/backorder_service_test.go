@@ -0,0 +1,67 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+)
+
+func TestBackorderHeldUntilPrint(t *testing.T) {
+	fixture := newTestFixture(t, domain.ApprovalPolicy{}, map[string]int{"P1": 5})
+
+	// A nota original leva as 5 unidades em estoque e deixa 10 pendentes
+	input := invoiceInput("customer-1", map[string]int{"P1": 15})
+	input.AllowBackorder = true
+	original, err := fixture.service.CreateInvoice(input)
+	if err != nil {
+		t.Fatalf("CreateInvoice() erro inesperado: %v", err)
+	}
+	if len(original.Backorders) != 1 {
+		t.Fatalf("pendências da nota = %d, esperada 1", len(original.Backorders))
+	}
+
+	// A entrada de 10 unidades é alocada à pendência, que gera a sua nota
+	if err := fixture.stock.RestockProducts([]domain.InvoiceItem{{ProductID: "P1", Quantity: 10}}); err != nil {
+		t.Fatalf("RestockProducts() erro inesperado: %v", err)
+	}
+	if available, err := fixture.backorderService.RecheckProduct("P1"); err != nil || len(available) != 1 {
+		t.Fatalf("RecheckProduct() = %d pendências, erro %v; esperada 1", len(available), err)
+	}
+	fulfilment, err := fixture.backorderService.InvoiceBackorder(original.Backorders[0], InvoiceBackorderInput{})
+	if err != nil {
+		t.Fatalf("InvoiceBackorder() erro inesperado: %v", err)
+	}
+
+	// Faturada, a pendência segue reservando as 10 unidades até a impressão:
+	// outra nota não pode consumi-las
+	other, err := fixture.service.CreateInvoice(invoiceInput("customer-1", map[string]int{"P1": 5}))
+	if err != nil {
+		t.Fatalf("CreateInvoice() erro inesperado: %v", err)
+	}
+	if _, err := fixture.service.PrintInvoice(original.ID, nil); err != nil {
+		t.Fatalf("PrintInvoice(original) erro inesperado: %v", err)
+	}
+	if _, err := fixture.service.PrintInvoice(other.ID, nil); !errors.Is(err, domain.ErrInsufficientStock) {
+		t.Fatalf("PrintInvoice(outra) erro = %v, esperado %v", err, domain.ErrInsufficientStock)
+	}
+
+	// A nota da pendência imprime com a reserva e a libera
+	if _, err := fixture.service.PrintInvoice(fulfilment.ID, nil); err != nil {
+		t.Fatalf("PrintInvoice(pendência) erro inesperado: %v", err)
+	}
+	backorder, err := fixture.backorders.FindByID(original.Backorders[0])
+	if err != nil {
+		t.Fatalf("FindByID() erro inesperado: %v", err)
+	}
+	if backorder.Status != domain.BackorderInvoiced || backorder.DeliveredAt == nil {
+		t.Errorf("pendência = %s entregue em %v, esperada faturada e entregue", backorder.Status, backorder.DeliveredAt)
+	}
+	held, err := fixture.service.heldStock("", "")
+	if err != nil {
+		t.Fatalf("heldStock() erro inesperado: %v", err)
+	}
+	if held["P1"] != 0 {
+		t.Errorf("reserva de P1 = %d, esperado 0", held["P1"])
+	}
+}
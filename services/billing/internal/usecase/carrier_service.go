@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+	"github.com/google/uuid"
+)
+
+// CarrierService contém a lógica de negócio do cadastro de transportadoras
+type CarrierService struct {
+	repo domain.CarrierRepository
+}
+
+// NewCarrierService cria uma nova instância do serviço
+func NewCarrierService(repo domain.CarrierRepository) *CarrierService {
+	return &CarrierService{
+		repo: repo,
+	}
+}
+
+// CreateCarrier cadastra uma nova transportadora
+func (s *CarrierService) CreateCarrier(carrier domain.Carrier) (*domain.Carrier, error) {
+	carrier.ID = uuid.New().String()
+	carrier.CreatedAt = time.Now()
+	carrier.UpdatedAt = carrier.CreatedAt
+
+	carrier.Normalize()
+	if err := carrier.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(&carrier); err != nil {
+		return nil, err
+	}
+	return &carrier, nil
+}
+
+// GetCarrier busca uma transportadora por ID
+func (s *CarrierService) GetCarrier(id string) (*domain.Carrier, error) {
+	return s.repo.FindByID(id)
+}
+
+// GetAllCarriers retorna todas as transportadoras
+func (s *CarrierService) GetAllCarriers() ([]*domain.Carrier, error) {
+	return s.repo.FindAll()
+}
+
+// UpdateCarrier atualiza os dados de uma transportadora
+func (s *CarrierService) UpdateCarrier(id string, data domain.Carrier) (*domain.Carrier, error) {
+	existing, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Trabalha sobre uma cópia para que o repositório detecte troca de documento
+	updated := data
+	updated.ID = existing.ID
+	updated.CreatedAt = existing.CreatedAt
+	updated.UpdatedAt = time.Now()
+
+	updated.Normalize()
+	if err := updated.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(&updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// DeleteCarrier remove uma transportadora. Notas já criadas mantêm o snapshot
+// da transportadora
+func (s *CarrierService) DeleteCarrier(id string) error {
+	return s.repo.Delete(id)
+}
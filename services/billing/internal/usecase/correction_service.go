@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/nfe"
+	"github.com/google/uuid"
+)
+
+// CorrectionService contém a lógica de negócio das cartas de correção (CC-e)
+type CorrectionService struct {
+	repo        domain.CorrectionRepository
+	invoiceRepo domain.InvoiceRepository
+	nfeConfig   nfe.Config
+}
+
+// NewCorrectionService cria uma nova instância do serviço
+func NewCorrectionService(repo domain.CorrectionRepository, invoiceRepo domain.InvoiceRepository, nfeConfig nfe.Config) *CorrectionService {
+	return &CorrectionService{
+		repo:        repo,
+		invoiceRepo: invoiceRepo,
+		nfeConfig:   nfeConfig,
+	}
+}
+
+// CreateCorrection registra uma carta de correção para a nota autorizada,
+// gerando e assinando o XML do evento com o próximo número de sequência
+func (s *CorrectionService) CreateCorrection(invoiceID, text string) (*domain.CorrectionLetter, error) {
+	invoice, err := s.invoiceRepo.FindByID(invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	if err := invoice.CanReceiveCorrection(); err != nil {
+		return nil, err
+	}
+
+	text = domain.NormalizeCorrectionText(text)
+	if err := domain.ValidateCorrectionText(text); err != nil {
+		return nil, err
+	}
+
+	sequence, err := s.repo.GetNextSequence(invoice.ID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao gerar sequência do evento: %w", err)
+	}
+	if sequence > domain.MaxCorrectionsPerNote {
+		return nil, domain.ErrCorrectionLimitReached
+	}
+
+	letter := &domain.CorrectionLetter{
+		ID:        uuid.New().String(),
+		InvoiceID: invoice.ID,
+		AccessKey: invoice.AccessKey,
+		Sequence:  sequence,
+		Text:      text,
+		EventID:   nfe.EventID(domain.EventCorrectionLetter, invoice.AccessKey, sequence),
+		CreatedAt: time.Now(),
+	}
+	document, err := nfe.GenerateCorrectionLetter(letter, s.nfeConfig)
+	if err != nil {
+		return nil, err
+	}
+	letter.Document = document
+
+	if err := s.repo.Create(letter); err != nil {
+		return nil, fmt.Errorf("erro ao registrar carta de correção: %w", err)
+	}
+	return letter, nil
+}
+
+// GetCorrections lista as cartas de correção da nota pela ordem da sequência
+func (s *CorrectionService) GetCorrections(invoiceID string) ([]*domain.CorrectionLetter, error) {
+	if _, err := s.invoiceRepo.FindByID(invoiceID); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByInvoice(invoiceID)
+}
+
+// GetCorrectionXML retorna o XML assinado de uma carta de correção
+func (s *CorrectionService) GetCorrectionXML(invoiceID string, sequence int) ([]byte, error) {
+	if _, err := s.invoiceRepo.FindByID(invoiceID); err != nil {
+		return nil, err
+	}
+	letter, err := s.repo.FindBySequence(invoiceID, sequence)
+	if err != nil {
+		return nil, err
+	}
+	return letter.Document, nil
+}
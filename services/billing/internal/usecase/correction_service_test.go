@@ -0,0 +1,99 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/nfe"
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/repo/mem"
+)
+
+// authorizedInvoice emite uma nota e a marca como autorizada pela SEFAZ
+func authorizedInvoice(t *testing.T, fixture *testFixture) *domain.Invoice {
+	t.Helper()
+	invoice, err := fixture.service.CreateInvoice(invoiceInput("customer-1", map[string]int{"P1": 1}))
+	if err != nil {
+		t.Fatalf("CreateInvoice() erro inesperado: %v", err)
+	}
+	if _, err := fixture.service.PrintInvoice(invoice.ID, nil); err != nil {
+		t.Fatalf("PrintInvoice() erro inesperado: %v", err)
+	}
+	stored, err := fixture.invoices.FindByID(invoice.ID)
+	if err != nil {
+		t.Fatalf("FindByID() erro inesperado: %v", err)
+	}
+	authorized := *stored
+	authorized.Authorization = &domain.Authorization{Status: domain.AuthorizationAuthorized, Protocol: "135250000000001"}
+	if err := fixture.invoices.Update(&authorized); err != nil {
+		t.Fatalf("Update() erro inesperado: %v", err)
+	}
+	return &authorized
+}
+
+func TestCreateCorrectionSequence(t *testing.T) {
+	fixture := newTestFixture(t, domain.ApprovalPolicy{}, map[string]int{"P1": 100})
+	service := NewCorrectionService(mem.NewCorrectionMemRepository(), fixture.invoices, nfe.Config{})
+	first, second := authorizedInvoice(t, fixture), authorizedInvoice(t, fixture)
+
+	// Textos recusados não consomem sequência
+	if _, err := service.CreateCorrection(first.ID, "curto"); !errors.Is(err, domain.ErrCorrectionTextLength) {
+		t.Fatalf("CreateCorrection() erro = %v, esperado %v", err, domain.ErrCorrectionTextLength)
+	}
+	if _, err := service.CreateCorrection(first.ID, "Corrigir a alíquota do item 1"); !errors.Is(err, domain.ErrCorrectionForbiddenField) {
+		t.Fatalf("CreateCorrection() erro = %v, esperado %v", err, domain.ErrCorrectionForbiddenField)
+	}
+
+	for expected := 1; expected <= domain.MaxCorrectionsPerNote; expected++ {
+		letter, err := service.CreateCorrection(first.ID, "  Endereço de entrega:\n Rua das Flores, 100 ")
+		if err != nil {
+			t.Fatalf("CreateCorrection(%d) erro inesperado: %v", expected, err)
+		}
+		if letter.Sequence != expected || letter.EventID != nfe.EventID(domain.EventCorrectionLetter, first.AccessKey, expected) {
+			t.Fatalf("CreateCorrection() sequência %d, Id %s; esperado %d", letter.Sequence, letter.EventID, expected)
+		}
+		if letter.Text != "Endereço de entrega: Rua das Flores, 100" {
+			t.Fatalf("CreateCorrection() texto = %q", letter.Text)
+		}
+	}
+	if _, err := service.CreateCorrection(first.ID, "Endereço de entrega: Rua das Flores, 200"); !errors.Is(err, domain.ErrCorrectionLimitReached) {
+		t.Fatalf("CreateCorrection() erro = %v, esperado %v", err, domain.ErrCorrectionLimitReached)
+	}
+
+	// A sequência é contada por nota
+	letter, err := service.CreateCorrection(second.ID, "Endereço de entrega: Rua das Flores, 100")
+	if err != nil {
+		t.Fatalf("CreateCorrection() erro inesperado: %v", err)
+	}
+	if letter.Sequence != 1 {
+		t.Errorf("CreateCorrection() sequência = %d, esperado 1", letter.Sequence)
+	}
+
+	letters, err := service.GetCorrections(first.ID)
+	if err != nil {
+		t.Fatalf("GetCorrections() erro inesperado: %v", err)
+	}
+	if len(letters) != domain.MaxCorrectionsPerNote || letters[0].Sequence != 1 || letters[len(letters)-1].Sequence != domain.MaxCorrectionsPerNote {
+		t.Errorf("GetCorrections() retornou %d cartas", len(letters))
+	}
+}
+
+func TestCreateCorrectionRequiresAuthorization(t *testing.T) {
+	fixture := newTestFixture(t, domain.ApprovalPolicy{}, map[string]int{"P1": 100})
+	service := NewCorrectionService(mem.NewCorrectionMemRepository(), fixture.invoices, nfe.Config{})
+
+	invoice, err := fixture.service.CreateInvoice(invoiceInput("customer-1", map[string]int{"P1": 1}))
+	if err != nil {
+		t.Fatalf("CreateInvoice() erro inesperado: %v", err)
+	}
+	if _, err := service.CreateCorrection(invoice.ID, "Endereço de entrega: Rua das Flores, 100"); !errors.Is(err, domain.ErrInvoiceNotClosed) {
+		t.Errorf("CreateCorrection() em nota aberta erro = %v, esperado %v", err, domain.ErrInvoiceNotClosed)
+	}
+
+	if _, err := fixture.service.PrintInvoice(invoice.ID, nil); err != nil {
+		t.Fatalf("PrintInvoice() erro inesperado: %v", err)
+	}
+	if _, err := service.CreateCorrection(invoice.ID, "Endereço de entrega: Rua das Flores, 100"); !errors.Is(err, domain.ErrCorrectionRequiresAuthorization) {
+		t.Errorf("CreateCorrection() em nota não autorizada erro = %v, esperado %v", err, domain.ErrCorrectionRequiresAuthorization)
+	}
+}
@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+	"github.com/google/uuid"
+)
+
+// CustomerService contém a lógica de negócio de clientes (destinatários)
+type CustomerService struct {
+	repo domain.CustomerRepository
+}
+
+// NewCustomerService cria uma nova instância do serviço
+func NewCustomerService(repo domain.CustomerRepository) *CustomerService {
+	return &CustomerService{
+		repo: repo,
+	}
+}
+
+// CreateCustomer cadastra um novo cliente
+func (s *CustomerService) CreateCustomer(customer domain.Customer) (*domain.Customer, error) {
+	customer.ID = uuid.New().String()
+	customer.CreatedAt = time.Now()
+	customer.UpdatedAt = customer.CreatedAt
+
+	customer.Normalize()
+	if err := customer.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(&customer); err != nil {
+		return nil, err
+	}
+	return &customer, nil
+}
+
+// GetCustomer busca um cliente por ID
+func (s *CustomerService) GetCustomer(id string) (*domain.Customer, error) {
+	return s.repo.FindByID(id)
+}
+
+// GetAllCustomers retorna todos os clientes
+func (s *CustomerService) GetAllCustomers() ([]*domain.Customer, error) {
+	return s.repo.FindAll()
+}
+
+// UpdateCustomer atualiza os dados de um cliente
+func (s *CustomerService) UpdateCustomer(id string, data domain.Customer) (*domain.Customer, error) {
+	existing, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Trabalha sobre uma cópia para que o repositório detecte troca de documento
+	updated := data
+	updated.ID = existing.ID
+	updated.CreatedAt = existing.CreatedAt
+	updated.UpdatedAt = time.Now()
+
+	updated.Normalize()
+	if err := updated.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(&updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// DeleteCustomer remove um cliente. Notas já emitidas mantêm o snapshot do destinatário
+func (s *CustomerService) DeleteCustomer(id string) error {
+	return s.repo.Delete(id)
+}
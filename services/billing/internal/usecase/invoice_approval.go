@@ -0,0 +1,58 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+)
+
+// ApproveInvoice aprova uma nota retida pelas regras de aprovação, liberando-a
+// para impressão
+func (s *InvoiceService) ApproveInvoice(id, user, comment string) (*domain.Invoice, error) {
+	return s.decideApproval(id, domain.DecisionApproved, user, comment)
+}
+
+// RejectInvoice reprova uma nota retida; a nota reprovada não pode ser
+// impressa
+func (s *InvoiceService) RejectInvoice(id, user, comment string) (*domain.Invoice, error) {
+	return s.decideApproval(id, domain.DecisionDisapproved, user, comment)
+}
+
+// decideApproval registra a decisão do usuário sobre a nota retida
+func (s *InvoiceService) decideApproval(id string, decision domain.ApprovalDecision, user, comment string) (*domain.Invoice, error) {
+	// A reprovação devolve o saldo do pedido, que não pode intercalar com o
+	// faturamento do mesmo pedido
+	s.holdMu.Lock()
+	defer s.holdMu.Unlock()
+
+	invoice, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := invoice.Decide(decision, user, comment, s.approvals, time.Now()); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(invoice); err != nil {
+		return nil, fmt.Errorf("erro ao registrar aprovação da nota: %w", err)
+	}
+
+	// A nota reprovada não será impressa: as quantidades voltam ao saldo a
+	// faturar do pedido de venda
+	if invoice.IsDisapproved() && invoice.SalesOrderID != "" {
+		order, err := s.orders.FindByID(invoice.SalesOrderID)
+		if err != nil {
+			return nil, err
+		}
+		order.ReleaseInvoice(invoice, time.Now())
+		if err := s.orders.Update(order); err != nil {
+			return nil, fmt.Errorf("erro ao atualizar pedido de venda: %w", err)
+		}
+	}
+	if invoice.IsDisapproved() {
+		if err := s.releaseBackorders(invoice); err != nil {
+			return nil, err
+		}
+	}
+	return invoice, nil
+}
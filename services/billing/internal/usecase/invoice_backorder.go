@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+	"github.com/google/uuid"
+)
+
+// prepareBackorders completa as pendências das quantidades sem estoque com os
+// dados da nota que faturou o disponível e as vincula à nota
+func (s *InvoiceService) prepareBackorders(invoice *domain.Invoice, backorders []*domain.Backorder) {
+	for _, backorder := range backorders {
+		backorder.ID = uuid.New().String()
+		backorder.Status = domain.BackorderPending
+		backorder.InvoiceID = invoice.ID
+		backorder.Model = invoice.Model
+		backorder.IssuerID = invoice.IssuerID
+		backorder.CustomerID = invoice.CustomerID
+		backorder.Payment = invoice.Payment
+		backorder.CreatedBy = invoice.CreatedBy
+		backorder.CreatedAt = invoice.CreatedAt
+		backorder.UpdatedAt = invoice.CreatedAt
+		invoice.Backorders = append(invoice.Backorders, backorder.ID)
+	}
+}
+
+// createBackorders persiste as pendências da nota criada
+func (s *InvoiceService) createBackorders(backorders []*domain.Backorder) error {
+	for _, backorder := range backorders {
+		if err := s.backorders.Create(backorder); err != nil {
+			return fmt.Errorf("erro ao registrar pendência de entrega: %w", err)
+		}
+	}
+	return nil
+}
+
+// registerBackorderDelivery registra na pendência faturada pela nota a
+// impressão, que baixa o estoque reservado para ela
+func (s *InvoiceService) registerBackorderDelivery(invoice *domain.Invoice) error {
+	if invoice.BackorderID == "" {
+		return nil
+	}
+	backorder, err := s.backorders.FindByID(invoice.BackorderID)
+	if err != nil {
+		return err
+	}
+	backorder.Deliver(time.Now())
+	if err := s.backorders.Update(backorder); err != nil {
+		return fmt.Errorf("erro ao atualizar pendência de entrega: %w", err)
+	}
+	return nil
+}
+
+// releaseBackorders trata as pendências ligadas a uma nota reprovada: a
+// pendência faturada pela nota volta à fila, e as pendências abertas pela
+// nota são canceladas junto com a venda
+func (s *InvoiceService) releaseBackorders(invoice *domain.Invoice) error {
+	now := time.Now()
+	if invoice.BackorderID != "" {
+		backorder, err := s.backorders.FindByID(invoice.BackorderID)
+		if err != nil {
+			return err
+		}
+		backorder.Reopen(now)
+		if err := s.backorders.Update(backorder); err != nil {
+			return fmt.Errorf("erro ao atualizar pendência de entrega: %w", err)
+		}
+	}
+	for _, id := range invoice.Backorders {
+		backorder, err := s.backorders.FindByID(id)
+		if err != nil {
+			return err
+		}
+		if !backorder.IsOpen() {
+			continue
+		}
+		backorder.Cancel(now)
+		if err := s.backorders.Update(backorder); err != nil {
+			return fmt.Errorf("erro ao atualizar pendência de entrega: %w", err)
+		}
+	}
+	return nil
+}
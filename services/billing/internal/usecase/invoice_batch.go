@@ -0,0 +1,45 @@
+package usecase
+
+import (
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+)
+
+// GetInvoiceBatch busca as notas de um grupo, na ordem de criação
+func (s *InvoiceService) GetInvoiceBatch(id string) (*domain.InvoiceBatch, error) {
+	invoices, err := s.repo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewInvoiceBatch(id, invoices)
+}
+
+// PrintInvoiceBatch imprime juntas as notas de um grupo, na ordem de criação.
+// Nenhuma nota é impressa enquanto alguma aguardar aprovação ou tiver sido
+// reprovada; as já fechadas são ignoradas, o que permite repetir a impressão
+// após uma falha no meio do grupo
+func (s *InvoiceService) PrintInvoiceBatch(id string, override *CreditOverrideInput) (*domain.InvoiceBatch, error) {
+	batch, err := s.GetInvoiceBatch(id)
+	if err != nil {
+		return nil, err
+	}
+	for _, invoice := range batch.Invoices {
+		switch {
+		case invoice.IsAwaitingApproval():
+			return nil, domain.ErrInvoiceAwaitingApproval
+		case invoice.IsDisapproved():
+			return nil, domain.ErrInvoiceDisapproved
+		}
+	}
+
+	for idx, invoice := range batch.Invoices {
+		if !invoice.CanBePrinted() {
+			continue
+		}
+		printed, err := s.PrintInvoice(invoice.ID, override)
+		if err != nil {
+			return nil, err
+		}
+		batch.Invoices[idx] = printed
+	}
+	return batch, nil
+}
@@ -0,0 +1,50 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+)
+
+// CreditOverrideInput é o pedido de liberação de uma nota acima do limite de
+// crédito, feito pelo usuário autenticado
+type CreditOverrideInput struct {
+	User   string
+	Reason string
+}
+
+// checkCredit verifica se os recebíveis em aberto do cliente somados ao total
+// das notas (uma nota ou o grupo de uma criação) cabem no limite de crédito.
+// Devoluções, clientes sem limite e notas já liberadas não são verificados.
+// Acima do limite, as notas só seguem com a liberação de um aprovador,
+// registrada em cada nota
+func (s *InvoiceService) checkCredit(customer *domain.Customer, override *CreditOverrideInput, invoices ...*domain.Invoice) error {
+	first := invoices[0]
+	if customer == nil || customer.CreditLimit == nil || first.IsReturn() || first.CreditOverride != nil {
+		return nil
+	}
+
+	all, err := s.repo.FindAll()
+	if err != nil {
+		return fmt.Errorf("erro ao buscar recebíveis do cliente: %w", err)
+	}
+	outstanding := domain.CustomerOutstanding(all, customer.ID)
+	check := domain.NewCreditCheck(customer.ID, *customer.CreditLimit, outstanding, domain.BatchTotal(invoices))
+	if !check.Exceeded() {
+		return nil
+	}
+	if override == nil {
+		return &domain.CreditLimitError{Check: check}
+	}
+
+	approval, err := s.creditPolicy.Approve(override.User, override.Reason, check, time.Now())
+	if err != nil {
+		return err
+	}
+	for _, invoice := range invoices {
+		granted := *approval
+		invoice.CreditOverride = &granted
+	}
+	return nil
+}
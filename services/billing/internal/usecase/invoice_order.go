@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+)
+
+// heldStock soma, por produto, as reservas dos pedidos de venda e das
+// pendências de entrega, exceto as do pedido e da pendência informados
+func (s *InvoiceService) heldStock(exceptOrderID, exceptBackorderID string) (map[string]int, error) {
+	orders, err := s.orders.FindAll()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar reservas dos pedidos: %w", err)
+	}
+	backorders, err := s.backorders.FindAll()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar reservas das pendências: %w", err)
+	}
+
+	held := domain.HeldStock(orders, exceptOrderID)
+	for productID, quantity := range domain.HeldBackorders(backorders, exceptBackorderID) {
+		held[productID] += quantity
+	}
+	return held, nil
+}
+
+// reserveStock baixa no Stock Service as mercadorias da nota impressa. O saldo
+// de cada produto, descontadas as reservas de outros pedidos e pendências,
+// deve comportar a nota; a conferência e a baixa não intercalam com outra
+// reserva
+func (s *InvoiceService) reserveStock(invoice *domain.Invoice, goods []domain.InvoiceItem) error {
+	s.holdMu.Lock()
+	defer s.holdMu.Unlock()
+
+	held, err := s.heldStock(invoice.SalesOrderID, invoice.BackorderID)
+	if err != nil {
+		return err
+	}
+	requested := make(map[string]int)
+	for _, item := range goods {
+		requested[item.ProductID] += item.Quantity
+	}
+	for _, item := range goods {
+		quantity, pending := requested[item.ProductID]
+		if !pending {
+			continue
+		}
+		delete(requested, item.ProductID)
+
+		product, err := s.stockClient.GetProduct(item.ProductID)
+		if err != nil {
+			return fmt.Errorf("erro ao buscar produto %s: %w", item.ProductID, err)
+		}
+		if available := product.Balance - held[item.ProductID]; available < quantity {
+			return fmt.Errorf("%w: produto %s (disponível: %d, solicitado: %d)",
+				domain.ErrInsufficientStock, product.Code, available, quantity)
+		}
+	}
+
+	if err := s.stockClient.ReserveProducts(goods); err != nil {
+		return fmt.Errorf("erro ao reservar produtos: %w", err)
+	}
+	return nil
+}
+
+// moveStock movimenta no Stock Service o estoque da nota impressa: baixa as
+// mercadorias vendidas e repõe as devolvidas. Serviços não movimentam estoque
+func (s *InvoiceService) moveStock(invoice *domain.Invoice) error {
+	if invoice.IsReturn() {
+		if err := s.stockClient.RestockProducts(invoice.Items); err != nil {
+			return fmt.Errorf("erro ao devolver produtos ao estoque: %w", err)
+		}
+		return nil
+	}
+	if goods := invoice.GoodsItems(); len(goods) > 0 {
+		return s.reserveStock(invoice, goods)
+	}
+	return nil
+}
+
+// revertStock desfaz o movimento de moveStock quando a nota impressa não pôde
+// ser gravada, para que uma nova impressão não movimente o estoque duas vezes
+func (s *InvoiceService) revertStock(invoice *domain.Invoice) error {
+	if invoice.IsReturn() {
+		if err := s.stockClient.ReserveProducts(invoice.Items); err != nil {
+			return fmt.Errorf("erro ao estornar devolução ao estoque: %w", err)
+		}
+		return nil
+	}
+	if goods := invoice.GoodsItems(); len(goods) > 0 {
+		if err := s.stockClient.RestockProducts(goods); err != nil {
+			return fmt.Errorf("erro ao estornar baixa de estoque: %w", err)
+		}
+	}
+	return nil
+}
+
+// registerOrderDelivery registra no pedido de venda as quantidades da nota
+// impressa, já baixadas do estoque
+func (s *InvoiceService) registerOrderDelivery(invoice *domain.Invoice) error {
+	if invoice.SalesOrderID == "" {
+		return nil
+	}
+	order, err := s.orders.FindByID(invoice.SalesOrderID)
+	if err != nil {
+		return err
+	}
+	order.RegisterDelivery(invoice, time.Now())
+	if err := s.orders.Update(order); err != nil {
+		return fmt.Errorf("erro ao atualizar pedido de venda: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+	"github.com/google/uuid"
+)
+
+// CreateReturnInput agrupa os dados de uma nota de devolução. A nota original
+// é informada pelo ID ou pela chave de acesso; sem linhas, todo o saldo a
+// devolver é incluído
+type CreateReturnInput struct {
+	OriginalID string
+	AccessKey  string
+	Lines      []domain.ReturnLine
+}
+
+// CreateReturn cria uma nota de devolução (NF-e de entrada, finalidade 4) que
+// referencia uma nota fechada, limitada ao saldo ainda não devolvido de cada
+// item. O estoque só é devolvido quando a nota de devolução é impressa
+func (s *InvoiceService) CreateReturn(input CreateReturnInput) (*domain.Invoice, error) {
+	original, err := s.findReturnOriginal(input)
+	if err != nil {
+		return nil, err
+	}
+	if err := original.CanBeReturned(); err != nil {
+		return nil, err
+	}
+
+	// Quantidades já comprometidas em devoluções ainda abertas
+	pending, err := s.pendingReturns(original)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := input.Lines
+	if len(lines) == 0 {
+		for _, line := range original.RemainingReturnLines() {
+			if line.Quantity -= pending[line.Item]; line.Quantity > 0 {
+				lines = append(lines, line)
+			}
+		}
+		if len(lines) == 0 {
+			return nil, domain.ErrNothingToReturn
+		}
+	}
+	if err := original.CheckReturnLines(lines, pending); err != nil {
+		return nil, err
+	}
+
+	issuer, err := s.issuerRepo.FindByID(original.IssuerID)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]domain.InvoiceItem, 0, len(lines))
+	for _, line := range lines {
+		items = append(items, original.ReturnItem(line))
+	}
+
+	// A devolução é sempre uma NF-e, mesmo quando a venda foi em NFC-e
+	series := issuer.SeriesFor(domain.ModelNFe)
+
+	customer := *original.Customer
+	now := time.Now()
+	ret := &domain.Invoice{
+		ID:         uuid.New().String(),
+		Model:      domain.ModelNFe,
+		Series:     series,
+		Status:     domain.StatusOpen,
+		ReturnOf:   original.Reference(),
+		IssuerID:   issuer.ID,
+		CustomerID: original.CustomerID,
+		Customer:   &customer,
+		Items:      items,
+		Charges:    original.ReturnCharges(lines),
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	ret.ApplyTaxes(s.taxConfig, ret.CreatedAt, issuer.CRT)
+
+	// O número só é gerado para a devolução válida, sem lacunas na sequência
+	if err := ret.ValidateDraft(); err != nil {
+		return nil, err
+	}
+	if ret.Number, err = s.repo.GetNextNumber(issuer.ID, domain.ModelNFe, series); err != nil {
+		return nil, fmt.Errorf("erro ao gerar número da nota: %w", err)
+	}
+	if err := s.repo.Create(ret); err != nil {
+		return nil, fmt.Errorf("erro ao criar nota de devolução: %w", err)
+	}
+
+	original.Returns = append(original.Returns, ret.ID)
+	original.UpdatedAt = now
+	if err := s.repo.Update(original); err != nil {
+		return nil, fmt.Errorf("erro ao atualizar nota original: %w", err)
+	}
+
+	return ret, nil
+}
+
+// GetReturns lista as notas de devolução emitidas para uma nota
+func (s *InvoiceService) GetReturns(id string) ([]*domain.Invoice, error) {
+	original, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	returns := make([]*domain.Invoice, 0, len(original.Returns))
+	for _, returnID := range original.Returns {
+		ret, err := s.repo.FindByID(returnID)
+		if err != nil {
+			return nil, err
+		}
+		returns = append(returns, ret)
+	}
+	return returns, nil
+}
+
+// findReturnOriginal busca a nota original pelo ID ou pela chave de acesso
+func (s *InvoiceService) findReturnOriginal(input CreateReturnInput) (*domain.Invoice, error) {
+	if input.OriginalID != "" {
+		return s.repo.FindByID(input.OriginalID)
+	}
+	return s.GetInvoiceByAccessKey(input.AccessKey)
+}
+
+// pendingReturns soma, por item da nota original, as quantidades das
+// devoluções ainda abertas
+func (s *InvoiceService) pendingReturns(original *domain.Invoice) (map[int]int, error) {
+	pending := make(map[int]int)
+	for _, returnID := range original.Returns {
+		ret, err := s.repo.FindByID(returnID)
+		if err != nil {
+			return nil, err
+		}
+		if !ret.IsOpen() {
+			continue
+		}
+		for _, line := range ret.ReturnLines() {
+			pending[line.Item] += line.Quantity
+		}
+	}
+	return pending, nil
+}
+
+// returnedInvoice busca a nota original de uma devolução e confere que o
+// saldo a devolver ainda comporta as linhas devolvidas
+func (s *InvoiceService) returnedInvoice(ret *domain.Invoice) (*domain.Invoice, error) {
+	original, err := s.repo.FindByID(ret.ReturnOf.InvoiceID)
+	if err != nil {
+		return nil, err
+	}
+	if err := original.CheckReturnLines(ret.ReturnLines(), nil); err != nil {
+		return nil, err
+	}
+	return original, nil
+}
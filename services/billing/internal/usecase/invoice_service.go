@@ -1,39 +1,197 @@
 package usecase
 
 import (
+	"crypto/x509"
+	"errors"
 	"fmt"
+	"slices"
+	"sync"
 	"time"
 	"github.com/google/uuid"
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/danfe"
 	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/nfe"
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/nfse"
 )
 
 // InvoiceService contém a lógica de negócio de notas fiscais
 type InvoiceService struct {
-	repo        domain.InvoiceRepository
-	stockClient domain.StockClient
+	repo         domain.InvoiceRepository
+	customerRepo domain.CustomerRepository
+	issuerRepo   domain.IssuerRepository
+	services     domain.ServiceRepository // Catálogo dos serviços faturados em NFS-e
+	carriers     domain.CarrierRepository // Transportadoras informadas no grupo de transporte
+	stockClient  domain.StockClient
+	taxConfig    domain.TaxConfig
+	nfeConfig    nfe.Config
+	authority    domain.AuthorityClient // Opcional: sem autorizador, as notas não são enviadas à SEFAZ
+	contingency  domain.ContingencyRepository
+	queue        domain.TransmissionQueue    // Notas emitidas em contingência a transmitir
+	transmitMu   sync.Mutex                  // Impede rodadas simultâneas de transmissão da fila
+	holdMu       sync.Mutex                  // Serializa a conferência do saldo livre com o registro das reservas
+	creditPolicy domain.CreditPolicy         // Aprovadores das notas acima do limite de crédito
+	approvals    domain.ApprovalPolicy       // Regras que retêm notas para aprovação antes da impressão
+	orders       domain.SalesOrderRepository // Pedidos de venda, que reservam estoque
+	backorders   domain.BackorderRepository  // Pendências das quantidades sem estoque
 }
 
 // NewInvoiceService cria uma nova instância do serviço
-func NewInvoiceService(repo domain.InvoiceRepository, stockClient domain.StockClient) *InvoiceService {
+func NewInvoiceService(repo domain.InvoiceRepository, customerRepo domain.CustomerRepository, issuerRepo domain.IssuerRepository, services domain.ServiceRepository, carriers domain.CarrierRepository, stockClient domain.StockClient, taxConfig domain.TaxConfig, nfeConfig nfe.Config, authority domain.AuthorityClient, contingency domain.ContingencyRepository, queue domain.TransmissionQueue, creditPolicy domain.CreditPolicy, approvals domain.ApprovalPolicy, orders domain.SalesOrderRepository, backorders domain.BackorderRepository) *InvoiceService {
 	return &InvoiceService{
-		repo:        repo,
-		stockClient: stockClient,
+		repo:         repo,
+		customerRepo: customerRepo,
+		issuerRepo:   issuerRepo,
+		services:     services,
+		carriers:     carriers,
+		stockClient:  stockClient,
+		taxConfig:    taxConfig,
+		nfeConfig:    nfeConfig,
+		authority:    authority,
+		contingency:  contingency,
+		queue:        queue,
+		creditPolicy: creditPolicy,
+		approvals:    approvals,
+		orders:       orders,
+		backorders:   backorders,
 	}
 }
 
-// CreateInvoice cria uma nova nota fiscal
-func (s *InvoiceService) CreateInvoice(items []domain.InvoiceItem) (*domain.Invoice, error) {
+// CreateInvoiceInput agrupa os dados necessários para criar uma nota fiscal
+type CreateInvoiceInput struct {
+	Model          string // 55 (NF-e, padrão para mercadorias), 65 (NFC-e) ou NFSE (padrão para serviços)
+	IssuerID       string
+	CustomerID     string // Opcional na NFC-e (consumidor não identificado)
+	Items          []domain.InvoiceItem
+	Charges        domain.Charges       // Frete, seguro e outras despesas do cabeçalho
+	Transport      *domain.Transport    // Opcional: sem transporte (modalidade 9) se ausente
+	Payment        *domain.PaymentTerms // Opcional: sem pagamento (tPag 90) se ausente
+	CreditOverride *CreditOverrideInput // Liberação acima do limite de crédito do cliente
+	User           string               // Usuário autenticado que cria a nota
+	SalesOrderID   string               // Pedido de venda que gera a nota (itens com OrderLine)
+	AllowBackorder bool                 // Fatura o disponível e registra pendência do restante
+	BackorderID    string               // Pendência de entrega faturada pela nota
+}
+
+// CreateInvoice cria uma nova nota fiscal. Quando os itens excedem o limite do
+// documento, cria o grupo de notas e retorna a primeira
+func (s *InvoiceService) CreateInvoice(input CreateInvoiceInput) (*domain.Invoice, error) {
+	invoices, err := s.CreateInvoices(input)
+	if err != nil {
+		return nil, err
+	}
+	return invoices[0], nil
+}
+
+// CreateInvoices cria as notas fiscais de uma criação: uma nota, ou um grupo
+// de notas quando os itens excedem o limite do documento
+func (s *InvoiceService) CreateInvoices(input CreateInvoiceInput) ([]*domain.Invoice, error) {
+	items := input.Items
+
 	// Valida se há itens
 	if len(items) == 0 {
 		return nil, domain.ErrInvoiceNoItems
 	}
 
-	// Valida e enriquece os itens com informações do produto
+	// Busca o emitente, que define a série e a sequência de numeração
+	if input.IssuerID == "" {
+		return nil, domain.ErrIssuerRequired
+	}
+	issuer, err := s.issuerRepo.FindByID(input.IssuerID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Mercadorias e serviços são emitidos em documentos diferentes: a nota de
+	// serviços é sempre uma NFS-e
+	services := 0
+	for idx := range items {
+		if items[idx].Type == "" {
+			items[idx].Type = domain.ItemGoods
+		}
+		if items[idx].Type != domain.ItemGoods && items[idx].Type != domain.ItemService {
+			return nil, domain.ErrInvalidItemType
+		}
+		if items[idx].IsService() {
+			services++
+		}
+	}
+	if services > 0 && services < len(items) {
+		return nil, domain.ErrMixedItems
+	}
+
+	model := input.Model
+	if model == "" {
+		model = domain.ModelNFe
+		if services > 0 {
+			model = domain.ModelNFSe
+		}
+	}
+	if model != domain.ModelNFe && model != domain.ModelNFCe && model != domain.ModelNFSe {
+		return nil, domain.ErrInvalidModel
+	}
+	if (model == domain.ModelNFSe) != (services > 0) {
+		return nil, domain.ErrServiceModel
+	}
+
+	// Busca o destinatário, cujos dados são copiados para a nota. A NFC-e pode
+	// ser emitida sem identificar o consumidor, mas apenas dentro da UF
+	var recipient *domain.Customer
+	if input.CustomerID == "" && model != domain.ModelNFCe {
+		return nil, domain.ErrCustomerRequired
+	}
+	if input.CustomerID != "" {
+		customer, err := s.customerRepo.FindByID(input.CustomerID)
+		if err != nil {
+			return nil, err
+		}
+		if model == domain.ModelNFCe && customer.Address.UF != issuer.Address.UF {
+			return nil, domain.ErrConsumerInterstate
+		}
+		snapshot := *customer
+		recipient = &snapshot
+	}
+
+	// Quantidades reservadas pelos pedidos de venda confirmados e pelas
+	// pendências disponíveis ou faturadas sem impressão; a nota gerada por um pedido ou por uma
+	// pendência consome a própria reserva
+	held, err := s.heldStock(input.SalesOrderID, input.BackorderID)
+	if err != nil {
+		return nil, err
+	}
+	used := make(map[string]int) // Quantidades já incluídas na nota, por produto
+	var shortages []*domain.Backorder
+
+	// Valida e enriquece os itens com informações do produto ou do serviço
 	enrichedItems := make([]domain.InvoiceItem, 0, len(items))
 	for _, item := range items {
 		if item.Quantity <= 0 {
 			return nil, domain.ErrInvalidQuantity
 		}
+		if item.UnitPrice < 0 {
+			return nil, domain.ErrInvalidUnitPrice
+		}
+
+		// Serviços vêm do catálogo, com a classificação e a alíquota do ISS
+		if item.IsService() {
+			service, err := s.services.FindByID(item.ServiceID)
+			if err != nil {
+				return nil, err
+			}
+			enrichedItems = append(enrichedItems, domain.InvoiceItem{
+				Type:            domain.ItemService,
+				ServiceID:       service.ID,
+				ProductCode:     service.Code,
+				Description:     service.Description,
+				Quantity:        item.Quantity,
+				ServiceListItem: service.ServiceListItem,
+				MunicipalCode:   service.MunicipalCode,
+				ISSRate:         service.ISSRate,
+				Unit:            service.Unit,
+				UnitPrice:       item.UnitPrice,
+				Discount:        item.Discount,
+			})
+			continue
+		}
 
 		// Busca informações do produto no Stock Service
 		product, err := s.stockClient.GetProduct(item.ProductID)
@@ -41,100 +199,558 @@ func (s *InvoiceService) CreateInvoice(items []domain.InvoiceItem) (*domain.Invo
 			return nil, fmt.Errorf("erro ao buscar produto %s: %w", item.ProductID, err)
 		}
 
-		// Verifica disponibilidade
-		if product.Balance < item.Quantity {
-			return nil, fmt.Errorf("produto %s com estoque insuficiente (disponível: %d, solicitado: %d)", 
-				product.Code, product.Balance, item.Quantity)
+		// Verifica disponibilidade, descontadas as reservas. Com pendência
+		// permitida, a nota leva o disponível e o restante aguarda o estoque
+		quantity, discount := item.Quantity, item.Discount
+		available := product.Balance - held[item.ProductID] - used[item.ProductID]
+		if available < item.Quantity {
+			if !input.AllowBackorder {
+				return nil, fmt.Errorf("produto %s com estoque insuficiente (disponível: %d, solicitado: %d)",
+					product.Code, available, item.Quantity)
+			}
+			quantity = max(available, 0)
+			discount = item.Discount * domain.Money(quantity) / domain.Money(item.Quantity)
+			shortages = append(shortages, &domain.Backorder{
+				ProductID:   item.ProductID,
+				ProductCode: product.Code,
+				Description: product.Description,
+				Quantity:    item.Quantity - quantity,
+				UnitPrice:   item.UnitPrice,
+				Discount:    item.Discount - discount,
+			})
+			if quantity == 0 {
+				continue
+			}
 		}
+		used[item.ProductID] += quantity
 
 		// Enriquece o item com informações do produto
 		enrichedItems = append(enrichedItems, domain.InvoiceItem{
+			Type:        domain.ItemGoods,
 			ProductID:   item.ProductID,
 			ProductCode: product.Code,
 			Description: product.Description,
-			Quantity:    item.Quantity,
+			Quantity:    quantity,
+			NCM:         product.NCM,
+			CEST:        product.CEST,
+			Origin:      product.Origin,
+			Unit:        product.Unit,
+			UnitWeight:  product.NetWeight,
+			UnitPrice:   item.UnitPrice,
+			Discount:    discount,
+			OrderLine:   item.OrderLine,
 		})
 	}
+	if len(enrichedItems) == 0 {
+		return nil, domain.ErrNothingAvailable
+	}
 
-	// Gera o próximo número sequencial
-	number, err := s.repo.GetNextNumber()
-	if err != nil {
-		return nil, fmt.Errorf("erro ao gerar número da nota: %w", err)
+	// NF-e e NFC-e admitem até 990 itens: criações maiores são divididas em
+	// notas da mesma série, ligadas por um grupo, com frete, seguro, outras
+	// despesas, volumes e pesos rateados entre elas
+	groups := [][]domain.InvoiceItem{enrichedItems}
+	if model != domain.ModelNFSe {
+		groups = domain.SplitItems(enrichedItems, domain.MaxInvoiceItems)
+	}
+	charges := []domain.Charges{input.Charges}
+	transports := []*domain.Transport{input.Transport}
+	if len(groups) > 1 {
+		weights := domain.SplitWeights(groups)
+		charges = input.Charges.Split(weights)
+		transports = input.Transport.Split(weights)
 	}
 
-	// Cria a nota fiscal
-	invoice := &domain.Invoice{
-		ID:        uuid.New().String(),
-		Number:    number,
-		Status:    domain.StatusOpen,
-		Items:     enrichedItems,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+	series := issuer.SeriesFor(model)
+	invoices := make([]*domain.Invoice, 0, len(groups))
+	for idx, groupItems := range groups {
+		// Dados de transporte, com a transportadora copiada do cadastro e o
+		// peso líquido calculado pelos pesos dos produtos quando não informado
+		transport, err := s.buildTransport(transports[idx], groupItems, issuer, recipient)
+		if err != nil {
+			return nil, err
+		}
+
+		// Condição de pagamento, copiada para não compartilhar o payload
+		var payment *domain.PaymentTerms
+		if input.Payment != nil {
+			terms := *input.Payment
+			terms.Normalize()
+			// Valida antes de gerar as parcelas, que dependem da quantidade
+			if err := terms.Validate(); err != nil {
+				return nil, err
+			}
+			payment = &terms
+		}
+
+		// Cria a nota fiscal
+		invoice := &domain.Invoice{
+			ID:           uuid.New().String(),
+			Model:        model,
+			Series:       series,
+			Status:       domain.StatusOpen,
+			SalesOrderID: input.SalesOrderID,
+			BackorderID:  input.BackorderID,
+			IssuerID:     issuer.ID,
+			CustomerID:   input.CustomerID,
+			Customer:     recipient,
+			Items:        groupItems,
+			Charges:      charges[idx],
+			Transport:    transport,
+			Payment:      payment,
+			CreatedBy:    input.User,
+			CreatedAt:    time.Now(),
+			UpdatedAt:    time.Now(),
+		}
+
+		// Calcula valores e tributos (legados, ISS e IBS/CBS) dos itens, com as
+		// despesas acessórias rateadas entre eles
+		invoice.ApplyTaxes(s.taxConfig, invoice.CreatedAt, issuer.CRT)
+
+		// Parcelas previstas; os vencimentos são recalculados no fechamento
+		invoice.ScheduleInstallments(invoice.CreatedAt)
+
+		// Valida a nota; o número é atribuído somente na gravação
+		if err := invoice.ValidateDraft(); err != nil {
+			return nil, err
+		}
+		invoices = append(invoices, invoice)
 	}
+	domain.LinkBatch(invoices, uuid.New().String())
 
-	// Valida a nota
-	if err := invoice.Validate(); err != nil {
+	// Verifica o limite de crédito do cliente contra o total do grupo
+	if err := s.checkCredit(recipient, input.CreditOverride, invoices...); err != nil {
 		return nil, err
 	}
 
-	// Persiste a nota
-	if err := s.repo.Create(invoice); err != nil {
-		return nil, fmt.Errorf("erro ao criar nota fiscal: %w", err)
+	// Notas que caem nas regras de aprovação ficam retidas até a decisão de
+	// outro usuário, por isso o criador precisa estar identificado
+	for idx, reasons := range s.approvals.EvaluateBatch(invoices) {
+		if len(reasons) == 0 {
+			continue
+		}
+		if input.User == "" {
+			return nil, domain.ErrApprovalUserRequired
+		}
+		invoices[idx].RequestApproval(reasons, invoices[idx].CreatedAt)
 	}
 
-	return invoice, nil
+	// Pendências das quantidades sem estoque, vinculadas à primeira nota
+	s.prepareBackorders(invoices[0], shortages)
+
+	// Gera os números sequenciais (isolados por emitente, modelo e série) só
+	// depois de todas as verificações, e persiste as notas
+	for _, invoice := range invoices {
+		number, err := s.repo.GetNextNumber(issuer.ID, model, series)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao gerar número da nota: %w", err)
+		}
+		invoice.Number = number
+		if err := s.repo.Create(invoice); err != nil {
+			return nil, fmt.Errorf("erro ao criar nota fiscal: %w", err)
+		}
+	}
+	if err := s.createBackorders(shortages); err != nil {
+		return nil, err
+	}
+
+	return invoices, nil
 }
 
 // GetInvoice busca uma nota fiscal por ID
 func (s *InvoiceService) GetInvoice(id string) (*domain.Invoice, error) {
-	return s.repo.FindByID(id)
+	invoice, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return invoice.WithInstallmentStatus(time.Now()), nil
+}
+
+// GetInvoiceByAccessKey busca uma nota fiscal pela chave de acesso, aceitando
+// a chave com espaços, pontuação ou o prefixo "NFe"
+func (s *InvoiceService) GetInvoiceByAccessKey(key string) (*domain.Invoice, error) {
+	parsed, err := domain.ParseAccessKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.FindByAccessKey(parsed.String())
+}
+
+// ValidateAccessKey valida uma chave de acesso recebida externamente e
+// retorna os campos que a compõem
+func (s *InvoiceService) ValidateAccessKey(key string) (domain.AccessKey, error) {
+	return domain.ParseAccessKey(key)
 }
 
 // GetAllInvoices retorna todas as notas fiscais
 func (s *InvoiceService) GetAllInvoices() ([]*domain.Invoice, error) {
-	return s.repo.FindAll()
+	invoices, err := s.repo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	for idx, invoice := range invoices {
+		invoices[idx] = invoice.WithInstallmentStatus(now)
+	}
+	return invoices, nil
 }
 
 // PrintInvoice "imprime" a nota fiscal (fecha e atualiza estoque)
-// Esta é a operação mais crítica do sistema
-func (s *InvoiceService) PrintInvoice(id string) (*domain.Invoice, error) {
+// Esta é a operação mais crítica do sistema. A liberação de crédito é usada
+// apenas quando a nota excede o limite do cliente
+func (s *InvoiceService) PrintInvoice(id string, override *CreditOverrideInput) (*domain.Invoice, error) {
 	// Busca a nota fiscal
 	invoice, err := s.repo.FindByID(id)
 	if err != nil {
 		return nil, err
 	}
 
-	// Verifica se a nota pode ser impressa
-	if !invoice.CanBePrinted() {
+	// Verifica se a nota pode ser impressa; notas retidas dependem da aprovação
+	switch {
+	case invoice.IsAwaitingApproval():
+		return nil, domain.ErrInvoiceAwaitingApproval
+	case invoice.IsDisapproved():
+		return nil, domain.ErrInvoiceDisapproved
+	case !invoice.CanBePrinted():
 		return nil, domain.ErrCannotPrintOpenInvoice
 	}
 
-	// Reserva os produtos no Stock Service
-	// IMPORTANTE: Esta operação deve ser idempotente em produção
-	err = s.stockClient.ReserveProducts(invoice.Items)
+	// Carrega o emitente para registrar seus dados na nota fechada
+	issuer, err := s.issuerRepo.FindByID(invoice.IssuerID)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao reservar produtos: %w", err)
+		return nil, err
 	}
 
-	// Fecha a nota fiscal
-	if err := invoice.Close(); err != nil {
+	// Verifica novamente o limite de crédito, com o cadastro e os recebíveis
+	// atuais do cliente
+	if invoice.CustomerID != "" {
+		customer, err := s.customerRepo.FindByID(invoice.CustomerID)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.checkCredit(customer, override, invoice); err != nil {
+			return nil, err
+		}
+	}
+
+	// Em contingência a nota é emitida com a forma de emissão e a justificativa
+	// vigentes, sem consulta à SEFAZ. A contingência off-line da NF-e não se
+	// aplica à NFC-e, e a NFS-e não passa pela SEFAZ
+	var contingency *domain.Contingency
+	if !invoice.IsService() {
+		contingency, err = s.contingency.Get()
+		if err != nil {
+			return nil, fmt.Errorf("erro ao consultar contingência: %w", err)
+		}
+	}
+	if contingency != nil && invoice.IsConsumer() {
+		return nil, domain.ErrConsumerContingency
+	}
+
+	// Confere o saldo a devolver da nota original antes de repor o estoque
+	var original *domain.Invoice
+	if invoice.IsReturn() {
+		if original, err = s.returnedInvoice(invoice); err != nil {
+			return nil, err
+		}
+	}
+
+	// Fecha uma cópia da nota com o snapshot do emitente antes de movimentar o
+	// estoque: uma nota que não pode ser fechada não baixa nem repõe saldo
+	closed := *invoice
+	closed.Items = slices.Clone(invoice.Items)
+	closed.Contingency = contingency
+	if err := closed.Close(issuer); err != nil {
+		return nil, err
+	}
+	if closed.Contingency != nil {
+		closed.AwaitTransmission()
+	}
+
+	// Baixa as mercadorias no Stock Service, ou repõe as devolvidas
+	// IMPORTANTE: Esta operação deve ser idempotente em produção
+	if err := s.moveStock(&closed); err != nil {
 		return nil, err
 	}
 
-	// Atualiza no repositório
-	if err := s.repo.Update(invoice); err != nil {
-		return nil, fmt.Errorf("erro ao atualizar nota fiscal: %w", err)
+	// Atualiza no repositório; sem a gravação, o movimento de estoque é
+	// estornado e a nota continua aberta para nova impressão
+	if err := s.repo.Update(&closed); err != nil {
+		err = fmt.Errorf("erro ao atualizar nota fiscal: %w", err)
+		if revertErr := s.revertStock(&closed); revertErr != nil {
+			return nil, errors.Join(err, revertErr)
+		}
+		return nil, err
 	}
+	invoice = &closed
 
+	// A quantidade impressa deixa de ser reservada pelo pedido de venda e pela
+	// pendência de entrega
+	if err := s.registerOrderDelivery(invoice); err != nil {
+		return nil, err
+	}
+	if err := s.registerBackorderDelivery(invoice); err != nil {
+		return nil, err
+	}
+
+	// Abate a devolução do saldo a devolver da nota original
+	if original != nil {
+		if err := original.RegisterReturn(invoice); err != nil {
+			return nil, err
+		}
+		original.UpdatedAt = time.Now()
+		if err := s.repo.Update(original); err != nil {
+			return nil, fmt.Errorf("erro ao atualizar nota original: %w", err)
+		}
+	}
+
+	if invoice.Contingency != nil {
+		if err := s.queue.Enqueue(&domain.QueuedTransmission{
+			InvoiceID:  invoice.ID,
+			Number:     invoice.Number,
+			EnqueuedAt: time.Now(),
+		}); err != nil {
+			return nil, fmt.Errorf("erro ao enfileirar nota para transmissão: %w", err)
+		}
+		return invoice, nil
+	}
+
+	// Solicita a autorização de uso na SEFAZ. Falhas de comunicação não desfazem
+	// o fechamento: o motivo fica registrado na nota, que permanece pendente e
+	// pode ser reenviada por AuthorizeInvoice
+	if s.authority != nil && !invoice.IsService() {
+		s.requestAuthorization(invoice)
+	}
+
+	return invoice, nil
+}
+
+// AuthorizeInvoice (re)envia à SEFAZ uma nota fechada pendente ou rejeitada.
+// Se o lote anterior ainda estava em processamento, apenas o recibo é consultado
+func (s *InvoiceService) AuthorizeInvoice(id string) (*domain.Invoice, error) {
+	if s.authority == nil {
+		return nil, domain.ErrSEFAZNotConfigured
+	}
+
+	invoice, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := invoice.CanRequestAuthorization(); err != nil {
+		return nil, err
+	}
+
+	if err := s.requestAuthorization(invoice); err != nil {
+		return invoice, err
+	}
 	return invoice, nil
 }
 
+// requestAuthorization envia o XML assinado (ou consulta o recibo pendente) e
+// registra o retorno da SEFAZ na nota
+func (s *InvoiceService) requestAuthorization(invoice *domain.Invoice) error {
+	var result *domain.Authorization
+	var err error
+
+	current := invoice.Authorization
+	if current != nil && current.Status == domain.AuthorizationPending && current.Receipt != "" {
+		result, err = s.authority.QueryReceipt(current.Receipt)
+	} else {
+		var document []byte
+		document, err = nfe.Generate(invoice, s.nfeConfig)
+		if err == nil {
+			result, err = s.authority.Authorize(document)
+		}
+	}
+
+	if result == nil {
+		result = &domain.Authorization{Status: domain.AuthorizationPending}
+		if current != nil {
+			result.Receipt = current.Receipt
+		}
+	}
+	if err != nil {
+		result.Reason = err.Error()
+	}
+	invoice.RecordAuthorization(*result)
+
+	if updateErr := s.repo.Update(invoice); updateErr != nil {
+		return fmt.Errorf("erro ao atualizar nota fiscal: %w", updateErr)
+	}
+
+	// Notas de contingência com retorno definitivo deixam a fila de transmissão
+	if invoice.Contingency != nil && result.Status != domain.AuthorizationPending {
+		if removeErr := s.queue.Remove(invoice.ID); removeErr != nil && !errors.Is(removeErr, domain.ErrTransmissionNotQueued) {
+			return fmt.Errorf("erro ao atualizar fila de transmissão: %w", removeErr)
+		}
+	}
+	return err
+}
+
+// EnterContingency passa a emitir as notas em contingência off-line, com a
+// forma de emissão e a justificativa informadas
+func (s *InvoiceService) EnterContingency(emissionType domain.EmissionType, justification string) (*domain.Contingency, error) {
+	if s.authority == nil {
+		return nil, domain.ErrSEFAZNotConfigured
+	}
+	contingency, err := domain.NewContingency(emissionType, justification, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.contingency.Save(contingency); err != nil {
+		return nil, fmt.Errorf("erro ao registrar contingência: %w", err)
+	}
+	return contingency, nil
+}
+
+// LeaveContingency retorna à emissão normal. As notas já emitidas em
+// contingência continuam na fila até serem transmitidas
+func (s *InvoiceService) LeaveContingency() error {
+	current, err := s.contingency.Get()
+	if err != nil {
+		return err
+	}
+	if current == nil {
+		return domain.ErrContingencyInactive
+	}
+	return s.contingency.Clear()
+}
+
+// GetContingency retorna a contingência vigente (nil em emissão normal)
+func (s *InvoiceService) GetContingency() (*domain.Contingency, error) {
+	return s.contingency.Get()
+}
+
+// GetTransmissionQueue lista as notas de contingência aguardando transmissão
+func (s *InvoiceService) GetTransmissionQueue() ([]*domain.QueuedTransmission, error) {
+	return s.queue.Pending()
+}
+
+// TransmitQueue transmite as notas da fila em ordem de emissão e registra o
+// retorno da SEFAZ em cada uma. Lotes ainda em processamento permanecem na
+// fila para consulta do recibo; se a SEFAZ continuar indisponível, a rodada é
+// interrompida e ErrSEFAZUnavailable é retornado
+func (s *InvoiceService) TransmitQueue() (int, error) {
+	if s.authority == nil {
+		return 0, domain.ErrSEFAZNotConfigured
+	}
+	s.transmitMu.Lock()
+	defer s.transmitMu.Unlock()
+
+	entries, err := s.queue.Pending()
+	if err != nil {
+		return 0, err
+	}
+
+	transmitted := 0
+	for _, entry := range entries {
+		invoice, err := s.repo.FindByID(entry.InvoiceID)
+		if err == nil {
+			err = invoice.CanRequestAuthorization()
+		}
+		if err != nil {
+			// Nota inexistente ou já resolvida (ex.: reenviada manualmente)
+			if removeErr := s.queue.Remove(entry.InvoiceID); removeErr != nil && !errors.Is(removeErr, domain.ErrTransmissionNotQueued) {
+				return transmitted, removeErr
+			}
+			continue
+		}
+
+		now := time.Now()
+		entry.Attempts++
+		entry.LastAttemptAt = &now
+		entry.LastError = ""
+
+		err = s.requestAuthorization(invoice)
+		if invoice.Authorization.Status != domain.AuthorizationPending {
+			transmitted++
+		} else {
+			if err != nil {
+				entry.LastError = err.Error()
+			}
+			if updateErr := s.queue.Update(entry); updateErr != nil {
+				return transmitted, updateErr
+			}
+		}
+		if errors.Is(err, domain.ErrSEFAZUnavailable) {
+			return transmitted, err
+		}
+	}
+	return transmitted, nil
+}
+
+// GenerateXML gera o XML da NF-e de uma nota fechada, assinado com o
+// certificado do emitente e validado contra os schemas do leiaute 4.00
+func (s *InvoiceService) GenerateXML(id string) ([]byte, error) {
+	invoice, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if !invoice.IsClosed() {
+		return nil, domain.ErrInvoiceNotClosed
+	}
+	if invoice.IsService() {
+		return nil, domain.ErrServiceInvoice
+	}
+
+	return nfe.Generate(invoice, s.nfeConfig)
+}
+
+// GenerateNFSe gera o XML da DPS (padrão nacional da NFS-e) de uma nota de
+// serviço fechada, assinado com o certificado do emitente e validado contra o
+// leiaute 1.00
+func (s *InvoiceService) GenerateNFSe(id string) ([]byte, error) {
+	invoice, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if !invoice.IsClosed() {
+		return nil, domain.ErrInvoiceNotClosed
+	}
+
+	return nfse.Generate(invoice, s.nfeConfig)
+}
+
+// GenerateDANFE gera o DANFE em PDF de uma nota fechada (A4 para a NF-e,
+// bobina de 80 mm para a NFC-e). Notas sem autorização da SEFAZ saem marcadas
+// como sem valor fiscal
+func (s *InvoiceService) GenerateDANFE(id string) ([]byte, error) {
+	invoice, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if !invoice.IsClosed() {
+		return nil, domain.ErrInvoiceNotClosed
+	}
+	if invoice.IsService() {
+		return nil, domain.ErrServiceInvoice
+	}
+
+	document, err := nfe.Build(invoice, s.nfeConfig)
+	if err != nil {
+		return nil, err
+	}
+	if invoice.IsConsumer() {
+		return danfe.RenderConsumer(document, invoice.Authorization)
+	}
+	return danfe.Render(document, invoice.Authorization)
+}
+
+// VerifySignature verifica a assinatura digital de um XML de NF-e, de evento
+// ou de DPS e retorna o certificado do signatário
+func (s *InvoiceService) VerifySignature(document []byte) (*x509.Certificate, error) {
+	return nfe.Verify(document)
+}
+
 // ValidateInvoiceItems valida se os itens podem ser adicionados à nota
 func (s *InvoiceService) ValidateInvoiceItems(items []domain.InvoiceItem) error {
 	for _, item := range items {
 		if item.Quantity <= 0 {
 			return domain.ErrInvalidQuantity
 		}
+		// Serviços não dependem do estoque
+		if item.IsService() {
+			continue
+		}
 
 		// Verifica se o produto existe
 		_, err := s.stockClient.GetProduct(item.ProductID)
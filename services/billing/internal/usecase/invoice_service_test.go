@@ -0,0 +1,310 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/nfe"
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/repo/mem"
+)
+
+// fakeStock simula o Stock Service em memória
+type fakeStock struct {
+	mu       sync.Mutex
+	products map[string]*domain.ProductInfo
+	latency  time.Duration // Atraso das consultas, que simula a chamada de rede
+}
+
+func newFakeStock(balances map[string]int) *fakeStock {
+	stock := &fakeStock{products: make(map[string]*domain.ProductInfo)}
+	for id, balance := range balances {
+		stock.products[id] = &domain.ProductInfo{
+			ID:          id,
+			Code:        id,
+			Description: "Produto " + id,
+			Balance:     balance,
+			NCM:         "84713012",
+			Unit:        "UN",
+			NetWeight:   0.5,
+		}
+	}
+	return stock
+}
+
+func (f *fakeStock) ReserveProducts(items []domain.InvoiceItem) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	requested := make(map[string]int)
+	for _, item := range items {
+		requested[item.ProductID] += item.Quantity
+	}
+	for id, quantity := range requested {
+		product, exists := f.products[id]
+		if !exists || product.Balance < quantity {
+			return fmt.Errorf("%w: falha ao reservar produto %s", domain.ErrInsufficientStock, id)
+		}
+	}
+	for id, quantity := range requested {
+		f.products[id].Balance -= quantity
+	}
+	return nil
+}
+
+func (f *fakeStock) RestockProducts(items []domain.InvoiceItem) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, item := range items {
+		f.products[item.ProductID].Balance += item.Quantity
+	}
+	return nil
+}
+
+func (f *fakeStock) CheckAvailability(productID string, quantity int) (bool, error) {
+	product, err := f.GetProduct(productID)
+	if err != nil {
+		return false, err
+	}
+	return product.Balance >= quantity, nil
+}
+
+func (f *fakeStock) GetProduct(productID string) (*domain.ProductInfo, error) {
+	time.Sleep(f.latency)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	product, exists := f.products[productID]
+	if !exists {
+		return nil, errors.New("produto não encontrado")
+	}
+	copied := *product
+	return &copied, nil
+}
+
+// testFixture reúne os serviços de notas, pedidos e pendências e os
+// repositórios em memória, com o emitente "issuer-1" e os clientes
+// "customer-1" (sem limite de crédito) e "customer-limit" (limite de R$ 100,00)
+type testFixture struct {
+	service          *InvoiceService
+	orderService     *SalesOrderService
+	backorderService *BackorderService
+	invoices         *mem.InvoiceMemRepository
+	orders           *mem.SalesOrderMemRepository
+	backorders       *mem.BackorderMemRepository
+	stock            *fakeStock
+}
+
+func newTestFixture(t *testing.T, approvals domain.ApprovalPolicy, balances map[string]int) *testFixture {
+	t.Helper()
+	address := domain.Address{
+		Street:   "Avenida Paulista",
+		Number:   "1000",
+		District: "Bela Vista",
+		CityCode: "3550308",
+		City:     "São Paulo",
+		UF:       "SP",
+		CEP:      "01310100",
+	}
+	issuers := mem.NewIssuerMemRepository()
+	if err := issuers.Create(&domain.Issuer{
+		ID:                "issuer-1",
+		Name:              "EMPRESA TESTE LTDA",
+		CNPJ:              "11222333000181",
+		StateRegistration: "110042490114",
+		CRT:               domain.TaxRegimeNormal,
+		Series:            1,
+		Address:           address,
+	}); err != nil {
+		t.Fatalf("erro ao cadastrar emitente: %v", err)
+	}
+
+	limit := domain.Money(10000)
+	customers := mem.NewCustomerMemRepository()
+	for _, customer := range []*domain.Customer{
+		{ID: "customer-1", Name: "CLIENTE TESTE SA", Document: "11444777000161", DocumentType: domain.DocumentCNPJ, Address: address},
+		{ID: "customer-limit", Name: "CLIENTE LIMITE", Document: "52998224725", DocumentType: domain.DocumentCPF, Address: address, CreditLimit: &limit},
+	} {
+		if err := customers.Create(customer); err != nil {
+			t.Fatalf("erro ao cadastrar cliente: %v", err)
+		}
+	}
+
+	fixture := &testFixture{
+		invoices:   mem.NewInvoiceMemRepository(),
+		orders:     mem.NewSalesOrderMemRepository(),
+		backorders: mem.NewBackorderMemRepository(),
+		stock:      newFakeStock(balances),
+	}
+	fixture.service = NewInvoiceService(fixture.invoices, customers, issuers, mem.NewServiceMemRepository(),
+		mem.NewCarrierMemRepository(), fixture.stock, domain.DefaultTaxConfig(), nfe.Config{}, nil,
+		mem.NewContingencyMemRepository(), mem.NewTransmissionQueueMemRepository(),
+		domain.CreditPolicy{Approvers: []string{"gerente"}}, approvals, fixture.orders, fixture.backorders)
+	fixture.orderService = NewSalesOrderService(fixture.orders, customers, issuers, fixture.stock, fixture.service)
+	fixture.backorderService = NewBackorderService(fixture.backorders, fixture.stock, fixture.service)
+	return fixture
+}
+
+// invoiceInput monta a criação de uma NF-e para o cliente com os itens
+// informados (produto e quantidade), a R$ 12,50 a unidade
+func invoiceInput(customerID string, quantities map[string]int) CreateInvoiceInput {
+	input := CreateInvoiceInput{IssuerID: "issuer-1", CustomerID: customerID}
+	for productID, quantity := range quantities {
+		input.Items = append(input.Items, domain.InvoiceItem{ProductID: productID, Quantity: quantity, UnitPrice: 1250})
+	}
+	return input
+}
+
+func TestCreateInvoiceNumbersOnlyAccepted(t *testing.T) {
+	fixture := newTestFixture(t, domain.ApprovalPolicy{TotalThreshold: 50000}, map[string]int{"P1": 100})
+
+	refused := []struct {
+		name  string
+		input CreateInvoiceInput
+		err   error
+	}{
+		{
+			name:  "acima do limite de crédito",
+			input: invoiceInput("customer-limit", map[string]int{"P1": 10}),
+			err:   domain.ErrCreditLimitExceeded,
+		},
+		{
+			name:  "aprovação sem usuário identificado",
+			input: invoiceInput("customer-1", map[string]int{"P1": 50}),
+			err:   domain.ErrApprovalUserRequired,
+		},
+		{
+			name: "despesas inválidas",
+			input: func() CreateInvoiceInput {
+				input := invoiceInput("customer-1", map[string]int{"P1": 1})
+				input.Charges.Freight = -100
+				return input
+			}(),
+		},
+	}
+	for _, tt := range refused {
+		if _, err := fixture.service.CreateInvoice(tt.input); err == nil || (tt.err != nil && !errors.Is(err, tt.err)) {
+			t.Fatalf("%s: CreateInvoice() erro = %v, esperado %v", tt.name, err, tt.err)
+		}
+	}
+
+	// As recusas não consomem numeração: a primeira nota aceita é a de número 1
+	for expected := 1; expected <= 2; expected++ {
+		invoice, err := fixture.service.CreateInvoice(invoiceInput("customer-1", map[string]int{"P1": 1}))
+		if err != nil {
+			t.Fatalf("CreateInvoice() erro inesperado: %v", err)
+		}
+		if invoice.Number != expected {
+			t.Errorf("CreateInvoice() número = %d, esperado %d", invoice.Number, expected)
+		}
+	}
+}
+
+func TestCreateInvoiceRejectsInstallmentsBeforeScheduling(t *testing.T) {
+	fixture := newTestFixture(t, domain.ApprovalPolicy{TotalThreshold: 50000}, map[string]int{"P1": 100})
+
+	// Uma quantidade fora do limite não pode chegar à geração das parcelas,
+	// que alocaria uma posição por parcela
+	input := invoiceInput("customer-1", map[string]int{"P1": 1})
+	input.Payment = &domain.PaymentTerms{Method: domain.PaymentBankSlip, Installments: 1 << 40, IntervalDays: 30}
+	if _, err := fixture.service.CreateInvoice(input); !errors.Is(err, domain.ErrInvalidInstallments) {
+		t.Fatalf("CreateInvoice() erro = %v, esperado %v", err, domain.ErrInvalidInstallments)
+	}
+
+	// O domínio também não gera parcelas acima do limite
+	invoice := &domain.Invoice{
+		Totals:  domain.InvoiceTotals{Total: 100000},
+		Payment: &domain.PaymentTerms{Method: domain.PaymentBankSlip, Installments: domain.MaxInstallments + 1, IntervalDays: 30},
+	}
+	invoice.ScheduleInstallments(time.Now())
+	if invoice.Installments != nil {
+		t.Errorf("ScheduleInstallments() gerou %d parcelas, esperado nenhuma", len(invoice.Installments))
+	}
+}
+
+// failingUpdates recusa a gravação das notas enquanto fail estiver ligado
+type failingUpdates struct {
+	domain.InvoiceRepository
+	fail bool
+}
+
+func (r *failingUpdates) Update(invoice *domain.Invoice) error {
+	if r.fail {
+		return errors.New("repositório indisponível")
+	}
+	return r.InvoiceRepository.Update(invoice)
+}
+
+func TestPrintInvoiceRevertsStockWhenNotSaved(t *testing.T) {
+	fixture := newTestFixture(t, domain.ApprovalPolicy{}, map[string]int{"P1": 100})
+	repo := &failingUpdates{InvoiceRepository: fixture.invoices}
+	fixture.service.repo = repo
+
+	balance := func() int {
+		product, err := fixture.stock.GetProduct("P1")
+		if err != nil {
+			t.Fatalf("GetProduct() erro inesperado: %v", err)
+		}
+		return product.Balance
+	}
+	// printAfterFailure imprime a nota com a gravação recusada e depois
+	// liberada, conferindo o saldo de P1 após cada tentativa
+	printAfterFailure := func(id string, failed, printed int) {
+		t.Helper()
+		repo.fail = true
+		if _, err := fixture.service.PrintInvoice(id, nil); err == nil {
+			t.Fatalf("PrintInvoice() sem erro com a gravação recusada")
+		}
+		stored, err := fixture.invoices.FindByID(id)
+		if err != nil {
+			t.Fatalf("FindByID() erro inesperado: %v", err)
+		}
+		if !stored.IsOpen() || balance() != failed {
+			t.Fatalf("após a falha: situação %s, saldo %d; esperado %s, %d", stored.Status, balance(), domain.StatusOpen, failed)
+		}
+
+		repo.fail = false
+		if _, err := fixture.service.PrintInvoice(id, nil); err != nil {
+			t.Fatalf("PrintInvoice() erro inesperado: %v", err)
+		}
+		if balance() != printed {
+			t.Fatalf("após a impressão: saldo %d, esperado %d", balance(), printed)
+		}
+	}
+
+	// Venda: a baixa é estornada e a nova impressão baixa uma única vez
+	sale, err := fixture.service.CreateInvoice(invoiceInput("customer-1", map[string]int{"P1": 10}))
+	if err != nil {
+		t.Fatalf("CreateInvoice() erro inesperado: %v", err)
+	}
+	printAfterFailure(sale.ID, 100, 90)
+
+	// Devolução: a reposição é estornada e a nova impressão repõe uma única vez
+	ret, err := fixture.service.CreateReturn(CreateReturnInput{
+		OriginalID: sale.ID,
+		Lines:      []domain.ReturnLine{{Item: 1, Quantity: 4}},
+	})
+	if err != nil {
+		t.Fatalf("CreateReturn() erro inesperado: %v", err)
+	}
+	printAfterFailure(ret.ID, 90, 94)
+
+	// A nota que não pode ser fechada não chega a movimentar o estoque
+	other, err := fixture.service.CreateInvoice(invoiceInput("customer-1", map[string]int{"P1": 5}))
+	if err != nil {
+		t.Fatalf("CreateInvoice() erro inesperado: %v", err)
+	}
+	issuer, err := fixture.service.issuerRepo.FindByID("issuer-1")
+	if err != nil {
+		t.Fatalf("FindByID() erro inesperado: %v", err)
+	}
+	issuer.Address.UF = "XX"
+	if _, err := fixture.service.PrintInvoice(other.ID, nil); err == nil {
+		t.Fatalf("PrintInvoice() sem erro com emitente de UF inválida")
+	}
+	if balance() != 94 {
+		t.Errorf("após a falha no fechamento: saldo %d, esperado 94", balance())
+	}
+}
@@ -0,0 +1,37 @@
+package usecase
+
+import "github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+
+// buildTransport prepara os dados de transporte de uma nova nota: copia a
+// transportadora do cadastro, calcula o peso líquido pelos pesos unitários dos
+// produtos quando não informado e confere o veículo conforme a rota
+func (s *InvoiceService) buildTransport(input *domain.Transport, items []domain.InvoiceItem, issuer *domain.Issuer, recipient *domain.Customer) (*domain.Transport, error) {
+	if input == nil {
+		return nil, nil
+	}
+
+	transport := *input
+	transport.Carrier = nil
+	transport.Normalize()
+
+	if transport.CarrierID != "" {
+		carrier, err := s.carriers.FindByID(transport.CarrierID)
+		if err != nil {
+			return nil, err
+		}
+		snapshot := *carrier
+		transport.Carrier = &snapshot
+	}
+
+	if transport.NetWeight == 0 {
+		if weight, ok := domain.ItemsNetWeight(items); ok {
+			transport.NetWeight = weight
+		}
+	}
+
+	interstate := recipient != nil && recipient.Address.UF != issuer.Address.UF
+	if err := transport.CheckRoute(interstate); err != nil {
+		return nil, err
+	}
+	return &transport, nil
+}
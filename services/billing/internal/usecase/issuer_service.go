@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+	"github.com/google/uuid"
+)
+
+// IssuerService contém a lógica de negócio dos emitentes (estabelecimentos)
+type IssuerService struct {
+	repo domain.IssuerRepository
+}
+
+// NewIssuerService cria uma nova instância do serviço
+func NewIssuerService(repo domain.IssuerRepository) *IssuerService {
+	return &IssuerService{
+		repo: repo,
+	}
+}
+
+// CreateIssuer cadastra um novo estabelecimento emitente
+func (s *IssuerService) CreateIssuer(issuer domain.Issuer) (*domain.Issuer, error) {
+	if issuer.ID == "" {
+		issuer.ID = uuid.New().String()
+	}
+	issuer.CreatedAt = time.Now()
+	issuer.UpdatedAt = issuer.CreatedAt
+
+	issuer.Normalize()
+	if err := issuer.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(&issuer); err != nil {
+		return nil, err
+	}
+	return &issuer, nil
+}
+
+// GetIssuer busca um emitente por ID
+func (s *IssuerService) GetIssuer(id string) (*domain.Issuer, error) {
+	return s.repo.FindByID(id)
+}
+
+// GetAllIssuers retorna todos os emitentes
+func (s *IssuerService) GetAllIssuers() ([]*domain.Issuer, error) {
+	return s.repo.FindAll()
+}
+
+// UpdateIssuer atualiza os dados de um emitente. Notas já fechadas mantêm
+// o snapshot do emitente do momento do fechamento
+func (s *IssuerService) UpdateIssuer(id string, data domain.Issuer) (*domain.Issuer, error) {
+	existing, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := data
+	updated.ID = existing.ID
+	updated.CreatedAt = existing.CreatedAt
+	updated.UpdatedAt = time.Now()
+
+	updated.Normalize()
+	if err := updated.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(&updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
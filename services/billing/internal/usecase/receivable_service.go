@@ -0,0 +1,243 @@
+package usecase
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/boleto"
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/pix"
+	"github.com/google/uuid"
+)
+
+// ReceivableService contém a lógica de negócio das parcelas a receber:
+// listagem, registro de recebimentos, cobranças PIX, boletos e relatório de
+// vencimentos
+type ReceivableService struct {
+	repo       domain.InvoiceRepository
+	issuerRepo domain.IssuerRepository
+	lateFees   domain.LateFeeConfig
+	pixConfig  pix.Config
+	mu         sync.Mutex // Serializa os recebimentos, que alteram o saldo das parcelas
+}
+
+// NewReceivableService cria uma nova instância do serviço
+func NewReceivableService(repo domain.InvoiceRepository, issuerRepo domain.IssuerRepository, lateFees domain.LateFeeConfig, pixConfig pix.Config) *ReceivableService {
+	return &ReceivableService{
+		repo:       repo,
+		issuerRepo: issuerRepo,
+		lateFees:   lateFees,
+		pixConfig:  pixConfig,
+	}
+}
+
+// ReceivableFilter restringe a listagem de recebíveis. Datas zeradas não
+// limitam o período; o intervalo inclui os dois extremos
+type ReceivableFilter struct {
+	From       time.Time
+	To         time.Time
+	CustomerID string
+	Status     domain.InstallmentStatus
+}
+
+// GetReceivables lista as parcelas a receber das notas emitidas, com a
+// situação na data atual, ordenadas pelo vencimento
+func (s *ReceivableService) GetReceivables(filter ReceivableFilter) ([]domain.Receivable, error) {
+	all, err := s.receivables(time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	receivables := []domain.Receivable{}
+	for _, receivable := range all {
+		if filter.CustomerID != "" && receivable.CustomerID != filter.CustomerID {
+			continue
+		}
+		if filter.Status != "" && receivable.Status != filter.Status {
+			continue
+		}
+		if !filter.From.IsZero() && receivable.DueDate.Before(filter.From) {
+			continue
+		}
+		if !filter.To.IsZero() && receivable.DueDate.After(filter.To) {
+			continue
+		}
+		receivables = append(receivables, receivable)
+	}
+	return receivables, nil
+}
+
+// GetAging monta o relatório de vencimentos (aging) do saldo em aberto na
+// data de referência
+func (s *ReceivableService) GetAging(date time.Time) (*domain.AgingReport, error) {
+	receivables, err := s.receivables(date)
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewAgingReport(receivables, date), nil
+}
+
+// RegisterPayment registra um recebimento contra as parcelas de uma nota
+// emitida. Sem data, o recebimento é registrado na data atual
+func (s *ReceivableService) RegisterPayment(invoiceID string, input domain.PaymentInput) (*domain.PaymentRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	invoice, err := s.repo.FindByID(invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	input.ID = uuid.New().String()
+	if input.PaidAt.IsZero() {
+		input.PaidAt = time.Now()
+	}
+	if input.Method == "" && invoice.Payment != nil {
+		input.Method = invoice.Payment.Method
+	}
+
+	// Registra sobre uma cópia, gravada por inteiro: a nota armazenada pode
+	// estar sendo lida por uma consulta
+	updated := invoice.WithInstallmentStatus(time.Now())
+	updated.Payments = slices.Clone(invoice.Payments)
+	record, err := updated.RegisterPayment(input, s.lateFees)
+	if err != nil {
+		return nil, err
+	}
+	updated.RefreshInstallments(time.Now())
+	updated.UpdatedAt = time.Now()
+
+	if err := s.repo.Update(updated); err != nil {
+		return nil, fmt.Errorf("erro ao registrar recebimento: %w", err)
+	}
+	return record, nil
+}
+
+// GetPayments lista os recebimentos registrados para a nota
+func (s *ReceivableService) GetPayments(invoiceID string) ([]domain.PaymentRecord, error) {
+	invoice, err := s.repo.FindByID(invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	if invoice.Payments == nil {
+		return []domain.PaymentRecord{}, nil
+	}
+	return invoice.Payments, nil
+}
+
+// GetPixCharge monta a cobrança PIX do valor devido hoje, com encargos, da
+// parcela informada ou, sem ela, de todas as parcelas em aberto da nota. A
+// chave PIX vem do cadastro atual do emitente
+func (s *ReceivableService) GetPixCharge(invoiceID, installment string, mode domain.PixMode) (*domain.PixCharge, error) {
+	invoice, err := s.repo.FindByID(invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	amount, err := invoice.AmountDue(installment, time.Now(), s.lateFees)
+	if err != nil {
+		return nil, err
+	}
+	issuer, err := s.issuerRepo.FindByID(invoice.IssuerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return pix.NewCharge(invoice, issuer, installment, amount, mode, s.pixConfig)
+}
+
+// GetBankSlip monta o boleto de uma parcela em aberto com a conta de cobrança
+// atual do emitente
+func (s *ReceivableService) GetBankSlip(invoiceID, installment string) (*domain.BankSlip, error) {
+	document, err := s.bankSlip(invoiceID, installment)
+	if err != nil {
+		return nil, err
+	}
+	return document.Slip, nil
+}
+
+// GenerateBankSlipPDF gera o boleto de uma parcela em aberto em PDF
+func (s *ReceivableService) GenerateBankSlipPDF(invoiceID, installment string) ([]byte, error) {
+	document, err := s.bankSlip(invoiceID, installment)
+	if err != nil {
+		return nil, err
+	}
+	return boleto.Render(*document)
+}
+
+// bankSlip reúne os dados do boleto da parcela: código de barras, conta de
+// cobrança, beneficiário, pagador e instruções de multa e juros
+func (s *ReceivableService) bankSlip(invoiceID, number string) (*boleto.Document, error) {
+	invoice, err := s.repo.FindByID(invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	installment, err := invoice.OpenInstallment(number)
+	if err != nil {
+		return nil, err
+	}
+	issuer, err := s.issuerRepo.FindByID(invoice.IssuerID)
+	if err != nil {
+		return nil, err
+	}
+	if issuer.BankSlip == nil {
+		return nil, domain.ErrBankSlipNotConfigured
+	}
+
+	slip, err := boleto.Generate(*issuer.BankSlip, invoice, *installment)
+	if err != nil {
+		return nil, err
+	}
+
+	instructions := []string{fmt.Sprintf("Referente à nota fiscal %d, série %d, parcela %s", invoice.Number, invoice.Series, installment.Number)}
+	if s.lateFees.PenaltyRate > 0 {
+		instructions = append(instructions, fmt.Sprintf("Após o vencimento, cobrar multa de %.2f%%", s.lateFees.PenaltyRate))
+	}
+	if s.lateFees.MonthlyInterestRate > 0 {
+		instructions = append(instructions, fmt.Sprintf("Após o vencimento, cobrar juros de mora de %.2f%% ao mês", s.lateFees.MonthlyInterestRate))
+	}
+	issuedAt := invoice.CreatedAt
+	if invoice.ClosedAt != nil {
+		issuedAt = *invoice.ClosedAt
+	}
+
+	return &boleto.Document{
+		Slip:         slip,
+		Account:      *issuer.BankSlip,
+		Beneficiary:  issuer,
+		Payer:        invoice.Customer,
+		IssuedAt:     issuedAt,
+		Instructions: instructions,
+	}, nil
+}
+
+// receivables reúne as parcelas das notas emitidas com a situação na data
+// informada, ordenadas pelo vencimento
+func (s *ReceivableService) receivables(date time.Time) ([]domain.Receivable, error) {
+	invoices, err := s.repo.FindAll()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar notas fiscais: %w", err)
+	}
+
+	var receivables []domain.Receivable
+	for _, invoice := range invoices {
+		for _, receivable := range invoice.Receivables() {
+			receivable.Status = receivable.StatusAt(date)
+			receivables = append(receivables, receivable)
+		}
+	}
+
+	sort.Slice(receivables, func(a, b int) bool {
+		ra, rb := receivables[a], receivables[b]
+		if !ra.DueDate.Equal(rb.DueDate) {
+			return ra.DueDate.Before(rb.DueDate)
+		}
+		if ra.InvoiceNumber != rb.InvoiceNumber {
+			return ra.InvoiceNumber < rb.InvoiceNumber
+		}
+		return ra.Number < rb.Number
+	})
+	return receivables, nil
+}
@@ -0,0 +1,60 @@
+package usecase
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/pix"
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/repo/mem"
+)
+
+func TestRegisterPaymentConcurrentReads(t *testing.T) {
+	fixture := newTestFixture(t, domain.ApprovalPolicy{}, map[string]int{"P1": 100})
+	receivables := NewReceivableService(fixture.invoices, mem.NewIssuerMemRepository(), domain.DefaultLateFeeConfig(), pix.Config{})
+
+	input := invoiceInput("customer-1", map[string]int{"P1": 8})
+	input.Payment = &domain.PaymentTerms{Method: domain.PaymentBankSlip, Installments: 2, IntervalDays: 30}
+	invoice, err := fixture.service.CreateInvoice(input)
+	if err != nil {
+		t.Fatalf("CreateInvoice() erro inesperado: %v", err)
+	}
+	if _, err := fixture.service.PrintInvoice(invoice.ID, nil); err != nil {
+		t.Fatalf("PrintInvoice() erro inesperado: %v", err)
+	}
+
+	// As consultas calculam a situação das parcelas sem alterar a nota que os
+	// recebimentos atualizam; com -race, qualquer escrita compartilhada falha
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if _, err := receivables.RegisterPayment(invoice.ID, domain.PaymentInput{Amount: 100}); err != nil {
+				t.Errorf("RegisterPayment() erro inesperado: %v", err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if _, err := fixture.service.GetInvoice(invoice.ID); err != nil {
+				t.Errorf("GetInvoice() erro inesperado: %v", err)
+			}
+			if _, err := fixture.service.GetAllInvoices(); err != nil {
+				t.Errorf("GetAllInvoices() erro inesperado: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	stored, err := fixture.service.GetInvoice(invoice.ID)
+	if err != nil {
+		t.Fatalf("GetInvoice() erro inesperado: %v", err)
+	}
+	var paid domain.Money
+	for _, installment := range stored.Installments {
+		paid += installment.Paid
+	}
+	if paid != 1000 || len(stored.Payments) != 10 {
+		t.Errorf("recebido = %d em %d registros, esperado 1000 em 10", paid, len(stored.Payments))
+	}
+}
@@ -0,0 +1,290 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+	"github.com/google/uuid"
+)
+
+// SalesOrderService contém a lógica de negócio dos pedidos de venda
+type SalesOrderService struct {
+	repo         domain.SalesOrderRepository
+	customerRepo domain.CustomerRepository
+	issuerRepo   domain.IssuerRepository
+	stockClient  domain.StockClient
+	invoices     *InvoiceService // Gera as notas dos pedidos
+}
+
+// NewSalesOrderService cria uma nova instância do serviço
+func NewSalesOrderService(repo domain.SalesOrderRepository, customerRepo domain.CustomerRepository, issuerRepo domain.IssuerRepository, stockClient domain.StockClient, invoices *InvoiceService) *SalesOrderService {
+	return &SalesOrderService{
+		repo:         repo,
+		customerRepo: customerRepo,
+		issuerRepo:   issuerRepo,
+		stockClient:  stockClient,
+		invoices:     invoices,
+	}
+}
+
+// CreateSalesOrderInput agrupa os dados de um novo pedido de venda
+type CreateSalesOrderInput struct {
+	IssuerID   string
+	CustomerID string
+	Lines      []domain.SalesOrderLine // Produto, quantidade e preço unitário
+	Payment    *domain.PaymentTerms
+	Notes      string
+	User       string // Usuário autenticado que cria o pedido
+}
+
+// GenerateInvoiceInput agrupa os dados da nota gerada por um pedido. Sem
+// linhas, todo o saldo a faturar do pedido é incluído
+type GenerateInvoiceInput struct {
+	Lines          []domain.OrderLine
+	Model          string
+	Charges        domain.Charges
+	Transport      *domain.Transport
+	CreditOverride *CreditOverrideInput
+	User           string
+}
+
+// CreateSalesOrder cria um pedido em rascunho, com a descrição dos produtos
+// copiada do Stock Service. O estoque só é reservado na confirmação
+func (s *SalesOrderService) CreateSalesOrder(input CreateSalesOrderInput) (*domain.SalesOrder, error) {
+	if input.IssuerID == "" {
+		return nil, domain.ErrIssuerRequired
+	}
+	if _, err := s.issuerRepo.FindByID(input.IssuerID); err != nil {
+		return nil, err
+	}
+	if input.CustomerID == "" {
+		return nil, domain.ErrCustomerRequired
+	}
+	if _, err := s.customerRepo.FindByID(input.CustomerID); err != nil {
+		return nil, err
+	}
+	if len(input.Lines) == 0 {
+		return nil, domain.ErrSalesOrderNoLines
+	}
+
+	lines := make([]domain.SalesOrderLine, 0, len(input.Lines))
+	for idx, line := range input.Lines {
+		if line.ProductID == "" || line.Quantity <= 0 {
+			return nil, domain.ErrInvalidQuantity
+		}
+		product, err := s.stockClient.GetProduct(line.ProductID)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao buscar produto %s: %w", line.ProductID, err)
+		}
+		lines = append(lines, domain.SalesOrderLine{
+			Number:      idx + 1,
+			ProductID:   line.ProductID,
+			ProductCode: product.Code,
+			Description: product.Description,
+			Quantity:    line.Quantity,
+			UnitPrice:   line.UnitPrice,
+		})
+	}
+
+	var payment *domain.PaymentTerms
+	if input.Payment != nil {
+		terms := *input.Payment
+		terms.Normalize()
+		payment = &terms
+	}
+
+	number, err := s.repo.GetNextNumber()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao gerar número do pedido: %w", err)
+	}
+
+	now := time.Now()
+	order := &domain.SalesOrder{
+		ID:         uuid.New().String(),
+		Number:     number,
+		Status:     domain.OrderDraft,
+		IssuerID:   input.IssuerID,
+		CustomerID: input.CustomerID,
+		Lines:      lines,
+		Payment:    payment,
+		Notes:      input.Notes,
+		CreatedBy:  input.User,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := order.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(order); err != nil {
+		return nil, fmt.Errorf("erro ao criar pedido de venda: %w", err)
+	}
+	return order, nil
+}
+
+// GetSalesOrder busca um pedido de venda por ID
+func (s *SalesOrderService) GetSalesOrder(id string) (*domain.SalesOrder, error) {
+	return s.repo.FindByID(id)
+}
+
+// GetAllSalesOrders retorna todos os pedidos de venda
+func (s *SalesOrderService) GetAllSalesOrders() ([]*domain.SalesOrder, error) {
+	return s.repo.FindAll()
+}
+
+// ConfirmSalesOrder confirma o pedido, reservando as quantidades das linhas.
+// O saldo de cada produto, descontadas as demais reservas, deve
+// comportar o pedido inteiro
+func (s *SalesOrderService) ConfirmSalesOrder(id string) (*domain.SalesOrder, error) {
+	// A conferência e a gravação da reserva não podem intercalar com outra
+	// reserva, que contaria o mesmo saldo livre
+	s.invoices.holdMu.Lock()
+	defer s.invoices.holdMu.Unlock()
+
+	order, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != domain.OrderDraft {
+		return nil, domain.ErrOrderNotDraft
+	}
+
+	held, err := s.invoices.heldStock(order.ID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	requested := make(map[string]int, len(order.Lines))
+	for _, line := range order.Lines {
+		requested[line.ProductID] += line.Quantity
+	}
+	for _, line := range order.Lines {
+		quantity, pending := requested[line.ProductID]
+		if !pending {
+			continue
+		}
+		delete(requested, line.ProductID)
+
+		product, err := s.stockClient.GetProduct(line.ProductID)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao buscar produto %s: %w", line.ProductID, err)
+		}
+		if available := product.Balance - held[line.ProductID]; available < quantity {
+			return nil, fmt.Errorf("%w: produto %s (disponível: %d, solicitado: %d)",
+				domain.ErrOrderInsufficientStock, product.Code, available, quantity)
+		}
+	}
+
+	if err := order.Confirm(time.Now()); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(order); err != nil {
+		return nil, fmt.Errorf("erro ao atualizar pedido de venda: %w", err)
+	}
+	return order, nil
+}
+
+// CancelSalesOrder cancela o pedido, liberando a reserva do saldo não faturado
+func (s *SalesOrderService) CancelSalesOrder(id string) (*domain.SalesOrder, error) {
+	order, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := order.Cancel(time.Now()); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(order); err != nil {
+		return nil, fmt.Errorf("erro ao atualizar pedido de venda: %w", err)
+	}
+	return order, nil
+}
+
+// GenerateInvoice gera a nota a partir das linhas do pedido confirmado, com
+// os preços e a condição de pagamento do pedido. As quantidades faturadas são
+// abatidas do saldo a faturar das linhas. Acima do limite de itens, retorna
+// todas as notas do grupo, registradas no pedido
+func (s *SalesOrderService) GenerateInvoice(id string, input GenerateInvoiceInput) ([]*domain.Invoice, error) {
+	// A conferência do saldo a faturar, a criação das notas e o registro no
+	// pedido não podem intercalar com outro faturamento ou reserva
+	s.invoices.holdMu.Lock()
+	defer s.invoices.holdMu.Unlock()
+
+	order, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if !order.IsActive() {
+		return nil, domain.ErrOrderNotActive
+	}
+	lines, err := order.InvoiceLines(input.Lines)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]domain.InvoiceItem, 0, len(lines))
+	for _, line := range lines {
+		orderLine := order.Lines[line.Line-1]
+		items = append(items, domain.InvoiceItem{
+			Type:      domain.ItemGoods,
+			ProductID: orderLine.ProductID,
+			Quantity:  line.Quantity,
+			UnitPrice: orderLine.UnitPrice,
+			OrderLine: orderLine.Number,
+		})
+	}
+
+	// O saldo é abatido e gravado antes da criação das notas: se o pedido não
+	// puder ser gravado, nenhuma nota é criada
+	order.ClaimItems(items, time.Now())
+	if err := s.repo.Update(order); err != nil {
+		return nil, fmt.Errorf("erro ao atualizar pedido de venda: %w", err)
+	}
+
+	invoices, err := s.invoices.CreateInvoices(CreateInvoiceInput{
+		Model:          input.Model,
+		IssuerID:       order.IssuerID,
+		CustomerID:     order.CustomerID,
+		Items:          items,
+		Charges:        input.Charges,
+		Transport:      input.Transport,
+		Payment:        order.Payment,
+		CreditOverride: input.CreditOverride,
+		User:           input.User,
+		SalesOrderID:   order.ID,
+	})
+	if err != nil {
+		order.ReleaseItems(items, time.Now())
+		if updateErr := s.repo.Update(order); updateErr != nil {
+			return nil, errors.Join(err, fmt.Errorf("erro ao devolver o saldo do pedido de venda: %w", updateErr))
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	for _, invoice := range invoices {
+		order.RegisterInvoice(invoice, now)
+	}
+	if err := s.repo.Update(order); err != nil {
+		return nil, fmt.Errorf("erro ao atualizar pedido de venda: %w", err)
+	}
+	return invoices, nil
+}
+
+// GetOrderInvoices lista as notas geradas pelo pedido
+func (s *SalesOrderService) GetOrderInvoices(id string) ([]*domain.Invoice, error) {
+	order, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	invoices := make([]*domain.Invoice, 0, len(order.Invoices))
+	for _, invoiceID := range order.Invoices {
+		invoice, err := s.invoices.GetInvoice(invoiceID)
+		if err != nil {
+			return nil, err
+		}
+		invoices = append(invoices, invoice)
+	}
+	return invoices, nil
+}
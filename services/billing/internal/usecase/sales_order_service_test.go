@@ -0,0 +1,196 @@
+package usecase
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+)
+
+func TestConfirmSalesOrderConcurrent(t *testing.T) {
+	fixture := newTestFixture(t, domain.ApprovalPolicy{}, map[string]int{"P1": 100})
+
+	// Cada pedido cabe sozinho no saldo, mas dois juntos não. A consulta ao
+	// estoque demora, como a chamada ao Stock Service, e as confirmações
+	// simultâneas leem o saldo antes que qualquer uma grave a reserva
+	const orders = 8
+	ids := make([]string, orders)
+	for i := range ids {
+		order, err := fixture.orderService.CreateSalesOrder(CreateSalesOrderInput{
+			IssuerID:   "issuer-1",
+			CustomerID: "customer-1",
+			Lines:      []domain.SalesOrderLine{{ProductID: "P1", Quantity: 60, UnitPrice: 1250}},
+		})
+		if err != nil {
+			t.Fatalf("CreateSalesOrder() erro inesperado: %v", err)
+		}
+		ids[i] = order.ID
+	}
+
+	fixture.stock.latency = 10 * time.Millisecond
+	var wg sync.WaitGroup
+	errs := make([]error, orders)
+	for i, id := range ids {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = fixture.orderService.ConfirmSalesOrder(id)
+		}()
+	}
+	wg.Wait()
+
+	confirmed := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			confirmed++
+		case !errors.Is(err, domain.ErrOrderInsufficientStock):
+			t.Errorf("ConfirmSalesOrder() erro = %v, esperado %v", err, domain.ErrOrderInsufficientStock)
+		}
+	}
+	if confirmed != 1 {
+		t.Errorf("pedidos confirmados = %d, esperado 1", confirmed)
+	}
+
+	held, err := fixture.service.heldStock("", "")
+	if err != nil {
+		t.Fatalf("heldStock() erro inesperado: %v", err)
+	}
+	if held["P1"] != 60 {
+		t.Errorf("reserva de P1 = %d, esperado 60", held["P1"])
+	}
+}
+
+// confirmedOrder cria e confirma um pedido de uma linha de P1
+func confirmedOrder(t *testing.T, fixture *testFixture, quantity int) *domain.SalesOrder {
+	t.Helper()
+	order, err := fixture.orderService.CreateSalesOrder(CreateSalesOrderInput{
+		IssuerID:   "issuer-1",
+		CustomerID: "customer-1",
+		Lines:      []domain.SalesOrderLine{{ProductID: "P1", Quantity: quantity, UnitPrice: 1250}},
+	})
+	if err != nil {
+		t.Fatalf("CreateSalesOrder() erro inesperado: %v", err)
+	}
+	if order, err = fixture.orderService.ConfirmSalesOrder(order.ID); err != nil {
+		t.Fatalf("ConfirmSalesOrder() erro inesperado: %v", err)
+	}
+	return order
+}
+
+func TestGenerateInvoicePartialBalance(t *testing.T) {
+	fixture := newTestFixture(t, domain.ApprovalPolicy{}, map[string]int{"P1": 100})
+	order := confirmedOrder(t, fixture, 60)
+
+	// Cada passo fatura sobre o saldo deixado pelos anteriores
+	steps := []struct {
+		name      string
+		input     GenerateInvoiceInput
+		err       error
+		invoiced  int
+		remaining int
+		status    domain.SalesOrderStatus
+	}{
+		{
+			name:      "parte da linha",
+			input:     GenerateInvoiceInput{Lines: []domain.OrderLine{{Line: 1, Quantity: 20}}},
+			invoiced:  20,
+			remaining: 40,
+			status:    domain.OrderPartiallyInvoiced,
+		},
+		{
+			name:      "acima do saldo",
+			input:     GenerateInvoiceInput{Lines: []domain.OrderLine{{Line: 1, Quantity: 41}}},
+			err:       domain.ErrOrderQuantityExceeded,
+			invoiced:  20,
+			remaining: 40,
+			status:    domain.OrderPartiallyInvoiced,
+		},
+		{
+			name:      "nota recusada devolve o saldo",
+			input:     GenerateInvoiceInput{Lines: []domain.OrderLine{{Line: 1, Quantity: 10}}, Charges: domain.Charges{Freight: -100}},
+			err:       domain.ErrInvalidCharges,
+			invoiced:  20,
+			remaining: 40,
+			status:    domain.OrderPartiallyInvoiced,
+		},
+		{
+			name:      "saldo restante",
+			invoiced:  60,
+			remaining: 0,
+			status:    domain.OrderInvoiced,
+		},
+		{
+			name:      "sem saldo",
+			err:       domain.ErrOrderNotActive,
+			invoiced:  60,
+			remaining: 0,
+			status:    domain.OrderInvoiced,
+		},
+	}
+	for _, step := range steps {
+		_, err := fixture.orderService.GenerateInvoice(order.ID, step.input)
+		if !errors.Is(err, step.err) {
+			t.Fatalf("%s: GenerateInvoice() erro = %v, esperado %v", step.name, err, step.err)
+		}
+		current, err := fixture.orderService.GetSalesOrder(order.ID)
+		if err != nil {
+			t.Fatalf("GetSalesOrder() erro inesperado: %v", err)
+		}
+		line := current.Lines[0]
+		if line.Invoiced != step.invoiced || line.Remaining() != step.remaining || current.Status != step.status {
+			t.Errorf("%s: faturado %d, saldo %d, situação %s; esperado %d, %d, %s", step.name,
+				line.Invoiced, line.Remaining(), current.Status, step.invoiced, step.remaining, step.status)
+		}
+	}
+
+	invoices, err := fixture.orderService.GetOrderInvoices(order.ID)
+	if err != nil {
+		t.Fatalf("GetOrderInvoices() erro inesperado: %v", err)
+	}
+	if len(invoices) != 2 {
+		t.Errorf("notas do pedido = %d, esperado 2", len(invoices))
+	}
+}
+
+func TestGenerateInvoiceConcurrent(t *testing.T) {
+	fixture := newTestFixture(t, domain.ApprovalPolicy{}, map[string]int{"P1": 100})
+	order := confirmedOrder(t, fixture, 60)
+
+	// Faturamentos simultâneos de todo o saldo: só um pode gerar nota
+	const requests = 8
+	fixture.stock.latency = 10 * time.Millisecond
+	var wg sync.WaitGroup
+	errs := make([]error, requests)
+	for i := range requests {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = fixture.orderService.GenerateInvoice(order.ID, GenerateInvoiceInput{})
+		}()
+	}
+	wg.Wait()
+
+	generated := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			generated++
+		case !errors.Is(err, domain.ErrOrderNotActive):
+			t.Errorf("GenerateInvoice() erro = %v, esperado %v", err, domain.ErrOrderNotActive)
+		}
+	}
+	if generated != 1 {
+		t.Errorf("faturamentos = %d, esperado 1", generated)
+	}
+
+	current, err := fixture.orderService.GetSalesOrder(order.ID)
+	if err != nil {
+		t.Fatalf("GetSalesOrder() erro inesperado: %v", err)
+	}
+	if current.Lines[0].Invoiced != 60 || len(current.Invoices) != 1 {
+		t.Errorf("faturado %d em %d notas, esperado 60 em 1", current.Lines[0].Invoiced, len(current.Invoices))
+	}
+}
@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+	"github.com/google/uuid"
+)
+
+// ServiceCatalogService contém a lógica de negócio do catálogo de serviços
+type ServiceCatalogService struct {
+	repo domain.ServiceRepository
+}
+
+// NewServiceCatalogService cria uma nova instância do serviço
+func NewServiceCatalogService(repo domain.ServiceRepository) *ServiceCatalogService {
+	return &ServiceCatalogService{
+		repo: repo,
+	}
+}
+
+// CreateService cadastra um novo serviço no catálogo
+func (s *ServiceCatalogService) CreateService(service domain.Service) (*domain.Service, error) {
+	service.ID = uuid.New().String()
+	service.CreatedAt = time.Now()
+	service.UpdatedAt = service.CreatedAt
+
+	service.Normalize()
+	if err := service.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(&service); err != nil {
+		return nil, err
+	}
+	return &service, nil
+}
+
+// GetService busca um serviço por ID
+func (s *ServiceCatalogService) GetService(id string) (*domain.Service, error) {
+	return s.repo.FindByID(id)
+}
+
+// GetAllServices retorna todos os serviços do catálogo
+func (s *ServiceCatalogService) GetAllServices() ([]*domain.Service, error) {
+	return s.repo.FindAll()
+}
+
+// UpdateService atualiza os dados de um serviço. Notas já criadas mantêm a
+// classificação e a alíquota copiadas do catálogo
+func (s *ServiceCatalogService) UpdateService(id string, data domain.Service) (*domain.Service, error) {
+	existing, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Trabalha sobre uma cópia para que o repositório detecte troca de código
+	updated := data
+	updated.ID = existing.ID
+	updated.CreatedAt = existing.CreatedAt
+	updated.UpdatedAt = time.Now()
+
+	updated.Normalize()
+	if err := updated.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(&updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// DeleteService remove um serviço do catálogo
+func (s *ServiceCatalogService) DeleteService(id string) error {
+	return s.repo.Delete(id)
+}
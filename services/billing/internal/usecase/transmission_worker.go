@@ -0,0 +1,57 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// TransmissionWorker transmite periodicamente as notas emitidas em
+// contingência, até que a comunicação com a SEFAZ seja restabelecida
+type TransmissionWorker struct {
+	service  *InvoiceService
+	interval time.Duration
+}
+
+// NewTransmissionWorker cria o worker com o intervalo entre as rodadas
+func NewTransmissionWorker(service *InvoiceService, interval time.Duration) *TransmissionWorker {
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	return &TransmissionWorker{service: service, interval: interval}
+}
+
+// Run executa as rodadas de transmissão até o contexto ser cancelado
+func (w *TransmissionWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.runOnce()
+		}
+	}
+}
+
+// runOnce transmite a fila quando há notas pendentes
+func (w *TransmissionWorker) runOnce() {
+	queued, err := w.service.GetTransmissionQueue()
+	if err != nil {
+		log.Printf("Fila de contingência: erro ao consultar: %v", err)
+		return
+	}
+	if len(queued) == 0 {
+		return
+	}
+
+	transmitted, err := w.service.TransmitQueue()
+	if transmitted > 0 {
+		log.Printf("Fila de contingência: %d nota(s) transmitida(s) à SEFAZ", transmitted)
+	}
+	if err != nil {
+		log.Printf("Fila de contingência: %d nota(s) pendente(s): %v", len(queued)-transmitted, err)
+	}
+}
@@ -0,0 +1,343 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/billing/internal/domain"
+)
+
+var documentKey = regexp.MustCompile(`Id="NFe(\d{44})"`)
+
+// fakeAuthority simula a SEFAZ: respond decide o retorno de cada envio e de
+// cada consulta de recibo, identificados pela chave de acesso
+type fakeAuthority struct {
+	mu      sync.Mutex
+	respond func(key, receipt string) (*domain.Authorization, error)
+	sent    map[string]int // Envios por chave de acesso
+	queries []string       // Recibos consultados
+	latency time.Duration
+}
+
+func newFakeAuthority(respond func(key, receipt string) (*domain.Authorization, error)) *fakeAuthority {
+	return &fakeAuthority{respond: respond, sent: make(map[string]int)}
+}
+
+func (f *fakeAuthority) Authorize(document []byte) (*domain.Authorization, error) {
+	time.Sleep(f.latency)
+	match := documentKey.FindSubmatch(document)
+	if match == nil {
+		return nil, errors.New("documento sem chave de acesso")
+	}
+	key := string(match[1])
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.sent[key]++
+	return f.respond(key, "")
+}
+
+func (f *fakeAuthority) QueryReceipt(receipt string) (*domain.Authorization, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.queries = append(f.queries, receipt)
+	return f.respond("", receipt)
+}
+
+func (f *fakeAuthority) sends(key string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.sent[key]
+}
+
+// authorized é o retorno de uma nota autorizada pela SEFAZ
+func authorized() (*domain.Authorization, error) {
+	return &domain.Authorization{Status: domain.AuthorizationAuthorized, StatusCode: 100, Protocol: "135250000000001"}, nil
+}
+
+// contingencyInvoices imprime em contingência as notas informadas, uma por
+// quantidade de P1, e as retorna na ordem da fila
+func contingencyInvoices(t *testing.T, fixture *testFixture, quantities ...int) []*domain.Invoice {
+	t.Helper()
+	if _, err := fixture.service.EnterContingency(domain.EmissionFSDA, "Falha de comunicação com a SEFAZ"); err != nil {
+		t.Fatalf("EnterContingency() erro inesperado: %v", err)
+	}
+	var invoices []*domain.Invoice
+	for _, quantity := range quantities {
+		invoice, err := fixture.service.CreateInvoice(invoiceInput("customer-1", map[string]int{"P1": quantity}))
+		if err != nil {
+			t.Fatalf("CreateInvoice() erro inesperado: %v", err)
+		}
+		if invoice, err = fixture.service.PrintInvoice(invoice.ID, nil); err != nil {
+			t.Fatalf("PrintInvoice() erro inesperado: %v", err)
+		}
+		invoices = append(invoices, invoice)
+	}
+	if err := fixture.service.LeaveContingency(); err != nil {
+		t.Fatalf("LeaveContingency() erro inesperado: %v", err)
+	}
+	return invoices
+}
+
+// queuedEntries indexa a fila de transmissão pela nota
+func queuedEntries(t *testing.T, fixture *testFixture) map[string]*domain.QueuedTransmission {
+	t.Helper()
+	pending, err := fixture.service.GetTransmissionQueue()
+	if err != nil {
+		t.Fatalf("GetTransmissionQueue() erro inesperado: %v", err)
+	}
+	entries := make(map[string]*domain.QueuedTransmission)
+	for _, entry := range pending {
+		entries[entry.InvoiceID] = entry
+	}
+	return entries
+}
+
+func TestContingencyRequiresAuthority(t *testing.T) {
+	fixture := newTestFixture(t, domain.ApprovalPolicy{}, map[string]int{"P1": 100})
+	if _, err := fixture.service.EnterContingency(domain.EmissionFSDA, "Falha de comunicação com a SEFAZ"); !errors.Is(err, domain.ErrSEFAZNotConfigured) {
+		t.Errorf("EnterContingency() erro = %v, esperado %v", err, domain.ErrSEFAZNotConfigured)
+	}
+	if _, err := fixture.service.TransmitQueue(); !errors.Is(err, domain.ErrSEFAZNotConfigured) {
+		t.Errorf("TransmitQueue() erro = %v, esperado %v", err, domain.ErrSEFAZNotConfigured)
+	}
+	if err := fixture.service.LeaveContingency(); !errors.Is(err, domain.ErrContingencyInactive) {
+		t.Errorf("LeaveContingency() erro = %v, esperado %v", err, domain.ErrContingencyInactive)
+	}
+}
+
+func TestPrintInvoiceInContingency(t *testing.T) {
+	fixture := newTestFixture(t, domain.ApprovalPolicy{}, map[string]int{"P1": 100})
+	authority := newFakeAuthority(func(string, string) (*domain.Authorization, error) { return authorized() })
+	fixture.service.authority = authority
+
+	invoices := contingencyInvoices(t, fixture, 1, 2)
+
+	// As notas saem com tpEmis 5 na chave, pendentes e sem envio à SEFAZ
+	for _, invoice := range invoices {
+		key, err := domain.ParseAccessKey(invoice.AccessKey)
+		if err != nil {
+			t.Fatalf("ParseAccessKey() erro inesperado: %v", err)
+		}
+		if key.EmissionType != domain.EmissionFSDA || invoice.Authorization == nil || invoice.Authorization.Status != domain.AuthorizationPending {
+			t.Errorf("nota %d: forma de emissão %d, autorização %+v", invoice.Number, key.EmissionType, invoice.Authorization)
+		}
+		if authority.sends(invoice.AccessKey) != 0 {
+			t.Errorf("nota %d enviada à SEFAZ durante a contingência", invoice.Number)
+		}
+	}
+	pending, err := fixture.service.GetTransmissionQueue()
+	if err != nil {
+		t.Fatalf("GetTransmissionQueue() erro inesperado: %v", err)
+	}
+	if len(pending) != 2 || pending[0].InvoiceID != invoices[0].ID || pending[1].InvoiceID != invoices[1].ID {
+		t.Fatalf("fila de transmissão = %+v, esperado as duas notas na ordem de emissão", pending)
+	}
+
+	// Após a contingência, a emissão volta a ser normal
+	invoice, err := fixture.service.CreateInvoice(invoiceInput("customer-1", map[string]int{"P1": 1}))
+	if err != nil {
+		t.Fatalf("CreateInvoice() erro inesperado: %v", err)
+	}
+	if invoice, err = fixture.service.PrintInvoice(invoice.ID, nil); err != nil {
+		t.Fatalf("PrintInvoice() erro inesperado: %v", err)
+	}
+	if invoice.Contingency != nil || !invoice.IsAuthorized() {
+		t.Errorf("nota após a contingência: contingência %+v, autorização %+v", invoice.Contingency, invoice.Authorization)
+	}
+}
+
+func TestTransmitQueueRetry(t *testing.T) {
+	fixture := newTestFixture(t, domain.ApprovalPolicy{}, map[string]int{"P1": 100})
+	var unavailable bool
+	authority := newFakeAuthority(nil)
+	fixture.service.authority = authority
+
+	invoices := contingencyInvoices(t, fixture, 1, 2, 3)
+	first, second, third := invoices[0], invoices[1], invoices[2]
+	authority.respond = func(key, receipt string) (*domain.Authorization, error) {
+		switch {
+		case unavailable:
+			return nil, domain.ErrSEFAZUnavailable
+		case key == first.AccessKey:
+			// Lote em processamento: a nota fica na fila para consulta do recibo
+			return &domain.Authorization{Status: domain.AuthorizationPending, Receipt: "351000000000001"}, nil
+		case key == second.AccessKey:
+			return &domain.Authorization{Status: domain.AuthorizationRejected, StatusCode: 225, Reason: "Falha no schema XML"}, nil
+		}
+		return authorized()
+	}
+
+	// SEFAZ indisponível: a rodada para na primeira nota, que registra a tentativa
+	unavailable = true
+	transmitted, err := fixture.service.TransmitQueue()
+	if transmitted != 0 || !errors.Is(err, domain.ErrSEFAZUnavailable) {
+		t.Fatalf("TransmitQueue() = %d, %v; esperado 0, %v", transmitted, err, domain.ErrSEFAZUnavailable)
+	}
+	entries := queuedEntries(t, fixture)
+	if len(entries) != 3 {
+		t.Fatalf("fila com %d notas, esperado 3", len(entries))
+	}
+	if entry := entries[first.ID]; entry.Attempts != 1 || entry.LastAttemptAt == nil || entry.LastError == "" {
+		t.Errorf("primeira nota: %+v, esperado uma tentativa com erro", entry)
+	}
+	if entries[second.ID].Attempts != 0 || entries[third.ID].Attempts != 0 {
+		t.Errorf("notas seguintes tentadas após a indisponibilidade")
+	}
+
+	// SEFAZ disponível: a rejeitada e a autorizada deixam a fila; a nota em
+	// processamento permanece com o recibo
+	unavailable = false
+	if transmitted, err = fixture.service.TransmitQueue(); transmitted != 2 || err != nil {
+		t.Fatalf("TransmitQueue() = %d, %v; esperado 2, nil", transmitted, err)
+	}
+	entries = queuedEntries(t, fixture)
+	if entry, ok := entries[first.ID]; len(entries) != 1 || !ok || entry.Attempts != 2 || entry.LastError != "" {
+		t.Fatalf("fila = %+v, esperado somente a primeira nota com duas tentativas", entries)
+	}
+	for _, expected := range []struct {
+		invoice *domain.Invoice
+		status  domain.AuthorizationStatus
+	}{
+		{invoice: first, status: domain.AuthorizationPending},
+		{invoice: second, status: domain.AuthorizationRejected},
+		{invoice: third, status: domain.AuthorizationAuthorized},
+	} {
+		stored, err := fixture.invoices.FindByID(expected.invoice.ID)
+		if err != nil {
+			t.Fatalf("FindByID() erro inesperado: %v", err)
+		}
+		if stored.Authorization.Status != expected.status {
+			t.Errorf("nota %d: autorização %s, esperado %s", stored.Number, stored.Authorization.Status, expected.status)
+		}
+	}
+
+	// A nova rodada consulta o recibo em vez de reenviar a nota
+	if transmitted, err = fixture.service.TransmitQueue(); transmitted != 1 || err != nil {
+		t.Fatalf("TransmitQueue() = %d, %v; esperado 1, nil", transmitted, err)
+	}
+	if len(authority.queries) != 1 || authority.queries[0] != "351000000000001" || authority.sends(first.AccessKey) != 2 {
+		t.Errorf("recibos consultados %v, envios da primeira nota %d", authority.queries, authority.sends(first.AccessKey))
+	}
+	if entries = queuedEntries(t, fixture); len(entries) != 0 {
+		t.Errorf("fila com %d notas após a autorização", len(entries))
+	}
+}
+
+func TestTransmitQueueDropsResolvedInvoices(t *testing.T) {
+	fixture := newTestFixture(t, domain.ApprovalPolicy{}, map[string]int{"P1": 100})
+	authority := newFakeAuthority(func(string, string) (*domain.Authorization, error) { return authorized() })
+	fixture.service.authority = authority
+
+	invoices := contingencyInvoices(t, fixture, 1, 2)
+
+	// A primeira nota é reenviada manualmente e sai da fila
+	if _, err := fixture.service.AuthorizeInvoice(invoices[0].ID); err != nil {
+		t.Fatalf("AuthorizeInvoice() erro inesperado: %v", err)
+	}
+	if entries := queuedEntries(t, fixture); len(entries) != 1 {
+		t.Fatalf("fila com %d notas, esperado 1", len(entries))
+	}
+
+	// Uma entrada órfã, sem nota, é descartada sem envio
+	if err := fixture.service.queue.Enqueue(&domain.QueuedTransmission{InvoiceID: "inexistente", EnqueuedAt: time.Now()}); err != nil {
+		t.Fatalf("Enqueue() erro inesperado: %v", err)
+	}
+	if transmitted, err := fixture.service.TransmitQueue(); transmitted != 1 || err != nil {
+		t.Fatalf("TransmitQueue() = %d, %v; esperado 1, nil", transmitted, err)
+	}
+	if entries := queuedEntries(t, fixture); len(entries) != 0 {
+		t.Errorf("fila com %d notas, esperado vazia", len(entries))
+	}
+	for _, invoice := range invoices {
+		if sends := authority.sends(invoice.AccessKey); sends != 1 {
+			t.Errorf("nota %d enviada %d vezes, esperado 1", invoice.Number, sends)
+		}
+	}
+}
+
+func TestTransmitQueueConcurrentRounds(t *testing.T) {
+	fixture := newTestFixture(t, domain.ApprovalPolicy{}, map[string]int{"P1": 100})
+	authority := newFakeAuthority(func(string, string) (*domain.Authorization, error) { return authorized() })
+	authority.latency = 10 * time.Millisecond
+	fixture.service.authority = authority
+
+	invoices := contingencyInvoices(t, fixture, 1, 2, 3)
+
+	// Rodadas simultâneas (worker e transmissão manual) não reenviam a mesma nota
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	total := 0
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			transmitted, err := fixture.service.TransmitQueue()
+			if err != nil {
+				t.Errorf("TransmitQueue() erro inesperado: %v", err)
+			}
+			mu.Lock()
+			total += transmitted
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	if total != len(invoices) {
+		t.Errorf("rodadas transmitiram %d notas, esperado %d", total, len(invoices))
+	}
+	for _, invoice := range invoices {
+		if sends := authority.sends(invoice.AccessKey); sends != 1 {
+			t.Errorf("nota %d enviada %d vezes, esperado 1", invoice.Number, sends)
+		}
+	}
+}
+
+func TestTransmissionWorker(t *testing.T) {
+	fixture := newTestFixture(t, domain.ApprovalPolicy{}, map[string]int{"P1": 100})
+	authority := newFakeAuthority(func(string, string) (*domain.Authorization, error) { return authorized() })
+	fixture.service.authority = authority
+	worker := NewTransmissionWorker(fixture.service, time.Millisecond)
+
+	// Sem notas na fila, a rodada não chama a SEFAZ
+	worker.runOnce()
+	if len(authority.sent) != 0 {
+		t.Fatalf("rodada com fila vazia enviou %d notas", len(authority.sent))
+	}
+
+	invoices := contingencyInvoices(t, fixture, 1, 2)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		worker.Run(ctx)
+		close(done)
+	}()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(queuedEntries(t, fixture)) > 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	<-done
+
+	if entries := queuedEntries(t, fixture); len(entries) != 0 {
+		t.Fatalf("fila com %d notas após as rodadas do worker", len(entries))
+	}
+	for _, invoice := range invoices {
+		stored, err := fixture.invoices.FindByID(invoice.ID)
+		if err != nil {
+			t.Fatalf("FindByID() erro inesperado: %v", err)
+		}
+		if !stored.IsAuthorized() || authority.sends(invoice.AccessKey) != 1 {
+			t.Errorf("nota %d: autorização %+v, %d envios", stored.Number, stored.Authorization, authority.sends(invoice.AccessKey))
+		}
+	}
+
+	if NewTransmissionWorker(fixture.service, 0).interval != 30*time.Second {
+		t.Errorf("intervalo padrão do worker diferente de 30s")
+	}
+}
@@ -0,0 +1,491 @@
+// Package xsd implementa um validador XML Schema (XSD 1.0) para o subconjunto
+// de construções usado pelos leiautes fiscais: elementos globais e locais,
+// tipos nomeados, sequence/choice com minOccurs/maxOccurs, atributos,
+// xs:any e restrições de tipos simples (pattern, enumeration e comprimentos).
+package xsd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const xsNamespace = "http://www.w3.org/2001/XMLSchema"
+
+// unbounded representa maxOccurs="unbounded"
+const unbounded = -1
+
+// Schema é um conjunto de declarações compiladas a partir de um ou mais arquivos XSD
+// que compartilham o mesmo targetNamespace
+type Schema struct {
+	TargetNamespace string
+	elements        map[string]*elementDecl
+	complexTypes    map[string]*complexType
+	simpleTypes     map[string]*simpleType
+}
+
+type elementDecl struct {
+	name       string
+	typeName   string
+	complex    *complexType
+	simple     *simpleType
+	minOccurs  int
+	maxOccurs  int
+	resolved   bool
+	resolveErr error
+}
+
+type particleKind int
+
+const (
+	particleElement particleKind = iota
+	particleSequence
+	particleChoice
+	particleAny
+)
+
+// particle representa um item do modelo de conteúdo (elemento, sequence, choice ou any)
+type particle struct {
+	kind      particleKind
+	element   *elementDecl
+	children  []*particle
+	namespace string // para xs:any
+	minOccurs int
+	maxOccurs int
+}
+
+type attributeDecl struct {
+	name     string
+	typeName string
+	simple   *simpleType
+	required bool
+}
+
+type complexType struct {
+	name       string
+	content    *particle // nil para conteúdo vazio
+	attributes []*attributeDecl
+}
+
+type simpleType struct {
+	name         string
+	base         string
+	baseType     *simpleType
+	patterns     []*regexp.Regexp
+	patternText  []string
+	enumerations []string
+	length       int
+	minLength    int
+	maxLength    int
+}
+
+// node é a representação genérica de um elemento XML lido pelo validador
+type node struct {
+	XMLName  xml.Name
+	Attrs    []xml.Attr `xml:",any,attr"`
+	Children []*node    `xml:",any"`
+	Text     string     `xml:",chardata"`
+}
+
+// Load compila os arquivos XSD de um fs.FS. Os arquivos referenciados por
+// xs:include são carregados a partir do mesmo diretório
+func Load(fsys fs.FS, files ...string) (*Schema, error) {
+	schema := &Schema{
+		elements:     make(map[string]*elementDecl),
+		complexTypes: make(map[string]*complexType),
+		simpleTypes:  make(map[string]*simpleType),
+	}
+
+	loaded := make(map[string]bool)
+	var load func(name string) error
+	load = func(name string) error {
+		if loaded[name] {
+			return nil
+		}
+		loaded[name] = true
+
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return fmt.Errorf("erro ao ler schema %s: %w", name, err)
+		}
+		var root node
+		if err := xml.Unmarshal(data, &root); err != nil {
+			return fmt.Errorf("erro ao decodificar schema %s: %w", name, err)
+		}
+		if root.XMLName.Space != xsNamespace || root.XMLName.Local != "schema" {
+			return fmt.Errorf("%s não é um XML Schema", name)
+		}
+
+		if tns := attr(&root, "targetNamespace"); tns != "" {
+			if schema.TargetNamespace != "" && schema.TargetNamespace != tns {
+				return fmt.Errorf("%s: targetNamespace %q difere de %q", name, tns, schema.TargetNamespace)
+			}
+			schema.TargetNamespace = tns
+		}
+
+		for _, child := range root.Children {
+			if child.XMLName.Space != xsNamespace {
+				continue
+			}
+			switch child.XMLName.Local {
+			case "include":
+				if err := load(path.Join(path.Dir(name), attr(child, "schemaLocation"))); err != nil {
+					return err
+				}
+			case "element":
+				decl, err := schema.parseElement(child)
+				if err != nil {
+					return err
+				}
+				schema.elements[decl.name] = decl
+			case "complexType":
+				ct, err := schema.parseComplexType(child)
+				if err != nil {
+					return err
+				}
+				schema.complexTypes[ct.name] = ct
+			case "simpleType":
+				st, err := parseSimpleType(child)
+				if err != nil {
+					return err
+				}
+				schema.simpleTypes[st.name] = st
+			case "annotation":
+			default:
+				return fmt.Errorf("%s: construção xs:%s não suportada", name, child.XMLName.Local)
+			}
+		}
+		return nil
+	}
+
+	for _, file := range files {
+		if err := load(file); err != nil {
+			return nil, err
+		}
+	}
+	if err := schema.resolve(); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+func (s *Schema) parseElement(n *node) (*elementDecl, error) {
+	decl := &elementDecl{
+		name:     attr(n, "name"),
+		typeName: localName(attr(n, "type")),
+	}
+	if decl.name == "" {
+		return nil, fmt.Errorf("xs:element sem nome (xs:element ref não é suportado)")
+	}
+	var err error
+	if decl.minOccurs, decl.maxOccurs, err = occurs(n); err != nil {
+		return nil, err
+	}
+
+	for _, child := range n.Children {
+		switch child.XMLName.Local {
+		case "complexType":
+			if decl.complex, err = s.parseComplexType(child); err != nil {
+				return nil, err
+			}
+		case "simpleType":
+			if decl.simple, err = parseSimpleType(child); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return decl, nil
+}
+
+func (s *Schema) parseComplexType(n *node) (*complexType, error) {
+	ct := &complexType{name: attr(n, "name")}
+	for _, child := range n.Children {
+		switch child.XMLName.Local {
+		case "sequence", "choice":
+			p, err := s.parseParticle(child)
+			if err != nil {
+				return nil, err
+			}
+			ct.content = p
+		case "attribute":
+			a := &attributeDecl{
+				name:     attr(child, "name"),
+				typeName: localName(attr(child, "type")),
+				required: attr(child, "use") == "required",
+			}
+			for _, inner := range child.Children {
+				if inner.XMLName.Local == "simpleType" {
+					st, err := parseSimpleType(inner)
+					if err != nil {
+						return nil, err
+					}
+					a.simple = st
+				}
+			}
+			ct.attributes = append(ct.attributes, a)
+		case "annotation":
+		default:
+			return nil, fmt.Errorf("complexType %q: construção xs:%s não suportada", ct.name, child.XMLName.Local)
+		}
+	}
+	return ct, nil
+}
+
+func (s *Schema) parseParticle(n *node) (*particle, error) {
+	p := &particle{}
+	var err error
+	if p.minOccurs, p.maxOccurs, err = occurs(n); err != nil {
+		return nil, err
+	}
+
+	switch n.XMLName.Local {
+	case "element":
+		decl, err := s.parseElement(n)
+		if err != nil {
+			return nil, err
+		}
+		p.kind = particleElement
+		p.element = decl
+		p.minOccurs, p.maxOccurs = decl.minOccurs, decl.maxOccurs
+		return p, nil
+	case "any":
+		p.kind = particleAny
+		p.namespace = attr(n, "namespace")
+		return p, nil
+	case "sequence":
+		p.kind = particleSequence
+	case "choice":
+		p.kind = particleChoice
+	default:
+		return nil, fmt.Errorf("partícula xs:%s não suportada", n.XMLName.Local)
+	}
+
+	for _, child := range n.Children {
+		if child.XMLName.Local == "annotation" {
+			continue
+		}
+		cp, err := s.parseParticle(child)
+		if err != nil {
+			return nil, err
+		}
+		p.children = append(p.children, cp)
+	}
+	return p, nil
+}
+
+func parseSimpleType(n *node) (*simpleType, error) {
+	st := &simpleType{name: attr(n, "name"), length: -1, minLength: -1, maxLength: -1}
+	for _, child := range n.Children {
+		if child.XMLName.Local != "restriction" {
+			if child.XMLName.Local == "annotation" {
+				continue
+			}
+			return nil, fmt.Errorf("simpleType %q: apenas xs:restriction é suportado", st.name)
+		}
+		st.base = attr(child, "base")
+		for _, facet := range child.Children {
+			value := attr(facet, "value")
+			switch facet.XMLName.Local {
+			case "pattern":
+				re, err := regexp.Compile("^(?:" + value + ")$")
+				if err != nil {
+					return nil, fmt.Errorf("simpleType %q: padrão inválido %q: %w", st.name, value, err)
+				}
+				st.patterns = append(st.patterns, re)
+				st.patternText = append(st.patternText, value)
+			case "enumeration":
+				st.enumerations = append(st.enumerations, value)
+			case "length", "minLength", "maxLength":
+				number, err := strconv.Atoi(value)
+				if err != nil {
+					return nil, fmt.Errorf("simpleType %q: %s inválido", st.name, facet.XMLName.Local)
+				}
+				switch facet.XMLName.Local {
+				case "length":
+					st.length = number
+				case "minLength":
+					st.minLength = number
+				case "maxLength":
+					st.maxLength = number
+				}
+			case "whiteSpace", "annotation":
+			default:
+				return nil, fmt.Errorf("simpleType %q: faceta xs:%s não suportada", st.name, facet.XMLName.Local)
+			}
+		}
+	}
+	return st, nil
+}
+
+// resolve liga as referências de tipos nomeados após todos os arquivos serem lidos
+func (s *Schema) resolve() error {
+	for _, st := range s.simpleTypes {
+		if err := s.resolveSimple(st); err != nil {
+			return err
+		}
+	}
+	for _, ct := range s.complexTypes {
+		if err := s.resolveComplex(ct, map[*complexType]bool{}); err != nil {
+			return err
+		}
+	}
+	for _, decl := range s.elements {
+		if err := s.resolveElement(decl, map[*complexType]bool{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Schema) resolveSimple(st *simpleType) error {
+	if st.baseType != nil || st.base == "" || strings.HasPrefix(st.base, "xs:") {
+		return nil
+	}
+	base, ok := s.simpleTypes[localName(st.base)]
+	if !ok {
+		return fmt.Errorf("tipo simples %q não declarado", st.base)
+	}
+	st.baseType = base
+	return s.resolveSimple(base)
+}
+
+func (s *Schema) resolveComplex(ct *complexType, visiting map[*complexType]bool) error {
+	if visiting[ct] {
+		return nil
+	}
+	visiting[ct] = true
+	for _, a := range ct.attributes {
+		if a.simple == nil && a.typeName != "" && !isBuiltin(a.typeName) {
+			st, ok := s.simpleTypes[a.typeName]
+			if !ok {
+				return fmt.Errorf("tipo %q do atributo %q não declarado", a.typeName, a.name)
+			}
+			a.simple = st
+		}
+		if a.simple != nil {
+			if err := s.resolveSimple(a.simple); err != nil {
+				return err
+			}
+		}
+	}
+	return s.resolveParticle(ct.content, visiting)
+}
+
+func (s *Schema) resolveParticle(p *particle, visiting map[*complexType]bool) error {
+	if p == nil {
+		return nil
+	}
+	if p.kind == particleElement {
+		return s.resolveElement(p.element, visiting)
+	}
+	for _, child := range p.children {
+		if err := s.resolveParticle(child, visiting); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Schema) resolveElement(decl *elementDecl, visiting map[*complexType]bool) error {
+	if decl.resolved {
+		return decl.resolveErr
+	}
+	decl.resolved = true
+
+	if decl.typeName != "" && decl.complex == nil && decl.simple == nil && !isBuiltin(decl.typeName) {
+		if ct, ok := s.complexTypes[decl.typeName]; ok {
+			decl.complex = ct
+		} else if st, ok := s.simpleTypes[decl.typeName]; ok {
+			decl.simple = st
+		} else {
+			decl.resolveErr = fmt.Errorf("tipo %q do elemento %q não declarado", decl.typeName, decl.name)
+			return decl.resolveErr
+		}
+	}
+	if decl.simple != nil {
+		decl.resolveErr = s.resolveSimple(decl.simple)
+	}
+	if decl.complex != nil {
+		decl.resolveErr = s.resolveComplex(decl.complex, visiting)
+	}
+	return decl.resolveErr
+}
+
+// Validate valida um documento XML contra os elementos globais do schema
+func (s *Schema) Validate(document []byte) error {
+	var root node
+	decoder := xml.NewDecoder(bytes.NewReader(document))
+	if err := decoder.Decode(&root); err != nil {
+		return fmt.Errorf("XML malformado: %w", err)
+	}
+
+	v := &validator{schema: s}
+	decl, ok := s.elements[root.XMLName.Local]
+	if !ok || root.XMLName.Space != s.TargetNamespace {
+		v.addf("/"+root.XMLName.Local, "elemento raiz não declarado no schema")
+	} else {
+		v.validateElement(&root, decl, "/"+root.XMLName.Local)
+	}
+
+	if len(v.problems) > 0 {
+		return &ValidationError{Problems: v.problems}
+	}
+	return nil
+}
+
+// ValidationError reúne as violações encontradas na validação
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "XML não respeita o schema: " + strings.Join(e.Problems, "; ")
+}
+
+func attr(n *node, name string) string {
+	for _, a := range n.Attrs {
+		if a.Name.Local == name && a.Name.Space == "" {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+func occurs(n *node) (int, int, error) {
+	minOccurs, maxOccurs := 1, 1
+	if value := attr(n, "minOccurs"); value != "" {
+		number, err := strconv.Atoi(value)
+		if err != nil {
+			return 0, 0, fmt.Errorf("minOccurs inválido: %q", value)
+		}
+		minOccurs = number
+	}
+	if value := attr(n, "maxOccurs"); value != "" {
+		if value == "unbounded" {
+			maxOccurs = unbounded
+		} else {
+			number, err := strconv.Atoi(value)
+			if err != nil {
+				return 0, 0, fmt.Errorf("maxOccurs inválido: %q", value)
+			}
+			maxOccurs = number
+		}
+	}
+	return minOccurs, maxOccurs, nil
+}
+
+func localName(qname string) string {
+	if idx := strings.IndexByte(qname, ':'); idx >= 0 && !strings.HasPrefix(qname, "xs:") {
+		return qname[idx+1:]
+	}
+	return qname
+}
+
+func isBuiltin(typeName string) bool {
+	return strings.HasPrefix(typeName, "xs:")
+}
@@ -0,0 +1,179 @@
+package xsd
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// testSchemas reproduz, em escala reduzida, as construções dos leiautes
+// fiscais: include de tipos básicos, sequence com grupos opcionais e
+// repetidos, choice, atributos obrigatórios e restrições de tipos simples
+var testSchemas = fstest.MapFS{
+	"tipos.xsd": {Data: []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" xmlns="urn:teste" targetNamespace="urn:teste" elementFormDefault="qualified">
+	<xs:simpleType name="TCnpj">
+		<xs:restriction base="xs:string">
+			<xs:maxLength value="14"/>
+			<xs:pattern value="[0-9]{14}"/>
+		</xs:restriction>
+	</xs:simpleType>
+	<xs:simpleType name="TString">
+		<xs:restriction base="xs:string">
+			<xs:minLength value="2"/>
+			<xs:maxLength value="10"/>
+		</xs:restriction>
+	</xs:simpleType>
+</xs:schema>`)},
+	"nota.xsd": {Data: []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" xmlns="urn:teste" targetNamespace="urn:teste" elementFormDefault="qualified">
+	<xs:include schemaLocation="tipos.xsd"/>
+	<xs:element name="nota">
+		<xs:complexType>
+			<xs:sequence>
+				<xs:element name="mod">
+					<xs:simpleType>
+						<xs:restriction base="xs:string">
+							<xs:enumeration value="55"/>
+							<xs:enumeration value="65"/>
+						</xs:restriction>
+					</xs:simpleType>
+				</xs:element>
+				<xs:element name="emit">
+					<xs:complexType>
+						<xs:choice>
+							<xs:element name="CNPJ" type="TCnpj"/>
+							<xs:element name="CPF" type="xs:string"/>
+						</xs:choice>
+					</xs:complexType>
+				</xs:element>
+				<xs:element name="obs" type="TString" minOccurs="0"/>
+				<xs:element name="item" maxOccurs="2">
+					<xs:complexType>
+						<xs:attribute name="n" use="required"/>
+					</xs:complexType>
+				</xs:element>
+			</xs:sequence>
+		</xs:complexType>
+	</xs:element>
+</xs:schema>`)},
+}
+
+func TestSchemaValidate(t *testing.T) {
+	schema, err := Load(testSchemas, "nota.xsd")
+	if err != nil {
+		t.Fatalf("Load() erro inesperado: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		document string
+		message  string // Trecho esperado na violação; vazio para documento válido
+	}{
+		{
+			name:     "documento válido",
+			document: `<nota xmlns="urn:teste"><mod>55</mod><emit><CNPJ>11222333000181</CNPJ></emit><item n="1"/></nota>`,
+		},
+		{
+			name:     "opcional e escolha alternativa",
+			document: `<nota xmlns="urn:teste"><mod>65</mod><emit><CPF>52998224725</CPF></emit><obs>entrega</obs><item n="1"/><item n="2"/></nota>`,
+		},
+		{
+			name:     "grupo obrigatório ausente",
+			document: `<nota xmlns="urn:teste"><mod>55</mod><item n="1"/></nota>`,
+			message:  "/nota: conteúdo inválido: encontrado <item>, esperado <emit>",
+		},
+		{
+			name:     "escolha sem alternativa",
+			document: `<nota xmlns="urn:teste"><mod>55</mod><emit/><item n="1"/></nota>`,
+			message:  "/nota/emit: conteúdo inválido",
+		},
+		{
+			name:     "padrão inválido",
+			document: `<nota xmlns="urn:teste"><mod>55</mod><emit><CNPJ>1122233300018A</CNPJ></emit><item n="1"/></nota>`,
+			message:  `/nota/emit/CNPJ: valor "1122233300018A" não corresponde ao padrão`,
+		},
+		{
+			name:     "enumeração inválida",
+			document: `<nota xmlns="urn:teste"><mod>57</mod><emit><CNPJ>11222333000181</CNPJ></emit><item n="1"/></nota>`,
+			message:  `/nota/mod: valor "57" não permitido`,
+		},
+		{
+			name:     "comprimento mínimo",
+			document: `<nota xmlns="urn:teste"><mod>55</mod><emit><CNPJ>11222333000181</CNPJ></emit><obs>x</obs><item n="1"/></nota>`,
+			message:  "/nota/obs:",
+		},
+		{
+			name:     "ordem dos elementos",
+			document: `<nota xmlns="urn:teste"><emit><CNPJ>11222333000181</CNPJ></emit><mod>55</mod><item n="1"/></nota>`,
+			message:  "encontrado <emit>, esperado <mod>",
+		},
+		{
+			name:     "ocorrências acima do máximo",
+			document: `<nota xmlns="urn:teste"><mod>55</mod><emit><CNPJ>11222333000181</CNPJ></emit><item n="1"/><item n="2"/><item n="3"/></nota>`,
+			message:  "encontrado <item>, esperado fim do elemento",
+		},
+		{
+			name:     "atributo obrigatório ausente",
+			document: `<nota xmlns="urn:teste"><mod>55</mod><emit><CNPJ>11222333000181</CNPJ></emit><item/></nota>`,
+			message:  `atributo obrigatório "n" ausente`,
+		},
+		{
+			name:     "namespace diferente",
+			document: `<nota xmlns="urn:outro"><mod>55</mod><emit><CNPJ>11222333000181</CNPJ></emit><item n="1"/></nota>`,
+			message:  "/nota: elemento raiz não declarado",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := schema.Validate([]byte(tt.document))
+			if tt.message == "" {
+				if err != nil {
+					t.Fatalf("Validate() erro inesperado: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.message) {
+				t.Errorf("Validate() erro = %v, esperado menção a %q", err, tt.message)
+			}
+		})
+	}
+}
+
+func TestLoadUnsupported(t *testing.T) {
+	const header = `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" xmlns:ds="http://www.w3.org/2000/09/xmldsig#" targetNamespace="urn:teste">`
+
+	// Construções do pacote oficial fora do subconjunto suportado devem
+	// falhar no carregamento, e não ser ignoradas na validação
+	tests := []struct {
+		name    string
+		body    string
+		message string
+	}{
+		{
+			name:    "import de outro namespace",
+			body:    `<xs:import namespace="http://www.w3.org/2000/09/xmldsig#" schemaLocation="xmldsig-core-schema_v1.01.xsd"/>`,
+			message: "xs:import não suportada",
+		},
+		{
+			name:    "referência a elemento",
+			body:    `<xs:complexType name="TNFe"><xs:sequence><xs:element ref="ds:Signature"/></xs:sequence></xs:complexType>`,
+			message: "xs:element ref não é suportado",
+		},
+		{
+			name:    "faceta numérica",
+			body:    `<xs:simpleType name="TDec"><xs:restriction base="xs:decimal"><xs:totalDigits value="15"/></xs:restriction></xs:simpleType>`,
+			message: "faceta xs:totalDigits não suportada",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schemas := fstest.MapFS{"leiaute.xsd": {Data: []byte(header + tt.body + `</xs:schema>`)}}
+			if _, err := Load(schemas, "leiaute.xsd"); err == nil || !strings.Contains(err.Error(), tt.message) {
+				t.Errorf("Load() erro = %v, esperado menção a %q", err, tt.message)
+			}
+		})
+	}
+}
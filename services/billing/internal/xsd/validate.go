@@ -0,0 +1,254 @@
+package xsd
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+type validator struct {
+	schema   *Schema
+	problems []string
+}
+
+func (v *validator) addf(path, format string, args ...interface{}) {
+	v.problems = append(v.problems, path+": "+fmt.Sprintf(format, args...))
+}
+
+func (v *validator) validateElement(n *node, decl *elementDecl, path string) {
+	if decl.complex == nil {
+		if len(n.Children) > 0 {
+			v.addf(path, "elemento de tipo simples não pode conter elementos filhos")
+			return
+		}
+		if decl.simple != nil {
+			if err := decl.simple.check(n.Text); err != nil {
+				v.addf(path, "%v", err)
+			}
+		}
+		return
+	}
+
+	ct := decl.complex
+	v.validateAttributes(n, ct, path)
+
+	if strings.TrimSpace(n.Text) != "" {
+		v.addf(path, "conteúdo textual não permitido")
+	}
+
+	if ct.content == nil {
+		if len(n.Children) > 0 {
+			v.addf(path, "elemento deve ser vazio")
+		}
+		return
+	}
+
+	m := &matcher{
+		schema:   v.schema,
+		children: n.Children,
+		assigned: make([]*elementDecl, len(n.Children)),
+		furthest: -1,
+	}
+	ok := m.particle(ct.content, 0, func(pos int) bool {
+		if pos == len(n.Children) {
+			return true
+		}
+		m.expect(pos, "fim do elemento")
+		return false
+	})
+	if !ok {
+		found := "fim do elemento"
+		if m.furthest >= 0 && m.furthest < len(n.Children) {
+			found = "<" + n.Children[m.furthest].XMLName.Local + ">"
+		}
+		v.addf(path, "conteúdo inválido: encontrado %s, esperado %s", found, strings.Join(m.expected, " | "))
+		return
+	}
+
+	for i, child := range n.Children {
+		if m.assigned[i] != nil {
+			v.validateElement(child, m.assigned[i], path+"/"+child.XMLName.Local)
+		}
+	}
+}
+
+func (v *validator) validateAttributes(n *node, ct *complexType, path string) {
+	declared := make(map[string]bool, len(ct.attributes))
+	for _, a := range ct.attributes {
+		declared[a.name] = true
+		value, present := "", false
+		for _, instanceAttr := range n.Attrs {
+			if instanceAttr.Name.Space == "" && instanceAttr.Name.Local == a.name {
+				value, present = instanceAttr.Value, true
+			}
+		}
+		if !present {
+			if a.required {
+				v.addf(path, "atributo obrigatório %q ausente", a.name)
+			}
+			continue
+		}
+		if a.simple != nil {
+			if err := a.simple.check(value); err != nil {
+				v.addf(path+"/@"+a.name, "%v", err)
+			}
+		}
+	}
+	for _, instanceAttr := range n.Attrs {
+		if instanceAttr.Name.Space == "" && instanceAttr.Name.Local != "xmlns" && !declared[instanceAttr.Name.Local] {
+			v.addf(path, "atributo %q não declarado", instanceAttr.Name.Local)
+		}
+	}
+}
+
+// matcher verifica a sequência de filhos contra o modelo de conteúdo usando
+// backtracking, registrando a declaração associada a cada filho
+type matcher struct {
+	schema   *Schema
+	children []*node
+	assigned []*elementDecl
+	furthest int
+	expected []string
+}
+
+func (m *matcher) expect(pos int, what string) {
+	if pos > m.furthest {
+		m.furthest = pos
+		m.expected = nil
+	}
+	if pos == m.furthest {
+		for _, existing := range m.expected {
+			if existing == what {
+				return
+			}
+		}
+		m.expected = append(m.expected, what)
+	}
+}
+
+func (m *matcher) particle(p *particle, pos int, k func(int) bool) bool {
+	return m.repeat(p, pos, 0, k)
+}
+
+func (m *matcher) repeat(p *particle, pos, count int, k func(int) bool) bool {
+	if p.maxOccurs == unbounded || count < p.maxOccurs {
+		matched := m.once(p, pos, func(next int) bool {
+			if next == pos {
+				return false
+			}
+			return m.repeat(p, next, count+1, k)
+		})
+		if matched {
+			return true
+		}
+	}
+	if count >= p.minOccurs {
+		return k(pos)
+	}
+	// Partículas que aceitam conteúdo vazio satisfazem o mínimo sem consumir filhos
+	return m.once(p, pos, func(next int) bool {
+		return next == pos && k(pos)
+	})
+}
+
+func (m *matcher) once(p *particle, pos int, k func(int) bool) bool {
+	switch p.kind {
+	case particleElement:
+		if pos < len(m.children) {
+			child := m.children[pos]
+			if child.XMLName.Local == p.element.name && child.XMLName.Space == m.schema.TargetNamespace {
+				m.assigned[pos] = p.element
+				return k(pos + 1)
+			}
+		}
+		m.expect(pos, "<"+p.element.name+">")
+		return false
+	case particleAny:
+		if pos < len(m.children) && m.namespaceAllowed(p.namespace, m.children[pos].XMLName.Space) {
+			m.assigned[pos] = nil
+			return k(pos + 1)
+		}
+		m.expect(pos, "qualquer elemento ("+p.namespace+")")
+		return false
+	case particleSequence:
+		return m.sequence(p.children, 0, pos, k)
+	case particleChoice:
+		for _, option := range p.children {
+			if m.particle(option, pos, k) {
+				return true
+			}
+		}
+		return false
+	}
+	return false
+}
+
+func (m *matcher) sequence(items []*particle, idx, pos int, k func(int) bool) bool {
+	if idx == len(items) {
+		return k(pos)
+	}
+	return m.particle(items[idx], pos, func(next int) bool {
+		return m.sequence(items, idx+1, next, k)
+	})
+}
+
+func (m *matcher) namespaceAllowed(constraint, namespace string) bool {
+	switch constraint {
+	case "", "##any":
+		return true
+	case "##other":
+		return namespace != m.schema.TargetNamespace && namespace != ""
+	case "##targetNamespace":
+		return namespace == m.schema.TargetNamespace
+	}
+	for _, allowed := range strings.Fields(constraint) {
+		if allowed == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// check valida um valor contra as facetas do tipo simples e de seus tipos base
+func (st *simpleType) check(value string) error {
+	if st.baseType != nil {
+		if err := st.baseType.check(value); err != nil {
+			return err
+		}
+	}
+
+	length := utf8.RuneCountInString(value)
+	if st.length >= 0 && length != st.length {
+		return fmt.Errorf("valor %q deve ter %d caracteres", value, st.length)
+	}
+	if st.minLength >= 0 && length < st.minLength {
+		return fmt.Errorf("valor %q deve ter no mínimo %d caracteres", value, st.minLength)
+	}
+	if st.maxLength >= 0 && length > st.maxLength {
+		return fmt.Errorf("valor %q deve ter no máximo %d caracteres", value, st.maxLength)
+	}
+
+	if len(st.enumerations) > 0 {
+		found := false
+		for _, allowed := range st.enumerations {
+			if value == allowed {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("valor %q não permitido (valores aceitos: %s)", value, strings.Join(st.enumerations, ", "))
+		}
+	}
+
+	// Padrões declarados na mesma restrição são alternativos entre si
+	if len(st.patterns) > 0 {
+		for _, re := range st.patterns {
+			if re.MatchString(value) {
+				return nil
+			}
+		}
+		return fmt.Errorf("valor %q não corresponde ao padrão %s", value, strings.Join(st.patternText, " | "))
+	}
+	return nil
+}
@@ -8,6 +8,8 @@ import (
 	"os/signal"
 	"syscall"
 	"time"
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/stock/internal/client"
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/stock/internal/domain"
 	"github.com/VitorMozer9/korp-teste-VitorMozer/services/stock/internal/repo/mem"
 	"github.com/VitorMozer9/korp-teste-VitorMozer/services/stock/internal/usecase"
 	httpTransport "github.com/VitorMozer9/korp-teste-VitorMozer/services/stock/internal/transport/http"
@@ -16,10 +18,15 @@ import (
 func main() {
 	port := getEnv("PORT", "8081")
 
+	// Tabela NCM (opcional): sem ela apenas o formato do código é validado
+	if path := getEnv("NCM_TABLE_PATH", ""); path != "" {
+		loadNCMTable(path)
+	}
+
 	// (Dependency Injection)
 	// Repository -> UseCase -> Handler -> Router
 	productRepo := mem.NewProductMemRepository()
-	productService := usecase.NewProductService(productRepo)
+	productService := usecase.NewProductService(productRepo, loadNotifier())
 	handler := httpTransport.NewHandler(productService)
 	router := httpTransport.NewRouter(handler)
 
@@ -63,3 +70,30 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// loadNotifier cria o notificador das entradas de estoque. Sem
+// BILLING_SERVICE_URL as entradas não são avisadas ao Billing Service
+func loadNotifier() domain.StockNotifier {
+	url := getEnv("BILLING_SERVICE_URL", "")
+	if url == "" {
+		return nil
+	}
+	log.Printf("Entradas de estoque notificadas a %s", url)
+	return client.NewBillingHTTPNotifier(url)
+}
+
+// loadNCMTable carrega a tabela NCM usada na validação dos produtos
+func loadNCMTable(path string) {
+	file, err := os.Open(path)
+	if err != nil {
+		log.Fatalf("Erro ao abrir tabela NCM: %v", err)
+	}
+	defer file.Close()
+
+	table, err := domain.ParseNCMTable(file)
+	if err != nil {
+		log.Fatalf("Erro ao carregar tabela NCM: %v", err)
+	}
+	domain.SetNCMTable(table)
+	log.Printf("Tabela NCM carregada: %d códigos", table.Len())
+}
@@ -0,0 +1,66 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/stock/internal/domain"
+)
+
+// BillingHTTPNotifier implementa StockNotifier enviando as entradas de
+// estoque ao Billing Service
+type BillingHTTPNotifier struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewBillingHTTPNotifier cria um notificador para o Billing Service
+func NewBillingHTTPNotifier(baseURL string) *BillingHTTPNotifier {
+	return &BillingHTTPNotifier{
+		baseURL: baseURL,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// NotifyStockEntry envia a entrada em segundo plano, para não atrasar a
+// operação de estoque. Falhas são apenas registradas em log: o Billing
+// Service permite reavaliar as pendências manualmente
+func (n *BillingHTTPNotifier) NotifyStockEntry(entry domain.StockEntry) {
+	go func() {
+		if err := n.send(entry); err != nil {
+			log.Printf("Falha ao notificar entrada do produto %s ao Billing Service: %v", entry.ProductID, err)
+		}
+	}()
+}
+
+// send publica a entrada no endpoint de pendências do Billing Service
+func (n *BillingHTTPNotifier) send(entry domain.StockEntry) error {
+	payload, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar entrada: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/api/backorders/stock-entries", n.baseURL)
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(payload))
+	if err != nil {
+		return fmt.Errorf("erro ao criar requisição: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("erro ao comunicar com Billing Service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Billing Service retornou erro: status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package domain
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// NCMTable representa a tabela de códigos NCM válidos (Nomenclatura Comum do Mercosul)
+type NCMTable struct {
+	codes map[string]struct{}
+}
+
+// siscomexNCMFile representa o formato JSON publicado pelo Siscomex
+type siscomexNCMFile struct {
+	Nomenclaturas []struct {
+		Codigo    string `json:"Codigo"`
+		Descricao string `json:"Descricao"`
+	} `json:"Nomenclaturas"`
+}
+
+// ParseNCMTable lê uma tabela NCM. Aceita o JSON do Siscomex ou um arquivo
+// texto/CSV com o código na primeira coluna (separada por ';' ou ',').
+// Apenas códigos de 8 dígitos (subitens) são considerados válidos
+func ParseNCMTable(r io.Reader) (*NCMTable, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler tabela NCM: %w", err)
+	}
+
+	table := &NCMTable{codes: make(map[string]struct{})}
+
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		var file siscomexNCMFile
+		if err := json.Unmarshal(trimmed, &file); err != nil {
+			return nil, fmt.Errorf("erro ao decodificar tabela NCM: %w", err)
+		}
+		for _, entry := range file.Nomenclaturas {
+			table.add(entry.Codigo)
+		}
+	} else {
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			fields := strings.FieldsFunc(line, func(r rune) bool { return r == ';' || r == ',' })
+			if len(fields) > 0 {
+				table.add(fields[0])
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("erro ao ler tabela NCM: %w", err)
+		}
+	}
+
+	if len(table.codes) == 0 {
+		return nil, fmt.Errorf("tabela NCM não contém nenhum código de 8 dígitos")
+	}
+	return table, nil
+}
+
+// add normaliza o código (remove pontuação) e o inclui se tiver 8 dígitos
+func (t *NCMTable) add(code string) {
+	normalized := NormalizeNCM(code)
+	if isDigits(normalized, 8) {
+		t.codes[normalized] = struct{}{}
+	}
+}
+
+// Contains verifica se o código NCM existe na tabela
+func (t *NCMTable) Contains(code string) bool {
+	_, exists := t.codes[NormalizeNCM(code)]
+	return exists
+}
+
+// Len retorna a quantidade de códigos carregados
+func (t *NCMTable) Len() int {
+	return len(t.codes)
+}
+
+// NormalizeNCM remove pontos e espaços do código NCM (ex: 8471.30.12 -> 84713012)
+func NormalizeNCM(code string) string {
+	return strings.NewReplacer(".", "", " ", "", "-", "").Replace(strings.TrimSpace(code))
+}
+
+var (
+	ncmTableMu sync.RWMutex
+	ncmTable   *NCMTable
+)
+
+// SetNCMTable define a tabela usada por Product.Validate. Sem tabela carregada
+// apenas o formato do NCM é validado
+func SetNCMTable(table *NCMTable) {
+	ncmTableMu.Lock()
+	defer ncmTableMu.Unlock()
+	ncmTable = table
+}
+
+func currentNCMTable() *NCMTable {
+	ncmTableMu.RLock()
+	defer ncmTableMu.RUnlock()
+	return ncmTable
+}
+
+func isDigits(value string, length int) bool {
+	if len(value) != length {
+		return false
+	}
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseNCMTable(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		codes    []string // Códigos esperados na tabela
+		absent   []string // Códigos que não devem constar
+		hasError bool
+	}{
+		{
+			name: "JSON do Siscomex",
+			data: `{"Data_Ultima_Atualizacao_NCM": "Vigente em 01/01/2025", "Nomenclaturas": [
+				{"Codigo": "84.71", "Descricao": "Máquinas automáticas para processamento de dados"},
+				{"Codigo": "8471.30", "Descricao": "- Máquinas portáteis"},
+				{"Codigo": "8471.30.12", "Descricao": "-- Com teclado alfanumérico"},
+				{"Codigo": "7318.15.00", "Descricao": "-- Outros parafusos e pinos ou pernos"}
+			]}`,
+			codes:  []string{"84713012", "73181500"},
+			absent: []string{"8471", "847130"},
+		},
+		{
+			name:   "CSV separado por ponto e vírgula",
+			data:   "codigo;descricao\n8471.30.12;Com teclado\n\n# comentário\n7318.15.00;Parafusos\n",
+			codes:  []string{"84713012", "73181500"},
+			absent: []string{"codigo"},
+		},
+		{
+			name:  "texto com vírgula e apenas o código",
+			data:  "84713012,Com teclado\r\n73181500\r\n",
+			codes: []string{"84713012", "73181500"},
+		},
+		{name: "sem códigos de 8 dígitos", data: "84.71;Capítulo\n8471.30;Posição\n", hasError: true},
+		{name: "JSON inválido", data: `{"Nomenclaturas": [`, hasError: true},
+		{name: "arquivo vazio", data: "", hasError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table, err := ParseNCMTable(strings.NewReader(tt.data))
+			if tt.hasError {
+				if err == nil {
+					t.Fatalf("ParseNCMTable() esperava erro, tabela com %d códigos", table.Len())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseNCMTable() erro inesperado: %v", err)
+			}
+			if table.Len() != len(tt.codes) {
+				t.Errorf("Len() = %d, esperado %d", table.Len(), len(tt.codes))
+			}
+			for _, code := range tt.codes {
+				if !table.Contains(code) {
+					t.Errorf("Contains(%q) = false, esperado true", code)
+				}
+			}
+			for _, code := range tt.absent {
+				if table.Contains(code) {
+					t.Errorf("Contains(%q) = true, esperado false", code)
+				}
+			}
+		})
+	}
+}
+
+func TestNCMTableContains(t *testing.T) {
+	table, err := ParseNCMTable(strings.NewReader("84713012\n"))
+	if err != nil {
+		t.Fatalf("ParseNCMTable() erro inesperado: %v", err)
+	}
+
+	// A consulta aceita o código com a pontuação usual
+	tests := []struct {
+		code     string
+		expected bool
+	}{
+		{code: "84713012", expected: true},
+		{code: "8471.30.12", expected: true},
+		{code: " 8471 30 12 ", expected: true},
+		{code: "8471-30-12", expected: true},
+		{code: "84713019", expected: false},
+		{code: "847130", expected: false},
+		{code: "", expected: false},
+	}
+	for _, tt := range tests {
+		if got := table.Contains(tt.code); got != tt.expected {
+			t.Errorf("Contains(%q) = %t, esperado %t", tt.code, got, tt.expected)
+		}
+	}
+}
@@ -2,6 +2,8 @@ package domain
 
 import (
 	"errors"
+	"math"
+	"strings"
 	"time"
 )
 
@@ -11,10 +13,79 @@ type Product struct {
 	Code        string    `json:"code"`
 	Description string    `json:"description"`
 	Balance     int       `json:"balance"` // Saldo em estoque
+	FiscalInfo
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
 
+// MerchandiseOrigin representa a origem da mercadoria (tabela de origem do ICMS, 0 a 8)
+type MerchandiseOrigin int
+
+const (
+	OriginNational         MerchandiseOrigin = 0 // Nacional
+	OriginForeignDirect    MerchandiseOrigin = 1 // Estrangeira - importação direta
+	OriginForeignInternal  MerchandiseOrigin = 2 // Estrangeira - adquirida no mercado interno
+	OriginNationalImport40 MerchandiseOrigin = 3 // Nacional com conteúdo de importação > 40%
+	OriginNationalPPB      MerchandiseOrigin = 4 // Nacional conforme processos produtivos básicos
+	OriginNationalImport   MerchandiseOrigin = 5 // Nacional com conteúdo de importação <= 40%
+	OriginForeignCamexDir  MerchandiseOrigin = 6 // Estrangeira - importação direta, sem similar nacional (CAMEX)
+	OriginForeignCamexInt  MerchandiseOrigin = 7 // Estrangeira - mercado interno, sem similar nacional (CAMEX)
+	OriginNationalImport70 MerchandiseOrigin = 8 // Nacional com conteúdo de importação > 70%
+)
+
+// IsValid verifica se a origem está na tabela oficial
+func (o MerchandiseOrigin) IsValid() bool {
+	return o >= OriginNational && o <= OriginNationalImport70
+}
+
+// DefaultUnit é a unidade comercial usada quando nenhuma é informada
+const DefaultUnit = "UN"
+
+// FiscalInfo agrupa a classificação fiscal do produto, necessária para emissão da nota
+type FiscalInfo struct {
+	NCM    string            `json:"ncm"`            // Nomenclatura Comum do Mercosul (8 dígitos)
+	CEST   string            `json:"cest,omitempty"` // Código Especificador da Substituição Tributária (7 dígitos)
+	Origin MerchandiseOrigin `json:"origin"`         // Origem da mercadoria
+	Unit   string            `json:"unit"`           // Unidade comercial (ex: UN, KG, CX)
+	// Peso líquido de uma unidade em kg (opcional), usado no cálculo do peso
+	// líquido transportado na nota
+	NetWeight float64 `json:"net_weight,omitempty"`
+}
+
+// Normalize padroniza os campos fiscais (remove pontuação e aplica unidade padrão)
+func (f *FiscalInfo) Normalize() {
+	f.NCM = NormalizeNCM(f.NCM)
+	f.CEST = NormalizeNCM(f.CEST)
+	f.Unit = strings.ToUpper(strings.TrimSpace(f.Unit))
+	if f.Unit == "" {
+		f.Unit = DefaultUnit
+	}
+	f.NetWeight = math.Round(f.NetWeight*1000) / 1000
+}
+
+// Validate valida a classificação fiscal, consultando a tabela NCM quando carregada
+func (f *FiscalInfo) Validate() error {
+	if !isDigits(f.NCM, 8) {
+		return ErrInvalidNCM
+	}
+	if table := currentNCMTable(); table != nil && !table.Contains(f.NCM) {
+		return ErrUnknownNCM
+	}
+	if f.CEST != "" && !isDigits(f.CEST, 7) {
+		return ErrInvalidCEST
+	}
+	if !f.Origin.IsValid() {
+		return ErrInvalidOrigin
+	}
+	if len(f.Unit) == 0 || len(f.Unit) > 6 {
+		return ErrInvalidUnit
+	}
+	if f.NetWeight < 0 {
+		return ErrInvalidNetWeight
+	}
+	return nil
+}
+
 // Erros de domínio
 var (
 	ErrProductNotFound      = errors.New("produto não encontrado")
@@ -22,6 +93,12 @@ var (
 	ErrInvalidProduct       = errors.New("produto inválido")
 	ErrDuplicateCode        = errors.New("código de produto já existe")
 	ErrInvalidQuantity      = errors.New("quantidade inválida")
+	ErrInvalidNCM           = errors.New("NCM deve conter 8 dígitos")
+	ErrUnknownNCM           = errors.New("NCM não encontrado na tabela")
+	ErrInvalidCEST          = errors.New("CEST deve conter 7 dígitos")
+	ErrInvalidOrigin        = errors.New("origem da mercadoria inválida")
+	ErrInvalidUnit          = errors.New("unidade comercial inválida")
+	ErrInvalidNetWeight     = errors.New("peso líquido unitário não pode ser negativo")
 )
 
 // Validate -> valida os dados do produto
@@ -35,7 +112,7 @@ func (p *Product) Validate() error {
 	if p.Balance < 0 {
 		return ErrInvalidProduct
 	}
-	return nil
+	return p.FiscalInfo.Validate()
 }
 
 // CanReserve verifica se há saldo suficiente para reserva
@@ -56,6 +133,17 @@ func (p *Product) Reserve(quantity int) error {
 	return nil
 }
 
+// Restock devolve quantidade ao saldo do produto (usado ao fechar nota de
+// devolução)
+func (p *Product) Restock(quantity int) error {
+	if quantity <= 0 {
+		return ErrInvalidQuantity
+	}
+	p.Balance += quantity
+	p.UpdatedAt = time.Now()
+	return nil
+}
+
 // ProductRepository define o contrato para persistência de produtos
 // (Interface Segregation Principle - ISP)
 type ProductRepository interface {
@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestFiscalInfoValidate(t *testing.T) {
+	valid := FiscalInfo{NCM: "84713012", CEST: "2104700", Origin: OriginNational, Unit: "UN", NetWeight: 1.5}
+
+	tests := []struct {
+		name   string
+		change func(*FiscalInfo)
+		err    error
+	}{
+		{name: "válido", change: func(*FiscalInfo) {}},
+		{name: "sem CEST", change: func(f *FiscalInfo) { f.CEST = "" }},
+		{name: "NCM com 7 dígitos", change: func(f *FiscalInfo) { f.NCM = "8471301" }, err: ErrInvalidNCM},
+		{name: "NCM com letras", change: func(f *FiscalInfo) { f.NCM = "8471301A" }, err: ErrInvalidNCM},
+		{name: "CEST com 6 dígitos", change: func(f *FiscalInfo) { f.CEST = "210470" }, err: ErrInvalidCEST},
+		{name: "origem 8", change: func(f *FiscalInfo) { f.Origin = OriginNationalImport70 }},
+		{name: "origem 9", change: func(f *FiscalInfo) { f.Origin = 9 }, err: ErrInvalidOrigin},
+		{name: "origem negativa", change: func(f *FiscalInfo) { f.Origin = -1 }, err: ErrInvalidOrigin},
+		{name: "sem unidade", change: func(f *FiscalInfo) { f.Unit = "" }, err: ErrInvalidUnit},
+		{name: "unidade longa", change: func(f *FiscalInfo) { f.Unit = "CAIXA12" }, err: ErrInvalidUnit},
+		{name: "peso negativo", change: func(f *FiscalInfo) { f.NetWeight = -0.1 }, err: ErrInvalidNetWeight},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fiscal := valid
+			tt.change(&fiscal)
+			if err := fiscal.Validate(); !errors.Is(err, tt.err) {
+				t.Errorf("Validate() erro = %v, esperado %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestFiscalInfoValidateNCMTable(t *testing.T) {
+	table, err := ParseNCMTable(strings.NewReader("8471.30.12\n7318.15.00\n"))
+	if err != nil {
+		t.Fatalf("ParseNCMTable() erro inesperado: %v", err)
+	}
+	SetNCMTable(table)
+	t.Cleanup(func() { SetNCMTable(nil) })
+
+	// Com tabela carregada, o NCM precisa constar dela além de ter 8 dígitos
+	tests := []struct {
+		ncm string
+		err error
+	}{
+		{ncm: "84713012"},
+		{ncm: "73181500"},
+		{ncm: "84713019", err: ErrUnknownNCM},
+		{ncm: "8471301", err: ErrInvalidNCM},
+	}
+	for _, tt := range tests {
+		fiscal := FiscalInfo{NCM: tt.ncm, Unit: DefaultUnit}
+		if err := fiscal.Validate(); !errors.Is(err, tt.err) {
+			t.Errorf("Validate(%s) erro = %v, esperado %v", tt.ncm, err, tt.err)
+		}
+	}
+}
+
+func TestFiscalInfoNormalize(t *testing.T) {
+	fiscal := FiscalInfo{NCM: " 8471.30.12 ", CEST: "21.047.00", Unit: " kg ", NetWeight: 0.12345}
+	fiscal.Normalize()
+
+	expected := FiscalInfo{NCM: "84713012", CEST: "2104700", Unit: "KG", NetWeight: 0.123}
+	if fiscal != expected {
+		t.Errorf("Normalize() = %+v, esperado %+v", fiscal, expected)
+	}
+
+	empty := FiscalInfo{}
+	empty.Normalize()
+	if empty.Unit != DefaultUnit {
+		t.Errorf("Normalize() unidade = %q, esperado %q", empty.Unit, DefaultUnit)
+	}
+}
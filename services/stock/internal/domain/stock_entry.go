@@ -0,0 +1,15 @@
+package domain
+
+// StockEntry representa uma entrada de saldo de um produto (cadastro com
+// saldo inicial, aumento do saldo ou devolução)
+type StockEntry struct {
+	ProductID string `json:"product_id"`
+	Quantity  int    `json:"quantity"` // Quantidade que entrou
+	Balance   int    `json:"balance"`  // Saldo após a entrada
+}
+
+// StockNotifier avisa outros serviços sobre entradas de estoque
+// (o Billing Service reavalia as pendências de entrega do produto)
+type StockNotifier interface {
+	NotifyStockEntry(entry StockEntry)
+}
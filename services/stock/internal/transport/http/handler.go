@@ -22,16 +22,26 @@ func NewHandler(productService *usecase.ProductService) *Handler {
 
 // CreateProductRequest representa o payload de criação de produto
 type CreateProductRequest struct {
-	Code        string `json:"code"`
-	Description string `json:"description"`
-	Balance     int    `json:"balance"`
+	Code        string                   `json:"code"`
+	Description string                   `json:"description"`
+	Balance     int                      `json:"balance"`
+	NCM         string                   `json:"ncm"`
+	CEST        string                   `json:"cest"`
+	Origin      domain.MerchandiseOrigin `json:"origin"`
+	Unit        string                   `json:"unit"`
+	NetWeight   float64                  `json:"net_weight"` // Peso líquido unitário (kg)
 }
 
 // UpdateProductRequest representa o payload de atualização
 type UpdateProductRequest struct {
-	Code        string `json:"code"`
-	Description string `json:"description"`
-	Balance     int    `json:"balance"`
+	Code        string                   `json:"code"`
+	Description string                   `json:"description"`
+	Balance     int                      `json:"balance"`
+	NCM         string                   `json:"ncm"`
+	CEST        string                   `json:"cest"`
+	Origin      domain.MerchandiseOrigin `json:"origin"`
+	Unit        string                   `json:"unit"`
+	NetWeight   float64                  `json:"net_weight"` // Peso líquido unitário (kg)
 }
 
 // ErrorResponse representa uma resposta de erro
@@ -48,11 +58,19 @@ func (h *Handler) CreateProduct(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	product, err := h.productService.CreateProduct(req.Code, req.Description, req.Balance)
+	product, err := h.productService.CreateProduct(req.Code, req.Description, req.Balance, domain.FiscalInfo{
+		NCM:    req.NCM,
+		CEST:   req.CEST,
+		Origin: req.Origin,
+		Unit:   req.Unit,
+		NetWeight: req.NetWeight,
+	})
 	if err != nil {
 		switch err {
 		case domain.ErrInvalidProduct:
 			respondError(w, http.StatusBadRequest, "Dados do produto inválidos", err.Error())
+		case domain.ErrInvalidNCM, domain.ErrUnknownNCM, domain.ErrInvalidCEST, domain.ErrInvalidOrigin, domain.ErrInvalidUnit, domain.ErrInvalidNetWeight:
+			respondError(w, http.StatusBadRequest, "Classificação fiscal inválida", err.Error())
 		case domain.ErrDuplicateCode:
 			respondError(w, http.StatusConflict, "Código de produto já existe", err.Error())
 		default:
@@ -102,13 +120,21 @@ func (h *Handler) UpdateProduct(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	product, err := h.productService.UpdateProduct(id, req.Code, req.Description, req.Balance)
+	product, err := h.productService.UpdateProduct(id, req.Code, req.Description, req.Balance, domain.FiscalInfo{
+		NCM:    req.NCM,
+		CEST:   req.CEST,
+		Origin: req.Origin,
+		Unit:   req.Unit,
+		NetWeight: req.NetWeight,
+	})
 	if err != nil {
 		switch err {
 		case domain.ErrProductNotFound:
 			respondError(w, http.StatusNotFound, "Produto não encontrado", err.Error())
 		case domain.ErrInvalidProduct:
 			respondError(w, http.StatusBadRequest, "Dados do produto inválidos", err.Error())
+		case domain.ErrInvalidNCM, domain.ErrUnknownNCM, domain.ErrInvalidCEST, domain.ErrInvalidOrigin, domain.ErrInvalidUnit, domain.ErrInvalidNetWeight:
+			respondError(w, http.StatusBadRequest, "Classificação fiscal inválida", err.Error())
 		case domain.ErrDuplicateCode:
 			respondError(w, http.StatusConflict, "Código de produto já existe", err.Error())
 		default:
@@ -154,6 +180,24 @@ func (h *Handler) ReserveStock(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, responses)
 }
 
+// RestockStock devolve quantidades ao estoque (notas de devolução do serviço
+// de Billing)
+func (h *Handler) RestockStock(w http.ResponseWriter, r *http.Request) {
+	var requests []domain.ReservationRequest
+	if err := json.NewDecoder(r.Body).Decode(&requests); err != nil {
+		respondError(w, http.StatusBadRequest, "Payload inválido", err.Error())
+		return
+	}
+
+	responses, err := h.productService.RestockMultipleProducts(requests)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Erro ao devolver estoque", err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, responses)
+}
+
 // Health endpoint para healthcheck
 func (h *Handler) Health(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, map[string]string{
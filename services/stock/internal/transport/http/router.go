@@ -45,6 +45,8 @@ func NewRouter(handler *Handler) http.Handler {
 
 			// Endpoint para reserva de estoque (chamado pelo Billing Service)
 			r.Post("/reserve", handler.ReserveStock)
+			// Endpoint para devolução ao estoque (notas de devolução do Billing Service)
+			r.Post("/restock", handler.RestockStock)
 		})
 	})
 
@@ -9,27 +9,31 @@ import (
 // ProductService contém a lógica de negócio de produtos
 // (Single Responsibility Principle - SRP: apenas lógica de negócio)
 type ProductService struct {
-	repo domain.ProductRepository
+	repo     domain.ProductRepository
+	notifier domain.StockNotifier // Opcional: sem notificador, as entradas não são avisadas
 }
 
 // NewProductService cria uma nova instância do serviço
 // (Dependency Injection via construtor)
-func NewProductService(repo domain.ProductRepository) *ProductService {
+func NewProductService(repo domain.ProductRepository, notifier domain.StockNotifier) *ProductService {
 	return &ProductService{
-		repo: repo,
+		repo:     repo,
+		notifier: notifier,
 	}
 }
 
 // CreateProduct cria um novo produto
-func (s *ProductService) CreateProduct(code, description string, balance int) (*domain.Product, error) {
+func (s *ProductService) CreateProduct(code, description string, balance int, fiscal domain.FiscalInfo) (*domain.Product, error) {
 	product := &domain.Product{
 		ID:          uuid.New().String(),
 		Code:        code,
 		Description: description,
 		Balance:     balance,
+		FiscalInfo:  fiscal,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
+	product.FiscalInfo.Normalize()
 
 	// Valida o produto
 	if err := product.Validate(); err != nil {
@@ -41,6 +45,9 @@ func (s *ProductService) CreateProduct(code, description string, balance int) (*
 		return nil, err
 	}
 
+	// O saldo inicial é uma entrada de estoque
+	s.notifyEntry(product, product.Balance)
+
 	return product, nil
 }
 
@@ -55,30 +62,37 @@ func (s *ProductService) GetAllProducts() ([]*domain.Product, error) {
 }
 
 // UpdateProduct atualiza um produto
-func (s *ProductService) UpdateProduct(id, code, description string, balance int) (*domain.Product, error) {
+func (s *ProductService) UpdateProduct(id, code, description string, balance int, fiscal domain.FiscalInfo) (*domain.Product, error) {
 	// Busca o produto existente
 	product, err := s.repo.FindByID(id)
 	if err != nil {
 		return nil, err
 	}
 
-	// Atualiza os campos
-	product.Code = code
-	product.Description = description
-	product.Balance = balance
-	product.UpdatedAt = time.Now()
+	// Atualiza os campos em uma cópia, para que dados inválidos não alterem
+	// o produto armazenado
+	updated := *product
+	updated.Code = code
+	updated.Description = description
+	updated.Balance = balance
+	updated.FiscalInfo = fiscal
+	updated.FiscalInfo.Normalize()
+	updated.UpdatedAt = time.Now()
 
 	// Valida
-	if err := product.Validate(); err != nil {
+	if err := updated.Validate(); err != nil {
 		return nil, err
 	}
 
 	// Persiste
-	if err := s.repo.Update(product); err != nil {
+	if err := s.repo.Update(&updated); err != nil {
 		return nil, err
 	}
 
-	return product, nil
+	// Aumento do saldo é uma entrada de estoque
+	s.notifyEntry(&updated, updated.Balance-product.Balance)
+
+	return &updated, nil
 }
 
 // DeleteProduct deleta um produto
@@ -153,6 +167,52 @@ func (s *ProductService) ReserveMultipleProducts(requests []domain.ReservationRe
 	return responses, nil
 }
 
+// RestockMultipleProducts devolve ao estoque as quantidades de múltiplos
+// produtos (notas de devolução do serviço de Billing)
+func (s *ProductService) RestockMultipleProducts(requests []domain.ReservationRequest) ([]domain.ReservationResponse, error) {
+	responses := make([]domain.ReservationResponse, 0, len(requests))
+
+	for _, req := range requests {
+		product, err := s.repo.FindByID(req.ProductID)
+		if err == nil {
+			err = product.Restock(req.Quantity)
+		}
+		if err == nil {
+			err = s.repo.Update(product)
+		}
+		if err != nil {
+			responses = append(responses, domain.ReservationResponse{
+				Success:      false,
+				ProductID:    req.ProductID,
+				ErrorMessage: err.Error(),
+			})
+			continue
+		}
+
+		s.notifyEntry(product, req.Quantity)
+
+		responses = append(responses, domain.ReservationResponse{
+			Success:    true,
+			ProductID:  req.ProductID,
+			NewBalance: product.Balance,
+		})
+	}
+
+	return responses, nil
+}
+
+// notifyEntry avisa a entrada de estoque do produto, se houver notificador
+func (s *ProductService) notifyEntry(product *domain.Product, quantity int) {
+	if s.notifier == nil || quantity <= 0 {
+		return
+	}
+	s.notifier.NotifyStockEntry(domain.StockEntry{
+		ProductID: product.ID,
+		Quantity:  quantity,
+		Balance:   product.Balance,
+	})
+}
+
 // CheckAvailability verifica se há estoque disponível (sem reservar)
 func (s *ProductService) CheckAvailability(productID string, quantity int) (bool, error) {
 	product, err := s.repo.FindByID(productID)
@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/stock/internal/domain"
+	"github.com/VitorMozer9/korp-teste-VitorMozer/services/stock/internal/repo/mem"
+)
+
+func TestUpdateProductKeepsStoredOnError(t *testing.T) {
+	repo := mem.NewProductMemRepository()
+	service := NewProductService(repo, nil)
+	fiscal := domain.FiscalInfo{NCM: "84713012", Unit: "UN"}
+
+	product, err := service.CreateProduct("P001", "Notebook", 10, fiscal)
+	if err != nil {
+		t.Fatalf("CreateProduct() erro inesperado: %v", err)
+	}
+	if _, err := service.CreateProduct("P002", "Mouse", 5, fiscal); err != nil {
+		t.Fatalf("CreateProduct() erro inesperado: %v", err)
+	}
+
+	// Dados recusados na validação ou na gravação não alteram o produto
+	tests := []struct {
+		name   string
+		code   string
+		fiscal domain.FiscalInfo
+		err    error
+	}{
+		{name: "NCM inválido", code: "P001-A", fiscal: domain.FiscalInfo{NCM: "8471", Unit: "UN"}, err: domain.ErrInvalidNCM},
+		{name: "código duplicado", code: "P002", fiscal: fiscal, err: domain.ErrDuplicateCode},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := service.UpdateProduct(product.ID, tt.code, "Alterado", 99, tt.fiscal); !errors.Is(err, tt.err) {
+				t.Fatalf("UpdateProduct() erro = %v, esperado %v", err, tt.err)
+			}
+			stored, err := repo.FindByID(product.ID)
+			if err != nil {
+				t.Fatalf("FindByID() erro inesperado: %v", err)
+			}
+			if stored.Code != "P001" || stored.Description != "Notebook" || stored.Balance != 10 || stored.NCM != "84713012" {
+				t.Errorf("produto alterado após a recusa: %+v", stored)
+			}
+			if found, err := repo.FindByCode("P001"); err != nil || found.ID != product.ID {
+				t.Errorf("FindByCode(P001) = %v, %v; esperado o produto original", found, err)
+			}
+		})
+	}
+
+	updated, err := service.UpdateProduct(product.ID, "P001-A", "Notebook 14", 12, domain.FiscalInfo{NCM: "8471.30.12", Unit: "un"})
+	if err != nil {
+		t.Fatalf("UpdateProduct() erro inesperado: %v", err)
+	}
+	if updated.Code != "P001-A" || updated.Balance != 12 || updated.NCM != "84713012" || updated.Unit != "UN" {
+		t.Errorf("UpdateProduct() = %+v", updated)
+	}
+	if _, err := repo.FindByCode("P001-A"); err != nil {
+		t.Errorf("FindByCode(P001-A) erro inesperado: %v", err)
+	}
+}
@@ -12,8 +12,16 @@ import { MatSnackBar, MatSnackBarModule } from '@angular/material/snack-bar';
 import { MatTableModule } from '@angular/material/table';
 import { ProductService } from '../../../services/product.service';
 import { InvoiceService } from '../../../services/invoice.service';
+import { CustomerService } from '../../../services/customer.service';
+import { IssuerService } from '../../../services/issuer.service';
+import { ServiceCatalogService } from '../../../services/service-catalog.service';
+import { CarrierService } from '../../../services/carrier.service';
 import { Product } from '../../../models/product.model';
-import { CreateInvoiceDTO, CreateInvoiceItemDTO } from '../../../models/invoice.model';
+import { Customer } from '../../../models/customer.model';
+import { Issuer } from '../../../models/issuer.model';
+import { Service } from '../../../models/service.model';
+import { Carrier } from '../../../models/carrier.model';
+import { CreateInvoiceDTO, CreateInvoiceItemDTO, FREIGHT_MODES, FreightMode, InvoiceModel, isInvoiceBatch, ItemType, PAYMENT_METHODS, PaymentTerms, TransportDTO } from '../../../models/invoice.model';
 
 @Component({
   selector: 'app-invoice-form',
@@ -36,24 +44,183 @@ import { CreateInvoiceDTO, CreateInvoiceItemDTO } from '../../../models/invoice.
 export class InvoiceFormComponent implements OnInit {
   invoiceForm: FormGroup;
   products: Product[] = [];
+  customers: Customer[] = [];
+  issuers: Issuer[] = [];
+  services: Service[] = [];
+  carriers: Carrier[] = [];
   loading = false;
   loadingProducts = true;
   displayedColumns: string[] = ['product', 'quantity', 'actions'];
+  readonly InvoiceModel = InvoiceModel;
+  readonly freightModes = FREIGHT_MODES;
+  readonly paymentMethods = PAYMENT_METHODS;
 
   constructor(
     private fb: FormBuilder,
     private productService: ProductService,
     private invoiceService: InvoiceService,
+    private customerService: CustomerService,
+    private issuerService: IssuerService,
+    private serviceCatalog: ServiceCatalogService,
+    private carrierService: CarrierService,
     private router: Router,
     private snackBar: MatSnackBar
   ) {
     this.invoiceForm = this.fb.group({
-      items: this.fb.array([], Validators.required)
+      model: [InvoiceModel.NFE, Validators.required],
+      issuer_id: ['', Validators.required],
+      customer_id: ['', Validators.required],
+      items: this.fb.array([], Validators.required),
+      charges: this.fb.group({
+        freight: [0, Validators.min(0)],
+        insurance: [0, Validators.min(0)],
+        other: [0, Validators.min(0)]
+      }),
+      transport: this.fb.group({
+        freight_mode: [FreightMode.NO_TRANSPORT, Validators.required],
+        carrier_id: [''],
+        plate: ['', Validators.pattern(/^[A-Za-z]{3}-?[0-9][A-Za-z0-9][0-9]{2}$/)],
+        vehicle_uf: ['', Validators.maxLength(2)],
+        volumes: [0, Validators.min(0)],
+        species: [''],
+        gross_weight: [0, Validators.min(0)],
+        net_weight: [0, Validators.min(0)]
+      }),
+      payment: this.fb.group({
+        method: [''],
+        description: ['', Validators.maxLength(60)],
+        installments: [1, [Validators.min(1), Validators.max(120)]],
+        interval_days: [0, Validators.min(0)]
+      })
     });
   }
 
   ngOnInit(): void {
     this.loadProducts();
+    this.loadCustomers();
+    this.loadIssuers();
+    this.loadServices();
+    this.loadCarriers();
+    this.invoiceForm.get('model')?.valueChanges.subscribe(model => this.onModelChange(model));
+  }
+
+  /**
+   * Na NFC-e o consumidor pode não ser identificado, então o cliente deixa de
+   * ser obrigatório. A NFS-e recebe apenas serviços, então os itens são
+   * recriados ao entrar ou sair dela
+   */
+  onModelChange(model: InvoiceModel): void {
+    const customer = this.invoiceForm.get('customer_id');
+    customer?.setValidators(model === InvoiceModel.NFCE ? [] : [Validators.required]);
+    customer?.updateValueAndValidity();
+
+    this.items.clear();
+    this.addItem();
+  }
+
+  get isConsumer(): boolean {
+    return this.invoiceForm.get('model')?.value === InvoiceModel.NFCE;
+  }
+
+  get isService(): boolean {
+    return this.invoiceForm.get('model')?.value === InvoiceModel.NFSE;
+  }
+
+  /**
+   * Série do emitente no modelo selecionado
+   */
+  getSeries(issuer: Issuer): number {
+    if (this.isService) return issuer.service_series;
+    return this.isConsumer ? issuer.consumer_series : issuer.series;
+  }
+
+  /**
+   * Carrega os emitentes e seleciona o primeiro por padrão
+   */
+  loadIssuers(): void {
+    this.issuerService.getIssuers().subscribe({
+      next: (issuers) => {
+        this.issuers = issuers;
+        if (issuers.length > 0) {
+          this.invoiceForm.get('issuer_id')?.setValue(issuers[0].id);
+        }
+      },
+      error: (error) => this.showError(error.message)
+    });
+  }
+
+  /**
+   * Carrega lista de clientes (destinatários)
+   */
+  loadCustomers(): void {
+    this.customerService.getCustomers().subscribe({
+      next: (customers) => this.customers = customers,
+      error: (error) => this.showError(error.message)
+    });
+  }
+
+  /**
+   * Carrega o catálogo de serviços faturados em NFS-e
+   */
+  loadServices(): void {
+    this.serviceCatalog.getServices().subscribe({
+      next: (services) => this.services = services,
+      error: (error) => this.showError(error.message)
+    });
+  }
+
+  /**
+   * Carrega as transportadoras informadas no transporte da NF-e
+   */
+  loadCarriers(): void {
+    this.carrierService.getCarriers().subscribe({
+      next: (carriers) => this.carriers = carriers,
+      error: (error) => this.showError(error.message)
+    });
+  }
+
+  /**
+   * Indica se a modalidade de frete selecionada envolve transporte
+   */
+  get hasFreight(): boolean {
+    return this.invoiceForm.get('transport.freight_mode')?.value !== FreightMode.NO_TRANSPORT;
+  }
+
+  /**
+   * Monta os dados de transporte da NF-e. Transportadora e veículo só seguem
+   * quando há frete; o peso líquido zerado é calculado pelos produtos
+   */
+  buildTransport(): TransportDTO {
+    const t = this.invoiceForm.get('transport')?.value;
+    const transport: TransportDTO = {
+      freight_mode: t.freight_mode,
+      volumes: t.volumes || undefined,
+      species: t.species || undefined,
+      gross_weight: t.gross_weight || undefined,
+      net_weight: t.net_weight || undefined
+    };
+    if (this.hasFreight) {
+      transport.carrier_id = t.carrier_id || undefined;
+      if (t.plate) {
+        transport.vehicle = { plate: t.plate, uf: t.vehicle_uf };
+      }
+    }
+    return transport;
+  }
+
+  /**
+   * Monta a condição de pagamento; sem meio selecionado a nota sai sem
+   * pagamento. A NFC-e é sempre à vista
+   */
+  buildPayment(): PaymentTerms | undefined {
+    const p = this.invoiceForm.get('payment')?.value;
+    if (!p.method) return undefined;
+    return {
+      method: p.method,
+      description: p.method === '99' ? p.description : undefined,
+      installments: this.isConsumer ? 1 : p.installments,
+      interval_days: this.isConsumer ? 0 : p.interval_days
+    };
   }
 
   /**
@@ -83,10 +250,18 @@ export class InvoiceFormComponent implements OnInit {
   }
 
   /**
-   * Cria um novo FormGroup para item
+   * Cria um novo FormGroup para item (produto ou serviço, conforme o modelo)
    */
   createItem(): FormGroup {
+    if (this.isService) {
+      return this.fb.group({
+        type: [ItemType.SERVICE],
+        service_id: ['', Validators.required],
+        quantity: [1, [Validators.required, Validators.min(1)]]
+      });
+    }
     return this.fb.group({
+      type: [ItemType.GOODS],
       product_id: ['', Validators.required],
       quantity: [1, [Validators.required, Validators.min(1)]]
     });
@@ -111,6 +286,7 @@ export class InvoiceFormComponent implements OnInit {
    */
   getSelectedProduct(index: number): Product | undefined {
     const productId = this.items.at(index).get('product_id')?.value;
+    if (!productId) return undefined;
     return this.products.find(p => p.id === productId);
   }
 
@@ -145,12 +321,22 @@ export class InvoiceFormComponent implements OnInit {
 
     this.loading = true;
     const invoice: CreateInvoiceDTO = {
-      items: this.items.value as CreateInvoiceItemDTO[]
+      model: this.invoiceForm.get('model')?.value,
+      issuer_id: this.invoiceForm.get('issuer_id')?.value,
+      customer_id: this.invoiceForm.get('customer_id')?.value || undefined,
+      items: this.items.value as CreateInvoiceItemDTO[],
+      // A NFS-e não tem frete, seguro nem outras despesas
+      charges: this.isService ? undefined : this.invoiceForm.get('charges')?.value,
+      // Transporte é informado apenas na NF-e; NFC-e e NFS-e seguem sem o grupo
+      transport: this.isService || this.isConsumer ? undefined : this.buildTransport(),
+      payment: this.buildPayment()
     };
 
     this.invoiceService.createInvoice(invoice).subscribe({
-      next: () => {
-        this.showSuccess('Nota fiscal criada com sucesso!');
+      next: (created) => {
+        this.showSuccess(isInvoiceBatch(created)
+          ? `${created.invoices.length} notas fiscais criadas (limite de 990 itens por nota)`
+          : 'Nota fiscal criada com sucesso!');
         this.router.navigate(['/invoices']);
       },
       error: (error) => {
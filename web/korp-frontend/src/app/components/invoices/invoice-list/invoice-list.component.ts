@@ -9,7 +9,7 @@ import { MatProgressSpinnerModule } from '@angular/material/progress-spinner';
 import { MatChipsModule } from '@angular/material/chips';
 import { Subscription } from 'rxjs';
 import { InvoiceService } from '../../../services/invoice.service';
-import { Invoice, InvoiceStatus } from '../../../models/invoice.model';
+import { Invoice, InvoiceModel, InvoiceStatus } from '../../../models/invoice.model';
 
 @Component({
   selector: 'app-invoice-list',
@@ -82,6 +82,24 @@ export class InvoiceListComponent implements OnInit, OnDestroy {
     return invoice.items.reduce((sum, item) => sum + item.quantity, 0);
   }
 
+  /**
+   * URL de download do XML da NF-e, ou da DPS nas notas de serviço (apenas
+   * notas fechadas)
+   */
+  getXmlUrl(invoice: Invoice): string {
+    if (invoice.model === InvoiceModel.NFSE) {
+      return this.invoiceService.getNfseUrl(invoice.id);
+    }
+    return this.invoiceService.getXmlUrl(invoice.id);
+  }
+
+  /**
+   * URL do DANFE (PDF) da nota fechada
+   */
+  getDanfeUrl(invoice: Invoice): string {
+    return this.invoiceService.getDanfeUrl(invoice.id);
+  }
+
   /**
    * Formata data para exibição
    */
@@ -1,9 +1,12 @@
 import { Component, OnInit, OnDestroy } from '@angular/core';
 import { CommonModule } from '@angular/common';
+import { FormsModule } from '@angular/forms';
 import { ActivatedRoute, Router } from '@angular/router';
 import { MatButtonModule } from '@angular/material/button';
 import { MatCardModule } from '@angular/material/card';
 import { MatIconModule } from '@angular/material/icon';
+import { MatFormFieldModule } from '@angular/material/form-field';
+import { MatInputModule } from '@angular/material/input';
 import { MatProgressSpinnerModule } from '@angular/material/progress-spinner';
 import { MatSnackBar, MatSnackBarModule } from '@angular/material/snack-bar';
 import { MatTableModule } from '@angular/material/table';
@@ -11,16 +14,19 @@ import { MatChipsModule } from '@angular/material/chips';
 import { MatDialog, MatDialogModule } from '@angular/material/dialog';
 import { Subscription } from 'rxjs';
 import { InvoiceService } from '../../../services/invoice.service';
-import { Invoice, InvoiceStatus } from '../../../models/invoice.model';
+import { AuthorizationStatus, CorrectionLetter, Installment, Invoice, InvoiceStatus, PixCharge } from '../../../models/invoice.model';
 
 @Component({
   selector: 'app-invoice-print',
   standalone: true,
   imports: [
     CommonModule,
+    FormsModule,
     MatButtonModule,
     MatCardModule,
     MatIconModule,
+    MatFormFieldModule,
+    MatInputModule,
     MatProgressSpinnerModule,
     MatSnackBarModule,
     MatTableModule,
@@ -37,6 +43,14 @@ export class InvoicePrintComponent implements OnInit, OnDestroy {
   error: string | null = null;
   InvoiceStatus = InvoiceStatus;
   displayedColumns: string[] = ['code', 'description', 'quantity'];
+  corrections: CorrectionLetter[] = [];
+  correctionText = '';
+  sendingCorrection = false;
+  paymentAmount: number | null = null;
+  paymentInstallment = '';
+  paymentDate = '';
+  sendingPayment = false;
+  pixCharge: PixCharge | null = null;
   
   private subscription?: Subscription;
 
@@ -71,6 +85,14 @@ export class InvoicePrintComponent implements OnInit, OnDestroy {
       next: (invoice) => {
         this.invoice = invoice;
         this.loading = false;
+        // Notas com devoluções exibem o saldo já devolvido de cada item
+        this.displayedColumns = invoice.returns?.length
+          ? ['code', 'description', 'quantity', 'returned']
+          : ['code', 'description', 'quantity'];
+        if (this.isAuthorized()) {
+          this.loadCorrections();
+        }
+        this.loadPixCharge();
       },
       error: (error) => {
         this.error = error.message;
@@ -93,9 +115,15 @@ export class InvoicePrintComponent implements OnInit, OnDestroy {
     
     this.invoiceService.printInvoice(this.invoice.id).subscribe({
       next: (response) => {
-        this.showSuccess(response.message);
         this.invoice = response.invoice || this.invoice;
         this.printing = false;
+
+        // Rejeições da SEFAZ mantêm o usuário na tela para reenviar a nota
+        if (!response.success) {
+          this.showError(response.message);
+          return;
+        }
+        this.showSuccess(response.message);
         
         // Aguarda 2 segundos e volta para lista
         setTimeout(() => {
@@ -109,6 +137,210 @@ export class InvoicePrintComponent implements OnInit, OnDestroy {
     });
   }
 
+  /**
+   * Reenvia à SEFAZ uma nota pendente ou rejeitada
+   */
+  authorizeInvoice(): void {
+    if (!this.invoice) return;
+
+    this.printing = true;
+    this.invoiceService.authorizeInvoice(this.invoice.id).subscribe({
+      next: (response) => {
+        this.invoice = response.invoice || this.invoice;
+        this.printing = false;
+        if (response.success) {
+          this.showSuccess(response.message);
+        } else {
+          this.showError(response.message);
+        }
+      },
+      error: (error) => {
+        this.showError(error.message);
+        this.printing = false;
+      }
+    });
+  }
+
+  /**
+   * URL do DANFE (PDF) gerado pelo Billing Service
+   */
+  getDanfeUrl(): string {
+    return this.invoice ? this.invoiceService.getDanfeUrl(this.invoice.id) : '';
+  }
+
+  /**
+   * Indica se a nota fechada ainda tem saldo a devolver
+   */
+  canReturn(): boolean {
+    const invoice = this.invoice;
+    if (!invoice || invoice.status !== InvoiceStatus.CLOSED) return false;
+    if (invoice.model === 'NFSE' || invoice.return_of || !invoice.customer) return false;
+    return invoice.items.some(item => item.quantity > (item.returned_quantity || 0));
+  }
+
+  /**
+   * Cria a nota de devolução do saldo ainda não devolvido e abre a nova nota
+   * para impressão, que devolve as quantidades ao estoque
+   */
+  createReturn(): void {
+    if (!this.invoice) return;
+
+    this.printing = true;
+    this.invoiceService.createReturn(this.invoice.id).subscribe({
+      next: (ret) => {
+        this.printing = false;
+        this.showSuccess(`Nota de devolução Nº ${ret.number} criada`);
+        this.router.navigate(['/invoices', ret.id, 'print']).then(() => this.loadInvoice(ret.id));
+      },
+      error: (error) => {
+        this.showError(error.message);
+        this.printing = false;
+      }
+    });
+  }
+
+  /**
+   * URL do XML da DPS de uma nota de serviço (NFS-e)
+   */
+  getNfseUrl(): string {
+    return this.invoice ? this.invoiceService.getNfseUrl(this.invoice.id) : '';
+  }
+
+  /**
+   * Indica se a nota fechada pode ser reenviada à SEFAZ
+   */
+  canAuthorize(): boolean {
+    const status = this.invoice?.authorization?.status;
+    return status === AuthorizationStatus.PENDING || status === AuthorizationStatus.REJECTED;
+  }
+
+  /**
+   * Indica se a SEFAZ autorizou o uso da nota (condição para a CC-e)
+   */
+  isAuthorized(): boolean {
+    return this.invoice?.authorization?.status === AuthorizationStatus.AUTHORIZED;
+  }
+
+  /**
+   * Carrega as cartas de correção da nota
+   */
+  loadCorrections(): void {
+    if (!this.invoice) return;
+
+    this.invoiceService.getCorrections(this.invoice.id).subscribe({
+      next: (corrections) => this.corrections = corrections,
+      error: (error) => this.showError(error.message)
+    });
+  }
+
+  /**
+   * Envia uma carta de correção. O texto substitui as correções anteriores
+   */
+  sendCorrection(): void {
+    if (!this.invoice || !this.correctionText.trim()) return;
+
+    this.sendingCorrection = true;
+    this.invoiceService.createCorrection(this.invoice.id, this.correctionText).subscribe({
+      next: (correction) => {
+        this.corrections = [...this.corrections, correction];
+        this.correctionText = '';
+        this.sendingCorrection = false;
+        this.showSuccess(`Carta de correção ${correction.sequence} registrada`);
+      },
+      error: (error) => {
+        this.showError(error.message);
+        this.sendingCorrection = false;
+      }
+    });
+  }
+
+  /**
+   * Indica se a nota tem parcelas em aberto e admite recebimentos: com pedido
+   * de autorização, somente notas autorizadas pela SEFAZ recebem
+   */
+  canReceivePayment(): boolean {
+    if (this.invoice?.status !== InvoiceStatus.CLOSED) return false;
+    const authorization = this.invoice.authorization;
+    if (authorization && authorization.status !== AuthorizationStatus.AUTHORIZED) return false;
+    return !!this.invoice.installments?.some(installment => installment.amount > installment.paid);
+  }
+
+  /**
+   * Registra um recebimento na nota. Recebimentos em atraso devem incluir
+   * multa e juros, calculados pelo serviço de faturamento
+   */
+  registerPayment(): void {
+    if (!this.invoice || !this.paymentAmount) return;
+
+    const invoiceId = this.invoice.id;
+    this.sendingPayment = true;
+    this.invoiceService.registerPayment(invoiceId, {
+      amount: this.paymentAmount,
+      installment: this.paymentInstallment.trim() || undefined,
+      paid_at: this.paymentDate || undefined
+    }).subscribe({
+      next: () => {
+        this.paymentAmount = null;
+        this.paymentInstallment = '';
+        this.paymentDate = '';
+        this.sendingPayment = false;
+        this.showSuccess('Recebimento registrado');
+        this.loadInvoice(invoiceId);
+      },
+      error: (error) => {
+        this.showError(error.message);
+        this.sendingPayment = false;
+      }
+    });
+  }
+
+  /**
+   * Carrega a cobrança PIX do saldo em aberto. Sem chave PIX cadastrada no
+   * emitente a cobrança não é exibida
+   */
+  loadPixCharge(): void {
+    this.pixCharge = null;
+    if (!this.invoice || !this.canReceivePayment()) return;
+
+    this.invoiceService.getPixCharge(this.invoice.id).subscribe({
+      next: (charge) => this.pixCharge = charge,
+      error: () => this.pixCharge = null
+    });
+  }
+
+  /**
+   * URL do QR Code da cobrança PIX
+   */
+  getPixQrCodeUrl(): string {
+    return this.invoice ? this.invoiceService.getPixQrCodeUrl(this.invoice.id) : '';
+  }
+
+  /**
+   * URL do boleto (PDF) de uma parcela
+   */
+  getBankSlipUrl(installment: Installment): string {
+    return this.invoice ? this.invoiceService.getBankSlipPdfUrl(this.invoice.id, installment.number) : '';
+  }
+
+  /**
+   * Copia o payload "copia e cola" do PIX para a área de transferência
+   */
+  copyPixPayload(): void {
+    if (!this.pixCharge) return;
+
+    navigator.clipboard.writeText(this.pixCharge.payload).then(
+      () => this.showSuccess('Código PIX copiado'),
+      () => this.showError('Não foi possível copiar o código PIX')
+    );
+  }
+
+  /**
+   * URL do XML assinado de uma carta de correção
+   */
+  getCorrectionXmlUrl(correction: CorrectionLetter): string {
+    return this.invoiceService.getCorrectionXmlUrl(correction.invoice_id, correction.sequence);
+  }
+
   /**
    * Retorna o total de unidades da nota
    */
@@ -41,7 +41,12 @@ export class ProductFormComponent implements OnInit {
     this.productForm = this.fb.group({
       code: ['', [Validators.required, Validators.minLength(3)]],
       description: ['', [Validators.required, Validators.minLength(5)]],
-      balance: [0, [Validators.required, Validators.min(0)]]
+      balance: [0, [Validators.required, Validators.min(0)]],
+      ncm: ['', [Validators.required, Validators.pattern(/^\d{4}\.?\d{2}\.?\d{2}$/)]],
+      cest: ['', [Validators.pattern(/^\d{2}\.?\d{3}\.?\d{2}$/)]],
+      origin: [0, [Validators.required, Validators.min(0), Validators.max(8)]],
+      unit: ['UN', [Validators.required, Validators.maxLength(6)]],
+      net_weight: [0, [Validators.min(0)]]
     });
   }
 
@@ -117,4 +122,12 @@ export class ProductFormComponent implements OnInit {
   get balance() {
     return this.productForm.get('balance');
   }
+
+  get ncm() {
+    return this.productForm.get('ncm');
+  }
+
+  get cest() {
+    return this.productForm.get('cest');
+  }
 }
\ No newline at end of file
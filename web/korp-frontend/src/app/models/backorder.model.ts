@@ -0,0 +1,39 @@
+import { Charges, CreditOverrideDTO, PaymentTerms, TransportDTO } from './invoice.model';
+
+// Model de Pendência de Entrega (quantidade faturada sem estoque)
+export interface Backorder {
+  id: string;
+  status: BackorderStatus;
+  invoice_id: string; // Nota que faturou a quantidade disponível
+  model: string;
+  issuer_id: string;
+  customer_id?: string;
+  product_id: string;
+  product_code: string;
+  description: string;
+  quantity: number; // Quantidade pendente de entrega
+  unit_price: number;
+  discount?: number;
+  payment?: PaymentTerms;
+  fulfilled_by?: string; // Nota gerada para a pendência
+  created_by?: string;
+  created_at: string;
+  updated_at: string;
+  available_at?: string;
+  delivered_at?: string; // Impressão da nota gerada, que libera a reserva
+}
+
+// Situação da pendência; disponível quando uma entrada de estoque a atende
+export enum BackorderStatus {
+  PENDING = 'PENDENTE',
+  AVAILABLE = 'DISPONIVEL',
+  INVOICED = 'FATURADA',
+  CANCELLED = 'CANCELADA'
+}
+
+// DTO para gerar a nota de uma pendência disponível
+export interface BackorderInvoiceDTO {
+  charges?: Charges;
+  transport?: TransportDTO;
+  credit_override?: CreditOverrideDTO;
+}
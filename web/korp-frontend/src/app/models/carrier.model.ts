@@ -0,0 +1,23 @@
+import { Address } from './customer.model';
+
+// Model de Transportadora
+export interface Carrier {
+  id: string;
+  name: string;
+  document: string;
+  document_type: 'CPF' | 'CNPJ';
+  state_registration?: string;
+  rntc?: string; // Registro Nacional de Transportador de Carga (ANTT)
+  address: Address;
+  created_at: string;
+  updated_at: string;
+}
+
+// DTO para cadastro/atualização de transportadora
+export interface CarrierDTO {
+  name: string;
+  document: string;
+  state_registration?: string;
+  rntc?: string;
+  address: Address;
+}
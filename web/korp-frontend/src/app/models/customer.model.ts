@@ -0,0 +1,38 @@
+// Endereço fiscal
+export interface Address {
+  street: string;
+  number: string;
+  complement?: string;
+  district: string;
+  city_code: string;
+  city: string;
+  uf: string;
+  cep: string;
+  phone?: string;
+}
+
+// Model de Cliente (destinatário)
+export interface Customer {
+  id: string;
+  name: string;
+  document: string;
+  document_type: 'CPF' | 'CNPJ';
+  state_registration?: string;
+  tax_regime: number;
+  email?: string;
+  address: Address;
+  credit_limit?: number; // Ausente: sem limite de crédito
+  created_at: string;
+  updated_at: string;
+}
+
+// DTO para criação/atualização de cliente
+export interface CustomerDTO {
+  name: string;
+  document: string;
+  state_registration?: string;
+  tax_regime?: number;
+  email?: string;
+  address: Address;
+  credit_limit?: number;
+}
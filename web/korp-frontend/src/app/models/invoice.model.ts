@@ -1,36 +1,452 @@
+import { Customer } from './customer.model';
+import { Issuer } from './issuer.model';
+import { Carrier } from './carrier.model';
+
 // Model de Nota Fiscal
 export interface Invoice {
   id: string;
   number: number;
+  model: InvoiceModel;
+  series: number;
   status: InvoiceStatus;
+  access_key?: string;
+  authorization?: Authorization;
+  contingency?: Contingency;
+  return_of?: InvoiceReference;
+  returns?: string[];
+  sales_order_id?: string; // Pedido de venda que gerou a nota
+  backorders?: string[]; // Pendências das quantidades sem estoque na criação
+  backorder_id?: string; // Pendência de entrega faturada pela nota
+  batch?: BatchReference; // Grupo das notas divididas pelo limite de 990 itens
+  issuer_id: string;
+  issuer?: Issuer;
+  customer_id?: string;
+  customer?: Customer;
   items: InvoiceItem[];
+  charges: Charges;
+  transport?: Transport;
+  totals: InvoiceTotals;
+  payment?: PaymentTerms;
+  installments?: Installment[];
+  payments?: PaymentRecord[];
+  credit_override?: CreditOverride;
+  approval?: InvoiceApproval;
+  created_by?: string;
   created_at: string;
   updated_at: string;
   closed_at?: string;
 }
 
+// Modelo do documento fiscal
+export enum InvoiceModel {
+  NFE = '55',
+  NFCE = '65',
+  NFSE = 'NFSE'
+}
+
+// Tipo do item: mercadorias saem do estoque, serviços são faturados em NFS-e
+export enum ItemType {
+  GOODS = 'MERCADORIA',
+  SERVICE = 'SERVICO'
+}
+
 // Status da Nota Fiscal
 export enum InvoiceStatus {
   OPEN = 'ABERTA',
-  CLOSED = 'FECHADA'
+  CLOSED = 'FECHADA',
+  AWAITING_APPROVAL = 'AGUARDANDO_APROVACAO',
+  DISAPPROVED = 'REPROVADA'
+}
+
+// Situação da nota perante a SEFAZ
+export enum AuthorizationStatus {
+  PENDING = 'PENDENTE',
+  AUTHORIZED = 'AUTORIZADA',
+  REJECTED = 'REJEITADA',
+  DENIED = 'DENEGADA'
+}
+
+// Retorno do pedido de autorização na SEFAZ
+export interface Authorization {
+  status: AuthorizationStatus;
+  receipt?: string;
+  protocol?: string;
+  status_code?: number;
+  reason?: string;
+  received_at?: string;
+  attempts: number;
+  updated_at: string;
+}
+
+// Emissão em contingência off-line (tpEmis 2 = FS-IA, 5 = FS-DA)
+export interface Contingency {
+  emission_type: number;
+  justification: string;
+  started_at: string;
+}
+
+// Frete, seguro e outras despesas: no cabeçalho, informadas pelo usuário; nos
+// itens, a parcela rateada proporcionalmente ao valor de cada linha
+export interface Charges {
+  freight: number;
+  insurance: number;
+  other: number;
+}
+
+// Modalidade do frete (modFrete da NF-e)
+export enum FreightMode {
+  SENDER = 0,
+  RECIPIENT = 1,
+  THIRD_PARTY = 2,
+  OWN_SENDER = 3,
+  OWN_RECIPIENT = 4,
+  NO_TRANSPORT = 9
+}
+
+// Rótulos das modalidades de frete
+export const FREIGHT_MODES: { value: FreightMode; label: string }[] = [
+  { value: FreightMode.SENDER, label: '0 - Por conta do remetente (CIF)' },
+  { value: FreightMode.RECIPIENT, label: '1 - Por conta do destinatário (FOB)' },
+  { value: FreightMode.THIRD_PARTY, label: '2 - Por conta de terceiros' },
+  { value: FreightMode.OWN_SENDER, label: '3 - Transporte próprio do remetente' },
+  { value: FreightMode.OWN_RECIPIENT, label: '4 - Transporte próprio do destinatário' },
+  { value: FreightMode.NO_TRANSPORT, label: '9 - Sem ocorrência de transporte' }
+];
+
+// Veículo que transporta a mercadoria
+export interface Vehicle {
+  plate: string;
+  uf: string;
+  rntc?: string;
+}
+
+// Dados de transporte da nota de mercadorias (pesos em kg)
+export interface Transport {
+  freight_mode: FreightMode;
+  carrier_id?: string;
+  carrier?: Carrier;
+  vehicle?: Vehicle;
+  volumes?: number;
+  species?: string;
+  gross_weight?: number;
+  net_weight?: number;
+}
+
+// Meios de pagamento (tPag) aceitos na condição de pagamento
+export const PAYMENT_METHODS: { value: string; label: string }[] = [
+  { value: '01', label: '01 - Dinheiro' },
+  { value: '02', label: '02 - Cheque' },
+  { value: '03', label: '03 - Cartão de crédito' },
+  { value: '04', label: '04 - Cartão de débito' },
+  { value: '05', label: '05 - Crédito loja' },
+  { value: '15', label: '15 - Boleto bancário' },
+  { value: '16', label: '16 - Depósito bancário' },
+  { value: '17', label: '17 - PIX' },
+  { value: '18', label: '18 - Transferência bancária' },
+  { value: '99', label: '99 - Outros' }
+];
+
+// Condição de pagamento: uma parcela sem intervalo é pagamento à vista
+export interface PaymentTerms {
+  method: string;
+  description?: string;
+  installments: number;
+  interval_days?: number;
+}
+
+// Parcela (duplicata) da nota
+export interface Installment {
+  number: string;
+  due_date: string;
+  amount: number;
+  paid: number;
+  status?: InstallmentStatus;
+}
+
+// Situação da parcela na data da consulta
+export enum InstallmentStatus {
+  OPEN = 'ABERTA',
+  PARTIALLY_PAID = 'PARCIALMENTE_PAGA',
+  PAID = 'PAGA',
+  OVERDUE = 'VENCIDA'
+}
+
+// Parte de um recebimento aplicada a uma parcela
+export interface PaymentAllocation {
+  installment: string;
+  principal: number;
+  penalty: number;
+  interest: number;
+  days_late?: number;
+}
+
+// Recebimento registrado na nota
+export interface PaymentRecord {
+  id: string;
+  amount: number;
+  paid_at: string;
+  method?: string;
+  allocations: PaymentAllocation[];
+  created_at: string;
+}
+
+// Dados para registrar um recebimento; sem parcela, o valor quita as
+// parcelas em aberto pela ordem de vencimento
+export interface PaymentDTO {
+  amount: number;
+  installment?: string;
+  paid_at?: string;
+  method?: string;
+}
+
+// Modo do BR Code do PIX
+export enum PixMode {
+  STATIC = 'ESTATICO',
+  DYNAMIC = 'DINAMICO'
+}
+
+// Cobrança PIX de uma nota ou parcela, com o payload "copia e cola"
+export interface PixCharge {
+  mode: PixMode;
+  key?: string;
+  location?: string;
+  txid: string;
+  amount: number;
+  installment?: string;
+  payload: string;
+}
+
+// Boleto de uma parcela, com código de barras e linha digitável (Febraban)
+export interface BankSlip {
+  bank: string;
+  installment: string;
+  document_number: string;
+  due_date: string;
+  amount: number;
+  our_number: string;
+  barcode: string;
+  digitable_line: string;
+  digitable_line_formatted: string;
+}
+
+// Saldos em aberto por faixa de atraso
+export interface AgingBuckets {
+  current: number;
+  overdue_1_30: number;
+  overdue_31_60: number;
+  overdue_61_90: number;
+  overdue_over_90: number;
+  total: number;
+}
+
+export interface CustomerAging extends AgingBuckets {
+  customer_id?: string;
+  customer_name?: string;
+}
+
+// Relatório de vencimentos (aging) dos recebíveis
+export interface AgingReport {
+  date: string;
+  totals: AgingBuckets;
+  customers: CustomerAging[];
+}
+
+// Parcela a receber de uma nota emitida
+export interface Receivable extends Installment {
+  invoice_id: string;
+  invoice_number: number;
+  model: InvoiceModel;
+  series: number;
+  customer_id?: string;
+  customer_name?: string;
+  method: string;
+  outstanding: number;
+}
+
+// Nota original referenciada por uma nota de devolução
+export interface InvoiceReference {
+  invoice_id: string;
+  model: InvoiceModel;
+  series: number;
+  number: number;
+  access_key: string;
 }
 
 // Item da Nota Fiscal
 export interface InvoiceItem {
-  product_id: string;
+  type: ItemType;
+  product_id?: string;
+  service_id?: string;
   product_code: string;
   description: string;
   quantity: number;
+  ncm?: string;
+  cest?: string;
+  origin: number;
+  service_list_item?: string;
+  municipal_code?: string;
+  iss_rate?: number;
+  unit: string;
+  unit_weight?: number;
+  cfop?: string;
+  unit_price: number;
+  total: number;
+  discount?: number; // Desconto da linha (vDesc)
+  charges: Charges;
+  taxes: ItemTaxes;
+  original_item?: number;
+  returned_quantity?: number;
+  order_line?: number; // Linha do pedido de venda
+}
+
+// Tributos atuais (ICMS, PIS, COFINS) de um item
+export interface LegacyTaxes {
+  icms_cst: string;
+  pis_cst: string;
+  cofins_cst: string;
+  icms_base: number;
+  icms_rate: number;
+  icms_value: number;
+  pis_rate: number;
+  pis_value: number;
+  cofins_rate: number;
+  cofins_value: number;
+}
+
+// Grupo IBS/CBS da reforma tributária de um item
+export interface IBSCBSTaxes {
+  cst: string;
+  class_trib: string;
+  base: number;
+  ibs_uf_rate: number;
+  ibs_uf_value: number;
+  ibs_mun_rate: number;
+  ibs_mun_value: number;
+  cbs_rate: number;
+  cbs_value: number;
+}
+
+// ISS de um item de serviço
+export interface ISSTaxes {
+  base: number;
+  rate: number;
+  value: number;
+}
+
+// Tributos de um item (legado e reforma separados)
+export interface ItemTaxes {
+  legacy: LegacyTaxes;
+  iss?: ISSTaxes;
+  ibs_cbs?: IBSCBSTaxes;
+}
+
+// Totais da nota fiscal
+export interface InvoiceTotals {
+  products: number;
+  services: number;
+  freight: number;
+  insurance: number;
+  other: number;
+  discount: number;
+  legacy: {
+    icms_base: number;
+    icms_value: number;
+    pis_value: number;
+    cofins_value: number;
+  };
+  iss?: {
+    base: number;
+    value: number;
+  };
+  ibs_cbs?: {
+    base: number;
+    ibs_uf_value: number;
+    ibs_mun_value: number;
+    ibs_value: number;
+    cbs_value: number;
+  };
+  total: number;
 }
 
 // DTO para criação de nota fiscal
 export interface CreateInvoiceDTO {
+  model: InvoiceModel;
+  issuer_id: string;
+  customer_id?: string;
   items: CreateInvoiceItemDTO[];
+  charges?: Charges;
+  transport?: TransportDTO;
+  payment?: PaymentTerms;
+  credit_override?: CreditOverrideDTO; // Exige usuário aprovador (X-User)
+  allow_backorder?: boolean; // Fatura o disponível e registra pendência do que faltar
+}
+
+// Regras que retiveram a nota para aprovação e a decisão do aprovador
+export interface InvoiceApproval {
+  reasons: ApprovalReason[];
+  requested_at: string;
+  decision?: 'APROVADA' | 'REPROVADA';
+  decided_by?: string;
+  comment?: string;
+  decided_at?: string;
+}
+
+export interface ApprovalReason {
+  rule: 'VALOR_TOTAL' | 'DESCONTO' | 'ITEM_NAO_VERIFICADO';
+  item?: number;
+  message: string;
+}
+
+// Liberação de uma nota acima do limite de crédito do cliente
+export interface CreditOverride {
+  approved_by: string;
+  reason: string;
+  limit: number;
+  outstanding: number;
+  invoice_total: number;
+  approved_at: string;
+}
+
+// Pedido de liberação de crédito; o aprovador é o usuário autenticado
+export interface CreditOverrideDTO {
+  reason: string;
+}
+
+// Valores da verificação do limite de crédito, devolvidos na recusa (422)
+export interface CreditCheck {
+  customer_id: string;
+  limit: number;
+  outstanding: number;
+  invoice_total: number;
+  available: number;
+  excess: number;
+}
+
+// Dados de transporte informados na criação; o peso líquido é calculado
+// pelos produtos quando não informado
+export interface TransportDTO {
+  freight_mode: FreightMode;
+  carrier_id?: string;
+  vehicle?: Vehicle;
+  volumes?: number;
+  species?: string;
+  gross_weight?: number;
+  net_weight?: number;
 }
 
 // DTO para item ao criar nota
 export interface CreateInvoiceItemDTO {
-  product_id: string;
+  type?: ItemType;
+  product_id?: string;
+  service_id?: string;
+  quantity: number;
+  unit_price?: number;
+  discount?: number; // Somente mercadorias
+}
+
+// Item devolvido: número do item na nota original e quantidade
+export interface ReturnItemDTO {
+  item: number;
   quantity: number;
 }
 
@@ -39,4 +455,43 @@ export interface PrintResponse {
   success: boolean;
   message: string;
   invoice?: Invoice;
-}
\ No newline at end of file
+}
+
+// Referência ao grupo de notas geradas por uma mesma criação
+export interface BatchReference {
+  id: string;
+  sequence: number; // Posição da nota no grupo, a partir de 1
+  count: number;
+}
+
+// Grupo de notas, na ordem de criação
+export interface InvoiceBatch {
+  id: string;
+  total: number;
+  invoices: Invoice[];
+}
+
+// Resposta da criação: a nota, ou o grupo quando os itens excedem 990
+export type CreatedInvoices = Invoice | InvoiceBatch;
+
+// Indica se a criação gerou um grupo de notas
+export function isInvoiceBatch(created: CreatedInvoices): created is InvoiceBatch {
+  return Array.isArray((created as InvoiceBatch).invoices);
+}
+
+// Resposta da impressão de um grupo de notas
+export interface BatchPrintResponse {
+  success: boolean;
+  message: string;
+  batch?: InvoiceBatch;
+}
+// Carta de correção (CC-e) de uma nota autorizada
+export interface CorrectionLetter {
+  id: string;
+  invoice_id: string;
+  access_key: string;
+  sequence: number;
+  text: string;
+  event_id: string;
+  created_at: string;
+}
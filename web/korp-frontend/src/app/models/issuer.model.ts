@@ -0,0 +1,29 @@
+import { Address } from './customer.model';
+
+// Model de Emitente (estabelecimento)
+export interface Issuer {
+  id: string;
+  name: string;
+  trade_name?: string;
+  cnpj: string;
+  state_registration: string;
+  municipal_registration?: string;
+  crt: number;
+  series: number;
+  consumer_series: number;
+  service_series: number;
+  address: Address;
+  pix_key?: string;
+  bank_slip?: BankSlipAccount;
+  created_at: string;
+  updated_at: string;
+}
+
+// Conta de cobrança do emitente para emissão de boletos (bancos 001, 237 e 341)
+export interface BankSlipAccount {
+  bank: string;
+  agency: string;
+  account: string;
+  wallet: string;
+  agreement?: string;
+}
@@ -4,6 +4,11 @@ export interface Product {
   code: string;
   description: string;
   balance: number;
+  ncm: string;
+  cest?: string;
+  origin: number;
+  unit: string;
+  net_weight?: number; // Peso líquido unitário em kg
   created_at: string;
   updated_at: string;
 }
@@ -13,6 +18,11 @@ export interface CreateProductDTO {
   code: string;
   description: string;
   balance: number;
+  ncm: string;
+  cest?: string;
+  origin?: number;
+  unit?: string;
+  net_weight?: number;
 }
 
 // DTO para atualização de produto
@@ -20,4 +30,9 @@ export interface UpdateProductDTO {
   code: string;
   description: string;
   balance: number;
+  ncm: string;
+  cest?: string;
+  origin?: number;
+  unit?: string;
+  net_weight?: number;
 }
\ No newline at end of file
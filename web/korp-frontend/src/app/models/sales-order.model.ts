@@ -0,0 +1,64 @@
+import { Charges, CreditOverrideDTO, PaymentTerms, TransportDTO } from './invoice.model';
+
+// Model de Pedido de Venda
+export interface SalesOrder {
+  id: string;
+  number: number;
+  status: SalesOrderStatus;
+  issuer_id: string;
+  customer_id: string;
+  lines: SalesOrderLine[];
+  payment?: PaymentTerms; // Copiada para as notas geradas
+  notes?: string;
+  invoices?: string[]; // Notas geradas a partir do pedido
+  created_by?: string;
+  created_at: string;
+  updated_at: string;
+  confirmed_at?: string;
+  cancelled_at?: string;
+}
+
+// Situação do pedido; a partir da confirmação o saldo a faturar fica reservado
+export enum SalesOrderStatus {
+  DRAFT = 'RASCUNHO',
+  CONFIRMED = 'CONFIRMADO',
+  PARTIALLY_INVOICED = 'FATURADO_PARCIAL',
+  INVOICED = 'FATURADO',
+  CANCELLED = 'CANCELADO'
+}
+
+// Linha do pedido com as quantidades faturadas e entregues (notas impressas)
+export interface SalesOrderLine {
+  number: number;
+  product_id: string;
+  product_code: string;
+  description: string;
+  quantity: number;
+  unit_price: number;
+  invoiced: number;
+  delivered: number;
+}
+
+// DTO para criação de pedido
+export interface SalesOrderDTO {
+  issuer_id: string;
+  customer_id: string;
+  lines: SalesOrderLineDTO[];
+  payment?: PaymentTerms;
+  notes?: string;
+}
+
+export interface SalesOrderLineDTO {
+  product_id: string;
+  quantity: number;
+  unit_price: number;
+}
+
+// DTO para gerar nota do pedido; sem linhas, todo o saldo a faturar é incluído
+export interface OrderInvoiceDTO {
+  lines?: { line: number; quantity: number }[];
+  model?: string;
+  charges?: Charges;
+  transport?: TransportDTO;
+  credit_override?: CreditOverrideDTO;
+}
@@ -0,0 +1,22 @@
+// Model de Serviço do catálogo faturado em NFS-e
+export interface Service {
+  id: string;
+  code: string;
+  description: string;
+  service_list_item: string; // Subitem da LC 116/2003 (ex.: 01.07)
+  municipal_code?: string;
+  iss_rate: number;
+  unit: string;
+  created_at: string;
+  updated_at: string;
+}
+
+// DTO para cadastro de serviço
+export interface ServiceDTO {
+  code: string;
+  description: string;
+  service_list_item: string;
+  municipal_code?: string;
+  iss_rate: number;
+  unit?: string;
+}
@@ -0,0 +1,80 @@
+import { Injectable } from '@angular/core';
+import { HttpClient, HttpErrorResponse } from '@angular/common/http';
+import { Observable, throwError } from 'rxjs';
+import { catchError } from 'rxjs/operators';
+import { Backorder, BackorderInvoiceDTO } from '../models/backorder.model';
+import { Invoice } from '../models/invoice.model';
+
+@Injectable({
+  providedIn: 'root'
+})
+export class BackorderService {
+  private apiUrl = 'http://localhost:8082/api/backorders';
+
+  constructor(private http: HttpClient) {}
+
+  /**
+   * Lista as pendências de entrega, da mais antiga para a mais nova
+   */
+  getBackorders(): Observable<Backorder[]> {
+    return this.http.get<Backorder[]>(this.apiUrl).pipe(
+      catchError(this.handleError)
+    );
+  }
+
+  /**
+   * Busca uma pendência de entrega por ID
+   */
+  getBackorder(id: string): Observable<Backorder> {
+    return this.http.get<Backorder>(`${this.apiUrl}/${id}`).pipe(
+      catchError(this.handleError)
+    );
+  }
+
+  /**
+   * Reavalia as pendências de todos os produtos contra o estoque atual
+   */
+  recheckBackorders(): Observable<Backorder[]> {
+    return this.http.post<Backorder[]>(`${this.apiUrl}/recheck`, {}).pipe(
+      catchError(this.handleError)
+    );
+  }
+
+  /**
+   * Gera a nota de uma pendência com estoque disponível
+   */
+  createInvoice(id: string, request: BackorderInvoiceDTO = {}): Observable<Invoice> {
+    return this.http.post<Invoice>(`${this.apiUrl}/${id}/invoice`, request).pipe(
+      catchError(this.handleError)
+    );
+  }
+
+  /**
+   * Cancela a pendência de entrega
+   */
+  cancelBackorder(id: string): Observable<Backorder> {
+    return this.http.post<Backorder>(`${this.apiUrl}/${id}/cancel`, {}).pipe(
+      catchError(this.handleError)
+    );
+  }
+
+  /**
+   * Tratamento centralizado de erros
+   */
+  private handleError(error: HttpErrorResponse) {
+    let errorMessage = 'Ocorreu um erro desconhecido';
+
+    if (error.error instanceof ErrorEvent) {
+      errorMessage = `Erro: ${error.error.message}`;
+    } else if (error.status === 0) {
+      errorMessage = 'Não foi possível conectar ao servidor. Verifique se o Billing Service está rodando.';
+    } else if (error.error?.message) {
+      errorMessage = error.error.message;
+    } else {
+      errorMessage = `Erro do servidor: ${error.status}`;
+    }
+
+    console.error('Erro no BackorderService:', error);
+    return throwError(() => new Error(errorMessage));
+  }
+}
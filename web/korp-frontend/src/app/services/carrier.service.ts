@@ -0,0 +1,72 @@
+import { Injectable } from '@angular/core';
+import { HttpClient, HttpErrorResponse } from '@angular/common/http';
+import { Observable, throwError } from 'rxjs';
+import { catchError } from 'rxjs/operators';
+import { Carrier, CarrierDTO } from '../models/carrier.model';
+
+@Injectable({
+  providedIn: 'root'
+})
+export class CarrierService {
+  private apiUrl = 'http://localhost:8082/api/carriers';
+
+  constructor(private http: HttpClient) {}
+
+  /**
+   * Lista as transportadoras cadastradas
+   */
+  getCarriers(): Observable<Carrier[]> {
+    return this.http.get<Carrier[]>(this.apiUrl).pipe(
+      catchError(this.handleError)
+    );
+  }
+
+  /**
+   * Busca uma transportadora por ID
+   */
+  getCarrier(id: string): Observable<Carrier> {
+    return this.http.get<Carrier>(`${this.apiUrl}/${id}`).pipe(
+      catchError(this.handleError)
+    );
+  }
+
+  /**
+   * Cadastra uma nova transportadora
+   */
+  createCarrier(carrier: CarrierDTO): Observable<Carrier> {
+    return this.http.post<Carrier>(this.apiUrl, carrier).pipe(
+      catchError(this.handleError)
+    );
+  }
+
+  /**
+   * Atualiza uma transportadora existente
+   */
+  updateCarrier(id: string, carrier: CarrierDTO): Observable<Carrier> {
+    return this.http.put<Carrier>(`${this.apiUrl}/${id}`, carrier).pipe(
+      catchError(this.handleError)
+    );
+  }
+
+  /**
+   * Tratamento centralizado de erros
+   */
+  private handleError(error: HttpErrorResponse) {
+    let errorMessage = 'Ocorreu um erro desconhecido';
+
+    if (error.error instanceof ErrorEvent) {
+      errorMessage = `Erro: ${error.error.message}`;
+    } else if (error.status === 0) {
+      errorMessage = 'Não foi possível conectar ao servidor. Verifique se o Billing Service está rodando.';
+    } else if (error.status === 409) {
+      errorMessage = 'Já existe transportadora com este documento';
+    } else if (error.error?.message) {
+      errorMessage = error.error.message;
+    } else {
+      errorMessage = `Erro do servidor: ${error.status}`;
+    }
+
+    console.error('Erro no CarrierService:', error);
+    return throwError(() => new Error(errorMessage));
+  }
+}
@@ -0,0 +1,72 @@
+import { Injectable } from '@angular/core';
+import { HttpClient, HttpErrorResponse } from '@angular/common/http';
+import { Observable, throwError } from 'rxjs';
+import { catchError } from 'rxjs/operators';
+import { Customer, CustomerDTO } from '../models/customer.model';
+
+@Injectable({
+  providedIn: 'root'
+})
+export class CustomerService {
+  private apiUrl = 'http://localhost:8082/api/customers';
+
+  constructor(private http: HttpClient) {}
+
+  /**
+   * Lista todos os clientes
+   */
+  getCustomers(): Observable<Customer[]> {
+    return this.http.get<Customer[]>(this.apiUrl).pipe(
+      catchError(this.handleError)
+    );
+  }
+
+  /**
+   * Busca um cliente por ID
+   */
+  getCustomer(id: string): Observable<Customer> {
+    return this.http.get<Customer>(`${this.apiUrl}/${id}`).pipe(
+      catchError(this.handleError)
+    );
+  }
+
+  /**
+   * Cadastra um novo cliente
+   */
+  createCustomer(customer: CustomerDTO): Observable<Customer> {
+    return this.http.post<Customer>(this.apiUrl, customer).pipe(
+      catchError(this.handleError)
+    );
+  }
+
+  /**
+   * Atualiza um cliente existente
+   */
+  updateCustomer(id: string, customer: CustomerDTO): Observable<Customer> {
+    return this.http.put<Customer>(`${this.apiUrl}/${id}`, customer).pipe(
+      catchError(this.handleError)
+    );
+  }
+
+  /**
+   * Tratamento centralizado de erros
+   */
+  private handleError(error: HttpErrorResponse) {
+    let errorMessage = 'Ocorreu um erro desconhecido';
+
+    if (error.error instanceof ErrorEvent) {
+      errorMessage = `Erro: ${error.error.message}`;
+    } else if (error.status === 0) {
+      errorMessage = 'Não foi possível conectar ao servidor. Verifique se o Billing Service está rodando.';
+    } else if (error.status === 409) {
+      errorMessage = 'Documento já cadastrado';
+    } else if (error.error?.message) {
+      errorMessage = error.error.message;
+    } else {
+      errorMessage = `Erro do servidor: ${error.status}`;
+    }
+
+    console.error('Erro no CustomerService:', error);
+    return throwError(() => new Error(errorMessage));
+  }
+}
@@ -2,7 +2,7 @@ import { Injectable } from '@angular/core';
 import { HttpClient, HttpErrorResponse } from '@angular/common/http';
 import { Observable, throwError, BehaviorSubject } from 'rxjs';
 import { catchError, tap } from 'rxjs/operators';
-import { Invoice, CreateInvoiceDTO, PrintResponse } from '../models/invoice.model';
+import { Invoice, CreateInvoiceDTO, PrintResponse, CorrectionLetter, ReturnItemDTO, Receivable, PaymentRecord, PaymentDTO, AgingReport, PixCharge, PixMode, BankSlip, CreditCheck, CreditOverrideDTO, InvoiceBatch, BatchPrintResponse, CreatedInvoices } from '../models/invoice.model';
 
 @Injectable({
   providedIn: 'root'
@@ -40,10 +40,10 @@ export class InvoiceService {
   }
 
   /**
-   * Cria uma nova nota fiscal
+   * Cria uma nova nota fiscal; acima de 990 itens, retorna o grupo de notas
    */
-  createInvoice(invoice: CreateInvoiceDTO): Observable<Invoice> {
-    return this.http.post<Invoice>(this.apiUrl, invoice).pipe(
+  createInvoice(invoice: CreateInvoiceDTO): Observable<CreatedInvoices> {
+    return this.http.post<CreatedInvoices>(this.apiUrl, invoice).pipe(
       tap(() => this.getInvoices().subscribe()), // Recarrega lista
       catchError(this.handleError)
     );
@@ -53,10 +53,11 @@ export class InvoiceService {
    * Imprime (fecha) uma nota fiscal
    * Esta é a operação mais crítica do sistema
    */
-  printInvoice(id: string): Observable<PrintResponse> {
+  printInvoice(id: string, creditOverride?: CreditOverrideDTO): Observable<PrintResponse> {
     this.printingSubject.next(true); // Ativa indicador de loading
     
-    return this.http.post<PrintResponse>(`${this.apiUrl}/${id}/print`, {}).pipe(
+    const body = creditOverride ? { credit_override: creditOverride } : {};
+    return this.http.post<PrintResponse>(`${this.apiUrl}/${id}/print`, body).pipe(
       tap(() => {
         this.printingSubject.next(false); // Desativa loading
         this.getInvoices().subscribe(); // Recarrega lista
@@ -68,6 +69,203 @@ export class InvoiceService {
     );
   }
 
+  /**
+   * Busca as notas de um grupo (notas divididas pelo limite de 990 itens)
+   */
+  getInvoiceBatch(batchId: string): Observable<InvoiceBatch> {
+    return this.http.get<InvoiceBatch>(`${this.apiUrl}/batches/${batchId}`).pipe(
+      catchError(this.handleError)
+    );
+  }
+
+  /**
+   * Imprime (fecha) juntas as notas de um grupo
+   */
+  printInvoiceBatch(batchId: string, creditOverride?: CreditOverrideDTO): Observable<BatchPrintResponse> {
+    this.printingSubject.next(true);
+
+    const body = creditOverride ? { credit_override: creditOverride } : {};
+    return this.http.post<BatchPrintResponse>(`${this.apiUrl}/batches/${batchId}/print`, body).pipe(
+      tap(() => {
+        this.printingSubject.next(false);
+        this.getInvoices().subscribe();
+      }),
+      catchError(error => {
+        this.printingSubject.next(false);
+        return this.handleError(error);
+      })
+    );
+  }
+
+  /**
+   * Reenvia à SEFAZ uma nota fechada pendente ou rejeitada
+   */
+  authorizeInvoice(id: string): Observable<PrintResponse> {
+    return this.http.post<PrintResponse>(`${this.apiUrl}/${id}/authorize`, {}).pipe(
+      tap(() => this.getInvoices().subscribe()),
+      catchError(this.handleError)
+    );
+  }
+
+  /**
+   * Aprova uma nota retida pelas regras de aprovação
+   */
+  approveInvoice(id: string, comment: string): Observable<Invoice> {
+    return this.http.post<Invoice>(`${this.apiUrl}/${id}/approve`, { comment }).pipe(
+      tap(() => this.getInvoices().subscribe()),
+      catchError(this.handleError)
+    );
+  }
+
+  /**
+   * Reprova uma nota retida pelas regras de aprovação
+   */
+  rejectInvoice(id: string, comment: string): Observable<Invoice> {
+    return this.http.post<Invoice>(`${this.apiUrl}/${id}/reject`, { comment }).pipe(
+      tap(() => this.getInvoices().subscribe()),
+      catchError(this.handleError)
+    );
+  }
+
+  /**
+   * URL do XML da NF-e de uma nota fechada
+   */
+  getXmlUrl(id: string): string {
+    return `${this.apiUrl}/${id}/xml`;
+  }
+
+  /**
+   * URL do DANFE (PDF) de uma nota fechada
+   */
+  getDanfeUrl(id: string): string {
+    return `${this.apiUrl}/${id}/danfe.pdf`;
+  }
+
+  /**
+   * URL do XML da DPS (NFS-e) de uma nota de serviço fechada
+   */
+  getNfseUrl(id: string): string {
+    return `${this.apiUrl}/${id}/nfse`;
+  }
+
+  /**
+   * Lista as cartas de correção de uma nota
+   */
+  getCorrections(id: string): Observable<CorrectionLetter[]> {
+    return this.http.get<CorrectionLetter[]>(`${this.apiUrl}/${id}/corrections`).pipe(
+      catchError(this.handleError)
+    );
+  }
+
+  /**
+   * Registra uma carta de correção para uma nota autorizada
+   */
+  createCorrection(id: string, text: string): Observable<CorrectionLetter> {
+    return this.http.post<CorrectionLetter>(`${this.apiUrl}/${id}/corrections`, { text }).pipe(
+      catchError(this.handleError)
+    );
+  }
+
+  /**
+   * Cria uma nota de devolução da nota fechada. Sem itens, devolve todo o
+   * saldo ainda não devolvido
+   */
+  createReturn(id: string, items: ReturnItemDTO[] = []): Observable<Invoice> {
+    return this.http.post<Invoice>(`${this.apiUrl}/${id}/returns`, { items }).pipe(
+      tap(() => this.getInvoices().subscribe()),
+      catchError(this.handleError)
+    );
+  }
+
+  /**
+   * Lista as parcelas a receber das notas emitidas, ordenadas pelo
+   * vencimento. As datas seguem o formato AAAA-MM-DD
+   */
+  getReceivables(from?: string, to?: string): Observable<Receivable[]> {
+    const params: Record<string, string> = {};
+    if (from) params['from'] = from;
+    if (to) params['to'] = to;
+    return this.http.get<Receivable[]>(this.apiUrl.replace('/invoices', '/receivables'), { params }).pipe(
+      catchError(this.handleError)
+    );
+  }
+
+  /**
+   * Busca o relatório de vencimentos dos recebíveis na data informada
+   */
+  getAging(date?: string): Observable<AgingReport> {
+    const params: Record<string, string> = {};
+    if (date) params['date'] = date;
+    return this.http.get<AgingReport>(this.apiUrl.replace('/invoices', '/receivables/aging'), { params }).pipe(
+      catchError(this.handleError)
+    );
+  }
+
+  /**
+   * Busca a cobrança PIX do valor devido na nota ou em uma parcela
+   */
+  getPixCharge(id: string, installment?: string, mode?: PixMode): Observable<PixCharge> {
+    return this.http.get<PixCharge>(`${this.apiUrl}/${id}/pix`, { params: this.pixParams(installment, mode) }).pipe(
+      catchError(this.handleError)
+    );
+  }
+
+  /**
+   * URL do QR Code (PNG) da cobrança PIX
+   */
+  getPixQrCodeUrl(id: string, installment?: string, mode?: PixMode): string {
+    const query = new URLSearchParams(this.pixParams(installment, mode)).toString();
+    return `${this.apiUrl}/${id}/pix.png` + (query ? `?${query}` : '');
+  }
+
+  /**
+   * Busca o boleto de uma parcela em aberto
+   */
+  getBankSlip(id: string, installment: string): Observable<BankSlip> {
+    return this.http.get<BankSlip>(`${this.apiUrl}/${id}/installments/${installment}/boleto`).pipe(
+      catchError(this.handleError)
+    );
+  }
+
+  /**
+   * URL do boleto (PDF) de uma parcela em aberto
+   */
+  getBankSlipPdfUrl(id: string, installment: string): string {
+    return `${this.apiUrl}/${id}/installments/${installment}/boleto.pdf`;
+  }
+
+  private pixParams(installment?: string, mode?: PixMode): Record<string, string> {
+    const params: Record<string, string> = {};
+    if (installment) params['installment'] = installment;
+    if (mode) params['mode'] = mode;
+    return params;
+  }
+
+  /**
+   * Lista os recebimentos registrados na nota
+   */
+  getPayments(id: string): Observable<PaymentRecord[]> {
+    return this.http.get<PaymentRecord[]>(`${this.apiUrl}/${id}/payments`).pipe(
+      catchError(this.handleError)
+    );
+  }
+
+  /**
+   * Registra um recebimento na nota. Pagamentos em atraso incluem multa e juros
+   */
+  registerPayment(id: string, payment: PaymentDTO): Observable<PaymentRecord> {
+    return this.http.post<PaymentRecord>(`${this.apiUrl}/${id}/payments`, payment).pipe(
+      catchError(this.handleError)
+    );
+  }
+
+  /**
+   * URL do XML assinado de uma carta de correção
+   */
+  getCorrectionXmlUrl(id: string, sequence: number): string {
+    return `${this.apiUrl}/${id}/corrections/${sequence}/xml`;
+  }
+
   /**
    * Tratamento centralizado de erros
    */
@@ -85,6 +283,11 @@ export class InvoiceService {
         errorMessage = 'Nota fiscal não encontrada';
       } else if (error.status === 400) {
         errorMessage = error.error?.error || 'Dados inválidos';
+      } else if (error.status === 422 && error.error?.credit) {
+        // Limite de crédito excedido: exibe os valores da verificação
+        const credit: CreditCheck = error.error.credit;
+        errorMessage = `${error.error.error}: limite ${credit.limit.toFixed(2)}, em aberto ${credit.outstanding.toFixed(2)}, ` +
+          `nota ${credit.invoice_total.toFixed(2)}, excedente ${credit.excess.toFixed(2)}`;
       } else if (error.status === 503) {
         // Falha na comunicação com Stock Service
         errorMessage = 'Falha ao comunicar com o serviço de estoque. Tente novamente.';
@@ -0,0 +1,41 @@
+import { Injectable } from '@angular/core';
+import { HttpClient, HttpErrorResponse } from '@angular/common/http';
+import { Observable, throwError } from 'rxjs';
+import { catchError } from 'rxjs/operators';
+import { Issuer } from '../models/issuer.model';
+
+@Injectable({
+  providedIn: 'root'
+})
+export class IssuerService {
+  private apiUrl = 'http://localhost:8082/api/issuers';
+
+  constructor(private http: HttpClient) {}
+
+  /**
+   * Lista os emitentes (estabelecimentos) configurados
+   */
+  getIssuers(): Observable<Issuer[]> {
+    return this.http.get<Issuer[]>(this.apiUrl).pipe(
+      catchError(this.handleError)
+    );
+  }
+
+  /**
+   * Tratamento centralizado de erros
+   */
+  private handleError(error: HttpErrorResponse) {
+    let errorMessage = 'Ocorreu um erro desconhecido';
+
+    if (error.status === 0) {
+      errorMessage = 'Não foi possível conectar ao servidor. Verifique se o Billing Service está rodando.';
+    } else if (error.error?.message) {
+      errorMessage = error.error.message;
+    } else {
+      errorMessage = `Erro do servidor: ${error.status}`;
+    }
+
+    console.error('Erro no IssuerService:', error);
+    return throwError(() => new Error(errorMessage));
+  }
+}
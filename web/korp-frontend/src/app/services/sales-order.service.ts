@@ -0,0 +1,99 @@
+import { Injectable } from '@angular/core';
+import { HttpClient, HttpErrorResponse } from '@angular/common/http';
+import { Observable, throwError } from 'rxjs';
+import { catchError } from 'rxjs/operators';
+import { SalesOrder, SalesOrderDTO, OrderInvoiceDTO } from '../models/sales-order.model';
+import { Invoice, CreatedInvoices } from '../models/invoice.model';
+
+@Injectable({
+  providedIn: 'root'
+})
+export class SalesOrderService {
+  private apiUrl = 'http://localhost:8082/api/sales-orders';
+
+  constructor(private http: HttpClient) {}
+
+  /**
+   * Lista os pedidos de venda
+   */
+  getSalesOrders(): Observable<SalesOrder[]> {
+    return this.http.get<SalesOrder[]>(this.apiUrl).pipe(
+      catchError(this.handleError)
+    );
+  }
+
+  /**
+   * Busca um pedido de venda por ID
+   */
+  getSalesOrder(id: string): Observable<SalesOrder> {
+    return this.http.get<SalesOrder>(`${this.apiUrl}/${id}`).pipe(
+      catchError(this.handleError)
+    );
+  }
+
+  /**
+   * Cria um pedido de venda em rascunho
+   */
+  createSalesOrder(order: SalesOrderDTO): Observable<SalesOrder> {
+    return this.http.post<SalesOrder>(this.apiUrl, order).pipe(
+      catchError(this.handleError)
+    );
+  }
+
+  /**
+   * Confirma o pedido, reservando o estoque das linhas
+   */
+  confirmSalesOrder(id: string): Observable<SalesOrder> {
+    return this.http.post<SalesOrder>(`${this.apiUrl}/${id}/confirm`, {}).pipe(
+      catchError(this.handleError)
+    );
+  }
+
+  /**
+   * Cancela o pedido, liberando a reserva do saldo não faturado
+   */
+  cancelSalesOrder(id: string): Observable<SalesOrder> {
+    return this.http.post<SalesOrder>(`${this.apiUrl}/${id}/cancel`, {}).pipe(
+      catchError(this.handleError)
+    );
+  }
+
+  /**
+   * Gera uma nota a partir das linhas do pedido; acima de 990 itens, retorna o
+   * grupo de notas
+   */
+  createInvoice(id: string, request: OrderInvoiceDTO = {}): Observable<CreatedInvoices> {
+    return this.http.post<CreatedInvoices>(`${this.apiUrl}/${id}/invoices`, request).pipe(
+      catchError(this.handleError)
+    );
+  }
+
+  /**
+   * Lista as notas geradas pelo pedido
+   */
+  getInvoices(id: string): Observable<Invoice[]> {
+    return this.http.get<Invoice[]>(`${this.apiUrl}/${id}/invoices`).pipe(
+      catchError(this.handleError)
+    );
+  }
+
+  /**
+   * Tratamento centralizado de erros
+   */
+  private handleError(error: HttpErrorResponse) {
+    let errorMessage = 'Ocorreu um erro desconhecido';
+
+    if (error.error instanceof ErrorEvent) {
+      errorMessage = `Erro: ${error.error.message}`;
+    } else if (error.status === 0) {
+      errorMessage = 'Não foi possível conectar ao servidor. Verifique se o Billing Service está rodando.';
+    } else if (error.error?.message) {
+      errorMessage = error.error.message;
+    } else {
+      errorMessage = `Erro do servidor: ${error.status}`;
+    }
+
+    console.error('Erro no SalesOrderService:', error);
+    return throwError(() => new Error(errorMessage));
+  }
+}
@@ -0,0 +1,72 @@
+import { Injectable } from '@angular/core';
+import { HttpClient, HttpErrorResponse } from '@angular/common/http';
+import { Observable, throwError } from 'rxjs';
+import { catchError } from 'rxjs/operators';
+import { Service, ServiceDTO } from '../models/service.model';
+
+@Injectable({
+  providedIn: 'root'
+})
+export class ServiceCatalogService {
+  private apiUrl = 'http://localhost:8082/api/services';
+
+  constructor(private http: HttpClient) {}
+
+  /**
+   * Lista os serviços do catálogo
+   */
+  getServices(): Observable<Service[]> {
+    return this.http.get<Service[]>(this.apiUrl).pipe(
+      catchError(this.handleError)
+    );
+  }
+
+  /**
+   * Busca um serviço por ID
+   */
+  getService(id: string): Observable<Service> {
+    return this.http.get<Service>(`${this.apiUrl}/${id}`).pipe(
+      catchError(this.handleError)
+    );
+  }
+
+  /**
+   * Cadastra um novo serviço
+   */
+  createService(service: ServiceDTO): Observable<Service> {
+    return this.http.post<Service>(this.apiUrl, service).pipe(
+      catchError(this.handleError)
+    );
+  }
+
+  /**
+   * Atualiza um serviço existente
+   */
+  updateService(id: string, service: ServiceDTO): Observable<Service> {
+    return this.http.put<Service>(`${this.apiUrl}/${id}`, service).pipe(
+      catchError(this.handleError)
+    );
+  }
+
+  /**
+   * Tratamento centralizado de erros
+   */
+  private handleError(error: HttpErrorResponse) {
+    let errorMessage = 'Ocorreu um erro desconhecido';
+
+    if (error.error instanceof ErrorEvent) {
+      errorMessage = `Erro: ${error.error.message}`;
+    } else if (error.status === 0) {
+      errorMessage = 'Não foi possível conectar ao servidor. Verifique se o Billing Service está rodando.';
+    } else if (error.status === 409) {
+      errorMessage = 'Código de serviço já cadastrado';
+    } else if (error.error?.message) {
+      errorMessage = error.error.message;
+    } else {
+      errorMessage = `Erro do servidor: ${error.status}`;
+    }
+
+    console.error('Erro no ServiceCatalogService:', error);
+    return throwError(() => new Error(errorMessage));
+  }
+}